@@ -3,12 +3,18 @@ package bootstrap
 import (
 	"github.com/micros-template/user-service/cmd/di"
 	"github.com/micros-template/user-service/config/env"
+	"github.com/micros-template/user-service/pkg/secret"
 
+	"github.com/spf13/viper"
 	"go.uber.org/dig"
 )
 
 func Run() *dig.Container {
 	env.Load()
+	// two-factor secrets can neither be sealed nor opened without the key, fail before serving anything
+	if _, err := secret.ParseKey(viper.GetString("app.two_factor.encryption_key")); err != nil {
+		panic("app.two_factor.encryption_key: " + err.Error())
+	}
 	container := di.BuildContainer()
 	return container
 }
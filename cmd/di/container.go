@@ -7,17 +7,21 @@ import (
 	"github.com/micros-template/user-service/config/logger"
 	mq "github.com/micros-template/user-service/config/message-queue"
 	"github.com/micros-template/user-service/config/router"
+	"github.com/micros-template/user-service/config/tracing"
 	"github.com/micros-template/user-service/internal/domain/handler"
 	"github.com/micros-template/user-service/internal/domain/repository"
 	"github.com/micros-template/user-service/internal/domain/service"
 	_cache "github.com/micros-template/user-service/internal/infrastructure/cache"
 	_db "github.com/micros-template/user-service/internal/infrastructure/database"
-	"github.com/micros-template/user-service/internal/infrastructure/eventbus"
+	"github.com/micros-template/user-service/internal/infrastructure/database/migration"
 	"github.com/micros-template/user-service/internal/infrastructure/grpc"
+	"github.com/micros-template/user-service/internal/infrastructure/health"
 	_logger "github.com/micros-template/user-service/internal/infrastructure/logger"
 	_mq "github.com/micros-template/user-service/internal/infrastructure/message-queue"
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
 
 	"go.uber.org/dig"
+	grpchealth "google.golang.org/grpc/health"
 )
 
 func BuildContainer() *dig.Container {
@@ -26,14 +30,30 @@ func BuildContainer() *dig.Container {
 	if err := container.Provide(logger.New); err != nil {
 		panic("Failed to provide logger: " + err.Error())
 	}
+	// tracer provider
+	if err := container.Provide(tracing.New); err != nil {
+		panic("Failed to provide tracer provider: " + err.Error())
+	}
 	// db connection
 	if err := container.Provide(db.New); err != nil {
 		panic("Failed to provide database: " + err.Error())
 	}
+	// metrics
+	if err := container.Provide(metrics.New); err != nil {
+		panic("Failed to provide metrics: " + err.Error())
+	}
 	// db querier
 	if err := container.Provide(_db.NewQuerier); err != nil {
 		panic("Failed to provide database querier`: " + err.Error())
 	}
+	// db transaction manager
+	if err := container.Provide(_db.NewTxManager); err != nil {
+		panic("Failed to provide transaction manager: " + err.Error())
+	}
+	// db migrator
+	if err := container.Provide(migration.New); err != nil {
+		panic("Failed to provide database migrator: " + err.Error())
+	}
 	// nats connection
 	if err := container.Provide(mq.New); err != nil {
 		panic("Failed to provide nats connection: " + err.Error())
@@ -50,10 +70,6 @@ func BuildContainer() *dig.Container {
 	if err := container.Provide(logemitter.NewInfraLogEmitter); err != nil {
 		panic("Failed to provide log emitter: " + err.Error())
 	}
-	// event emitter
-	if err := container.Provide(eventbus.NewEventBusEmitterInfra); err != nil {
-		panic("Failed to provide event bus emitter: " + err.Error())
-	}
 	// redis connection
 	if err := container.Provide(cache.New); err != nil {
 		panic("Failed to provide cache client: " + err.Error())
@@ -70,6 +86,14 @@ func BuildContainer() *dig.Container {
 	if err := container.Provide(grpc.NewFileServiceConnection); err != nil {
 		panic("Failed to provide user service grpc connection: " + err.Error())
 	}
+	// dependency health checker
+	if err := container.Provide(health.New); err != nil {
+		panic("Failed to provide health checker: " + err.Error())
+	}
+	// grpc health server
+	if err := container.Provide(grpchealth.NewServer); err != nil {
+		panic("Failed to provide grpc health server: " + err.Error())
+	}
 	// user service utils
 	if err := container.Provide(_logger.NewLoggerInfra); err != nil {
 		panic("Failed to provide user service utils: " + err.Error())
@@ -78,6 +102,14 @@ func BuildContainer() *dig.Container {
 	if err := container.Provide(repository.NewUserRepository); err != nil {
 		panic("Failed to provide authRepository: " + err.Error())
 	}
+	// outbox_repo
+	if err := container.Provide(repository.NewOutboxRepository); err != nil {
+		panic("Failed to provide outbox repository: " + err.Error())
+	}
+	// profile_cache_repo
+	if err := container.Provide(repository.NewProfileCacheRepository); err != nil {
+		panic("Failed to provide profile cache repository: " + err.Error())
+	}
 	// redis_repo
 	if err := container.Provide(repository.NewRedisRepository); err != nil {
 		panic("Failed to provide cache client: " + err.Error())
@@ -90,10 +122,18 @@ func BuildContainer() *dig.Container {
 	if err := container.Provide(service.NewUserService); err != nil {
 		panic("Failed to provide user service: " + err.Error())
 	}
+	// outbox_relay
+	if err := container.Provide(service.NewOutboxRelay); err != nil {
+		panic("Failed to provide outbox relay: " + err.Error())
+	}
 	// user_handler
 	if err := container.Provide(handler.NewUserHandler); err != nil {
 		panic("Failed to provide user handler: " + err.Error())
 	}
+	// health_handler
+	if err := container.Provide(handler.NewHealthHandler); err != nil {
+		panic("Failed to provide health handler: " + err.Error())
+	}
 	if err := container.Provide(router.NewHTTP); err != nil {
 		panic("Failed to provide HTTP Server: " + err.Error())
 	}
@@ -21,6 +21,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -28,18 +29,32 @@ import (
 	"syscall"
 
 	"github.com/micros-template/user-service/cmd/bootstrap"
+	"github.com/micros-template/user-service/cmd/migrate"
 	"github.com/micros-template/user-service/cmd/server"
 
 	"github.com/spf13/viper"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate.Run(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	log.SetOutput(io.Discard)
 	container := bootstrap.Run()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if err := migrate.OnBoot(ctx, container); err != nil {
+		fmt.Fprintln(os.Stderr, "auto migrate failed:", err)
+		os.Exit(1)
+	}
+
 	httpServerReady := make(chan bool)
 	httpServerDone := make(chan struct{})
 	httpServer := &server.HTTPServer{
@@ -66,6 +81,38 @@ func main() {
 	}()
 	<-grpcServerReady
 
+	purgeWorkerDone := make(chan struct{})
+	purgeWorker := &server.PurgeWorker{
+		Container: container,
+		Interval:  viper.GetDuration("app.soft_delete.purge_interval"),
+	}
+	go func() {
+		purgeWorker.Run(ctx)
+		close(purgeWorkerDone)
+	}()
+
+	outboxRelayDone := make(chan struct{})
+	outboxRelay := &server.OutboxRelayWorker{
+		Container: container,
+		Interval:  viper.GetDuration("app.outbox.relay_interval"),
+	}
+	go func() {
+		outboxRelay.Run(ctx)
+		close(outboxRelayDone)
+	}()
+
+	metricsServerDone := make(chan struct{})
+	metricsServer := &server.MetricsServer{
+		Container: container,
+	}
+	if port := viper.GetString("app.metrics.port"); port != "" {
+		metricsServer.Address = ":" + port
+	}
+	go func() {
+		metricsServer.Run(ctx)
+		close(metricsServerDone)
+	}()
+
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, os.Interrupt, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGABRT, syscall.SIGTERM)
 
@@ -74,4 +121,7 @@ func main() {
 
 	<-httpServerDone
 	<-grpcServerDone
+	<-purgeWorkerDone
+	<-outboxRelayDone
+	<-metricsServerDone
 }
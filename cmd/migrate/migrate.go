@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	db "github.com/micros-template/user-service/config/database"
+	"github.com/micros-template/user-service/config/env"
+	"github.com/micros-template/user-service/config/logger"
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+	"github.com/micros-template/user-service/internal/infrastructure/database/migration"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.uber.org/dig"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up               apply every pending migration
+  down [steps]     revert the latest applied migrations, 1 by default
+  status           list migrations and when they were applied
+  to <version>     migrate up or down to the given version, 0 reverts everything`
+
+var ErrUsage = errors.New(usage)
+
+// run the migrate subcommand against the database configured for the current ENV
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	env.Load()
+	log := logger.New()
+	pool := db.New(log)
+	defer pool.Close()
+
+	m, err := migration.New(_db.NewQuerier(pool), log)
+	if err != nil {
+		return err
+	}
+	return Execute(context.Background(), m, args, out)
+}
+
+func Execute(ctx context.Context, m migration.Migrator, args []string, out io.Writer) error {
+	switch args[0] {
+	case "up":
+		done, err := m.Up(ctx)
+		printMigrations(out, "applied", done)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return ErrUsage
+			}
+			steps = n
+		}
+		done, err := m.Down(ctx, steps)
+		printMigrations(out, "reverted", done)
+		return err
+	case "to":
+		if len(args) < 2 {
+			return ErrUsage
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return ErrUsage
+		}
+		done, err := m.To(ctx, version)
+		printMigrations(out, "migrated", done)
+		return err
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	}
+	return ErrUsage
+}
+
+// apply pending migrations before the servers start when database.auto_migrate is enabled
+func OnBoot(ctx context.Context, container *dig.Container) error {
+	if !viper.GetBool("database.auto_migrate") {
+		return nil
+	}
+	return container.Invoke(func(m migration.Migrator, logger zerolog.Logger) error {
+		done, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info().Msgf("Auto migrate applied %d migrations", len(done))
+		return nil
+	})
+}
+
+func printMigrations(out io.Writer, action string, migrations []migration.Migration) {
+	if len(migrations) == 0 {
+		fmt.Fprintln(out, "no migration to run")
+		return
+	}
+	for _, m := range migrations {
+		fmt.Fprintf(out, "%s %d_%s\n", action, m.Version, m.Name)
+	}
+}
@@ -9,12 +9,16 @@ import (
 
 	"github.com/micros-template/user-service/internal/domain/handler"
 	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/internal/infrastructure/health"
 	"github.com/micros-template/user-service/internal/infrastructure/logger"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"go.uber.org/dig"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type GRPCServer struct {
@@ -29,7 +33,10 @@ func (s *GRPCServer) Run(ctx context.Context) {
 		logger zerolog.Logger,
 		db *pgxpool.Pool,
 		svc service.AuthService,
+		userSvc service.UserService,
 		logEmitter logger.LoggerInfra,
+		healthServer *grpchealth.Server,
+		checker health.Checker,
 
 	) {
 		defer db.Close()
@@ -38,6 +45,7 @@ func (s *GRPCServer) Run(ctx context.Context) {
 			logger.Fatal().Msgf("failed to listen:%v", err)
 		}
 		handler.RegisterAuthService(grpcServer, svc)
+		handler.RegisterAccountService(grpcServer, userSvc, svc)
 
 		go func() {
 			if serveErr := grpcServer.Serve(listen); serveErr != nil {
@@ -74,6 +82,7 @@ func (s *GRPCServer) Run(ctx context.Context) {
 			}
 		}()
 		logger.Info().Msg("gRPC server running in port " + s.Address)
+		go watchReadiness(ctx, healthServer, checker, viper.GetDuration("app.health.interval"))
 		<-ctx.Done()
 
 		go func() {
@@ -82,6 +91,8 @@ func (s *GRPCServer) Run(ctx context.Context) {
 			}
 		}()
 		logger.Info().Msg("Shutting down gRPC server...")
+		// report NOT_SERVING so balancers drain this instance before connections close
+		healthServer.Shutdown()
 		grpcServer.GracefulStop()
 
 		go func() {
@@ -95,3 +106,25 @@ func (s *GRPCServer) Run(ctx context.Context) {
 		log.Fatalf("failed to initialize application: %v", err)
 	}
 }
+
+// mirrors the /readyz result into the grpc.health.v1 overall service status
+func watchReadiness(ctx context.Context, healthServer *grpchealth.Server, checker health.Checker, interval time.Duration) {
+	update := func() {
+		status := healthpb.HealthCheckResponse_SERVING
+		if checker.Ready(ctx).Status != health.StatusUp {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		healthServer.SetServingStatus("", status)
+	}
+	update()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			update()
+		}
+	}
+}
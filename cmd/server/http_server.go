@@ -18,6 +18,7 @@ import (
 	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/dig"
 )
 
@@ -33,13 +34,23 @@ func (s *HTTPServer) Run(ctx context.Context) {
 			logger zerolog.Logger,
 			router *gin.Engine,
 			uh handler.UserHandler,
+			hh handler.HealthHandler,
 			pgx *pgxpool.Pool,
 			nc *nats.Conn,
 			redis *redis.Client,
 			grpcClientManager *grpc.GRPCClientManager,
 			logEmitter logger.LoggerInfra,
+			tp *sdktrace.TracerProvider,
 
 		) {
+			defer func() {
+				// flush spans still buffered in the batcher before exit
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := tp.Shutdown(shutdownCtx); err != nil {
+					logger.Error().Err(err).Msg("Failed to shutdown tracer provider")
+				}
+			}()
 			defer grpcClientManager.CloseAllConnections()
 			defer pgx.Close()
 			defer func() {
@@ -54,6 +65,7 @@ func (s *HTTPServer) Run(ctx context.Context) {
 			}()
 
 			handler.RegisterUserRoutes(router, uh)
+			handler.RegisterHealthRoutes(router, hh)
 			srv := &http.Server{
 				Addr:              s.Address,
 				Handler:           router,
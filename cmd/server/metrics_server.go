@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/dig"
+)
+
+// serves /metrics on its own plain http listener, the port is meant to stay inside the cluster
+// and is never routed through the public https server or the gateway
+type MetricsServer struct {
+	Container *dig.Container
+	Address   string
+}
+
+func (s *MetricsServer) Run(ctx context.Context) {
+	err := s.Container.Invoke(func(
+		logger zerolog.Logger,
+		m *metrics.Metrics,
+	) {
+		if s.Address == "" {
+			logger.Warn().Msg("metrics server disabled, port is not set")
+			return
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.Handler())
+		srv := &http.Server{
+			Addr:              s.Address,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error().Err(err).Msg("Failed to listen and serve metrics server")
+			}
+		}()
+		logger.Info().Msgf("Metrics Server Starting in port %s", s.Address)
+
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("Metrics server forced to shutdown")
+		}
+		logger.Info().Msg("Metrics server stopped.")
+	})
+	if err != nil {
+		log.Fatalf("failed to initialize application: %v", err)
+	}
+}
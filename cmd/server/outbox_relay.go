@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/service"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/dig"
+)
+
+type OutboxRelayWorker struct {
+	Container *dig.Container
+	Interval  time.Duration
+}
+
+func (w *OutboxRelayWorker) Run(ctx context.Context) {
+	err := w.Container.Invoke(func(
+		logger zerolog.Logger,
+		relay service.OutboxRelay,
+	) {
+		if w.Interval <= 0 {
+			logger.Warn().Msg("outbox relay disabled, interval is not set")
+			return
+		}
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		logger.Info().Msgf("Outbox relay running every %s", w.Interval)
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info().Msg("Outbox relay stopped.")
+				return
+			case <-ticker.C:
+				// drain the backlog before waiting for the next tick
+				for {
+					sent, err := relay.RelayPending(ctx)
+					if err != nil {
+						logger.Error().Err(err).Msg("failed to relay outbox messages")
+						break
+					}
+					if sent == 0 || ctx.Err() != nil {
+						break
+					}
+				}
+			}
+		}
+	})
+	if err != nil {
+		log.Fatalf("failed to initialize application: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/internal/infrastructure/logger"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/dig"
+)
+
+type PurgeWorker struct {
+	Container *dig.Container
+	Interval  time.Duration
+}
+
+func (w *PurgeWorker) Run(ctx context.Context) {
+	err := w.Container.Invoke(func(
+		logger zerolog.Logger,
+		svc service.UserService,
+		logEmitter logger.LoggerInfra,
+	) {
+		if w.Interval <= 0 {
+			logger.Warn().Msg("purge worker disabled, interval is not set")
+			return
+		}
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		logger.Info().Msgf("Purge worker running every %s", w.Interval)
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info().Msg("Purge worker stopped.")
+				return
+			case <-ticker.C:
+				purged, err := svc.PurgeDeletedUsers(ctx)
+				if err != nil {
+					logger.Error().Err(err).Msg("failed to purge deleted users")
+					continue
+				}
+				if purged > 0 {
+					go func() {
+						if err := logEmitter.EmitLog("INFO", fmt.Sprintf("purged %d deleted users", purged)); err != nil {
+							logger.Error().Err(err).Msg("failed to emit log")
+						}
+					}()
+				}
+			}
+		}
+	})
+	if err != nil {
+		log.Fatalf("failed to initialize application: %v", err)
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 )
 
-func New(zerolog zerolog.Logger) (*redis.Client, error) {
+func New(zerolog zerolog.Logger, m *metrics.Metrics) (*redis.Client, error) {
 	addr := fmt.Sprintf("%s:%s", viper.GetString("redis.address"), viper.GetString("redis.port"))
 	client := redis.NewClient(&redis.Options{
 		Addr:       addr,
@@ -17,6 +20,10 @@ func New(zerolog zerolog.Logger) (*redis.Client, error) {
 		Protocol:   2,
 		Password:   viper.GetString("redis.password"),
 	})
+	client.AddHook(m.RedisHook())
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		zerolog.Fatal().Err(err).Msg("failed to instrument redis tracing")
+	}
 
 	ctx := context.Background()
 	if err := client.Ping(ctx).Err(); err != nil {
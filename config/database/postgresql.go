@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/exaring/otelpgx"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
@@ -20,7 +21,13 @@ func New(logger zerolog.Logger) *pgxpool.Pool {
 	if dsn == "" {
 		logger.Fatal().Msg("Database configuration is not set")
 	}
-	pool, err := pgxpool.New(context.Background(), dsn)
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Database configuration is invalid")
+	}
+	// every query through the pool becomes a span under the caller's context
+	config.ConnConfig.Tracer = otelpgx.NewTracer()
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Database configuration is not set")
 	}
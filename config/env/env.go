@@ -22,4 +22,8 @@ func Load() {
 	if err := viper.ReadInConfig(); err != nil {
 		panic("failed to read config")
 	}
+	// secrets are not committed with the config, the environment wins over the file
+	if err := viper.BindEnv("app.two_factor.encryption_key", "TWO_FACTOR_ENCRYPTION_KEY"); err != nil {
+		panic("failed to bind env")
+	}
 }
@@ -13,5 +13,6 @@ func NewJetstream(nc *nats.Conn) jetstream.JetStream {
 		panic("failed to init jetstream")
 	}
 	_mq.NewNotificationStream(js)
+	_mq.NewEventBusStream(js)
 	return js
 }
@@ -5,10 +5,17 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
 	"github.com/micros-template/log-service/pkg"
 	ld "github.com/micros-template/log-service/pkg/dto"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func loggingUnaryInterceptor(logEmitter pkg.LogEmitter, logger zerolog.Logger) grpc.UnaryServerInterceptor {
@@ -63,9 +70,29 @@ func loggingUnaryInterceptor(logEmitter pkg.LogEmitter, logger zerolog.Logger) g
 	}
 }
 
-func NewGRPC(logEmitter pkg.LogEmitter, logger zerolog.Logger) *grpc.Server {
+// a deadline set by the caller wins when it is shorter than the configured one
+func timeoutUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, cancel := timeout.WithConfig(ctx, constant.TIMEOUT_GRPC)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+func NewGRPC(logEmitter pkg.LogEmitter, logger zerolog.Logger, m *metrics.Metrics, hs *grpchealth.Server) *grpc.Server {
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(loggingUnaryInterceptor(logEmitter, logger)),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			loggingUnaryInterceptor(logEmitter, logger),
+			m.UnaryServerInterceptor(),
+			timeoutUnaryInterceptor(),
+		),
 	)
+	healthpb.RegisterHealthServer(grpcServer, hs)
 	return grpcServer
 }
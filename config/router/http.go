@@ -5,21 +5,29 @@ import (
 	"strings"
 	"time"
 
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/micros-template/log-service/pkg"
 	"github.com/micros-template/sharedlib/middleware"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func NewHTTP(logEmitter pkg.LogEmitter, zerolog zerolog.Logger) *gin.Engine {
+func NewHTTP(logEmitter pkg.LogEmitter, zerolog zerolog.Logger, m *metrics.Metrics) *gin.Engine {
 	env := os.Getenv("ENV")
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	r := gin.New()
+	r.Use(otelgin.Middleware("user_service"))
 	r.Use(middleware.AccessLogger(logEmitter, "user_service", zerolog))
+	r.Use(m.HTTPMiddleware())
+	r.Use(requestTimeout())
 
 	allowOrigins := viper.GetString("server.cors.allow_origins")
 	allowMethods := viper.GetString("server.cors.allow_methods")
@@ -35,6 +43,16 @@ func NewHTTP(logEmitter pkg.LogEmitter, zerolog zerolog.Logger) *gin.Engine {
 		AllowCredentials: allowCredentials,
 		MaxAge:           time.Duration(maxAge),
 	}))
-
+	// /metrics is served by server.MetricsServer on the internal app.metrics.port
 	return r
 }
+
+// bound the request context so downstream db, cache and file service calls stop once the budget is spent
+func requestTimeout() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c, cancel := timeout.WithConfig(ctx.Request.Context(), constant.TIMEOUT_HTTP)
+		defer cancel()
+		ctx.Request = ctx.Request.WithContext(c)
+		ctx.Next()
+	}
+}
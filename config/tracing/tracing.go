@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const serviceName = "user_service"
+
+// the provider is registered globally so the gin, grpc, pgx, redis and nats instrumentation pick it up.
+// with the "none" exporter spans are still created for context propagation but never exported
+func New(logger zerolog.Logger) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version()),
+		semconv.DeploymentEnvironment(os.Getenv("ENV")),
+	)
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(viper.GetFloat64("tracing.sample_ratio")))),
+	}
+
+	exporter := viper.GetString("tracing.exporter")
+	switch exporter {
+	case "otlp":
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(viper.GetString("tracing.endpoint"))}
+		if viper.GetBool("tracing.insecure") {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exp, err := otlptracegrpc.New(context.Background(), clientOpts...)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("failed to create otlp trace exporter")
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	case "stdout":
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			logger.Fatal().Err(err).Msg("failed to create stdout trace exporter")
+		}
+		opts = append(opts, sdktrace.WithSyncer(exp))
+	case "none", "":
+	default:
+		logger.Fatal().Str("exporter", exporter).Msg("unknown tracing exporter, expected otlp, stdout or none")
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp
+}
+
+func version() string {
+	v, err := os.ReadFile("VERSION")
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(v))
+}
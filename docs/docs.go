@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/": {
             "delete": {
-                "description": "Delete User based on its ID (from token)",
+                "description": "Request deletion of User based on its ID (from token), a confirmation link is sent by email",
                 "consumes": [
                     "application/json"
                 ],
@@ -45,6 +45,13 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag from GET /me",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "description": "Body Request",
                         "name": "request",
@@ -57,9 +64,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Delete User Success",
+                        "description": "Delete User Requested - need confirmation from email",
                         "schema": {
-                            "$ref": "#/definitions/dto.DeleteUserSuccessExample"
+                            "$ref": "#/definitions/dto.RequestDeleteUserSuccessExample"
                         }
                     },
                     "400": {
@@ -80,6 +87,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
                         }
                     },
+                    "412": {
+                        "description": "Precondition failed - user was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - missing If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -89,9 +108,10 @@ const docTemplate = `{
                 }
             },
             "patch": {
-                "description": "Update User based on its ID (from token)",
+                "description": "Partially update User based on its ID (from token). Absent fields are left unchanged, a JSON merge patch (RFC 7396) can remove the avatar with \"image\": null and the optional profile fields with null, a multipart form removes them with an empty value",
                 "consumes": [
-                    "multipart/form-data"
+                    "multipart/form-data",
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
@@ -108,6 +128,13 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "ETag from GET /me",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "file",
                         "description": "Profile image",
@@ -115,32 +142,61 @@ const docTemplate = `{
                         "in": "formData"
                     },
                     {
-                        "maxLength": 100,
-                        "minLength": 1,
+                        "type": "string",
+                        "example": "backend engineer",
+                        "name": "bio",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "1990-01-31",
+                        "name": "date_of_birth",
+                        "in": "formData"
+                    },
+                    {
                         "type": "string",
                         "example": "john doe",
                         "name": "full_name",
-                        "in": "formData",
-                        "required": true
+                        "in": "formData"
                     },
                     {
-                        "type": "boolean",
-                        "example": true,
-                        "name": "two_factor_enabled",
+                        "type": "string",
+                        "example": "en-US",
+                        "name": "locale",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "only removal, numbers are added through PATCH /phone",
+                        "name": "phone_number",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Asia/Jakarta",
+                        "name": "timezone",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "example": "john.doe",
+                        "description": "an empty value removes the field",
+                        "name": "username",
                         "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Update user Success",
+                        "description": "Update user Success, returns the updated profile",
                         "schema": {
                             "$ref": "#/definitions/dto.UpdateUserSuccessExample"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid input, wrong image extension, and limit image exceeded",
+                        "description": "Bad request - invalid fields, wrong image extension, and limit image exceeded",
                         "schema": {
-                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                            "$ref": "#/definitions/dto.InvalidFieldsResponse"
                         }
                     },
                     "401": {
@@ -155,6 +211,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
                         }
                     },
+                    "409": {
+                        "description": "Conflict - username is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - user was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported content type",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - missing If-Match",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -164,9 +244,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/email": {
-            "patch": {
-                "description": "Change Email based on its ID (from token)",
+        "/2fa/recovery-codes": {
+            "get": {
+                "description": "Get the number of unused two-factor recovery codes of User based on its ID (from token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Count recovery codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Count Recovery Codes Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CountRecoveryCodesSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Replace the two-factor recovery codes of User based on its ID (from token), authorized with a current code or the password. The previous codes stop working",
                 "consumes": [
                     "application/json"
                 ],
@@ -176,7 +295,7 @@ const docTemplate = `{
                 "tags": [
                     "User-Service"
                 ],
-                "summary": "Change Email",
+                "summary": "Regenerate recovery codes",
                 "parameters": [
                     {
                         "type": "string",
@@ -191,15 +310,15 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateEmailRequest"
+                            "$ref": "#/definitions/dto.RegenerateRecoveryCodesRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Change Email Success - need verification in auth API",
+                        "description": "Regenerate Recovery Codes Success",
                         "schema": {
-                            "$ref": "#/definitions/dto.ChangeEmailSuccessExample"
+                            "$ref": "#/definitions/dto.RegenerateRecoveryCodesSuccessExample"
                         }
                     },
                     "400": {
@@ -209,11 +328,29 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Unauthorized - code invalid or wrong password",
                         "schema": {
                             "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
                         }
                     },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalTooManyRequestsExample"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -223,11 +360,11 @@ const docTemplate = `{
                 }
             }
         },
-        "/me": {
-            "get": {
-                "description": "Get profile User based on its ID (from token)",
+        "/2fa/totp/disable": {
+            "post": {
+                "description": "Disable two-factor authentication for User based on its ID (from token) with a current code or the password",
                 "consumes": [
-                    "*/*"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
@@ -235,7 +372,7 @@ const docTemplate = `{
                 "tags": [
                     "User-Service"
                 ],
-                "summary": "Get User Profile",
+                "summary": "Disable TOTP",
                 "parameters": [
                     {
                         "type": "string",
@@ -243,17 +380,32 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DisableTOTPRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Get Profile Success",
+                        "description": "Disable TOTP Success",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetProfileSuccessExample"
+                            "$ref": "#/definitions/dto.DisableTOTPSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Unauthorized - code invalid or wrong password",
                         "schema": {
                             "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
                         }
@@ -264,6 +416,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
                         }
                     },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalTooManyRequestsExample"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -273,9 +437,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/password": {
-            "patch": {
-                "description": "Change Password based on its ID (from token)",
+        "/2fa/totp/enable": {
+            "post": {
+                "description": "Enable two-factor authentication for User based on its ID (from token) with a code from the secret returned by the setup. The recovery codes in the response are only shown once",
                 "consumes": [
                     "application/json"
                 ],
@@ -285,7 +449,7 @@ const docTemplate = `{
                 "tags": [
                     "User-Service"
                 ],
-                "summary": "Change Password",
+                "summary": "Enable TOTP",
                 "parameters": [
                     {
                         "type": "string",
@@ -300,35 +464,47 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePasswordRequest"
+                            "$ref": "#/definitions/dto.EnableTOTPRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Change Password Success",
+                        "description": "Enable TOTP Success",
                         "schema": {
-                            "$ref": "#/definitions/dto.ChangePasswordSuccessExample"
+                            "$ref": "#/definitions/dto.EnableTOTPSuccessExample"
                         }
                     },
                     "400": {
-                        "description": "Bad request - invalid input, password and confirm_password doesn't match",
+                        "description": "Bad request - invalid input",
                         "schema": {
                             "$ref": "#/definitions/dto.GlobalInvalidInputExample"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized - token invalid, wrong password",
+                        "description": "Unauthorized - code invalid",
                         "schema": {
                             "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "User not found or no pending enrollment",
                         "schema": {
                             "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
                         }
                     },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalTooManyRequestsExample"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -337,106 +513,1976 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.ChangeEmailSuccessExample": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "string",
-                    "example": "null"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "verify to change email"
-                },
-                "status_code": {
-                    "type": "integer",
-                    "example": 200
-                }
-            }
-        },
-        "dto.ChangePasswordSuccessExample": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "string",
-                    "example": "null"
-                },
-                "message": {
-                    "type": "string",
-                    "example": "success delete user"
-                },
-                "status_code": {
-                    "type": "integer",
-                    "example": 200
-                }
-            }
         },
-        "dto.DeleteUserRequest": {
-            "type": "object",
-            "required": [
-                "password"
-            ],
-            "properties": {
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+        "/2fa/totp/setup": {
+            "post": {
+                "description": "Generate a new TOTP (RFC 6238) secret for User based on its ID (from token) and return it as an otpauth URI and a base64 PNG QR code. Two-factor authentication is only enabled once a code from it is confirmed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Setup TOTP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Setup TOTP Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetupTOTPSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
                 }
             }
         },
-        "dto.DeleteUserSuccessExample": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "string",
-                    "example": "null"
+        "/admin/users": {
+            "get": {
+                "description": "List Users with cursor pagination, filters and sorting",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "List Users (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01-01T00:00:00Z",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-12-31T23:59:59Z",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "example": "john",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 20,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "example": "doe",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "full_name",
+                            "email"
+                        ],
+                        "type": "string",
+                        "example": "created_at",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "desc",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "two_factor_enabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "verified",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List Users Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListUsersSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input, invalid cursor",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - admin only",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalForbiddenErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/restore": {
+            "post": {
+                "description": "Restore a soft deleted User based on the ID in path while the restore window is open",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Restore User (Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restore User Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RestoreUserSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - admin only",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalForbiddenErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found or restore window expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/avatar": {
+            "get": {
+                "description": "Redirect to the avatar of the User based on its ID (from token) as served by the file service, or to the generated default avatar",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Get Avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "64",
+                            "256",
+                            "512"
+                        ],
+                        "type": "string",
+                        "example": "256",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the image"
+                    },
+                    "400": {
+                        "description": "Bad request - invalid size",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the avatar of the User based on its ID (from token), the image is stored in 64, 256 and 512 px square variants",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Update Avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Profile image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Update avatar Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateAvatarSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing image, wrong extension, limit exceeded, unsupported or corrupt image",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the avatar of the User based on its ID (from token), the stored files are deleted from the file service",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Delete Avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete avatar Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteAvatarSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/avatar/default/{id}": {
+            "get": {
+                "description": "Get the generated avatar of a User, an identicon derived from the user ID only",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Get Default Avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated SVG avatar",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/delete/confirm": {
+            "post": {
+                "description": "Delete User based on its ID (from token) using the token sent by email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Confirm Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ConfirmDeleteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete User Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteUserSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - token invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/email": {
+            "patch": {
+                "description": "Change Email based on its ID (from token)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Change Email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Change Email Success - need verification in auth API",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangeEmailSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/verify": {
+            "post": {
+                "description": "Apply the pending email change of User based on its ID (from token) using the token sent by email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Verify Email Change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyEmailChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verify Email Change Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyEmailChangeSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - token invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already used, or the user kept changing while the email was applied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Reports that the process is running, dependencies are not checked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Process is alive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "description": "Get profile User based on its ID (from token)",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Get User Profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Get Profile Success, the ETag header is required as If-Match by PATCH / and DELETE /",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetProfileSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys": {
+            "get": {
+                "description": "Get the passkeys of User based on its ID (from token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "List passkeys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List Passkeys Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ListPasskeysSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys/register/begin": {
+            "post": {
+                "description": "Start registering a passkey for User based on its ID (from token). The data is passed to navigator.credentials.create",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Begin passkey registration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Begin Passkey Registration Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BeginPasskeyRegistrationSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys/register/finish": {
+            "post": {
+                "description": "Store the passkey created by the authenticator for User based on its ID (from token)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Finish passkey registration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FinishPasskeyRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Finish Passkey Registration Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasskeySuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input or authenticator response",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "No pending registration",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - passkey is already registered",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/passkeys/{id}": {
+            "delete": {
+                "description": "Delete a passkey of User based on its ID (from token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Delete passkey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Passkey ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delete Passkey Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeletePasskeySuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "Passkey not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Rename a passkey of User based on its ID (from token)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Rename passkey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Passkey ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenamePasskeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rename Passkey Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenamePasskeySuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "Passkey not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/password": {
+            "patch": {
+                "description": "Change Password based on its ID (from token)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Change Password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Change Password Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input, password and confirm_password doesn't match, or the new password breaks the policy",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PasswordPolicyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - token invalid, wrong password",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone": {
+            "patch": {
+                "description": "Send a 6 digit verification code by sms to the new phone number of User based on its ID (from token), the number is stored once it is verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Change Phone Number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePhoneNumberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verification code sent",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePhoneNumberSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid phone number",
+                        "schema": {
+                            "$ref": "#/definitions/dto.InvalidFieldsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many codes requested",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalTooManyRequestsExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/phone/verify": {
+            "post": {
+                "description": "Store the pending phone number of User based on its ID (from token) as verified using the code sent by sms",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Verify Phone Number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Body Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyPhoneNumberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verify Phone Number Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyPhoneNumberSuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInvalidInputExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - code invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - the profile kept changing, the code stays valid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalConflictErrorExample"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes, a new code has to be requested",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalTooManyRequestsExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks postgres, redis, nats and the file service channel and reports each dependency with its latency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "All dependencies are up",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "At least one dependency is down",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/restore": {
+            "post": {
+                "description": "Restore a soft deleted User based on its ID (from token) while the restore window is open",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Restore User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restore User Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RestoreUserSuccessExample"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found or restore window expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUserNotFoundExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        },
+        "/username/availability": {
+            "get": {
+                "description": "Check if a username can be set by the User based on its ID (from token), the username the User already has counts as available",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User-Service"
+                ],
+                "summary": "Check Username Availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username to check",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Check username Success",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UsernameAvailabilitySuccessExample"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - missing or invalid username",
+                        "schema": {
+                            "$ref": "#/definitions/dto.InvalidFieldsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalUnauthorizedErrorExample"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GlobalInternalServerErrorExample"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.BeginPasskeyRegistrationSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "PublicKeyCredentialCreationOptions for navigator.credentials.create",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "message": {
+                    "type": "string",
+                    "example": "complete the registration with the authenticator"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.ChangeEmailSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "verify to change email"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.ChangePasswordSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success delete user"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.ConfirmDeleteUserRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CountRecoveryCodesSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.RecoveryCodesCountResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success count recovery codes"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.DeleteAvatarSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success delete avatar"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.DeletePasskeySuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success delete passkey"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.DeleteUserRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "dto.DeleteUserSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success delete user"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.DisableTOTPRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "password": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "dto.DisableTOTPSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success disable two-factor authentication"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.EnableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "dto.EnableTOTPSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.RecoveryCodesResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success enable two-factor authentication"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.FinishPasskeyRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "credential",
+                "name"
+            ],
+            "properties": {
+                "credential": {
+                    "type": "object"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "MacBook Touch ID"
+                }
+            }
+        },
+        "dto.GetProfileResponse": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string",
+                    "example": "backend engineer"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "example": "1990-01-31"
+                },
+                "default_image": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "full_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
+                },
+                "image_variants": {
+                    "$ref": "#/definitions/dto.ImageVariants"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "en-US"
+                },
+                "phone_number": {
+                    "type": "string",
+                    "example": "+6281234567890"
+                },
+                "phone_verified": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Jakarta"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                },
+                "verified": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.GetProfileSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.GetProfileResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success get profile data"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.GlobalConflictErrorExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "email is already used"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 409
+                }
+            }
+        },
+        "dto.GlobalForbiddenErrorExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "admin access required"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 403
+                }
+            }
+        },
+        "dto.GlobalInternalServerErrorExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "internal server error"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 500
+                }
+            }
+        },
+        "dto.GlobalInvalidInputExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "invalid input"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "dto.GlobalTooManyRequestsExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "too many verification codes requested, try again later"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 429
+                }
+            }
+        },
+        "dto.GlobalUnauthorizedErrorExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "unauthorized"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 401
+                }
+            }
+        },
+        "dto.GlobalUserNotFoundExample": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "user not found"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 404
+                }
+            }
+        },
+        "dto.ImageVariants": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "dto.InvalidFieldsResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "full_name": "must not be empty"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid input"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 400
+                }
+            }
+        },
+        "dto.ListPasskeysSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PasskeyResponse"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success list passkeys"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.ListUsersResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoiY3JlYXRlZF9hdCJ9"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserListItem"
+                    }
+                }
+            }
+        },
+        "dto.ListUsersSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.ListUsersResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success list users"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.PasskeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2025-01-02T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "MacBook Touch ID"
+                },
+                "synced": {
+                    "description": "synced passkeys survive the loss of a device",
+                    "type": "boolean",
+                    "example": true
+                },
+                "transports": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "internal",
+                        "hybrid"
+                    ]
+                }
+            }
+        },
+        "dto.PasskeySuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.PasskeyResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success add passkey"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.PasswordPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/password.Violation"
+                    }
                 },
                 "message": {
                     "type": "string",
-                    "example": "success delete user"
+                    "example": "password does not meet the policy"
                 },
                 "status_code": {
                     "type": "integer",
-                    "example": 200
+                    "example": 400
                 }
             }
         },
-        "dto.GetProfileResponse": {
+        "dto.RecoveryCodesCountResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "remaining": {
+                    "type": "integer",
+                    "example": 8
+                }
+            }
+        },
+        "dto.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k7m2p-x9q4r",
+                        "b3n8t-w6z2h"
+                    ]
+                }
+            }
+        },
+        "dto.RegenerateRecoveryCodesRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string",
-                    "example": "john.doe@example.com"
+                    "example": "123456"
                 },
-                "full_name": {
+                "password": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": ""
+                }
+            }
+        },
+        "dto.RegenerateRecoveryCodesSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.RecoveryCodesResponse"
                 },
-                "image": {
+                "message": {
                     "type": "string",
-                    "example": "https://example.com/image.jpg"
-                },
-                "two_factor_enabled": {
-                    "type": "boolean",
-                    "example": false
+                    "example": "store the recovery codes, they are only shown once"
                 },
-                "verified": {
-                    "type": "boolean",
-                    "example": true
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
                 }
             }
         },
-        "dto.GetProfileSuccessExample": {
+        "dto.RenamePasskeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "YubiKey 5"
+                }
+            }
+        },
+        "dto.RenamePasskeySuccessExample": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.GetProfileResponse"
+                    "type": "string",
+                    "example": "null"
                 },
                 "message": {
                     "type": "string",
-                    "example": "success get profile data"
+                    "example": "success rename passkey"
                 },
                 "status_code": {
                     "type": "integer",
@@ -444,55 +2490,89 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.GlobalInternalServerErrorExample": {
+        "dto.RequestDeleteUserSuccessExample": {
             "type": "object",
             "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
                 "message": {
                     "type": "string",
-                    "example": "internal server error"
+                    "example": "verify to delete account"
                 },
                 "status_code": {
                     "type": "integer",
-                    "example": 500
+                    "example": 200
                 }
             }
         },
-        "dto.GlobalInvalidInputExample": {
+        "dto.RestoreUserSuccessExample": {
             "type": "object",
             "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
                 "message": {
                     "type": "string",
-                    "example": "invalid input"
+                    "example": "success restore user"
                 },
                 "status_code": {
                     "type": "integer",
-                    "example": 400
+                    "example": 200
                 }
             }
         },
-        "dto.GlobalUnauthorizedErrorExample": {
+        "dto.SetupTOTPResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/dropboks:john.doe@example.com?algorithm=SHA1\u0026digits=6\u0026issuer=dropboks\u0026period=30\u0026secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                },
+                "qr_code": {
+                    "description": "base64 encoded png of the otpauth uri",
+                    "type": "string",
+                    "example": "iVBORw0KGgoAAAANSUhEUgAA..."
+                },
+                "secret": {
+                    "description": "for apps that cannot scan the qr code",
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "dto.SetupTOTPSuccessExample": {
             "type": "object",
             "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.SetupTOTPResponse"
+                },
                 "message": {
                     "type": "string",
-                    "example": "unauthorized"
+                    "example": "scan the qr code and confirm with a code"
                 },
                 "status_code": {
                     "type": "integer",
-                    "example": 401
+                    "example": 200
                 }
             }
         },
-        "dto.GlobalUserNotFoundExample": {
+        "dto.UpdateAvatarSuccessExample": {
             "type": "object",
             "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
                 "message": {
                     "type": "string",
-                    "example": "user not found"
+                    "example": "success update avatar"
                 },
                 "status_code": {
                     "type": "integer",
-                    "example": 404
+                    "example": 200
                 }
             }
         },
@@ -516,12 +2596,11 @@ const docTemplate = `{
             ],
             "properties": {
                 "confirm_new_password": {
-                    "type": "string",
-                    "minLength": 6
+                    "type": "string"
                 },
                 "new_password": {
-                    "type": "string",
-                    "minLength": 6
+                    "description": "length and the other rules come from the password policy",
+                    "type": "string"
                 },
                 "password": {
                     "type": "string",
@@ -529,13 +2608,41 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.UpdateUserSuccessExample": {
+        "dto.UpdatePhoneNumberRequest": {
+            "type": "object",
+            "required": [
+                "phone_number"
+            ],
+            "properties": {
+                "phone_number": {
+                    "type": "string",
+                    "example": "+6281234567890"
+                }
+            }
+        },
+        "dto.UpdatePhoneNumberSuccessExample": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "string",
                     "example": "null"
                 },
+                "message": {
+                    "type": "string",
+                    "example": "verification code sent"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.UpdateUserSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.GetProfileResponse"
+                },
                 "message": {
                     "type": "string",
                     "example": "success update profile data"
@@ -545,6 +2652,193 @@ const docTemplate = `{
                     "example": 200
                 }
             }
+        },
+        "dto.UserListItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "full_name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2b1c6c1e-3f7a-4a8e-9d3c-1f2e3d4c5b6a"
+                },
+                "image": {
+                    "type": "string",
+                    "example": "https://example.com/image.jpg"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "verified": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dto.UsernameAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "dto.UsernameAvailabilitySuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.UsernameAvailabilityResponse"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success check username"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.VerifyEmailChangeRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VerifyEmailChangeSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success change email"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "dto.VerifyPhoneNumberRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "dto.VerifyPhoneNumberSuccessExample": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string",
+                    "example": "null"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success verify phone number"
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "health.Dependency": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.Dependency"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "password.Rule": {
+            "type": "string",
+            "enum": [
+                "min_length",
+                "uppercase",
+                "lowercase",
+                "digit",
+                "symbol",
+                "common",
+                "personal_info",
+                "reuse"
+            ],
+            "x-enum-varnames": [
+                "RuleMinLength",
+                "RuleUppercase",
+                "RuleLowercase",
+                "RuleDigit",
+                "RuleSymbol",
+                "RuleCommon",
+                "RulePersonalInfo",
+                "RuleReuse"
+            ]
+        },
+        "password.Violation": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "must be at least 8 characters"
+                },
+                "rule": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/password.Rule"
+                        }
+                    ],
+                    "example": "min_length"
+                }
+            }
         }
     },
     "securityDefinitions": {
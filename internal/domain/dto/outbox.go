@@ -0,0 +1,10 @@
+package dto
+
+type OutboxMessage struct {
+	ID       int64
+	Subject  string
+	Payload  []byte
+	Attempts int
+	// only read back when the message is dead-lettered
+	LastError *string
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/micros-template/user-service/pkg/password"
+
+// every rule a new password broke, returned whole so clients can show them at once
+type PasswordPolicyError struct {
+	Violations []password.Violation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet the policy"
+}
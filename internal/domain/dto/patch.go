@@ -0,0 +1,233 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	maxFullNameLength = 100
+	maxBioLength      = 500
+	// layout of date_of_birth in requests and responses
+	DateLayout = "2006-01-02"
+)
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9._]{1,28})[A-Za-z0-9]$`)
+	// E.164, the separators people type are stripped before matching
+	phonePattern    = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+	// would read as an official account or collide with a route
+	reservedUsernames = map[string]struct{}{
+		"admin": {}, "administrator": {}, "root": {}, "system": {}, "support": {},
+		"help": {}, "me": {}, "null": {}, "undefined": {}, "user": {}, "users": {},
+	}
+	minDateOfBirth = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// a flag alone would enable 2FA without an enrolled secret
+	TwoFactorEnabledReadOnly = "cannot be changed here, use the /2fa/totp endpoints"
+)
+
+// field name to the reason it was rejected
+type FieldErrors map[string]string
+
+// a member of a patch that can be removed. Set is false when the member is absent,
+// Value is nil when it is null or empty
+type PatchString struct {
+	Set   bool
+	Value *string
+}
+
+// multipart forms have no null, an empty value removes the field instead
+func (p *PatchString) UnmarshalParam(param string) error {
+	p.Set = true
+	p.Value = nil
+	if param != "" {
+		p.Value = &param
+	}
+	return nil
+}
+
+// RFC 7396: an absent member is left unchanged and null removes the member.
+// full_name cannot be removed, a null image removes the avatar
+func DecodeUserMergePatch(body []byte) (*UpdateUserRequest, FieldErrors) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return nil, FieldErrors{"body": "must be a JSON object"}
+	}
+	req := &UpdateUserRequest{}
+	errs := FieldErrors{}
+	for name, raw := range members {
+		isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		switch name {
+		case "full_name":
+			if isNull {
+				errs[name] = "cannot be removed"
+				continue
+			}
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				errs[name] = "must be a string"
+				continue
+			}
+			req.FullName = &v
+		case "two_factor_enabled":
+			errs[name] = TwoFactorEnabledReadOnly
+		case "image":
+			if !isNull {
+				errs[name] = "only null is accepted, upload images with multipart/form-data"
+				continue
+			}
+			req.RemoveImage = true
+		case "username", "bio", "locale", "timezone", "phone_number", "date_of_birth":
+			field := req.patchString(name)
+			field.Set = true
+			if isNull {
+				continue
+			}
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				errs[name] = "must be a string or null"
+				continue
+			}
+			if v != "" {
+				field.Value = &v
+			}
+		default:
+			errs[name] = "unknown field"
+		}
+	}
+	for name, reason := range req.Validate() {
+		errs[name] = reason
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return req, nil
+}
+
+func (r *UpdateUserRequest) patchString(name string) *PatchString {
+	switch name {
+	case "username":
+		return &r.Username
+	case "bio":
+		return &r.Bio
+	case "locale":
+		return &r.Locale
+	case "timezone":
+		return &r.Timezone
+	case "phone_number":
+		return &r.PhoneNumber
+	case "date_of_birth":
+		return &r.DateOfBirth
+	}
+	return nil
+}
+
+// checks only the fields that are present, accepted values are rewritten in their canonical form
+func (r *UpdateUserRequest) Validate() FieldErrors {
+	errs := FieldErrors{}
+	if r.FullName != nil {
+		name := strings.TrimSpace(*r.FullName)
+		if name == "" {
+			errs["full_name"] = "must not be empty"
+		} else if utf8.RuneCountInString(name) > maxFullNameLength {
+			errs["full_name"] = "must be at most 100 characters"
+		}
+	}
+	normalize := func(name string, field *PatchString, fn func(string) (string, string)) {
+		if field.Value == nil {
+			return
+		}
+		value, reason := fn(*field.Value)
+		if reason != "" {
+			errs[name] = reason
+			return
+		}
+		// a bio of only whitespace removes it like an empty one
+		if value == "" {
+			field.Value = nil
+			return
+		}
+		field.Value = &value
+	}
+	normalize("username", &r.Username, func(v string) (string, string) {
+		return v, ValidateUsername(v)
+	})
+	normalize("bio", &r.Bio, normalizeBio)
+	normalize("locale", &r.Locale, normalizeLocale)
+	normalize("timezone", &r.Timezone, normalizeTimezone)
+	normalize("date_of_birth", &r.DateOfBirth, normalizeDateOfBirth)
+	// a number has to be verified before it is stored
+	if r.PhoneNumber.Value != nil {
+		errs["phone_number"] = "only removal is accepted, add or change the number with PATCH /phone"
+	}
+	return errs
+}
+
+// returns the reason the username is rejected, empty when it is valid
+func ValidateUsername(username string) string {
+	if !usernamePattern.MatchString(username) || strings.Contains(username, "..") {
+		return "must be 3-30 letters, digits, dots or underscores, starting and ending with a letter or digit"
+	}
+	if _, ok := reservedUsernames[strings.ToLower(username)]; ok {
+		return "is reserved"
+	}
+	return ""
+}
+
+func normalizeBio(v string) (string, string) {
+	v = strings.TrimSpace(v)
+	if utf8.RuneCountInString(v) > maxBioLength {
+		return "", "must be at most 500 characters"
+	}
+	if strings.IndexFunc(v, func(r rune) bool { return unicode.IsControl(r) && r != '\n' }) >= 0 {
+		return "", "must not contain control characters"
+	}
+	return v, ""
+}
+
+// BCP 47, stored canonicalized so en_us and en-US compare equal
+func normalizeLocale(v string) (string, string) {
+	tag, err := language.Parse(v)
+	if err != nil {
+		return "", "must be a BCP 47 language tag"
+	}
+	return tag.String(), ""
+}
+
+// IANA name, Local and UTC offsets are rejected because they do not follow daylight saving changes
+func normalizeTimezone(v string) (string, string) {
+	if v == "Local" || (!strings.Contains(v, "/") && v != "UTC") {
+		return "", "must be an IANA time zone name"
+	}
+	if _, err := time.LoadLocation(v); err != nil {
+		return "", "must be an IANA time zone name"
+	}
+	return v, ""
+}
+
+func NormalizePhoneNumber(v string) (string, string) {
+	v = phoneSeparators.Replace(v)
+	if !phonePattern.MatchString(v) {
+		return "", "must be an E.164 number, e.g. +6281234567890"
+	}
+	return v, ""
+}
+
+func normalizeDateOfBirth(v string) (string, string) {
+	date, err := time.Parse(DateLayout, v)
+	if err != nil {
+		return "", "must be a date formatted as YYYY-MM-DD"
+	}
+	if date.Before(minDateOfBirth) || !date.Before(time.Now().UTC()) {
+		return "", "must be between 1900-01-01 and today"
+	}
+	return v, ""
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/micros-template/sharedlib/model"
+)
+
+type (
+	// square size in pixels to the file service image name
+	ImageVariants map[string]string
+	// optional profile columns, nil is stored as NULL
+	ProfileFields struct {
+		Username    *string
+		Bio         *string
+		Locale      *string
+		Timezone    *string
+		PhoneNumber *string
+		// only set by the sms verification flow, cleared whenever the number changes
+		PhoneVerified bool
+		DateOfBirth   *time.Time
+	}
+	// user row together with the columns the shared model does not carry
+	UserProfile struct {
+		model.User
+		ImageVariants ImageVariants
+		ProfileFields
+		// bumped on every update, exposed as the ETag
+		Version int64
+	}
+)
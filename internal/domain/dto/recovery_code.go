@@ -0,0 +1,7 @@
+package dto
+
+// an unused recovery code, the code itself is only known to the user
+type RecoveryCode struct {
+	ID       int64
+	CodeHash string
+}
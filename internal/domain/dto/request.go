@@ -1,26 +1,120 @@
 package dto
 
-import "mime/multipart"
+import (
+	"encoding/json"
+	"mime/multipart"
+	"time"
+)
 
 type (
 	UserData struct {
 		UserId string `json:"user_id"`
+		Role   string `json:"role"`
 	}
 
+	// nil fields are left unchanged, see patch.go for the validation
 	UpdateUserRequest struct {
-		FullName         string                `form:"full_name" binding:"required,min=1,max=100" example:"john doe"`
-		Image            *multipart.FileHeader `form:"image" swaggerignore:"true"`
-		TwoFactorEnabled bool                  `form:"two_factor_enabled" example:"true"`
+		FullName *string               `form:"full_name" json:"full_name" example:"john doe"`
+		Image    *multipart.FileHeader `form:"image" json:"-" swaggerignore:"true"`
+		// an empty value removes the field
+		Username PatchString `form:"username" json:"username" swaggertype:"string" example:"john.doe"`
+		Bio      PatchString `form:"bio" json:"bio" swaggertype:"string" example:"backend engineer"`
+		Locale   PatchString `form:"locale" json:"locale" swaggertype:"string" example:"en-US"`
+		Timezone PatchString `form:"timezone" json:"timezone" swaggertype:"string" example:"Asia/Jakarta"`
+		// only removal, numbers are added through PATCH /phone
+		PhoneNumber PatchString `form:"phone_number" json:"phone_number" swaggertype:"string" example:""`
+		DateOfBirth PatchString `form:"date_of_birth" json:"date_of_birth" swaggertype:"string" example:"1990-01-31"`
+		// set by an explicit "image": null in a merge patch
+		RemoveImage bool `form:"-" json:"-" swaggerignore:"true"`
+		// from If-Match, any of them matches. empty accepts any version
+		ExpectedVersions []int64 `form:"-" json:"-" swaggerignore:"true"`
+	}
+	UpdateAvatarRequest struct {
+		Image *multipart.FileHeader `form:"image" binding:"required" swaggerignore:"true"`
+	}
+	GetAvatarRequest struct {
+		Size string `form:"size" binding:"omitempty,oneof=64 256 512" example:"256"`
+	}
+	UsernameAvailabilityRequest struct {
+		Username string `form:"username" binding:"required" example:"john.doe"`
+	}
+	UpdatePhoneNumberRequest struct {
+		PhoneNumber string `json:"phone_number" binding:"required" example:"+6281234567890"`
+	}
+	VerifyPhoneNumberRequest struct {
+		Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+	}
+	EnableTOTPRequest struct {
+		Code string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+	}
+	// either the current code or the password
+	DisableTOTPRequest struct {
+		Code     string `json:"code" binding:"required_without=Password,omitempty,len=6,numeric" example:"123456"`
+		Password string `json:"password" binding:"required_without=Code" example:""`
+	}
+	// either the current code or the password, the old codes stop working
+	RegenerateRecoveryCodesRequest struct {
+		Code     string `json:"code" binding:"required_without=Password,omitempty,len=6,numeric" example:"123456"`
+		Password string `json:"password" binding:"required_without=Code" example:""`
+	}
+	// Credential is the PublicKeyCredential returned by navigator.credentials.create
+	FinishPasskeyRegistrationRequest struct {
+		Name       string          `json:"name" binding:"required,max=64" example:"MacBook Touch ID"`
+		Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+	}
+	RenamePasskeyRequest struct {
+		Name string `json:"name" binding:"required,max=64" example:"YubiKey 5"`
 	}
 	UpdateEmailRequest struct {
 		Email string `json:"email" binding:"required,email"`
 	}
+	VerifyEmailChangeRequest struct {
+		Token string `json:"token" binding:"required"`
+	}
 	UpdatePasswordRequest struct {
-		Password           string `json:"password" binding:"required,min=6"`
-		NewPassword        string `json:"new_password" binding:"required,min=6"`
-		ConfirmNewPassword string `json:"confirm_new_password" binding:"required,min=6"`
+		Password string `json:"password" binding:"required,min=6"`
+		// length and the other rules come from the password policy
+		NewPassword        string `json:"new_password" binding:"required"`
+		ConfirmNewPassword string `json:"confirm_new_password" binding:"required"`
 	}
 	DeleteUserRequest struct {
 		Password string `json:"password" binding:"required,min=6"`
+		// from If-Match, any of them matches. empty accepts any version
+		ExpectedVersions []int64 `json:"-" swaggerignore:"true"`
+	}
+	ConfirmDeleteUserRequest struct {
+		Token string `json:"token" binding:"required"`
+	}
+	ListUsersRequest struct {
+		Cursor           string     `form:"cursor"`
+		Limit            uint64     `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+		Verified         *bool      `form:"verified" example:"true"`
+		TwoFactorEnabled *bool      `form:"two_factor_enabled" example:"false"`
+		CreatedFrom      *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+		CreatedTo        *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-12-31T23:59:59Z"`
+		Email            string     `form:"email" binding:"max=255" example:"john"`
+		Name             string     `form:"name" binding:"max=255" example:"doe"`
+		SortBy           string     `form:"sort_by" binding:"omitempty,oneof=created_at full_name email" example:"created_at"`
+		SortOrder        string     `form:"sort_order" binding:"omitempty,oneof=asc desc" example:"desc"`
+	}
+
+	ListUsersQuery struct {
+		Limit            uint64
+		Verified         *bool
+		TwoFactorEnabled *bool
+		CreatedFrom      *time.Time
+		CreatedTo        *time.Time
+		Email            string
+		Name             string
+		SortBy           string
+		SortDesc         bool
+		After            *ListUsersCursor
+	}
+	ListUsersCursor struct {
+		SortBy string `json:"s"`
+		// a cursor only continues the direction it was issued for
+		Desc  bool   `json:"d"`
+		Value string `json:"v"`
+		ID    string `json:"id"`
 	}
 )
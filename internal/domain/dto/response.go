@@ -1,46 +1,184 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+	"time"
+
+	"github.com/micros-template/user-service/pkg/password"
+)
 
 var (
 	SUCCESS_GET_PROFILE     = "success get profile data"
 	SUCCESS_UPDATE_PROFILE  = "success update profile data"
 	SUCCESS_UPDATE_EMAIL    = "verify to change email"
+	SUCCESS_VERIFY_EMAIL    = "success change email"
 	SUCCESS_UPDATE_PASSWORD = "success update password"
+	SUCCESS_REQUEST_DELETE  = "verify to delete account"
 	SUCCESS_DELETE_USER     = "success delete user"
+	SUCCESS_RESTORE_USER    = "success restore user"
+	SUCCESS_LIST_USERS      = "success list users"
+	SUCCESS_UPDATE_AVATAR   = "success update avatar"
+	SUCCESS_DELETE_AVATAR   = "success delete avatar"
+	SUCCESS_CHECK_USERNAME  = "success check username"
+	SUCCESS_UPDATE_PHONE    = "verification code sent"
+	SUCCESS_VERIFY_PHONE    = "success verify phone number"
+	SUCCESS_SETUP_TOTP      = "scan the qr code and confirm with a code"
+	SUCCESS_ENABLE_TOTP     = "success enable two-factor authentication"
+	SUCCESS_DISABLE_TOTP    = "success disable two-factor authentication"
+	SUCCESS_RECOVERY_CODES  = "store the recovery codes, they are only shown once"
+	SUCCESS_COUNT_RECOVERY  = "success count recovery codes"
+	SUCCESS_BEGIN_PASSKEY   = "complete the registration with the authenticator"
+	SUCCESS_ADD_PASSKEY     = "success add passkey"
+	SUCCESS_LIST_PASSKEYS   = "success list passkeys"
+	SUCCESS_RENAME_PASSKEY  = "success rename passkey"
+	SUCCESS_DELETE_PASSKEY  = "success delete passkey"
 )
 
 var (
-	Err_INTERNAL_FAILED_BUILD_QUERY = errors.New("failed to build query")
-	Err_INTERNAL_FAILED_SCAN_USER   = errors.New("failed to scan user")
-	Err_INTERNAL_FAILED_INSERT_USER = errors.New("failed to insert user")
-	Err_INTERNAL_FAILED_UPDATE_USER = errors.New("failed to update user")
-	Err_INTERNAL_FAILED_DELETE_USER = errors.New("failed to delete user")
-	Err_INTERNAL_CONVERT_IMAGE      = errors.New("error processing image")
-	Err_INTERNAL_GENERATE_TOKEN     = errors.New("error generate verification token")
-	Err_INTERNAL_GET_RESOURCE       = errors.New("failed to get resource")
-	Err_INTERNAL_SET_RESOURCE       = errors.New("failed save resource")
-	Err_INTERNAL_DELETE_RESOURCE    = errors.New("failed to delete resource")
-	Err_INTERNAL_PUBLISH_MESSAGE    = errors.New("error publish email")
-
-	Err_NOTFOUND_USER_NOT_FOUND = errors.New("user not found")
-	Err_NOTFOUND_KEY_NOTFOUND   = errors.New("resource is not found")
+	Err_INTERNAL_FAILED_BUILD_QUERY    = errors.New("failed to build query")
+	Err_INTERNAL_FAILED_SCAN_USER      = errors.New("failed to scan user")
+	Err_INTERNAL_FAILED_INSERT_USER    = errors.New("failed to insert user")
+	Err_INTERNAL_FAILED_UPDATE_USER    = errors.New("failed to update user")
+	Err_INTERNAL_FAILED_DELETE_USER    = errors.New("failed to delete user")
+	Err_INTERNAL_FAILED_RESTORE_USER   = errors.New("failed to restore user")
+	Err_INTERNAL_FAILED_PURGE_USER     = errors.New("failed to purge deleted user")
+	Err_INTERNAL_FAILED_LIST_USERS     = errors.New("failed to list users")
+	Err_INTERNAL_FAILED_QUERY_USERS    = errors.New("failed to query users")
+	Err_INTERNAL_FAILED_QUERY_USERNAME = errors.New("failed to query username")
+	Err_INTERNAL_FAILED_TRANSACTION    = errors.New("failed to run transaction")
+	Err_INTERNAL_FAILED_INSERT_OUTBOX  = errors.New("failed to insert outbox message")
+	Err_INTERNAL_FAILED_CLAIM_OUTBOX   = errors.New("failed to claim outbox message")
+	Err_INTERNAL_FAILED_UPDATE_OUTBOX  = errors.New("failed to update outbox message")
+	Err_INTERNAL_FAILED_QUERY_RECOVERY = errors.New("failed to query recovery codes")
+	Err_INTERNAL_FAILED_SAVE_RECOVERY  = errors.New("failed to save recovery codes")
+	Err_INTERNAL_FAILED_QUERY_PASSKEYS = errors.New("failed to query passkeys")
+	Err_INTERNAL_FAILED_SAVE_PASSKEY   = errors.New("failed to save passkey")
+	Err_INTERNAL_FAILED_QUERY_HISTORY  = errors.New("failed to query password history")
+	Err_INTERNAL_FAILED_SAVE_HISTORY   = errors.New("failed to save password history")
+	Err_INTERNAL_CONVERT_IMAGE         = errors.New("error processing image")
+	Err_INTERNAL_GENERATE_TOKEN        = errors.New("error generate verification token")
+	Err_INTERNAL_GET_RESOURCE          = errors.New("failed to get resource")
+	Err_INTERNAL_SET_RESOURCE          = errors.New("failed save resource")
+	Err_INTERNAL_DELETE_RESOURCE       = errors.New("failed to delete resource")
+	Err_INTERNAL_PUBLISH_MESSAGE       = errors.New("error publish email")
+	Err_INTERNAL_TOTP_SECRET           = errors.New("failed to process two-factor secret")
+	Err_INTERNAL_WEBAUTHN              = errors.New("failed to process passkey")
+
+	Err_NOTFOUND_USER_NOT_FOUND  = errors.New("user not found")
+	Err_NOTFOUND_KEY_NOTFOUND    = errors.New("resource is not found")
+	Err_NOTFOUND_TOTP_ENROLLMENT = errors.New("no pending two-factor enrollment, start a new one")
+	Err_NOTFOUND_PASSKEY         = errors.New("passkey not found")
+	Err_NOTFOUND_PASSKEY_SESSION = errors.New("no pending passkey registration, start a new one")
 
 	Err_UNAUTHORIZED_USER_ID_NOTFOUND = errors.New("invalid token")
 	Err_UNAUTHORIZED_PASSWORD_WRONG   = errors.New("wrong password")
+	Err_UNAUTHORIZED_TOKEN_INVALID    = errors.New("invalid or expired token")
+	Err_UNAUTHORIZED_OTP_INVALID      = errors.New("invalid or expired code")
+	Err_UNAUTHORIZED_RECOVERY_INVALID = errors.New("invalid or used recovery code")
+
+	Err_FORBIDDEN_ADMIN_ONLY = errors.New("admin access required")
+
+	Err_CONFLICT_EMAIL_EXIST         = errors.New("email is already used")
+	Err_CONFLICT_USERNAME_EXIST      = errors.New("username is already taken")
+	Err_CONFLICT_TWO_FACTOR_ENABLED  = errors.New("two-factor authentication is already enabled")
+	Err_CONFLICT_TWO_FACTOR_DISABLED = errors.New("two-factor authentication is not enabled")
+	Err_CONFLICT_TWO_FACTOR_REENROLL = errors.New("two-factor authentication has to be set up again")
+	Err_CONFLICT_PASSKEY_EXIST       = errors.New("passkey is already registered")
 
 	Err_BAD_REQUEST_WRONG_EXTENSION                        = errors.New("error file extension, support jpg, jpeg, and png")
 	Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED                    = errors.New("max size exceeded: 6mb")
+	Err_BAD_REQUEST_UNSUPPORTED_IMAGE                      = errors.New("unsupported image content, support jpeg and png")
+	Err_BAD_REQUEST_CORRUPT_IMAGE                          = errors.New("image is corrupt and cannot be decoded")
+	Err_BAD_REQUEST_IMAGE_DIMENSIONS_EXCEEDED              = errors.New("image dimensions exceed the limit")
+	Err_BAD_REQUEST_INVALID_AVATAR_SIZE                    = errors.New("invalid avatar size, support 64, 256 and 512")
 	Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH = errors.New("password doesn't match")
+	Err_BAD_REQUEST_INVALID_CURSOR                         = errors.New("invalid cursor")
+	Err_BAD_REQUEST_INVALID_PASSKEY                        = errors.New("invalid passkey registration response")
+
+	Err_PRECONDITION_FAILED_VERSION_MISMATCH = errors.New("user was modified, fetch the latest version and retry")
+	Err_PRECONDITION_REQUIRED_IF_MATCH       = errors.New("If-Match header is required")
+	Err_BAD_REQUEST_INVALID_EXPECTED_VERSION = errors.New("invalid expected version")
+
+	Err_TOO_MANY_REQUESTS_OTP_SENT      = errors.New("too many verification codes requested, try again later")
+	Err_TOO_MANY_REQUESTS_OTP_ATTEMPTS  = errors.New("too many wrong codes, request a new one")
+	Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS = errors.New("too many wrong codes, try again later")
+
+	Err_UNSUPPORTED_MEDIA_TYPE = errors.New("unsupported content type, use application/merge-patch+json or multipart/form-data")
 )
 
 type (
+	InvalidFieldsResponse struct {
+		StatusCode uint16      `json:"status_code" example:"400"`
+		Message    string      `json:"message" example:"invalid input"`
+		Errors     FieldErrors `json:"errors" swaggertype:"object,string" example:"full_name:must not be empty"`
+	}
+	PasswordPolicyResponse struct {
+		StatusCode uint16               `json:"status_code" example:"400"`
+		Message    string               `json:"message" example:"password does not meet the policy"`
+		Errors     []password.Violation `json:"errors"`
+	}
+
 	GetProfileResponse struct {
-		FullName         string  `json:"full_name" example:"John Doe"`
-		Image            *string `json:"image" example:"https://example.com/image.jpg"`
-		Email            string  `json:"email" example:"john.doe@example.com"`
-		Verified         bool    `json:"verified" example:"true"`
-		TwoFactorEnabled bool    `json:"two_factor_enabled" example:"false"`
+		FullName         string        `json:"full_name" example:"John Doe"`
+		Image            *string       `json:"image" example:"https://example.com/image.jpg"`
+		Email            string        `json:"email" example:"john.doe@example.com"`
+		Verified         bool          `json:"verified" example:"true"`
+		TwoFactorEnabled bool          `json:"two_factor_enabled" example:"false"`
+		ImageVariants    ImageVariants `json:"image_variants"`
+		DefaultImage     bool          `json:"default_image" example:"false"`
+		Username         *string       `json:"username" example:"john.doe"`
+		Bio              *string       `json:"bio" example:"backend engineer"`
+		Locale           *string       `json:"locale" example:"en-US"`
+		Timezone         *string       `json:"timezone" example:"Asia/Jakarta"`
+		PhoneNumber      *string       `json:"phone_number" example:"+6281234567890"`
+		PhoneVerified    bool          `json:"phone_verified" example:"true"`
+		DateOfBirth      *string       `json:"date_of_birth" example:"1990-01-31"`
+		// sent as the ETag header
+		Version int64 `json:"-"`
+	}
+
+	UsernameAvailabilityResponse struct {
+		Username  string `json:"username" example:"john.doe"`
+		Available bool   `json:"available" example:"true"`
+	}
+
+	RecoveryCodesResponse struct {
+		RecoveryCodes []string `json:"recovery_codes" example:"k7m2p-x9q4r,b3n8t-w6z2h"`
+	}
+	RecoveryCodesCountResponse struct {
+		Remaining int `json:"remaining" example:"8"`
+	}
+
+	SetupTOTPResponse struct {
+		// for apps that cannot scan the qr code
+		Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+		OtpauthURI string `json:"otpauth_uri" example:"otpauth://totp/dropboks:john.doe@example.com?algorithm=SHA1&digits=6&issuer=dropboks&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+		// base64 encoded png of the otpauth uri
+		QRCode string `json:"qr_code" example:"iVBORw0KGgoAAAANSUhEUgAA..."`
+	}
+
+	PasskeyResponse struct {
+		ID         int64    `json:"id" example:"1"`
+		Name       string   `json:"name" example:"MacBook Touch ID"`
+		Transports []string `json:"transports" example:"internal,hybrid"`
+		// synced passkeys survive the loss of a device
+		Synced     bool       `json:"synced" example:"true"`
+		CreatedAt  time.Time  `json:"created_at" example:"2025-01-01T00:00:00Z"`
+		LastUsedAt *time.Time `json:"last_used_at" example:"2025-01-02T00:00:00Z"`
+	}
+
+	UserListItem struct {
+		ID               string    `json:"id" example:"2b1c6c1e-3f7a-4a8e-9d3c-1f2e3d4c5b6a"`
+		FullName         string    `json:"full_name" example:"John Doe"`
+		Image            *string   `json:"image" example:"https://example.com/image.jpg"`
+		Email            string    `json:"email" example:"john.doe@example.com"`
+		Verified         bool      `json:"verified" example:"true"`
+		TwoFactorEnabled bool      `json:"two_factor_enabled" example:"false"`
+		CreatedAt        time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+	}
+	ListUsersResponse struct {
+		Users      []UserListItem `json:"users"`
+		NextCursor *string        `json:"next_cursor" example:"eyJzIjoiY3JlYXRlZF9hdCJ9"`
 	}
 
 	GlobalInternalServerErrorExample struct {
@@ -65,22 +203,132 @@ type (
 		Message    string             `json:"message" example:"success get profile data"`
 		Data       GetProfileResponse `json:"data"`
 	}
+	ListUsersSuccessExample struct {
+		StatusCode uint16            `json:"status_code" example:"200"`
+		Message    string            `json:"message" example:"success list users"`
+		Data       ListUsersResponse `json:"data"`
+	}
 	UpdateUserSuccessExample struct {
+		StatusCode uint16             `json:"status_code" example:"200"`
+		Message    string             `json:"message" example:"success update profile data"`
+		Data       GetProfileResponse `json:"data"`
+	}
+
+	UsernameAvailabilitySuccessExample struct {
+		StatusCode uint16                       `json:"status_code" example:"200"`
+		Message    string                       `json:"message" example:"success check username"`
+		Data       UsernameAvailabilityResponse `json:"data"`
+	}
+
+	UpdatePhoneNumberSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"verification code sent"`
+		Data       string `json:"data" example:"null"`
+	}
+	VerifyPhoneNumberSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success verify phone number"`
+		Data       string `json:"data" example:"null"`
+	}
+	SetupTOTPSuccessExample struct {
+		StatusCode uint16            `json:"status_code" example:"200"`
+		Message    string            `json:"message" example:"scan the qr code and confirm with a code"`
+		Data       SetupTOTPResponse `json:"data"`
+	}
+	EnableTOTPSuccessExample struct {
+		StatusCode uint16                `json:"status_code" example:"200"`
+		Message    string                `json:"message" example:"success enable two-factor authentication"`
+		Data       RecoveryCodesResponse `json:"data"`
+	}
+	BeginPasskeyRegistrationSuccessExample struct {
 		StatusCode uint16 `json:"status_code" example:"200"`
-		Message    string `json:"message" example:"success update profile data"`
+		Message    string `json:"message" example:"complete the registration with the authenticator"`
+		// PublicKeyCredentialCreationOptions for navigator.credentials.create
+		Data map[string]any `json:"data"`
+	}
+	PasskeySuccessExample struct {
+		StatusCode uint16          `json:"status_code" example:"200"`
+		Message    string          `json:"message" example:"success add passkey"`
+		Data       PasskeyResponse `json:"data"`
+	}
+	ListPasskeysSuccessExample struct {
+		StatusCode uint16            `json:"status_code" example:"200"`
+		Message    string            `json:"message" example:"success list passkeys"`
+		Data       []PasskeyResponse `json:"data"`
+	}
+	RenamePasskeySuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success rename passkey"`
 		Data       string `json:"data" example:"null"`
 	}
+	DeletePasskeySuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success delete passkey"`
+		Data       string `json:"data" example:"null"`
+	}
+	RegenerateRecoveryCodesSuccessExample struct {
+		StatusCode uint16                `json:"status_code" example:"200"`
+		Message    string                `json:"message" example:"store the recovery codes, they are only shown once"`
+		Data       RecoveryCodesResponse `json:"data"`
+	}
+	CountRecoveryCodesSuccessExample struct {
+		StatusCode uint16                     `json:"status_code" example:"200"`
+		Message    string                     `json:"message" example:"success count recovery codes"`
+		Data       RecoveryCodesCountResponse `json:"data"`
+	}
+	DisableTOTPSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success disable two-factor authentication"`
+		Data       string `json:"data" example:"null"`
+	}
+	GlobalTooManyRequestsExample struct {
+		StatusCode uint16 `json:"status_code" example:"429"`
+		Message    string `json:"message" example:"too many verification codes requested, try again later"`
+	}
 
 	ChangeEmailSuccessExample struct {
 		StatusCode uint16 `json:"status_code" example:"200"`
 		Message    string `json:"message" example:"verify to change email"`
 		Data       string `json:"data" example:"null"`
 	}
+	VerifyEmailChangeSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success change email"`
+		Data       string `json:"data" example:"null"`
+	}
+	GlobalConflictErrorExample struct {
+		StatusCode uint16 `json:"status_code" example:"409"`
+		Message    string `json:"message" example:"email is already used"`
+	}
 	ChangePasswordSuccessExample struct {
 		StatusCode uint16 `json:"status_code" example:"200"`
 		Message    string `json:"message" example:"success delete user"`
 		Data       string `json:"data" example:"null"`
 	}
+	GlobalForbiddenErrorExample struct {
+		StatusCode uint16 `json:"status_code" example:"403"`
+		Message    string `json:"message" example:"admin access required"`
+	}
+	RestoreUserSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success restore user"`
+		Data       string `json:"data" example:"null"`
+	}
+	UpdateAvatarSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success update avatar"`
+		Data       string `json:"data" example:"null"`
+	}
+	DeleteAvatarSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"success delete avatar"`
+		Data       string `json:"data" example:"null"`
+	}
+	RequestDeleteUserSuccessExample struct {
+		StatusCode uint16 `json:"status_code" example:"200"`
+		Message    string `json:"message" example:"verify to delete account"`
+		Data       string `json:"data" example:"null"`
+	}
 	DeleteUserSuccessExample struct {
 		StatusCode uint16 `json:"status_code" example:"200"`
 		Message    string `json:"message" example:"success delete user"`
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type (
+	// a registered passkey, CredentialID is the id the authenticator knows it by
+	WebAuthnCredential struct {
+		ID              int64
+		UserID          string
+		CredentialID    []byte
+		Name            string
+		PublicKey       []byte
+		AttestationType string
+		AAGUID          []byte
+		Transports      []string
+		SignCount       uint32
+		CloneWarning    bool
+		BackupEligible  bool
+		BackupState     bool
+		CreatedAt       time.Time
+		LastUsedAt      *time.Time
+	}
+
+	// reported by the auth service after a verified assertion
+	WebAuthnCredentialUsage struct {
+		UserID       string
+		CredentialID []byte
+		SignCount    uint32
+		CloneWarning bool
+		BackupState  bool
+	}
+)
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/pkg/uapb"
+
+	upb "github.com/micros-template/proto-user/pkg/upb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	_status "google.golang.org/grpc/status"
+)
+
+const maxBatchUserIds = 500
+
+type AccountGrpcHandler struct {
+	userService service.UserService
+	authService service.AuthService
+	uapb.UnimplementedUserAccountServiceServer
+}
+
+func NewAccountGrpcHandler(userService service.UserService, authService service.AuthService) *AccountGrpcHandler {
+	return &AccountGrpcHandler{
+		userService: userService,
+		authService: authService,
+	}
+}
+
+func RegisterAccountService(grpc *grpc.Server, userService service.UserService, authService service.AuthService) {
+	grpcHandler := NewAccountGrpcHandler(userService, authService)
+	uapb.RegisterUserAccountServiceServer(grpc, grpcHandler)
+}
+
+func (a *AccountGrpcHandler) VerifyEmailChange(c context.Context, req *uapb.VerifyEmailChangeRequest) (*upb.Status, error) {
+	if req.GetUserId() == "" || req.GetToken() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	if err := a.userService.VerifyEmailChange(c, &dto.VerifyEmailChangeRequest{Token: req.GetToken()}, req.GetUserId()); err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_TOKEN_INVALID:
+			return nil, _status.Error(codes.Unauthenticated, err.Error())
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		case dto.Err_CONFLICT_EMAIL_EXIST:
+			return nil, _status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return &upb.Status{Success: true}, nil
+}
+
+func (a *AccountGrpcHandler) GetUserById(c context.Context, req *upb.UserId) (*upb.User, error) {
+	if req.GetUserId() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	user, err := a.authService.GetUserById(c, req)
+	if err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return user, nil
+}
+
+func (a *AccountGrpcHandler) GetUsersByIds(c context.Context, req *uapb.GetUsersByIdsRequest) (*uapb.GetUsersByIdsResponse, error) {
+	if len(req.GetUserIds()) == 0 || len(req.GetUserIds()) > maxBatchUserIds {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	users, err := a.authService.GetUsersByIds(c, req.GetUserIds())
+	if err != nil {
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return &uapb.GetUsersByIdsResponse{Users: users}, nil
+}
+
+func (a *AccountGrpcHandler) GetUserByEmail(c context.Context, req *uapb.GetUserByEmailRequest) (*upb.User, error) {
+	if req.GetEmail() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	user, err := a.authService.GetUserByEmail(c, req.GetEmail())
+	if err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return user, nil
+}
+
+// the second login step in the auth service, the secret never leaves this service
+func (a *AccountGrpcHandler) VerifyTOTP(c context.Context, req *uapb.VerifyTOTPRequest) (*upb.Status, error) {
+	if req.GetUserId() == "" || req.GetCode() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	if err := a.userService.VerifyTOTP(c, req.GetCode(), req.GetUserId()); err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_OTP_INVALID:
+			return nil, _status.Error(codes.Unauthenticated, err.Error())
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		case dto.Err_CONFLICT_TWO_FACTOR_DISABLED, dto.Err_CONFLICT_TWO_FACTOR_REENROLL:
+			return nil, _status.Error(codes.FailedPrecondition, err.Error())
+		case dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS:
+			return nil, _status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return &upb.Status{Success: true}, nil
+}
+
+// the auth service falls back to this when the authenticator is lost, each code works once
+func (a *AccountGrpcHandler) UseRecoveryCode(c context.Context, req *uapb.UseRecoveryCodeRequest) (*upb.Status, error) {
+	if req.GetUserId() == "" || req.GetCode() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	if err := a.userService.UseRecoveryCode(c, req.GetCode(), req.GetUserId()); err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_RECOVERY_INVALID:
+			return nil, _status.Error(codes.Unauthenticated, err.Error())
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		case dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS:
+			return nil, _status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return &upb.Status{Success: true}, nil
+}
+
+// the auth service runs the login ceremony itself, the user handle is what it gets back from discoverable passkeys
+func (a *AccountGrpcHandler) GetWebAuthnCredentials(c context.Context, req *uapb.GetWebAuthnCredentialsRequest) (*uapb.GetWebAuthnCredentialsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	credentials, err := a.userService.GetWebAuthnCredentials(c, req.GetUserId())
+	if err != nil {
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	res := &uapb.GetWebAuthnCredentialsResponse{
+		UserHandle:  []byte(req.GetUserId()),
+		Credentials: make([]*uapb.WebAuthnCredential, 0, len(credentials)),
+	}
+	for _, credential := range credentials {
+		res.Credentials = append(res.Credentials, &uapb.WebAuthnCredential{
+			CredentialId:    credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Aaguid:          credential.AAGUID,
+			Transports:      credential.Transports,
+			SignCount:       credential.SignCount,
+			CloneWarning:    credential.CloneWarning,
+			BackupEligible:  credential.BackupEligible,
+			BackupState:     credential.BackupState,
+		})
+	}
+	return res, nil
+}
+
+// called after a verified assertion so the next login can detect a cloned authenticator
+func (a *AccountGrpcHandler) UpdateWebAuthnSignCount(c context.Context, req *uapb.UpdateWebAuthnSignCountRequest) (*upb.Status, error) {
+	if req.GetUserId() == "" || len(req.GetCredentialId()) == 0 {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	usage := &dto.WebAuthnCredentialUsage{
+		UserID:       req.GetUserId(),
+		CredentialID: req.GetCredentialId(),
+		SignCount:    req.GetSignCount(),
+		CloneWarning: req.GetCloneWarning(),
+		BackupState:  req.GetBackupState(),
+	}
+	if err := a.userService.UpdateWebAuthnCredentialUsage(c, usage); err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_PASSKEY:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return &upb.Status{Success: true}, nil
+}
+
+// the profile fields upb.User does not carry, the same shape as the user_profile events
+func (a *AccountGrpcHandler) GetUserProfile(c context.Context, req *upb.UserId) (*uapb.UserProfile, error) {
+	if req.GetUserId() == "" {
+		return nil, _status.Error(codes.InvalidArgument, "invalid input")
+	}
+	profile, err := a.userService.GetProfile(c, req.GetUserId())
+	if err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			return nil, _status.Error(codes.NotFound, err.Error())
+		}
+		return nil, _status.Error(codes.Internal, err.Error())
+	}
+	return &uapb.UserProfile{
+		Id:            req.GetUserId(),
+		Username:      profile.Username,
+		Bio:           profile.Bio,
+		Locale:        profile.Locale,
+		Timezone:      profile.Timezone,
+		PhoneNumber:   profile.PhoneNumber,
+		PhoneVerified: profile.PhoneVerified,
+		DateOfBirth:   profile.DateOfBirth,
+	}, nil
+}
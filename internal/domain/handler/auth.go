@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/service"
@@ -9,9 +10,12 @@ import (
 	upb "github.com/micros-template/proto-user/pkg/upb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	_status "google.golang.org/grpc/status"
 )
 
+const expectedVersionMetadataKey = "x-expected-version"
+
 type AuthGrpcHandler struct {
 	authService service.AuthService
 	upb.UnimplementedUserServiceServer
@@ -29,7 +33,7 @@ func RegisterAuthService(grpc *grpc.Server, authService service.AuthService) {
 }
 
 func (a *AuthGrpcHandler) CreateUser(c context.Context, user *upb.User) (*upb.Status, error) {
-	status, err := a.authService.CreateUser(user)
+	status, err := a.authService.CreateUser(c, user)
 	if err != nil {
 		if err == dto.Err_INTERNAL_FAILED_BUILD_QUERY || err == dto.Err_INTERNAL_FAILED_INSERT_USER {
 			return nil, _status.Error(codes.Internal, err.Error())
@@ -39,11 +43,21 @@ func (a *AuthGrpcHandler) CreateUser(c context.Context, user *upb.User) (*upb.St
 	return status, nil
 }
 
+// UpdateUser replaces the whole row with user. The expected version is read from the
+// x-expected-version metadata: when it is set the write only applies to that version and a
+// mismatch answers FailedPrecondition. When it is missing the write is unconditional and
+// overwrites any change made since the caller read the user
 func (a *AuthGrpcHandler) UpdateUser(c context.Context, user *upb.User) (*upb.Status, error) {
-	if err := a.authService.UpdateUser(c, user); err != nil {
+	expectedVersion, err := expectedVersionFromMetadata(c)
+	if err != nil {
+		return nil, _status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := a.authService.UpdateUser(c, user, expectedVersion); err != nil {
 		switch err {
 		case dto.Err_NOTFOUND_USER_NOT_FOUND:
 			return nil, _status.Error(codes.NotFound, err.Error())
+		case dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH:
+			return nil, _status.Error(codes.FailedPrecondition, err.Error())
 		case dto.Err_INTERNAL_FAILED_BUILD_QUERY, dto.Err_INTERNAL_FAILED_INSERT_USER:
 			return nil, _status.Error(codes.Internal, err.Error())
 		}
@@ -52,6 +66,20 @@ func (a *AuthGrpcHandler) UpdateUser(c context.Context, user *upb.User) (*upb.St
 	return &upb.Status{Success: true}, nil
 }
 
+// upb.User has no version field, so the expected version travels as metadata.
+// 0 means the caller did not send it
+func expectedVersionFromMetadata(c context.Context) (int64, error) {
+	values := metadata.ValueFromIncomingContext(c, expectedVersionMetadataKey)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || version < 1 {
+		return 0, dto.Err_BAD_REQUEST_INVALID_EXPECTED_VERSION
+	}
+	return version, nil
+}
+
 func (a *AuthGrpcHandler) DeleteUser(c context.Context, user *upb.UserId) (*upb.Status, error) {
 	if err := a.authService.DeleteUser(c, user); err != nil {
 		switch err {
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/micros-template/sharedlib/utils"
+)
+
+// the row version is the whole validator, so the ETag is strong
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// every listed tag is a candidate, the request goes ahead when any of them is the current version.
+// "*" matches any existing user and is returned as no versions. weak tags never match, If-Match uses
+// the strong comparison
+func parseIfMatch(header string) ([]int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return nil, dto.Err_PRECONDITION_REQUIRED_IF_MATCH
+	}
+	var versions []int64
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, nil
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64); err == nil && version > 0 {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH
+	}
+	return versions, nil
+}
+
+// aborts with 428 or 412 when the request carries no usable If-Match
+func requireIfMatch(ctx *gin.Context) ([]int64, bool) {
+	versions, err := parseIfMatch(ctx.GetHeader("If-Match"))
+	switch err {
+	case nil:
+		return versions, true
+	case dto.Err_PRECONDITION_REQUIRED_IF_MATCH:
+		res := utils.ReturnResponseError(428, err.Error())
+		ctx.AbortWithStatusJSON(http.StatusPreconditionRequired, res)
+	default:
+		res := utils.ReturnResponseError(412, err.Error())
+		ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, res)
+	}
+	return nil, false
+}
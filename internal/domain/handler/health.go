@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/micros-template/user-service/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	HealthHandler interface {
+		Livez(ctx *gin.Context)
+		Readyz(ctx *gin.Context)
+	}
+	healthHandler struct {
+		checker health.Checker
+	}
+)
+
+func NewHealthHandler(checker health.Checker) HealthHandler {
+	return &healthHandler{checker: checker}
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is running, dependencies are not checked
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string "Process is alive"
+// @Router /livez [get]
+func (h *healthHandler) Livez(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// @Summary Readiness probe
+// @Description Checks postgres, redis, nats and the file service channel and reports each dependency with its latency
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Report "All dependencies are up"
+// @Failure 503 {object} health.Report "At least one dependency is down"
+// @Router /readyz [get]
+func (h *healthHandler) Readyz(ctx *gin.Context) {
+	report := h.checker.Ready(ctx.Request.Context())
+	if report.Status != health.StatusUp {
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
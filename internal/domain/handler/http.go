@@ -18,10 +18,41 @@ func RegisterUserRoutes(r *gin.Engine, uh UserHandler) *gin.Engine {
 	})
 	{
 		r.PATCH("", uh.UpdateUser)
+		r.PUT("/avatar", uh.UpdateAvatar)
+		r.DELETE("/avatar", uh.DeleteAvatar)
+		r.GET("/avatar", uh.GetAvatar)
+		r.GET("/avatar/default/:id", uh.GetDefaultAvatar)
+		r.GET("/username/availability", uh.CheckUsernameAvailability)
 		r.DELETE("", uh.DeleteUser)
+		r.POST("/delete/confirm", uh.ConfirmDeleteUser)
 		r.PATCH("/email", uh.ChangeEmail)
+		r.POST("/email/verify", uh.VerifyEmailChange)
+		r.PATCH("/phone", uh.ChangePhoneNumber)
+		r.POST("/phone/verify", uh.VerifyPhoneNumber)
+		r.POST("/2fa/totp/setup", uh.SetupTOTP)
+		r.POST("/2fa/totp/enable", uh.EnableTOTP)
+		r.POST("/2fa/totp/disable", uh.DisableTOTP)
+		r.POST("/2fa/recovery-codes", uh.RegenerateRecoveryCodes)
+		r.GET("/2fa/recovery-codes", uh.CountRecoveryCodes)
+		r.POST("/passkeys/register/begin", uh.BeginPasskeyRegistration)
+		r.POST("/passkeys/register/finish", uh.FinishPasskeyRegistration)
+		r.GET("/passkeys", uh.ListPasskeys)
+		r.PATCH("/passkeys/:id", uh.RenamePasskey)
+		r.DELETE("/passkeys/:id", uh.DeletePasskey)
 		r.PATCH("/password", uh.ChangePassword)
 		r.GET("/me", uh.GetProfile)
+		r.POST("/restore", uh.RestoreUser)
 	}
+	admin := r.Group("/admin", RequireAdmin)
+	{
+		admin.GET("/users", uh.ListUsers)
+		admin.POST("/users/:id/restore", uh.AdminRestoreUser)
+	}
+	return r
+}
+
+func RegisterHealthRoutes(r *gin.Engine, hh HealthHandler) *gin.Engine {
+	r.GET("/livez", hh.Livez)
+	r.GET("/readyz", hh.Readyz)
 	return r
 }
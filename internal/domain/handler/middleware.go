@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/pkg/constant"
+
+	"github.com/gin-gonic/gin"
+	"github.com/micros-template/sharedlib/utils"
+)
+
+func RequireAdmin(ctx *gin.Context) {
+	var userData dto.UserData
+	if err := json.Unmarshal([]byte(ctx.GetHeader("User-Data")), &userData); err != nil || userData.UserId == "" {
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	if userData.Role != constant.ROLE_ADMIN {
+		res := utils.ReturnResponseError(403, dto.Err_FORBIDDEN_ADMIN_ONLY.Error())
+		ctx.AbortWithStatusJSON(http.StatusForbidden, res)
+		return
+	}
+	ctx.Next()
+}
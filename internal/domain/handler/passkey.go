@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/micros-template/sharedlib/utils"
+)
+
+// @Summary Begin passkey registration
+// @Description Start registering a passkey for User based on its ID (from token). The data is passed to navigator.credentials.create
+// @Tags User-Service
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.BeginPasskeyRegistrationSuccessExample "Begin Passkey Registration Success"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /passkeys/register/begin [post]
+func (u *userHandler) BeginPasskeyRegistration(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	creation, err := u.userService.BeginPasskeyRegistration(ctx.Request.Context(), userId)
+	if err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_BEGIN_PASSKEY, creation)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Finish passkey registration
+// @Description Store the passkey created by the authenticator for User based on its ID (from token)
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.FinishPasskeyRegistrationRequest true "Body Request"
+// @Success 200 {object} dto.PasskeySuccessExample "Finish Passkey Registration Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input or authenticator response"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "No pending registration"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - passkey is already registered"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /passkeys/register/finish [post]
+func (u *userHandler) FinishPasskeyRegistration(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.FinishPasskeyRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	passkey, err := u.userService.FinishPasskeyRegistration(ctx.Request.Context(), &req, userId)
+	if err != nil {
+		switch err {
+		case dto.Err_BAD_REQUEST_INVALID_PASSKEY:
+			res := utils.ReturnResponseError(400, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		case dto.Err_NOTFOUND_PASSKEY_SESSION:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		case dto.Err_CONFLICT_PASSKEY_EXIST:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_ADD_PASSKEY, passkey)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary List passkeys
+// @Description Get the passkeys of User based on its ID (from token)
+// @Tags User-Service
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.ListPasskeysSuccessExample "List Passkeys Success"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /passkeys [get]
+func (u *userHandler) ListPasskeys(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	passkeys, err := u.userService.ListPasskeys(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_LIST_PASSKEYS, passkeys)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Rename passkey
+// @Description Rename a passkey of User based on its ID (from token)
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Passkey ID"
+// @Param request body dto.RenamePasskeyRequest true "Body Request"
+// @Success 200 {object} dto.RenamePasskeySuccessExample "Rename Passkey Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "Passkey not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /passkeys/{id} [patch]
+func (u *userHandler) RenamePasskey(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	var req dto.RenamePasskeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.RenamePasskey(ctx.Request.Context(), &req, id, userId); err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_PASSKEY:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_RENAME_PASSKEY)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete passkey
+// @Description Delete a passkey of User based on its ID (from token)
+// @Tags User-Service
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path int true "Passkey ID"
+// @Success 200 {object} dto.DeletePasskeySuccessExample "Delete Passkey Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "Passkey not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /passkeys/{id} [delete]
+func (u *userHandler) DeletePasskey(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.DeletePasskey(ctx.Request.Context(), id, userId); err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_PASSKEY:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_DELETE_PASSKEY)
+	ctx.JSON(http.StatusOK, res)
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
@@ -9,19 +11,48 @@ import (
 	"github.com/micros-template/user-service/internal/infrastructure/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/micros-template/sharedlib/utils"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	mimeMergePatch = "application/merge-patch+json"
+	// a profile patch is a handful of short members
+	maxMergePatchSize = 64 << 10
+)
+
 type (
 	UserHandler interface {
 		GetProfile(ctx *gin.Context)
+		ListUsers(ctx *gin.Context)
 		UpdateUser(ctx *gin.Context)
+		UpdateAvatar(ctx *gin.Context)
+		DeleteAvatar(ctx *gin.Context)
+		GetAvatar(ctx *gin.Context)
+		GetDefaultAvatar(ctx *gin.Context)
+		CheckUsernameAvailability(ctx *gin.Context)
 		ChangeEmail(ctx *gin.Context)
+		VerifyEmailChange(ctx *gin.Context)
+		ChangePhoneNumber(ctx *gin.Context)
+		VerifyPhoneNumber(ctx *gin.Context)
+		SetupTOTP(ctx *gin.Context)
+		EnableTOTP(ctx *gin.Context)
+		DisableTOTP(ctx *gin.Context)
+		RegenerateRecoveryCodes(ctx *gin.Context)
+		CountRecoveryCodes(ctx *gin.Context)
+		BeginPasskeyRegistration(ctx *gin.Context)
+		FinishPasskeyRegistration(ctx *gin.Context)
+		ListPasskeys(ctx *gin.Context)
+		RenamePasskey(ctx *gin.Context)
+		DeletePasskey(ctx *gin.Context)
 		ChangePassword(ctx *gin.Context)
 		DeleteUser(ctx *gin.Context)
+		ConfirmDeleteUser(ctx *gin.Context)
+		RestoreUser(ctx *gin.Context)
+		AdminRestoreUser(ctx *gin.Context)
 	}
 	userHandler struct {
 		userService service.UserService
@@ -39,16 +70,19 @@ func NewUserHandler(userService service.UserService, logEmitter logger.LoggerInf
 }
 
 // @Summary Delete User
-// @Description Delete User based on its ID (from token)
+// @Description Request deletion of User based on its ID (from token), a confirmation link is sent by email
 // @Tags User-Service
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
+// @Param If-Match header string true "ETag from GET /me"
 // @Param request body dto.DeleteUserRequest true "Body Request"
-// @Success 200 {object} dto.DeleteUserSuccessExample "Delete User Success"
+// @Success 200 {object} dto.RequestDeleteUserSuccessExample "Delete User Requested - need confirmation from email"
 // @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input, password and confirm_password doesn't match"
 // @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - token invalid, wrong password"
 // @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 412 {object} dto.GlobalInvalidInputExample "Precondition failed - user was modified since it was read"
+// @Failure 428 {object} dto.GlobalInvalidInputExample "Precondition required - missing If-Match"
 // @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
 // @Router / [delete]
 func (u *userHandler) DeleteUser(ctx *gin.Context) {
@@ -63,6 +97,10 @@ func (u *userHandler) DeleteUser(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
 		return
 	}
+	expectedVersions, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
 	var req dto.DeleteUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		go func() {
@@ -74,8 +112,13 @@ func (u *userHandler) DeleteUser(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	if err := u.userService.DeleteUser(&req, userId); err != nil {
+	req.ExpectedVersions = expectedVersions
+	if err := u.userService.DeleteUser(ctx.Request.Context(), &req, userId); err != nil {
 		switch err {
+		case dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH:
+			res := utils.ReturnResponseError(412, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, res)
+			return
 		case dto.Err_UNAUTHORIZED_PASSWORD_WRONG:
 			res := utils.ReturnResponseError(401, err.Error())
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
@@ -89,10 +132,124 @@ func (u *userHandler) DeleteUser(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
 		return
 	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_REQUEST_DELETE)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Confirm Delete User
+// @Description Delete User based on its ID (from token) using the token sent by email
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.ConfirmDeleteUserRequest true "Body Request"
+// @Success 200 {object} dto.DeleteUserSuccessExample "Delete User Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - token invalid or expired"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /delete/confirm [post]
+func (u *userHandler) ConfirmDeleteUser(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.ConfirmDeleteUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.ConfirmDeleteUser(ctx.Request.Context(), &req, userId); err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_TOKEN_INVALID:
+			res := utils.ReturnResponseError(401, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
 	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_DELETE_USER)
 	ctx.JSON(http.StatusOK, res)
 }
 
+// @Summary Restore User
+// @Description Restore a soft deleted User based on its ID (from token) while the restore window is open
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.RestoreUserSuccessExample "Restore User Success"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found or restore window expired"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /restore [post]
+func (u *userHandler) RestoreUser(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	u.restoreUser(ctx, userId)
+}
+
+// @Summary Restore User (Admin)
+// @Description Restore a soft deleted User based on the ID in path while the restore window is open
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.RestoreUserSuccessExample "Restore User Success"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 403 {object} dto.GlobalForbiddenErrorExample "Forbidden - admin only"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found or restore window expired"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /admin/users/{id}/restore [post]
+func (u *userHandler) AdminRestoreUser(ctx *gin.Context) {
+	u.restoreUser(ctx, ctx.Param("id"))
+}
+
+func (u *userHandler) restoreUser(ctx *gin.Context, userId string) {
+	if err := u.userService.RestoreUser(ctx.Request.Context(), userId); err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_RESTORE_USER)
+	ctx.JSON(http.StatusOK, res)
+}
+
 // @Summary Change Password
 // @Description Change Password based on its ID (from token)
 // @Tags User-Service
@@ -101,7 +258,7 @@ func (u *userHandler) DeleteUser(ctx *gin.Context) {
 // @Param Authorization header string true "Bearer token"
 // @Param request body dto.UpdatePasswordRequest true "Body Request"
 // @Success 200 {object} dto.ChangePasswordSuccessExample "Change Password Success"
-// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input, password and confirm_password doesn't match"
+// @Failure 400 {object} dto.PasswordPolicyResponse "Bad request - invalid input, password and confirm_password doesn't match, or the new password breaks the policy"
 // @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - token invalid, wrong password"
 // @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
 // @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
@@ -129,7 +286,13 @@ func (u *userHandler) ChangePassword(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	if err := u.userService.UpdatePassword(&req, userId); err != nil {
+	if err := u.userService.UpdatePassword(ctx.Request.Context(), &req, userId); err != nil {
+		var policyErr *dto.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			res := dto.PasswordPolicyResponse{StatusCode: 400, Message: err.Error(), Errors: policyErr.Violations}
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
 		switch err {
 		case dto.Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH:
 			res := utils.ReturnResponseError(400, err.Error())
@@ -187,7 +350,7 @@ func (u *userHandler) ChangeEmail(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	if err := u.userService.UpdateEmail(&req, userId); err != nil {
+	if err := u.userService.UpdateEmail(ctx.Request.Context(), &req, userId); err != nil {
 		res := utils.ReturnResponseError(500, "internal server error")
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
 		return
@@ -196,21 +359,21 @@ func (u *userHandler) ChangeEmail(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res)
 }
 
-// @Summary Update User
-// @Description Update User based on its ID (from token)
+// @Summary Verify Email Change
+// @Description Apply the pending email change of User based on its ID (from token) using the token sent by email
 // @Tags User-Service
-// @Accept multipart/form-data
+// @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer token"
-// @Param image formData file false "Profile image"
-// @Param request formData dto.UpdateUserRequest true "Body Request"
-// @Success 200 {object} dto.UpdateUserSuccessExample "Update user Success"
-// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input, wrong image extension, and limit image exceeded"
-// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Param request body dto.VerifyEmailChangeRequest true "Body Request"
+// @Success 200 {object} dto.VerifyEmailChangeSuccessExample "Verify Email Change Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - token invalid or expired"
 // @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Email is already used, or the user kept changing while the email was applied"
 // @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
-// @Router / [patch]
-func (u *userHandler) UpdateUser(ctx *gin.Context) {
+// @Router /email/verify [post]
+func (u *userHandler) VerifyEmailChange(ctx *gin.Context) {
 	userId := utils.GetUserId(ctx)
 	if userId == "" {
 		go func() {
@@ -222,9 +385,8 @@ func (u *userHandler) UpdateUser(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
 		return
 	}
-
-	var req dto.UpdateUserRequest
-	if err := ctx.ShouldBind(&req); err != nil {
+	var req dto.VerifyEmailChangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		go func() {
 			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
 				u.logger.Error().Err(err).Msg("failed to emit log")
@@ -234,47 +396,169 @@ func (u *userHandler) UpdateUser(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
 		return
 	}
-	if err := u.userService.UpdateUser(&req, userId); err != nil {
+	if err := u.userService.VerifyEmailChange(ctx.Request.Context(), &req, userId); err != nil {
 		switch err {
+		case dto.Err_UNAUTHORIZED_TOKEN_INVALID:
+			res := utils.ReturnResponseError(401, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
 		case dto.Err_NOTFOUND_USER_NOT_FOUND:
 			res := utils.ReturnResponseError(404, err.Error())
 			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
 			return
-		case dto.Err_BAD_REQUEST_WRONG_EXTENSION:
-			res := utils.ReturnResponseError(400, err.Error())
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		case dto.Err_CONFLICT_EMAIL_EXIST:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
 			return
-		case dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED:
-			res := utils.ReturnResponseError(400, err.Error())
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		// no If-Match was sent, the row kept changing while it was retried. the link stays valid
+		case dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
 			return
 		}
-		code := status.Code(err)
-		if code == codes.Internal {
-			res := utils.ReturnResponseError(500, "internal server error")
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_VERIFY_EMAIL)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Change Phone Number
+// @Description Send a 6 digit verification code by sms to the new phone number of User based on its ID (from token), the number is stored once it is verified
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.UpdatePhoneNumberRequest true "Body Request"
+// @Success 200 {object} dto.UpdatePhoneNumberSuccessExample "Verification code sent"
+// @Failure 400 {object} dto.InvalidFieldsResponse "Bad request - invalid phone number"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 429 {object} dto.GlobalTooManyRequestsExample "Too many codes requested"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /phone [patch]
+func (u *userHandler) ChangePhoneNumber(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.UpdatePhoneNumberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	phoneNumber, reason := dto.NormalizePhoneNumber(req.PhoneNumber)
+	if reason != "" {
+		res := dto.InvalidFieldsResponse{StatusCode: 400, Message: "invalid input", Errors: dto.FieldErrors{"phone_number": reason}}
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.UpdatePhoneNumber(ctx.Request.Context(), phoneNumber, userId); err != nil {
+		switch err {
+		case dto.Err_TOO_MANY_REQUESTS_OTP_SENT:
+			res := utils.ReturnResponseError(429, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
 			return
 		}
 		res := utils.ReturnResponseError(500, "internal server error")
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
 		return
 	}
-	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_UPDATE_PROFILE)
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_UPDATE_PHONE)
 	ctx.JSON(http.StatusOK, res)
 }
 
-// @Summary Get User Profile
-// @Description Get profile User based on its ID (from token)
+// @Summary Verify Phone Number
+// @Description Store the pending phone number of User based on its ID (from token) as verified using the code sent by sms
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.VerifyPhoneNumberRequest true "Body Request"
+// @Success 200 {object} dto.VerifyPhoneNumberSuccessExample "Verify Phone Number Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - code invalid or expired"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - the profile kept changing, the code stays valid"
+// @Failure 429 {object} dto.GlobalTooManyRequestsExample "Too many wrong codes, a new code has to be requested"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /phone/verify [post]
+func (u *userHandler) VerifyPhoneNumber(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.VerifyPhoneNumberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.VerifyPhoneNumber(ctx.Request.Context(), &req, userId); err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_OTP_INVALID:
+			res := utils.ReturnResponseError(401, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		case dto.Err_TOO_MANY_REQUESTS_OTP_ATTEMPTS:
+			res := utils.ReturnResponseError(429, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		// the profile kept changing while it was retried. the code stays valid
+		case dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_VERIFY_PHONE)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Setup TOTP
+// @Description Generate a new TOTP (RFC 6238) secret for User based on its ID (from token) and return it as an otpauth URI and a base64 PNG QR code. Two-factor authentication is only enabled once a code from it is confirmed
 // @Tags User-Service
-// @Accept */*
 // @Produce json
 // @Param Authorization header string true "Bearer token"
-// @Success 200 {object} dto.GetProfileSuccessExample "Get Profile Success"
+// @Success 200 {object} dto.SetupTOTPSuccessExample "Setup TOTP Success"
 // @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
-// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found
-// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error
-// @Router /me [get]
-func (u *userHandler) GetProfile(ctx *gin.Context) {
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - two-factor authentication is already enabled"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /2fa/totp/setup [post]
+func (u *userHandler) SetupTOTP(ctx *gin.Context) {
 	userId := utils.GetUserId(ctx)
 	if userId == "" {
 		go func() {
@@ -286,19 +570,687 @@ func (u *userHandler) GetProfile(ctx *gin.Context) {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
 		return
 	}
-	user, err := u.userService.GetProfile(userId)
+	setup, err := u.userService.SetupTOTP(ctx.Request.Context(), userId)
 	if err != nil {
 		switch err {
-		case dto.Err_INTERNAL_FAILED_BUILD_QUERY, dto.Err_INTERNAL_FAILED_SCAN_USER:
-			res := utils.ReturnResponseError(500, "internal server error")
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
-			return
 		case dto.Err_NOTFOUND_USER_NOT_FOUND:
 			res := utils.ReturnResponseError(404, err.Error())
 			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
 			return
+		case dto.Err_CONFLICT_TWO_FACTOR_ENABLED:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
 		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
 	}
-	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_GET_PROFILE, user)
+	// the response carries the secret
+	ctx.Header("Cache-Control", "no-store")
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_SETUP_TOTP, setup)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Enable TOTP
+// @Description Enable two-factor authentication for User based on its ID (from token) with a code from the secret returned by the setup. The recovery codes in the response are only shown once
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.EnableTOTPRequest true "Body Request"
+// @Success 200 {object} dto.EnableTOTPSuccessExample "Enable TOTP Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - code invalid"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found or no pending enrollment"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - two-factor authentication is already enabled"
+// @Failure 429 {object} dto.GlobalTooManyRequestsExample "Too many wrong codes"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /2fa/totp/enable [post]
+func (u *userHandler) EnableTOTP(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.EnableTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	recoveryCodes, err := u.userService.EnableTOTP(ctx.Request.Context(), &req, userId)
+	if err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_OTP_INVALID:
+			res := utils.ReturnResponseError(401, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND, dto.Err_NOTFOUND_TOTP_ENROLLMENT:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		case dto.Err_CONFLICT_TWO_FACTOR_ENABLED:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		case dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS:
+			res := utils.ReturnResponseError(429, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	// the response carries the recovery codes
+	ctx.Header("Cache-Control", "no-store")
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_ENABLE_TOTP, recoveryCodes)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Disable TOTP
+// @Description Disable two-factor authentication for User based on its ID (from token) with a current code or the password
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.DisableTOTPRequest true "Body Request"
+// @Success 200 {object} dto.DisableTOTPSuccessExample "Disable TOTP Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - code invalid or wrong password"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - two-factor authentication is not enabled"
+// @Failure 429 {object} dto.GlobalTooManyRequestsExample "Too many wrong codes"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /2fa/totp/disable [post]
+func (u *userHandler) DisableTOTP(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.DisableTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.DisableTOTP(ctx.Request.Context(), &req, userId); err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_OTP_INVALID, dto.Err_UNAUTHORIZED_PASSWORD_WRONG:
+			res := utils.ReturnResponseError(401, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		case dto.Err_CONFLICT_TWO_FACTOR_DISABLED, dto.Err_CONFLICT_TWO_FACTOR_REENROLL:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		case dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS:
+			res := utils.ReturnResponseError(429, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_DISABLE_TOTP)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replace the two-factor recovery codes of User based on its ID (from token), authorized with a current code or the password. The previous codes stop working
+// @Tags User-Service
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.RegenerateRecoveryCodesRequest true "Body Request"
+// @Success 200 {object} dto.RegenerateRecoveryCodesSuccessExample "Regenerate Recovery Codes Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized - code invalid or wrong password"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - two-factor authentication is not enabled"
+// @Failure 429 {object} dto.GlobalTooManyRequestsExample "Too many wrong codes"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /2fa/recovery-codes [post]
+func (u *userHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.RegenerateRecoveryCodesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	recoveryCodes, err := u.userService.RegenerateRecoveryCodes(ctx.Request.Context(), &req, userId)
+	if err != nil {
+		switch err {
+		case dto.Err_UNAUTHORIZED_OTP_INVALID, dto.Err_UNAUTHORIZED_PASSWORD_WRONG:
+			res := utils.ReturnResponseError(401, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		case dto.Err_CONFLICT_TWO_FACTOR_DISABLED, dto.Err_CONFLICT_TWO_FACTOR_REENROLL:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		case dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS:
+			res := utils.ReturnResponseError(429, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	// the response carries the recovery codes
+	ctx.Header("Cache-Control", "no-store")
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_RECOVERY_CODES, recoveryCodes)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Count recovery codes
+// @Description Get the number of unused two-factor recovery codes of User based on its ID (from token)
+// @Tags User-Service
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.CountRecoveryCodesSuccessExample "Count Recovery Codes Success"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /2fa/recovery-codes [get]
+func (u *userHandler) CountRecoveryCodes(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	count, err := u.userService.CountRecoveryCodes(ctx.Request.Context(), userId)
+	if err != nil {
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_COUNT_RECOVERY, count)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Update User
+// @Description Partially update User based on its ID (from token). Absent fields are left unchanged, a JSON merge patch (RFC 7396) can remove the avatar with "image": null and the optional profile fields with null, a multipart form removes them with an empty value
+// @Tags User-Service
+// @Accept multipart/form-data,application/merge-patch+json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param If-Match header string true "ETag from GET /me"
+// @Param image formData file false "Profile image"
+// @Param request formData dto.UpdateUserRequest false "Body Request"
+// @Success 200 {object} dto.UpdateUserSuccessExample "Update user Success, returns the updated profile"
+// @Failure 400 {object} dto.InvalidFieldsResponse "Bad request - invalid fields, wrong image extension, and limit image exceeded"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 409 {object} dto.GlobalConflictErrorExample "Conflict - username is already taken"
+// @Failure 412 {object} dto.GlobalInvalidInputExample "Precondition failed - user was modified since it was read"
+// @Failure 415 {object} dto.GlobalInvalidInputExample "Unsupported content type"
+// @Failure 428 {object} dto.GlobalInvalidInputExample "Precondition required - missing If-Match"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router / [patch]
+func (u *userHandler) UpdateUser(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+
+	expectedVersions, ok := requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+	req, fieldErrs, err := bindUpdateUserRequest(ctx)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		if err == dto.Err_UNSUPPORTED_MEDIA_TYPE {
+			res := utils.ReturnResponseError(415, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, res)
+			return
+		}
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if len(fieldErrs) > 0 {
+		res := dto.InvalidFieldsResponse{StatusCode: 400, Message: "invalid input", Errors: fieldErrs}
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	req.ExpectedVersions = expectedVersions
+	profile, err := u.userService.UpdateUser(ctx.Request.Context(), req, userId)
+	if err != nil {
+		switch err {
+		case dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH:
+			res := utils.ReturnResponseError(412, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		case dto.Err_CONFLICT_USERNAME_EXIST:
+			res := utils.ReturnResponseError(409, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusConflict, res)
+			return
+		case dto.Err_BAD_REQUEST_WRONG_EXTENSION:
+			res := utils.ReturnResponseError(400, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		case dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED, dto.Err_BAD_REQUEST_UNSUPPORTED_IMAGE, dto.Err_BAD_REQUEST_CORRUPT_IMAGE, dto.Err_BAD_REQUEST_IMAGE_DIMENSIONS_EXCEEDED:
+			res := utils.ReturnResponseError(400, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
+		code := status.Code(err)
+		if code == codes.Internal {
+			res := utils.ReturnResponseError(500, "internal server error")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	ctx.Header("ETag", formatETag(profile.Version))
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_UPDATE_PROFILE, profile)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// a merge patch is decoded by hand because null and an absent member mean different things
+func bindUpdateUserRequest(ctx *gin.Context) (*dto.UpdateUserRequest, dto.FieldErrors, error) {
+	switch ctx.ContentType() {
+	case mimeMergePatch, binding.MIMEJSON:
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxMergePatchSize))
+		if err != nil {
+			return nil, nil, err
+		}
+		req, fieldErrs := dto.DecodeUserMergePatch(body)
+		return req, fieldErrs, nil
+	case binding.MIMEMultipartPOSTForm, binding.MIMEPOSTForm:
+		var req dto.UpdateUserRequest
+		if err := ctx.ShouldBind(&req); err != nil {
+			return nil, nil, err
+		}
+		fieldErrs := req.Validate()
+		if _, ok := ctx.GetPostForm("two_factor_enabled"); ok {
+			fieldErrs["two_factor_enabled"] = dto.TwoFactorEnabledReadOnly
+		}
+		return &req, fieldErrs, nil
+	}
+	return nil, nil, dto.Err_UNSUPPORTED_MEDIA_TYPE
+}
+
+// @Summary Update Avatar
+// @Description Replace the avatar of the User based on its ID (from token), the image is stored in 64, 256 and 512 px square variants
+// @Tags User-Service
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param image formData file true "Profile image"
+// @Success 200 {object} dto.UpdateAvatarSuccessExample "Update avatar Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - missing image, wrong extension, limit exceeded, unsupported or corrupt image"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /avatar [put]
+func (u *userHandler) UpdateAvatar(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.UpdateAvatarRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	if err := u.userService.UpdateAvatar(ctx.Request.Context(), req.Image, userId); err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		case dto.Err_BAD_REQUEST_WRONG_EXTENSION, dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED, dto.Err_BAD_REQUEST_UNSUPPORTED_IMAGE, dto.Err_BAD_REQUEST_CORRUPT_IMAGE, dto.Err_BAD_REQUEST_IMAGE_DIMENSIONS_EXCEEDED:
+			res := utils.ReturnResponseError(400, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_UPDATE_AVATAR)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Delete Avatar
+// @Description Remove the avatar of the User based on its ID (from token), the stored files are deleted from the file service
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.DeleteAvatarSuccessExample "Delete avatar Success"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /avatar [delete]
+func (u *userHandler) DeleteAvatar(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	if err := u.userService.DeleteAvatar(ctx.Request.Context(), userId); err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_DELETE_AVATAR)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Get Avatar
+// @Description Redirect to the avatar of the User based on its ID (from token) as served by the file service, or to the generated default avatar
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request query dto.GetAvatarRequest false "Query Params"
+// @Success 302 "Redirect to the image"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid size"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /avatar [get]
+func (u *userHandler) GetAvatar(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.GetAvatarRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := utils.ReturnResponseError(400, dto.Err_BAD_REQUEST_INVALID_AVATAR_SIZE.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	location, err := u.userService.GetAvatarURL(ctx.Request.Context(), userId, req.Size)
+	if err != nil {
+		switch err {
+		case dto.Err_BAD_REQUEST_INVALID_AVATAR_SIZE:
+			res := utils.ReturnResponseError(400, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	// the file name changes with every upload, so the redirect itself must not be cached
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Redirect(http.StatusFound, location)
+}
+
+// @Summary Get Default Avatar
+// @Description Get the generated avatar of a User, an identicon derived from the user ID only
+// @Tags User-Service
+// @Accept */*
+// @Produce image/svg+xml
+// @Param id path string true "User ID"
+// @Success 200 {file} file "Generated SVG avatar"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /avatar/default/{id} [get]
+func (u *userHandler) GetDefaultAvatar(ctx *gin.Context) {
+	userId := ctx.Param("id")
+	svg, err := u.userService.GetDefaultAvatar(ctx.Request.Context(), userId)
+	if err != nil {
+		switch err {
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	// the identicon only depends on the id, it is still not immutable since a deleted user returns 404.
+	// the policy keeps a browser from running anything embedded in the svg when it is opened directly
+	ctx.Header("Cache-Control", "public, max-age=86400")
+	ctx.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Data(http.StatusOK, "image/svg+xml", svg)
+}
+
+// @Summary Check Username Availability
+// @Description Check if a username can be set by the User based on its ID (from token), the username the User already has counts as available
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param username query string true "Username to check"
+// @Success 200 {object} dto.UsernameAvailabilitySuccessExample "Check username Success"
+// @Failure 400 {object} dto.InvalidFieldsResponse "Bad request - missing or invalid username"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /username/availability [get]
+func (u *userHandler) CheckUsernameAvailability(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	var req dto.UsernameAvailabilityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		res := dto.InvalidFieldsResponse{StatusCode: 400, Message: "invalid input", Errors: dto.FieldErrors{"username": "is required"}}
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	// a malformed or reserved name is reported as invalid instead of unavailable, so the client can tell the user why
+	if reason := dto.ValidateUsername(req.Username); reason != "" {
+		res := dto.InvalidFieldsResponse{StatusCode: 400, Message: "invalid input", Errors: dto.FieldErrors{"username": reason}}
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	availability, err := u.userService.CheckUsernameAvailability(ctx.Request.Context(), req.Username, userId)
+	if err != nil {
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	// availability changes as other users pick names
+	ctx.Header("Cache-Control", "no-store")
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_CHECK_USERNAME, availability)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary Get User Profile
+// @Description Get profile User based on its ID (from token)
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.GetProfileSuccessExample "Get Profile Success, the ETag header is required as If-Match by PATCH / and DELETE /"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 404 {object} dto.GlobalUserNotFoundExample "User not found
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error
+// @Router /me [get]
+func (u *userHandler) GetProfile(ctx *gin.Context) {
+	userId := utils.GetUserId(ctx)
+	if userId == "" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("unathorized. userId is not found. userId: %s", userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(401, dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, res)
+		return
+	}
+	user, err := u.userService.GetProfile(ctx.Request.Context(), userId)
+	if err != nil {
+		switch err {
+		case dto.Err_INTERNAL_FAILED_BUILD_QUERY, dto.Err_INTERNAL_FAILED_SCAN_USER:
+			res := utils.ReturnResponseError(500, "internal server error")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+			return
+		case dto.Err_NOTFOUND_USER_NOT_FOUND:
+			res := utils.ReturnResponseError(404, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusNotFound, res)
+			return
+		}
+	}
+	// profiles cached before versions existed have none
+	if user.Version > 0 {
+		ctx.Header("ETag", formatETag(user.Version))
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_GET_PROFILE, user)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// @Summary List Users (Admin)
+// @Description List Users with cursor pagination, filters and sorting
+// @Tags User-Service
+// @Accept */*
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request query dto.ListUsersRequest false "Query Params"
+// @Success 200 {object} dto.ListUsersSuccessExample "List Users Success"
+// @Failure 400 {object} dto.GlobalInvalidInputExample "Bad request - invalid input, invalid cursor"
+// @Failure 401 {object} dto.GlobalUnauthorizedErrorExample "Unauthorized"
+// @Failure 403 {object} dto.GlobalForbiddenErrorExample "Forbidden - admin only"
+// @Failure 500 {object} dto.GlobalInternalServerErrorExample "Internal server error"
+// @Router /admin/users [get]
+func (u *userHandler) ListUsers(ctx *gin.Context) {
+	var req dto.ListUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("bad request: Err:%s", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		res := utils.ReturnResponseError(400, "invalid input")
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		return
+	}
+	users, err := u.userService.ListUsers(ctx.Request.Context(), &req)
+	if err != nil {
+		switch err {
+		case dto.Err_BAD_REQUEST_INVALID_CURSOR:
+			res := utils.ReturnResponseError(400, err.Error())
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+			return
+		}
+		res := utils.ReturnResponseError(500, "internal server error")
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, res)
+		return
+	}
+	res := utils.ReturnResponseSuccess(200, dto.SUCCESS_LIST_USERS, users)
 	ctx.JSON(http.StatusOK, res)
 }
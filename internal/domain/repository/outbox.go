@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+	"github.com/micros-template/user-service/internal/infrastructure/logger"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/rs/zerolog"
+)
+
+type (
+	OutboxRepository interface {
+		Enqueue(c context.Context, msgs ...*dto.OutboxMessage) error
+		ClaimPending(c context.Context, limit uint64, maxAttempts int, leaseUntil time.Time) ([]dto.OutboxMessage, error)
+		MarkSent(c context.Context, id int64) error
+		MarkFailed(c context.Context, id int64, reason string, nextAttemptAt time.Time) error
+		Release(c context.Context, id int64) error
+		DeadLetterExhausted(c context.Context, maxAttempts int) ([]dto.OutboxMessage, error)
+		WithQuerier(q _db.Querier) OutboxRepository
+	}
+	outboxRepository struct {
+		pgx        _db.Querier
+		logger     zerolog.Logger
+		logEmitter logger.LoggerInfra
+	}
+)
+
+func NewOutboxRepository(pgx _db.Querier, logEmitter logger.LoggerInfra, logger zerolog.Logger) OutboxRepository {
+	return &outboxRepository{
+		pgx:        pgx,
+		logger:     logger,
+		logEmitter: logEmitter,
+	}
+}
+
+func (o *outboxRepository) WithQuerier(q _db.Querier) OutboxRepository {
+	return &outboxRepository{
+		pgx:        q,
+		logger:     o.logger,
+		logEmitter: o.logEmitter,
+	}
+}
+
+func (o *outboxRepository) Enqueue(c context.Context, msgs ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	return insertOutboxMessages(ctx, o.pgx, o.logEmitter, o.logger, msgs)
+}
+
+func (o *outboxRepository) ClaimPending(c context.Context, limit uint64, maxAttempts int, leaseUntil time.Time) ([]dto.OutboxMessage, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	// claimed rows are hidden from other relays until the lease expires, so a crashed relay only delays them.
+	// messages of a subject are delivered in order, a row waits while an earlier one of its subject is
+	// backing off, leased or about to be dead-lettered
+	query, args, err := sq.Update("outbox").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("next_attempt_at", leaseUntil).
+		Where(sq.Expr("id IN (SELECT id FROM outbox o WHERE sent_at IS NULL AND dead_at IS NULL AND attempts < ? AND next_attempt_at <= CURRENT_TIMESTAMP "+
+			"AND NOT EXISTS (SELECT 1 FROM outbox prev WHERE prev.subject = o.subject AND prev.id < o.id AND prev.sent_at IS NULL AND prev.dead_at IS NULL "+
+			"AND (prev.attempts >= ? OR prev.next_attempt_at > CURRENT_TIMESTAMP)) "+
+			"ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED)", maxAttempts, maxAttempts, limit)).
+		Suffix("RETURNING id, subject, payload, attempts").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := o.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX
+	}
+	defer rows.Close()
+
+	msgs := make([]dto.OutboxMessage, 0, limit)
+	for rows.Next() {
+		var msg dto.OutboxMessage
+		if err := rows.Scan(&msg.ID, &msg.Subject, &msg.Payload, &msg.Attempts); err != nil {
+			go func() {
+				if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX.Error()); err != nil {
+					o.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX
+	}
+	return msgs, nil
+}
+
+func (o *outboxRepository) MarkSent(c context.Context, id int64) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("outbox").
+		Set("sent_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("last_error", nil).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := o.pgx.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. id: %d", dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX.Error(), id)); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX
+	}
+	return nil
+}
+
+func (o *outboxRepository) MarkFailed(c context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("outbox").
+		Set("last_error", reason).
+		Set("next_attempt_at", nextAttemptAt).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := o.pgx.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. id: %d", dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX.Error(), id)); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX
+	}
+	return nil
+}
+
+// hands a claimed row back without spending its attempt, used for rows held back behind a failed one of their subject
+func (o *outboxRepository) Release(c context.Context, id int64) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("outbox").
+		Set("attempts", sq.Expr("attempts - 1")).
+		Set("next_attempt_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := o.pgx.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. id: %d", dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX.Error(), id)); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX
+	}
+	return nil
+}
+
+// rows out of attempts are never claimed again. once their last lease or backoff is over they are moved
+// to the dead-letter state and returned so the relay can report them, a row is only returned once
+func (o *outboxRepository) DeadLetterExhausted(c context.Context, maxAttempts int) ([]dto.OutboxMessage, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("outbox").
+		Set("dead_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"sent_at": nil, "dead_at": nil}).
+		Where(sq.GtOrEq{"attempts": maxAttempts}).
+		Where(sq.Expr("next_attempt_at <= CURRENT_TIMESTAMP")).
+		Suffix("RETURNING id, subject, attempts, last_error").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := o.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX
+	}
+	defer rows.Close()
+
+	var msgs []dto.OutboxMessage
+	for rows.Next() {
+		var msg dto.OutboxMessage
+		if err := rows.Scan(&msg.ID, &msg.Subject, &msg.Attempts, &msg.LastError); err != nil {
+			go func() {
+				if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX.Error()); err != nil {
+					o.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX.Error()); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX
+	}
+	return msgs, nil
+}
+
+func insertOutboxMessages(ctx context.Context, q _db.Querier, logEmitter logger.LoggerInfra, log zerolog.Logger, msgs []*dto.OutboxMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	builder := sq.Insert("outbox").Columns("subject", "payload")
+	for _, msg := range msgs {
+		builder = builder.Values(msg.Subject, msg.Payload)
+	}
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		go func() {
+			if err := logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				log.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := q.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_INSERT_OUTBOX.Error()); err != nil {
+				log.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_INSERT_OUTBOX
+	}
+	return nil
+}
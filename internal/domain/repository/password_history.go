@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// the hashes of the passwords used before the current one, newest first
+func (a *userRepository) QueryPasswordHistory(c context.Context, userId string, limit int) ([]string, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("password_hash").
+		From("password_history").
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := a.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_HISTORY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_HISTORY
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_HISTORY.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_QUERY_HISTORY
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_HISTORY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_HISTORY
+	}
+	return hashes, nil
+}
+
+// saves the new password and moves previousHash into the history, keeping only the newest keep entries.
+// only the password column is written so profile edits made while the password was checked are kept
+func (a *userRepository) UpdateUserPassword(c context.Context, userId, passwordHash, previousHash string, keep int, outbox ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	return a.withTx(ctx, func(q _db.Querier) error {
+		if err := a.updatePassword(ctx, q, userId, passwordHash); err != nil {
+			return err
+		}
+		if err := a.savePasswordHistory(ctx, q, userId, previousHash, keep); err != nil {
+			return err
+		}
+		return insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox)
+	})
+}
+
+func (a *userRepository) updatePassword(ctx context.Context, q _db.Querier, userId, passwordHash string) error {
+	query, args, err := sq.Update("users").
+		Set("password", passwordHash).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": userId}).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	cmdTag, err := q.Exec(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_UPDATE_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_UPDATE_USER
+	}
+	if cmdTag.RowsAffected() == 0 {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_NOTFOUND_USER_NOT_FOUND.Error(), userId)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_NOTFOUND_USER_NOT_FOUND
+	}
+	return nil
+}
+
+func (a *userRepository) savePasswordHistory(ctx context.Context, q _db.Querier, userId, previousHash string, keep int) error {
+	if keep > 0 {
+		query, args, err := sq.Insert("password_history").
+			Columns("user_id", "password_hash").
+			Values(userId, previousHash).
+			PlaceholderFormat(sq.Dollar).
+			ToSql()
+		if err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+		}
+		if _, err := q.Exec(ctx, query, args...); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_HISTORY.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.Err_INTERNAL_FAILED_SAVE_HISTORY
+		}
+	}
+
+	// also clears entries left over from a larger history size
+	query, args, err := sq.Delete("password_history").
+		Where(sq.Eq{"user_id": userId}).
+		Where(sq.Expr("id NOT IN (SELECT id FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?)", userId, keep)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := q.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_HISTORY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_SAVE_HISTORY
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/infrastructure/cache"
+	"github.com/micros-template/user-service/internal/infrastructure/logger"
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
+)
+
+type (
+	ProfileCacheRepository interface {
+		QueryProfileByUserId(c context.Context, userId string) (*dto.UserProfile, error)
+		Invalidate(c context.Context, userId string) error
+	}
+	profileCacheRepository struct {
+		userRepository UserRepository
+		redisClient    cache.RedisCache
+		group          singleflight.Group
+		metrics        *metrics.Metrics
+		logger         zerolog.Logger
+		logEmitter     logger.LoggerInfra
+	}
+	// password hash is left out on purpose, it must never be stored in redis
+	cachedProfile struct {
+		ID               string            `json:"id"`
+		FullName         string            `json:"full_name"`
+		Image            *string           `json:"image"`
+		Email            string            `json:"email"`
+		Verified         bool              `json:"verified"`
+		TwoFactorEnabled bool              `json:"two_factor_enabled"`
+		ImageVariants    dto.ImageVariants `json:"image_variants"`
+		Version          int64             `json:"version"`
+		Username         *string           `json:"username"`
+		Bio              *string           `json:"bio"`
+		Locale           *string           `json:"locale"`
+		Timezone         *string           `json:"timezone"`
+		PhoneNumber      *string           `json:"phone_number"`
+		PhoneVerified    bool              `json:"phone_verified"`
+		DateOfBirth      *time.Time        `json:"date_of_birth"`
+	}
+)
+
+const profileCacheName = "profile"
+
+func NewProfileCacheRepository(userRepository UserRepository, r cache.RedisCache, m *metrics.Metrics, logEmitter logger.LoggerInfra, logger zerolog.Logger) ProfileCacheRepository {
+	return &profileCacheRepository{
+		userRepository: userRepository,
+		redisClient:    r,
+		metrics:        m,
+		logger:         logger,
+		logEmitter:     logEmitter,
+	}
+}
+
+// read-through lookup, the returned user never carries the password hash
+func (p *profileCacheRepository) QueryProfileByUserId(c context.Context, userId string) (*dto.UserProfile, error) {
+	key := profileCacheKey(userId)
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	value, err := p.redisClient.Get(ctx, key)
+	cancel()
+	if err == nil {
+		var profile cachedProfile
+		if err := json.Unmarshal([]byte(value), &profile); err == nil {
+			p.metrics.CacheLookup(profileCacheName, true)
+			return profile.toProfile(), nil
+		}
+		p.logger.Warn().Str("key", key).Msg("dropping undecodable cached profile")
+	} else if !errors.Is(err, redis.Nil) {
+		// redis being down degrades to plain database reads
+		p.logger.Warn().Err(err).Str("key", key).Msg("failed to read cached profile")
+	}
+	p.metrics.CacheLookup(profileCacheName, false)
+
+	// concurrent misses for the same user share a single database query
+	ch := p.group.DoChan(userId, func() (interface{}, error) {
+		return p.load(context.WithoutCancel(c), userId)
+	})
+	select {
+	case <-c.Done():
+		return nil, c.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*cachedProfile).toProfile(), nil
+	}
+}
+
+func (p *profileCacheRepository) load(c context.Context, userId string) (*cachedProfile, error) {
+	// read before the database so an invalidation that lands while loading is noticed afterwards
+	generation, genErr := p.generation(c, userId)
+	user, err := p.userRepository.QueryProfileByUserId(c, userId)
+	if err != nil {
+		return nil, err
+	}
+	profile := &cachedProfile{
+		ID:               user.ID,
+		FullName:         user.FullName,
+		Image:            user.Image,
+		Email:            user.Email,
+		Verified:         user.Verified,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		ImageVariants:    user.ImageVariants,
+		Version:          user.Version,
+		Username:         user.Username,
+		Bio:              user.Bio,
+		Locale:           user.Locale,
+		Timezone:         user.Timezone,
+		PhoneNumber:      user.PhoneNumber,
+		PhoneVerified:    user.PhoneVerified,
+		DateOfBirth:      user.DateOfBirth,
+	}
+	value, err := json.Marshal(profile)
+	if err != nil || genErr != nil {
+		// without the generation an overlapping invalidation cannot be detected, so nothing is cached
+		return profile, nil
+	}
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+	if err := p.redisClient.Set(ctx, profileCacheKey(userId), value, viper.GetDuration("app.cache.profile_ttl")); err != nil {
+		p.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to cache profile")
+		return profile, nil
+	}
+	// an invalidation ran between the read and the SET, the stored row may predate the update
+	if current, err := p.generation(c, userId); err != nil || current != generation {
+		if err := p.redisClient.Delete(ctx, profileCacheKey(userId)); err != nil {
+			p.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to drop profile cached during invalidation")
+		}
+	}
+	return profile, nil
+}
+
+// bumped by every invalidation, possibly from another instance. A missing key is generation ""
+func (p *profileCacheRepository) generation(c context.Context, userId string) (string, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+	value, err := p.redisClient.Get(ctx, profileGenerationKey(userId))
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (p *profileCacheRepository) Invalidate(c context.Context, userId string) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+
+	// loads already past their database read see the new generation and drop what they stored
+	if _, err := p.redisClient.Incr(ctx, profileGenerationKey(userId), viper.GetDuration("app.cache.profile_ttl")); err != nil {
+		p.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to bump profile cache generation")
+	}
+	// forget an in-flight load so it cannot hand the pre-update row to later callers
+	p.group.Forget(userId)
+	if err := p.redisClient.Delete(ctx, profileCacheKey(userId)); err != nil {
+		go func() {
+			if err := p.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_INTERNAL_DELETE_RESOURCE.Error(), userId)); err != nil {
+				p.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_DELETE_RESOURCE
+	}
+	return nil
+}
+
+func (c *cachedProfile) toProfile() *dto.UserProfile {
+	return &dto.UserProfile{
+		User: model.User{
+			ID:               c.ID,
+			FullName:         c.FullName,
+			Image:            c.Image,
+			Email:            c.Email,
+			Verified:         c.Verified,
+			TwoFactorEnabled: c.TwoFactorEnabled,
+		},
+		ImageVariants: c.ImageVariants,
+		ProfileFields: dto.ProfileFields{
+			Username:      c.Username,
+			Bio:           c.Bio,
+			Locale:        c.Locale,
+			Timezone:      c.Timezone,
+			PhoneNumber:   c.PhoneNumber,
+			PhoneVerified: c.PhoneVerified,
+			DateOfBirth:   c.DateOfBirth,
+		},
+		Version: c.Version,
+	}
+}
+
+func profileCacheKey(userId string) string {
+	return fmt.Sprintf("userProfile:%s", userId)
+}
+
+func profileGenerationKey(userId string) string {
+	return fmt.Sprintf("userProfileGeneration:%s", userId)
+}
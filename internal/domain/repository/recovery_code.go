@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (a *userRepository) QueryRecoveryCodes(c context.Context, userId string) ([]dto.RecoveryCode, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("id", "code_hash").
+		From("recovery_codes").
+		Where(sq.Eq{"user_id": userId}).
+		Where(sq.Eq{"used_at": nil}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := a.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_RECOVERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_RECOVERY
+	}
+	defer rows.Close()
+
+	var codes []dto.RecoveryCode
+	for rows.Next() {
+		var code dto.RecoveryCode
+		if err := rows.Scan(&code.ID, &code.CodeHash); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_RECOVERY.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_QUERY_RECOVERY
+		}
+		codes = append(codes, code)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_RECOVERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_RECOVERY
+	}
+	return codes, nil
+}
+
+func (a *userRepository) CountRecoveryCodes(c context.Context, userId string) (int, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("COUNT(*)").
+		From("recovery_codes").
+		Where(sq.Eq{"user_id": userId}).
+		Where(sq.Eq{"used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return 0, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	var remaining int
+	if err := a.pgx.QueryRow(ctx, query, args...).Scan(&remaining); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_RECOVERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return 0, dto.Err_INTERNAL_FAILED_QUERY_RECOVERY
+	}
+	return remaining, nil
+}
+
+// the previous set is dropped, used or not, so a leaked list stops working
+func (a *userRepository) ReplaceRecoveryCodes(c context.Context, userId string, codeHashes []string) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	return a.withTx(ctx, func(q _db.Querier) error {
+		return a.replaceRecoveryCodes(ctx, q, userId, codeHashes)
+	})
+}
+
+// a code is burned with a guarded update so two concurrent logins can not both spend it
+func (a *userRepository) UseRecoveryCode(c context.Context, codeId int64) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("recovery_codes").
+		Set("used_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": codeId}).
+		Where(sq.Eq{"used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	cmdTag, err := a.pgx.Exec(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_RECOVERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_SAVE_RECOVERY
+	}
+	if cmdTag.RowsAffected() == 0 {
+		go func() {
+			if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. recovery_code_id: %d", dto.Err_UNAUTHORIZED_RECOVERY_INVALID.Error(), codeId)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_UNAUTHORIZED_RECOVERY_INVALID
+	}
+	return nil
+}
+
+func (a *userRepository) replaceRecoveryCodes(ctx context.Context, q _db.Querier, userId string, codeHashes []string) error {
+	query, args, err := sq.Delete("recovery_codes").
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := q.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_RECOVERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_SAVE_RECOVERY
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	insert := sq.Insert("recovery_codes").Columns("user_id", "code_hash")
+	for _, codeHash := range codeHashes {
+		insert = insert.Values(userId, codeHash)
+	}
+	query, args, err = insert.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if _, err := q.Exec(ctx, query, args...); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_RECOVERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_SAVE_RECOVERY
+	}
+	return nil
+}
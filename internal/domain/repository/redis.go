@@ -2,18 +2,26 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/infrastructure/cache"
 	"github.com/micros-template/user-service/internal/infrastructure/logger"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 )
 
 type (
 	RedisRepository interface {
 		SetResource(context.Context, string, string, time.Duration) error
+		GetResource(context.Context, string) (string, error)
+		TakeResource(context.Context, string) (string, error)
+		RemoveResource(context.Context, string) error
+		IncrementResource(context.Context, string, time.Duration) (int64, error)
 	}
 	redisRepository struct {
 		redisClient cache.RedisCache
@@ -31,7 +39,10 @@ func NewRedisRepository(r cache.RedisCache, logEmitter logger.LoggerInfra, logge
 }
 
 func (a *redisRepository) SetResource(c context.Context, key, value string, duration time.Duration) error {
-	err := a.redisClient.Set(c, key, value, duration)
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+
+	err := a.redisClient.Set(ctx, key, value, duration)
 	if err != nil {
 		go func() {
 			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_SET_RESOURCE.Error()); err != nil {
@@ -42,3 +53,74 @@ func (a *redisRepository) SetResource(c context.Context, key, value string, dura
 	}
 	return nil
 }
+
+func (a *redisRepository) GetResource(c context.Context, key string) (string, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+
+	value, err := a.redisClient.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", dto.Err_NOTFOUND_KEY_NOTFOUND
+		}
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_GET_RESOURCE.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return "", dto.Err_INTERNAL_GET_RESOURCE
+	}
+	return value, nil
+}
+
+// reads and removes the key in one step, a single-use token taken here cannot be used by a concurrent request
+func (a *redisRepository) TakeResource(c context.Context, key string) (string, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+
+	value, err := a.redisClient.GetDel(ctx, key)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", dto.Err_NOTFOUND_KEY_NOTFOUND
+		}
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_GET_RESOURCE.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return "", dto.Err_INTERNAL_GET_RESOURCE
+	}
+	return value, nil
+}
+
+func (a *redisRepository) RemoveResource(c context.Context, key string) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+
+	if err := a.redisClient.Delete(ctx, key); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_DELETE_RESOURCE.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_DELETE_RESOURCE
+	}
+	return nil
+}
+
+// counts within a fixed window that starts with the first increment
+func (a *redisRepository) IncrementResource(c context.Context, key string, window time.Duration) (int64, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_CACHE)
+	defer cancel()
+
+	count, err := a.redisClient.Incr(ctx, key, window)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_SET_RESOURCE.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return 0, dto.Err_INTERNAL_SET_RESOURCE
+	}
+	return count, nil
+}
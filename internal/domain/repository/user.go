@@ -4,23 +4,56 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	_db "github.com/micros-template/user-service/internal/infrastructure/database"
 	"github.com/micros-template/user-service/internal/infrastructure/logger"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/micros-template/sharedlib/model"
 	"github.com/rs/zerolog"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// unique index on LOWER(username), see migration 0005
+const usernameUniqueIndex = "users_username_key"
+
 type (
 	UserRepository interface {
-		CreateNewUser(*model.User) error
-		QueryUserByUserId(string) (*model.User, error)
-		UpdateUser(*model.User) error
-		DeleteUser(userId string) error
+		CreateNewUser(c context.Context, user *model.User, outbox ...*dto.OutboxMessage) error
+		QueryUserByUserId(c context.Context, userId string) (*model.User, error)
+		QueryProfileByUserId(c context.Context, userId string) (*dto.UserProfile, error)
+		QueryUserByEmail(c context.Context, email string) (*model.User, error)
+		QueryUsersByIds(c context.Context, userIds []string) ([]*model.User, error)
+		ListUsers(c context.Context, q *dto.ListUsersQuery) ([]dto.UserListItem, error)
+		UpdateUser(c context.Context, user *model.User, expectedVersion int64, outbox ...*dto.OutboxMessage) error
+		UpdateUserImage(c context.Context, user *model.User, variants dto.ImageVariants, expectedVersion int64, outbox ...*dto.OutboxMessage) error
+		UpdateProfile(c context.Context, profile *dto.UserProfile, expectedVersion int64, outbox ...*dto.OutboxMessage) error
+		IsUsernameTaken(c context.Context, username string, excludeUserId string) (bool, error)
+		QueryTOTPSecret(c context.Context, userId string) ([]byte, error)
+		UpdateTOTPSecret(c context.Context, userId string, sealedSecret []byte, recoveryCodeHashes []string, outbox ...*dto.OutboxMessage) error
+		QueryRecoveryCodes(c context.Context, userId string) ([]dto.RecoveryCode, error)
+		CountRecoveryCodes(c context.Context, userId string) (int, error)
+		ReplaceRecoveryCodes(c context.Context, userId string, codeHashes []string) error
+		UseRecoveryCode(c context.Context, codeId int64) error
+		QueryWebAuthnCredentials(c context.Context, userId string) ([]dto.WebAuthnCredential, error)
+		CreateWebAuthnCredential(c context.Context, credential *dto.WebAuthnCredential) error
+		RenameWebAuthnCredential(c context.Context, userId string, id int64, name string) error
+		DeleteWebAuthnCredential(c context.Context, userId string, id int64) error
+		UpdateWebAuthnCredentialUsage(c context.Context, usage *dto.WebAuthnCredentialUsage) error
+		QueryPasswordHistory(c context.Context, userId string, limit int) ([]string, error)
+		UpdateUserPassword(c context.Context, userId, passwordHash, previousHash string, keep int, outbox ...*dto.OutboxMessage) error
+		DeleteUser(c context.Context, userId string) error
+		RestoreUser(c context.Context, userId string, deletedAfter time.Time) error
+		PurgeDeletedUsers(c context.Context, deletedBefore time.Time, newEvent func(userId string) (*dto.OutboxMessage, error)) ([]string, error)
+		WithQuerier(q _db.Querier) UserRepository
 	}
 	userRepository struct {
 		pgx        _db.Querier
@@ -37,10 +70,23 @@ func NewUserRepository(pgx _db.Querier, logEmitter logger.LoggerInfra, logger ze
 	}
 }
 
-func (a *userRepository) DeleteUser(userId string) error {
-	// [IMRPOVE] change to soft delete
-	query, args, err := sq.Delete("users").
+// bind the repository to q, typically the querier handed out by TxManager.WithTx
+func (a *userRepository) WithQuerier(q _db.Querier) UserRepository {
+	return &userRepository{
+		pgx:        q,
+		logger:     a.logger,
+		logEmitter: a.logEmitter,
+	}
+}
+
+func (a *userRepository) DeleteUser(c context.Context, userId string) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("users").
+		Set("deleted_at", sq.Expr("CURRENT_TIMESTAMP")).
 		Where(sq.Eq{"id": userId}).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -52,7 +98,7 @@ func (a *userRepository) DeleteUser(userId string) error {
 		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
 	}
 
-	cmdTag, err := a.pgx.Exec(context.Background(), query, args...)
+	cmdTag, err := a.pgx.Exec(ctx, query, args...)
 	if err != nil {
 		go func() {
 			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_DELETE_USER.Error()); err != nil {
@@ -72,18 +118,214 @@ func (a *userRepository) DeleteUser(userId string) error {
 	return nil
 }
 
-func (a *userRepository) UpdateUser(user *model.User) error {
+func (a *userRepository) RestoreUser(c context.Context, userId string, deletedAfter time.Time) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
 	query, args, err := sq.Update("users").
+		Set("deleted_at", nil).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.And{
+			sq.Eq{"id": userId},
+			sq.NotEq{"deleted_at": nil},
+			sq.Gt{"deleted_at": deletedAfter},
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	cmdTag, err := a.pgx.Exec(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_RESTORE_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_RESTORE_USER
+	}
+	if cmdTag.RowsAffected() == 0 {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_NOTFOUND_USER_NOT_FOUND.Error(), userId)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_NOTFOUND_USER_NOT_FOUND
+	}
+	return nil
+}
+
+func (a *userRepository) PurgeDeletedUsers(c context.Context, deletedBefore time.Time, newEvent func(userId string) (*dto.OutboxMessage, error)) ([]string, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	var userIds []string
+	err := a.withTx(ctx, func(q _db.Querier) error {
+		ids, err := a.purgeDeletedUsers(ctx, q, deletedBefore)
+		if err != nil {
+			return err
+		}
+		outbox := make([]*dto.OutboxMessage, 0, len(ids))
+		for _, id := range ids {
+			msg, err := newEvent(id)
+			if err != nil {
+				return err
+			}
+			outbox = append(outbox, msg)
+		}
+		if err := insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox); err != nil {
+			return err
+		}
+		userIds = ids
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+func (a *userRepository) purgeDeletedUsers(ctx context.Context, q _db.Querier, deletedBefore time.Time) ([]string, error) {
+	query, args, err := sq.Delete("users").
+		Where(sq.And{
+			sq.NotEq{"deleted_at": nil},
+			sq.LtOrEq{"deleted_at": deletedBefore},
+		}).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_PURGE_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_PURGE_USER
+	}
+	defer rows.Close()
+
+	var userIds []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_PURGE_USER.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_PURGE_USER
+		}
+		userIds = append(userIds, id)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_PURGE_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_PURGE_USER
+	}
+	return userIds, nil
+}
+
+// an expectedVersion of 0 writes unconditionally
+func (a *userRepository) UpdateUser(c context.Context, user *model.User, expectedVersion int64, outbox ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	if len(outbox) == 0 {
+		return a.updateUser(ctx, a.pgx, user, nil, nil, expectedVersion)
+	}
+	return a.withTx(ctx, func(q _db.Querier) error {
+		if err := a.updateUser(ctx, q, user, nil, nil, expectedVersion); err != nil {
+			return err
+		}
+		return insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox)
+	})
+}
+
+// the image and its resized variants are swapped in one statement so readers never see a mixed set
+func (a *userRepository) UpdateUserImage(c context.Context, user *model.User, variants dto.ImageVariants, expectedVersion int64, outbox ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	if variants == nil {
+		variants = dto.ImageVariants{}
+	}
+	if len(outbox) == 0 {
+		return a.updateUser(ctx, a.pgx, user, variants, nil, expectedVersion)
+	}
+	return a.withTx(ctx, func(q _db.Querier) error {
+		if err := a.updateUser(ctx, q, user, variants, nil, expectedVersion); err != nil {
+			return err
+		}
+		return insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox)
+	})
+}
+
+// writes the optional profile fields too, nil variants keep the stored ones
+func (a *userRepository) UpdateProfile(c context.Context, profile *dto.UserProfile, expectedVersion int64, outbox ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	if len(outbox) == 0 {
+		return a.updateUser(ctx, a.pgx, &profile.User, profile.ImageVariants, &profile.ProfileFields, expectedVersion)
+	}
+	return a.withTx(ctx, func(q _db.Querier) error {
+		if err := a.updateUser(ctx, q, &profile.User, profile.ImageVariants, &profile.ProfileFields, expectedVersion); err != nil {
+			return err
+		}
+		return insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox)
+	})
+}
+
+// the variants column is only written when variants is not nil, the profile fields when fields is not nil.
+// two_factor_enabled is left to UpdateTOTPSecret so it is never set without an enrolled secret
+func (a *userRepository) updateUser(ctx context.Context, q _db.Querier, user *model.User, variants dto.ImageVariants, fields *dto.ProfileFields, expectedVersion int64) error {
+	builder := sq.Update("users").
 		Set("full_name", user.FullName).
 		Set("image", user.Image).
 		Set("email", user.Email).
 		Set("password", user.Password).
-		Set("verified", user.Verified).
-		Set("two_factor_enabled", user.TwoFactorEnabled).
+		Set("verified", user.Verified)
+	if variants != nil {
+		builder = builder.Set("image_variants", variants)
+	}
+	if fields != nil {
+		builder = builder.
+			Set("username", fields.Username).
+			Set("bio", fields.Bio).
+			Set("locale", fields.Locale).
+			Set("timezone", fields.Timezone).
+			Set("phone_number", fields.PhoneNumber).
+			Set("phone_verified", fields.PhoneVerified).
+			Set("date_of_birth", fields.DateOfBirth)
+	}
+	builder = builder.
 		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("version", sq.Expr("version + 1")).
 		Where(sq.Eq{"id": user.ID}).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+		Where(sq.Eq{"deleted_at": nil})
+	if expectedVersion > 0 {
+		builder = builder.Where(sq.Eq{"version": expectedVersion})
+	}
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
 	if err != nil {
 		go func() {
 			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
@@ -93,8 +335,25 @@ func (a *userRepository) UpdateUser(user *model.User) error {
 		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
 	}
 
-	cmdTag, err := a.pgx.Exec(context.Background(), query, args...)
+	cmdTag, err := q.Exec(ctx, query, args...)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == usernameUniqueIndex {
+			go func() {
+				if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_CONFLICT_USERNAME_EXIST.Error(), user.ID)); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.Err_CONFLICT_USERNAME_EXIST
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_CONFLICT_EMAIL_EXIST.Error(), user.ID)); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.Err_CONFLICT_EMAIL_EXIST
+		}
 		go func() {
 			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_UPDATE_USER.Error()); err != nil {
 				a.logger.Error().Err(err).Msg("failed to emit log")
@@ -102,6 +361,15 @@ func (a *userRepository) UpdateUser(user *model.User) error {
 		}()
 		return dto.Err_INTERNAL_FAILED_UPDATE_USER
 	}
+	// with a version condition the row was updated or deleted since it was read, either way the caller has to read it again
+	if cmdTag.RowsAffected() == 0 && expectedVersion > 0 {
+		go func() {
+			if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH.Error(), user.ID)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH
+	}
 	if cmdTag.RowsAffected() == 0 {
 		go func() {
 			if err := a.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_NOTFOUND_USER_NOT_FOUND.Error(), user.ID)); err != nil {
@@ -113,7 +381,22 @@ func (a *userRepository) UpdateUser(user *model.User) error {
 	return nil
 }
 
-func (a *userRepository) CreateNewUser(user *model.User) error {
+func (a *userRepository) CreateNewUser(c context.Context, user *model.User, outbox ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	if len(outbox) == 0 {
+		return a.createNewUser(ctx, a.pgx, user)
+	}
+	return a.withTx(ctx, func(q _db.Querier) error {
+		if err := a.createNewUser(ctx, q, user); err != nil {
+			return err
+		}
+		return insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox)
+	})
+}
+
+func (a *userRepository) createNewUser(ctx context.Context, q _db.Querier, user *model.User) error {
 	query, args, err := sq.Insert("users").
 		Columns("id", "full_name", "image", "email", "password", "verified", "two_factor_enabled").
 		Values(user.ID, user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled).
@@ -128,7 +411,7 @@ func (a *userRepository) CreateNewUser(user *model.User) error {
 		}()
 		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
 	}
-	row := a.pgx.QueryRow(context.Background(), query, args...)
+	row := q.QueryRow(ctx, query, args...)
 	if err := row.Scan(&user.ID); err != nil {
 		go func() {
 			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_INSERT_USER.Error()); err != nil {
@@ -140,11 +423,15 @@ func (a *userRepository) CreateNewUser(user *model.User) error {
 	return nil
 }
 
-func (a *userRepository) QueryUserByUserId(userId string) (*model.User, error) {
+func (a *userRepository) QueryUserByUserId(c context.Context, userId string) (*model.User, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
 	var user model.User
 	query, args, err := sq.Select("id", "full_name", "image", "email", "password", "verified", "two_factor_enabled").
 		From("users").
 		Where(sq.Eq{"id": userId}).
+		Where(sq.Eq{"deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
@@ -155,7 +442,7 @@ func (a *userRepository) QueryUserByUserId(userId string) (*model.User, error) {
 		}()
 		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
 	}
-	row := a.pgx.QueryRow(context.Background(), query, args...)
+	row := a.pgx.QueryRow(ctx, query, args...)
 	err = row.Scan(&user.ID, &user.FullName, &user.Image, &user.Email, &user.Password, &user.Verified, &user.TwoFactorEnabled)
 
 	if err != nil {
@@ -177,3 +464,379 @@ func (a *userRepository) QueryUserByUserId(userId string) (*model.User, error) {
 	return &user, nil
 
 }
+
+func (a *userRepository) QueryProfileByUserId(c context.Context, userId string) (*dto.UserProfile, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	var profile dto.UserProfile
+	query, args, err := sq.Select("id", "full_name", "image", "email", "password", "verified", "two_factor_enabled", "image_variants", "version",
+		"username", "bio", "locale", "timezone", "phone_number", "phone_verified", "date_of_birth").
+		From("users").
+		Where(sq.Eq{"id": userId}).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	row := a.pgx.QueryRow(ctx, query, args...)
+	err = row.Scan(&profile.ID, &profile.FullName, &profile.Image, &profile.Email, &profile.Password, &profile.Verified, &profile.TwoFactorEnabled, &profile.ImageVariants, &profile.Version,
+		&profile.Username, &profile.Bio, &profile.Locale, &profile.Timezone, &profile.PhoneNumber, &profile.PhoneVerified, &profile.DateOfBirth)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			go func() {
+				if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s user_id: %s", dto.Err_NOTFOUND_USER_NOT_FOUND.Error(), userId)); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_NOTFOUND_USER_NOT_FOUND
+		}
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SCAN_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_SCAN_USER
+	}
+	return &profile, nil
+}
+
+// soft deleted users keep their username so a restore cannot collide
+func (a *userRepository) IsUsernameTaken(c context.Context, username string, excludeUserId string) (bool, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("users").
+		Where("LOWER(username) = LOWER(?)", username).
+		Where(sq.NotEq{"id": excludeUserId}).
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return false, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	var taken bool
+	if err := a.pgx.QueryRow(ctx, query, args...).Scan(&taken); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_USERNAME.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return false, dto.Err_INTERNAL_FAILED_QUERY_USERNAME
+	}
+	return taken, nil
+}
+
+// the sealed secret, nil when two-factor authentication is not enabled
+func (a *userRepository) QueryTOTPSecret(c context.Context, userId string) ([]byte, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("totp_secret").
+		From("users").
+		Where(sq.Eq{"id": userId}).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	var sealedSecret []byte
+	if err := a.pgx.QueryRow(ctx, query, args...).Scan(&sealedSecret); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			go func() {
+				if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s user_id: %s", dto.Err_NOTFOUND_USER_NOT_FOUND.Error(), userId)); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_NOTFOUND_USER_NOT_FOUND
+		}
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SCAN_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_SCAN_USER
+	}
+	return sealedSecret, nil
+}
+
+// a nil secret disables two-factor authentication, two_factor_enabled always follows the secret.
+// the recovery codes are replaced in the same transaction, on disable they are simply dropped
+func (a *userRepository) UpdateTOTPSecret(c context.Context, userId string, sealedSecret []byte, recoveryCodeHashes []string, outbox ...*dto.OutboxMessage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	return a.withTx(ctx, func(q _db.Querier) error {
+		if err := a.updateTOTPSecret(ctx, q, userId, sealedSecret); err != nil {
+			return err
+		}
+		if err := a.replaceRecoveryCodes(ctx, q, userId, recoveryCodeHashes); err != nil {
+			return err
+		}
+		return insertOutboxMessages(ctx, q, a.logEmitter, a.logger, outbox)
+	})
+}
+
+func (a *userRepository) updateTOTPSecret(ctx context.Context, q _db.Querier, userId string, sealedSecret []byte) error {
+	query, args, err := sq.Update("users").
+		Set("totp_secret", sealedSecret).
+		Set("two_factor_enabled", sealedSecret != nil).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": userId}).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	cmdTag, err := q.Exec(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_UPDATE_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_UPDATE_USER
+	}
+	if cmdTag.RowsAffected() == 0 {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_NOTFOUND_USER_NOT_FOUND.Error(), userId)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_NOTFOUND_USER_NOT_FOUND
+	}
+	return nil
+}
+
+func (a *userRepository) QueryUserByEmail(c context.Context, email string) (*model.User, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	var user model.User
+	query, args, err := sq.Select("id", "full_name", "image", "email", "password", "verified", "two_factor_enabled").
+		From("users").
+		Where(sq.Eq{"email": email}).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	row := a.pgx.QueryRow(ctx, query, args...)
+	err = row.Scan(&user.ID, &user.FullName, &user.Image, &user.Email, &user.Password, &user.Verified, &user.TwoFactorEnabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, dto.Err_NOTFOUND_USER_NOT_FOUND
+		}
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SCAN_USER.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_SCAN_USER
+	}
+	return &user, nil
+}
+
+func (a *userRepository) QueryUsersByIds(c context.Context, userIds []string) ([]*model.User, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("id", "full_name", "image", "email", "password", "verified", "two_factor_enabled").
+		From("users").
+		Where(sq.Expr("id = ANY(?)", userIds)).
+		Where(sq.Eq{"deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := a.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_USERS.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_USERS
+	}
+	defer rows.Close()
+
+	users := make([]*model.User, 0, len(userIds))
+	for rows.Next() {
+		var user model.User
+		if err := rows.Scan(&user.ID, &user.FullName, &user.Image, &user.Email, &user.Password, &user.Verified, &user.TwoFactorEnabled); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SCAN_USER.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_SCAN_USER
+		}
+		users = append(users, &user)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_USERS.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_USERS
+	}
+	return users, nil
+}
+
+// email and created_at are nullable. a row comparison with NULL is NULL, so the keys are coalesced
+// the same way in the select, the keyset comparison and the order, and every row lands on exactly one page
+var listUsersSortKeys = map[string]string{
+	"full_name":  "full_name",
+	"email":      "COALESCE(email, '')",
+	"created_at": "COALESCE(created_at, 'epoch'::timestamp)",
+}
+
+func (a *userRepository) ListUsers(c context.Context, q *dto.ListUsersQuery) ([]dto.UserListItem, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	order := "ASC"
+	cmp := ">"
+	if q.SortDesc {
+		order = "DESC"
+		cmp = "<"
+	}
+	// the handler binds sort_by to the keys of listUsersSortKeys
+	sortKey, ok := listUsersSortKeys[q.SortBy]
+	if !ok {
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	builder := sq.Select("id", "full_name", "image", listUsersSortKeys["email"], "verified", "two_factor_enabled", listUsersSortKeys["created_at"]).
+		From("users").
+		Where(sq.Eq{"deleted_at": nil})
+	if q.Verified != nil {
+		builder = builder.Where(sq.Eq{"verified": *q.Verified})
+	}
+	if q.TwoFactorEnabled != nil {
+		builder = builder.Where(sq.Eq{"two_factor_enabled": *q.TwoFactorEnabled})
+	}
+	if q.CreatedFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"created_at": *q.CreatedFrom})
+	}
+	if q.CreatedTo != nil {
+		builder = builder.Where(sq.LtOrEq{"created_at": *q.CreatedTo})
+	}
+	if q.Email != "" {
+		builder = builder.Where(sq.ILike{"email": "%" + likeEscaper.Replace(q.Email) + "%"})
+	}
+	if q.Name != "" {
+		builder = builder.Where(sq.ILike{"full_name": "%" + likeEscaper.Replace(q.Name) + "%"})
+	}
+	if q.After != nil {
+		var value any = q.After.Value
+		if q.SortBy == "created_at" {
+			createdAt, err := time.Parse(time.RFC3339Nano, q.After.Value)
+			if err != nil {
+				return nil, dto.Err_BAD_REQUEST_INVALID_CURSOR
+			}
+			value = createdAt
+		}
+		builder = builder.Where(sq.Expr(fmt.Sprintf("(%s, id) %s (?, ?)", sortKey, cmp), value, q.After.ID))
+	}
+	query, args, err := builder.
+		OrderBy(fmt.Sprintf("%s %s", sortKey, order), fmt.Sprintf("id %s", order)).
+		Limit(q.Limit).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := a.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_LIST_USERS.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_LIST_USERS
+	}
+	defer rows.Close()
+
+	users := make([]dto.UserListItem, 0, q.Limit)
+	for rows.Next() {
+		var user dto.UserListItem
+		if err := rows.Scan(&user.ID, &user.FullName, &user.Image, &user.Email, &user.Verified, &user.TwoFactorEnabled, &user.CreatedAt); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SCAN_USER.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_SCAN_USER
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_LIST_USERS.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_LIST_USERS
+	}
+	return users, nil
+}
+
+// run fn in a single transaction so the user mutation and its outbox messages are committed together
+func (a *userRepository) withTx(ctx context.Context, fn func(q _db.Querier) error) error {
+	err := _db.WithTx(ctx, a.pgx, fn)
+	if errors.Is(err, _db.ErrTransaction) {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. err: %v", dto.Err_INTERNAL_FAILED_TRANSACTION.Error(), err)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_TRANSACTION
+	}
+	return err
+}
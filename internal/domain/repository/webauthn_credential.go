@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/timeout"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// unique index on credential_id, see migration 0009
+const webAuthnCredentialUniqueIndex = "webauthn_credentials_credential_id_key"
+
+func (a *userRepository) QueryWebAuthnCredentials(c context.Context, userId string) ([]dto.WebAuthnCredential, error) {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Select("id", "user_id", "credential_id", "name", "public_key", "attestation_type", "aaguid", "transports",
+		"sign_count", "clone_warning", "backup_eligible", "backup_state", "created_at", "last_used_at").
+		From("webauthn_credentials").
+		Where(sq.Eq{"user_id": userId}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+
+	rows, err := a.pgx.Query(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_PASSKEYS.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_PASSKEYS
+	}
+	defer rows.Close()
+
+	credentials := []dto.WebAuthnCredential{}
+	for rows.Next() {
+		var credential dto.WebAuthnCredential
+		var signCount int64
+		if err := rows.Scan(&credential.ID, &credential.UserID, &credential.CredentialID, &credential.Name, &credential.PublicKey,
+			&credential.AttestationType, &credential.AAGUID, &credential.Transports, &signCount, &credential.CloneWarning,
+			&credential.BackupEligible, &credential.BackupState, &credential.CreatedAt, &credential.LastUsedAt); err != nil {
+			go func() {
+				if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_PASSKEYS.Error()); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, dto.Err_INTERNAL_FAILED_QUERY_PASSKEYS
+		}
+		credential.SignCount = uint32(signCount)
+		credentials = append(credentials, credential)
+	}
+	if err := rows.Err(); err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_QUERY_PASSKEYS.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_FAILED_QUERY_PASSKEYS
+	}
+	return credentials, nil
+}
+
+// fills in the generated id and created_at
+func (a *userRepository) CreateWebAuthnCredential(c context.Context, credential *dto.WebAuthnCredential) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	transports := credential.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	query, args, err := sq.Insert("webauthn_credentials").
+		Columns("user_id", "credential_id", "name", "public_key", "attestation_type", "aaguid", "transports",
+			"sign_count", "backup_eligible", "backup_state").
+		Values(credential.UserID, credential.CredentialID, credential.Name, credential.PublicKey, credential.AttestationType,
+			credential.AAGUID, transports, int64(credential.SignCount), credential.BackupEligible, credential.BackupState).
+		Suffix("RETURNING id, created_at").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	if err := a.pgx.QueryRow(ctx, query, args...).Scan(&credential.ID, &credential.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == webAuthnCredentialUniqueIndex {
+			go func() {
+				if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_CONFLICT_PASSKEY_EXIST.Error(), credential.UserID)); err != nil {
+					a.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.Err_CONFLICT_PASSKEY_EXIST
+		}
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_PASSKEY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_SAVE_PASSKEY
+	}
+	return nil
+}
+
+func (a *userRepository) RenameWebAuthnCredential(c context.Context, userId string, id int64, name string) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("webauthn_credentials").
+		Set("name", name).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	return a.execWebAuthnCredential(ctx, query, args, userId)
+}
+
+// scoped to the user so one user can not remove another's passkey by guessing ids
+func (a *userRepository) DeleteWebAuthnCredential(c context.Context, userId string, id int64) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Delete("webauthn_credentials").
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"user_id": userId}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	return a.execWebAuthnCredential(ctx, query, args, userId)
+}
+
+// the counter never moves backwards here, a clone warning once raised stays until the passkey is removed
+func (a *userRepository) UpdateWebAuthnCredentialUsage(c context.Context, usage *dto.WebAuthnCredentialUsage) error {
+	ctx, cancel := timeout.WithConfig(c, constant.TIMEOUT_DATABASE)
+	defer cancel()
+
+	query, args, err := sq.Update("webauthn_credentials").
+		Set("sign_count", sq.Expr("GREATEST(sign_count, ?)", int64(usage.SignCount))).
+		Set("clone_warning", sq.Expr("clone_warning OR ?", usage.CloneWarning)).
+		Set("backup_state", usage.BackupState).
+		Set("last_used_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"credential_id": usage.CredentialID}).
+		Where(sq.Eq{"user_id": usage.UserID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_BUILD_QUERY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_BUILD_QUERY
+	}
+	return a.execWebAuthnCredential(ctx, query, args, usage.UserID)
+}
+
+func (a *userRepository) execWebAuthnCredential(ctx context.Context, query string, args []any, userId string) error {
+	cmdTag, err := a.pgx.Exec(ctx, query, args...)
+	if err != nil {
+		go func() {
+			if err := a.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_FAILED_SAVE_PASSKEY.Error()); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_FAILED_SAVE_PASSKEY
+	}
+	if cmdTag.RowsAffected() == 0 {
+		go func() {
+			if err := a.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_NOTFOUND_PASSKEY.Error(), userId)); err != nil {
+				a.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_NOTFOUND_PASSKEY
+	}
+	return nil
+}
@@ -3,101 +3,162 @@ package service
 import (
 	"context"
 
+	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/repository"
-
-	"github.com/micros-template/event-bus-client/pkg/event"
+	"github.com/micros-template/user-service/pkg/constant"
 
 	"github.com/micros-template/proto-user/pkg/upb"
 	"github.com/micros-template/sharedlib/model"
-	"github.com/micros-template/sharedlib/utils"
 	"github.com/rs/zerolog"
 )
 
 type (
 	AuthService interface {
-		CreateUser(*upb.User) (*upb.Status, error)
-		UpdateUser(c context.Context, user *upb.User) error
+		CreateUser(c context.Context, user *upb.User) (*upb.Status, error)
+		UpdateUser(c context.Context, user *upb.User, expectedVersion int64) error
 		DeleteUser(c context.Context, userId *upb.UserId) error
+		GetUserById(c context.Context, userId *upb.UserId) (*upb.User, error)
+		GetUsersByIds(c context.Context, userIds []string) ([]*upb.User, error)
+		GetUserByEmail(c context.Context, email string) (*upb.User, error)
 	}
 	authService struct {
 		userRepository repository.UserRepository
+		profileCache   repository.ProfileCacheRepository
 		logger         zerolog.Logger
-		eventEmitter   event.Emitter
 	}
 )
 
-func NewAuthService(userRepository repository.UserRepository, emitter event.Emitter, logger zerolog.Logger) AuthService {
+func NewAuthService(userRepository repository.UserRepository, profileCache repository.ProfileCacheRepository, logger zerolog.Logger) AuthService {
 	return &authService{
 		userRepository: userRepository,
+		profileCache:   profileCache,
 		logger:         logger,
-		eventEmitter:   emitter,
 	}
 }
 
-func (a *authService) UpdateUser(c context.Context, user *upb.User) error {
-	u := &model.User{
-		ID:               user.GetId(),
-		FullName:         user.GetFullName(),
-		Image:            utils.StringPtr(user.GetImage()),
-		Email:            user.GetEmail(),
-		Password:         user.GetPassword(),
-		Verified:         user.GetVerified(),
-		TwoFactorEnabled: user.GetTwoFactorEnabled(),
-	}
-	if err := a.userRepository.UpdateUser(u); err != nil {
-		return err
+// the caller sends the whole row, expectedVersion guards it against updates made since the caller read it.
+// 0 writes unconditionally, see AuthGrpcHandler.UpdateUser. two_factor_enabled is only changed by the TOTP
+// enrollment, the stored value is kept and published instead of the one sent by the caller
+func (a *authService) UpdateUser(c context.Context, user *upb.User, expectedVersion int64) error {
+	for attempt := 1; ; attempt++ {
+		stored, err := a.userRepository.QueryProfileByUserId(c, user.GetId())
+		if err != nil {
+			return err
+		}
+		u := &model.User{
+			ID:               user.GetId(),
+			FullName:         user.GetFullName(),
+			Image:            imageOrNil(user.GetImage()),
+			Email:            user.GetEmail(),
+			Password:         user.GetPassword(),
+			Verified:         user.GetVerified(),
+			TwoFactorEnabled: stored.TwoFactorEnabled,
+		}
+		// without a version from the caller the write is still guarded by the one that was read,
+		// so the published two_factor_enabled matches the row. a concurrent update is retried
+		version := expectedVersion
+		if version == 0 {
+			version = stored.Version
+		}
+		event, err := newUserEventMessage(constant.EVENT_UPDATE_USER, u)
+		if err != nil {
+			a.logger.Error().Err(err).Msg("failed to build user updated event")
+			return err
+		}
+		// the event is stored in the outbox within the same transaction and relayed later
+		err = a.userRepository.UpdateUser(c, u, version, event)
+		if err == dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH && expectedVersion == 0 && attempt < maxGuardedUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		a.invalidateProfile(c, u.ID)
+		return nil
 	}
-	// push event bus in goroutine
-	go func() {
-		a.eventEmitter.UpdateUser(context.Background(), &upb.User{
-			Id:               u.ID,
-			FullName:         u.FullName,
-			Image:            u.Image,
-			Email:            u.Email,
-			Password:         u.Password,
-			Verified:         u.Verified,
-			TwoFactorEnabled: u.TwoFactorEnabled,
-		})
-	}()
-	return nil
 }
 
-func (a *authService) CreateUser(user *upb.User) (*upb.Status, error) {
+func (a *authService) CreateUser(c context.Context, user *upb.User) (*upb.Status, error) {
+	// two_factor_enabled is ignored, a new user has no enrolled secret and only the TOTP enrollment turns it on
 	u := &model.User{
-		ID:               user.GetId(),
-		FullName:         user.GetFullName(),
-		Image:            utils.StringPtr(user.GetImage()),
-		Email:            user.GetEmail(),
-		Password:         user.GetPassword(),
-		Verified:         user.GetVerified(),
-		TwoFactorEnabled: user.GetTwoFactorEnabled(),
+		ID:       user.GetId(),
+		FullName: user.GetFullName(),
+		Image:    imageOrNil(user.GetImage()),
+		Email:    user.GetEmail(),
+		Password: user.GetPassword(),
+		Verified: user.GetVerified(),
 	}
-	err := a.userRepository.CreateNewUser(u)
+	event, err := newUserEventMessage(constant.EVENT_INSERT_USER, u)
 	if err != nil {
+		a.logger.Error().Err(err).Msg("failed to build user created event")
+		return nil, err
+	}
+	// the event is stored in the outbox within the same transaction and relayed later
+	if err := a.userRepository.CreateNewUser(c, u, event); err != nil {
 		return nil, err
 	}
-	// push event bus in goroutine
-	go func() {
-		a.eventEmitter.InsertUser(context.Background(), &upb.User{
-			Id:               u.ID,
-			FullName:         u.FullName,
-			Image:            u.Image,
-			Email:            u.Email,
-			Password:         u.Password,
-			Verified:         u.Verified,
-			TwoFactorEnabled: u.TwoFactorEnabled,
-		})
-	}()
 	return &upb.Status{Success: true}, nil
 }
 
 func (a *authService) DeleteUser(c context.Context, userId *upb.UserId) error {
-	if err := a.userRepository.DeleteUser(userId.GetUserId()); err != nil {
+	// soft delete, the event is pushed once the purger removes the row for good
+	if err := a.userRepository.DeleteUser(c, userId.GetUserId()); err != nil {
 		return err
 	}
-	// push event bus in goroutine
-	go func() {
-		a.eventEmitter.DeleteUser(context.Background(), userId)
-	}()
+	a.invalidateProfile(c, userId.GetUserId())
 	return nil
 }
+
+func (a *authService) GetUserById(c context.Context, userId *upb.UserId) (*upb.User, error) {
+	profile, err := a.profileCache.QueryProfileByUserId(c, userId.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return toPublicUser(&profile.User), nil
+}
+
+func (a *authService) GetUsersByIds(c context.Context, userIds []string) ([]*upb.User, error) {
+	users, err := a.userRepository.QueryUsersByIds(c, userIds)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*upb.User, 0, len(users))
+	for _, user := range users {
+		res = append(res, toPublicUser(user))
+	}
+	return res, nil
+}
+
+func (a *authService) GetUserByEmail(c context.Context, email string) (*upb.User, error) {
+	user, err := a.userRepository.QueryUserByEmail(c, email)
+	if err != nil {
+		return nil, err
+	}
+	return toPublicUser(user), nil
+}
+
+func (a *authService) invalidateProfile(c context.Context, userId string) {
+	if err := a.profileCache.Invalidate(c, userId); err != nil {
+		a.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to invalidate cached profile")
+	}
+}
+
+// password hash never leaves the service through read rpc
+func toPublicUser(u *model.User) *upb.User {
+	return &upb.User{
+		Id:               u.ID,
+		FullName:         u.FullName,
+		Image:            u.Image,
+		Email:            u.Email,
+		Verified:         u.Verified,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+	}
+}
+
+// callers without an image send an empty string, the column stays NULL so the default avatar is served
+func imageOrNil(image string) *string {
+	if image == "" {
+		return nil
+	}
+	return &image
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/uapb"
+
+	"github.com/micros-template/proto-event/pkg/epb"
+	"github.com/micros-template/proto-event/pkg/uepb"
+	"github.com/micros-template/proto-user/pkg/upb"
+	_dto "github.com/micros-template/sharedlib/dto"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/spf13/viper"
+	"google.golang.org/protobuf/proto"
+)
+
+var defaultEventFields = []string{
+	constant.EVENT_FIELD_FULL_NAME,
+	constant.EVENT_FIELD_IMAGE,
+	constant.EVENT_FIELD_EMAIL,
+	constant.EVENT_FIELD_VERIFIED,
+	constant.EVENT_FIELD_TWO_FACTOR_ENABLED,
+}
+
+var defaultProfileEventFields = []string{
+	constant.EVENT_FIELD_USERNAME,
+	constant.EVENT_FIELD_BIO,
+	constant.EVENT_FIELD_LOCALE,
+	constant.EVENT_FIELD_TIMEZONE,
+	constant.EVENT_FIELD_PHONE_NUMBER,
+	constant.EVENT_FIELD_PHONE_VERIFIED,
+	constant.EVENT_FIELD_DATE_OF_BIRTH,
+}
+
+func eventFields(eventType string, defaults []string) []string {
+	key := "jetstream.event.payload_fields." + eventType
+	if viper.IsSet(key) {
+		return viper.GetStringSlice(key)
+	}
+	return defaults
+}
+
+// build the user payload published on the event bus. only fields allowed for the
+// event type are copied and the password hash is never part of the payload
+func newUserEventPayload(eventType string, u *model.User) *upb.User {
+	fields := eventFields(eventType, defaultEventFields)
+	payload := &upb.User{Id: u.ID}
+	if slices.Contains(fields, constant.EVENT_FIELD_FULL_NAME) {
+		payload.FullName = u.FullName
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_IMAGE) {
+		payload.Image = u.Image
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_EMAIL) {
+		payload.Email = u.Email
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_VERIFIED) {
+		payload.Verified = u.Verified
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_TWO_FACTOR_ENABLED) {
+		payload.TwoFactorEnabled = u.TwoFactorEnabled
+	}
+	return payload
+}
+
+// outbox messages use the same subject and encoding as the event-bus-client emitter
+func newUserEventMessage(eventType string, u *model.User) (*dto.OutboxMessage, error) {
+	payload := newUserEventPayload(eventType, u)
+	userEvent := &uepb.UserEvent{}
+	switch eventType {
+	case constant.EVENT_INSERT_USER:
+		userEvent.Event = &uepb.UserEvent_UserCreated{
+			UserCreated: &uepb.UserCreated{
+				Id:               payload.GetId(),
+				FullName:         payload.GetFullName(),
+				Image:            payload.Image,
+				Email:            payload.GetEmail(),
+				Verified:         payload.GetVerified(),
+				TwoFactorEnabled: payload.GetTwoFactorEnabled(),
+			},
+		}
+	default:
+		userEvent.Event = &uepb.UserEvent_UserUpdated{
+			UserUpdated: &uepb.UserUpdated{
+				Id:               payload.GetId(),
+				FullName:         payload.GetFullName(),
+				Image:            payload.Image,
+				Email:            payload.GetEmail(),
+				Verified:         payload.GetVerified(),
+				TwoFactorEnabled: payload.GetTwoFactorEnabled(),
+			},
+		}
+	}
+	return newEventMessage(u.ID, userEvent)
+}
+
+// the profile fields allowed by jetstream.event.payload_fields.update_user_profile,
+// phone number and date of birth can be left out like any other field
+func newUserProfilePayload(p *dto.UserProfile) *uapb.UserProfile {
+	fields := eventFields(constant.EVENT_UPDATE_USER_PROFILE, defaultProfileEventFields)
+	payload := &uapb.UserProfile{Id: p.ID}
+	if slices.Contains(fields, constant.EVENT_FIELD_USERNAME) {
+		payload.Username = p.Username
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_BIO) {
+		payload.Bio = p.Bio
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_LOCALE) {
+		payload.Locale = p.Locale
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_TIMEZONE) {
+		payload.Timezone = p.Timezone
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_PHONE_NUMBER) {
+		payload.PhoneNumber = p.PhoneNumber
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_PHONE_VERIFIED) {
+		payload.PhoneVerified = p.PhoneVerified
+	}
+	if slices.Contains(fields, constant.EVENT_FIELD_DATE_OF_BIRTH) && p.DateOfBirth != nil {
+		dateOfBirth := p.DateOfBirth.Format(dto.DateLayout)
+		payload.DateOfBirth = &dateOfBirth
+	}
+	return payload
+}
+
+func newUserProfileMessage(p *dto.UserProfile) (*dto.OutboxMessage, error) {
+	encoded, err := proto.Marshal(newUserProfilePayload(p))
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OutboxMessage{
+		Subject: fmt.Sprintf("%s.user_profile.%s", viper.GetString("jetstream.event.subject.event_bus"), p.ID),
+		Payload: encoded,
+	}, nil
+}
+
+func newUserDeletedMessage(userId string) (*dto.OutboxMessage, error) {
+	return newEventMessage(userId, &uepb.UserEvent{
+		Event: &uepb.UserEvent_UserDeleted{
+			UserDeleted: &uepb.UserDeleted{
+				Id: userId,
+			},
+		},
+	})
+}
+
+func newEventMessage(userId string, userEvent *uepb.UserEvent) (*dto.OutboxMessage, error) {
+	encoded, err := proto.Marshal(&epb.EventMessage{
+		Event: &epb.EventMessage_UserEvent{UserEvent: userEvent},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OutboxMessage{
+		Subject: fmt.Sprintf("%s.user.%s", viper.GetString("jetstream.event.subject.event_bus"), userId),
+		Payload: encoded,
+	}, nil
+}
+
+func newMailMessage(userId string, msg *_dto.MailNotificationMessage) (*dto.OutboxMessage, error) {
+	return newNotificationMessage(viper.GetString("jetstream.notification.subject.mail"), userId, msg)
+}
+
+// sms uses the mail envelope, Receiver holds E.164 numbers instead of addresses
+func newSMSMessage(userId string, msg *_dto.MailNotificationMessage) (*dto.OutboxMessage, error) {
+	return newNotificationMessage(viper.GetString("jetstream.notification.subject.sms"), userId, msg)
+}
+
+func newNotificationMessage(subject, userId string, msg *_dto.MailNotificationMessage) (*dto.OutboxMessage, error) {
+	marshalledMsg, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OutboxMessage{
+		Subject: fmt.Sprintf("%s.%s", subject, userId),
+		Payload: marshalledMsg,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/repository"
+	"github.com/micros-template/user-service/internal/infrastructure/logger"
+	_mq "github.com/micros-template/user-service/internal/infrastructure/message-queue"
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+type (
+	OutboxRelay interface {
+		RelayPending(ctx context.Context) (int, error)
+	}
+	outboxRelay struct {
+		outboxRepository repository.OutboxRepository
+		natsInfra        _mq.Nats
+		metrics          *metrics.Metrics
+		logger           zerolog.Logger
+		logEmitter       logger.LoggerInfra
+	}
+)
+
+func NewOutboxRelay(outboxRepository repository.OutboxRepository, natsInfra _mq.Nats, m *metrics.Metrics, logEmitter logger.LoggerInfra, logger zerolog.Logger) OutboxRelay {
+	return &outboxRelay{
+		outboxRepository: outboxRepository,
+		natsInfra:        natsInfra,
+		metrics:          m,
+		logger:           logger,
+		logEmitter:       logEmitter,
+	}
+}
+
+func (o *outboxRelay) RelayPending(ctx context.Context) (int, error) {
+	maxAttempts := viper.GetInt("app.outbox.max_attempts")
+	o.deadLetterExhausted(ctx, maxAttempts)
+
+	leaseUntil := time.Now().Add(viper.GetDuration("app.outbox.lease"))
+	msgs, err := o.outboxRepository.ClaimPending(ctx, viper.GetUint64("app.outbox.batch_size"), maxAttempts, leaseUntil)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	// subjects with a failed message in this batch, their later messages wait so consumers see them in order
+	failed := make(map[string]bool)
+	for _, msg := range msgs {
+		if failed[msg.Subject] {
+			if err := o.outboxRepository.Release(ctx, msg.ID); err != nil {
+				o.logger.Error().Err(err).Int64("outbox_id", msg.ID).Msg("failed to release outbox message")
+			}
+			continue
+		}
+		if _, err := o.natsInfra.Publish(ctx, msg.Subject, msg.Payload); err != nil {
+			failed[msg.Subject] = true
+			go func() {
+				if err := o.logEmitter.EmitLog("ERR", fmt.Sprintf("failed to relay outbox message. id: %d, attempts: %d, err: %v", msg.ID, msg.Attempts, err)); err != nil {
+					o.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			// the last attempt is not retried, it is dead-lettered on the next run instead of after a backoff
+			nextAttemptAt := time.Now()
+			if msg.Attempts < maxAttempts {
+				nextAttemptAt = nextAttemptAt.Add(retryBackoff(msg.Attempts))
+			}
+			if err := o.outboxRepository.MarkFailed(ctx, msg.ID, err.Error(), nextAttemptAt); err != nil {
+				o.logger.Error().Err(err).Int64("outbox_id", msg.ID).Msg("failed to mark outbox message as failed")
+			}
+			continue
+		}
+		// a failure here only means the message is published again once the lease expires
+		if err := o.outboxRepository.MarkSent(ctx, msg.ID); err != nil {
+			o.logger.Error().Err(err).Int64("outbox_id", msg.ID).Msg("failed to mark outbox message as sent")
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// the dead-lettered rows stay in the table for inspection and a manual replay, a failure is retried on the next run
+func (o *outboxRelay) deadLetterExhausted(ctx context.Context, maxAttempts int) {
+	dead, err := o.outboxRepository.DeadLetterExhausted(ctx, maxAttempts)
+	if err != nil {
+		o.logger.Error().Err(err).Msg("failed to dead-letter exhausted outbox messages")
+		return
+	}
+	for _, msg := range dead {
+		o.metrics.OutboxDeadLettered(msg.Subject)
+		lastError := ""
+		if msg.LastError != nil {
+			lastError = *msg.LastError
+		}
+		go func() {
+			if err := o.logEmitter.EmitLog("ERR", fmt.Sprintf("outbox message dead-lettered. id: %d, subject: %s, attempts: %d, last_error: %s", msg.ID, msg.Subject, msg.Attempts, lastError)); err != nil {
+				o.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+	}
+}
+
+func retryBackoff(attempts int) time.Duration {
+	backoff := viper.GetDuration("app.outbox.retry_backoff")
+	maxBackoff := viper.GetDuration("app.outbox.max_backoff")
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
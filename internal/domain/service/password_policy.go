@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/micros-template/user-service/pkg/password"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/spf13/viper"
+)
+
+func newPasswordPolicy() password.Policy {
+	return password.Policy{
+		MinLength:       viper.GetInt("app.password_policy.min_length"),
+		RequireUpper:    viper.GetBool("app.password_policy.require_upper"),
+		RequireLower:    viper.GetBool("app.password_policy.require_lower"),
+		RequireDigit:    viper.GetBool("app.password_policy.require_digit"),
+		RequireSymbol:   viper.GetBool("app.password_policy.require_symbol"),
+		BanCommon:       viper.GetBool("app.password_policy.ban_common"),
+		BanPersonalInfo: viper.GetBool("app.password_policy.ban_personal_info"),
+	}
+}
+
+// how many recent passwords, the current one included, cannot be reused.
+// the current password is always one of them
+func passwordHistorySize() int {
+	return max(viper.GetInt("app.password_policy.history_size"), 1)
+}
+
+// the current password lives on the user row, the ones before it in the history table
+func (u *userService) isPasswordReused(ctx context.Context, user *model.User, newPassword string, historySize int) (bool, error) {
+	if utils.HashPasswordCompare(newPassword, user.Password) {
+		return true, nil
+	}
+	if historySize <= 1 {
+		return false, nil
+	}
+	hashes, err := u.userRepository.QueryPasswordHistory(ctx, user.ID, historySize-1)
+	if err != nil {
+		return false, err
+	}
+	for _, hash := range hashes {
+		if utils.HashPasswordCompare(newPassword, hash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
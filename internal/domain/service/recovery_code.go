@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/spf13/viper"
+)
+
+const (
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// no 0/o, 1/l/i so a code copied from paper is read back the same
+	recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+)
+
+var recoveryCodeNormalizer = strings.NewReplacer("-", "", " ", "")
+
+// the old codes stop working, used or not, so a leaked list can be revoked
+func (u *userService) RegenerateRecoveryCodes(ctx context.Context, req *dto.RegenerateRecoveryCodesRequest, userId string) (dto.RecoveryCodesResponse, error) {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	if !user.TwoFactorEnabled {
+		return dto.RecoveryCodesResponse{}, dto.Err_CONFLICT_TWO_FACTOR_DISABLED
+	}
+	if err := u.reauthenticateTwoFactor(ctx, user, req.Password, req.Code); err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+
+	codes, codeHashes, err := u.generateRecoveryCodes()
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	if err := u.userRepository.ReplaceRecoveryCodes(ctx, userId, codeHashes); err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	return dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *userService) CountRecoveryCodes(ctx context.Context, userId string) (dto.RecoveryCodesCountResponse, error) {
+	remaining, err := u.userRepository.CountRecoveryCodes(ctx, userId)
+	if err != nil {
+		return dto.RecoveryCodesCountResponse{}, err
+	}
+	return dto.RecoveryCodesCountResponse{Remaining: remaining}, nil
+}
+
+// spends a recovery code in place of a totp code, used at login through grpc
+func (u *userService) UseRecoveryCode(ctx context.Context, code string, userId string) error {
+	// limited like totp codes, each guess costs a round of bcrypt comparisons
+	attemptsKey := fmt.Sprintf("recoveryCodeAttempts:%s", userId)
+	attempts, err := u.redisRepository.IncrementResource(ctx, attemptsKey, viper.GetDuration("app.two_factor.attempt_window"))
+	if err != nil {
+		return err
+	}
+	if attempts > viper.GetInt64("app.two_factor.max_attempts") {
+		go func() {
+			if err := u.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS
+	}
+
+	codes, err := u.userRepository.QueryRecoveryCodes(ctx, userId)
+	if err != nil {
+		return err
+	}
+	normalized := normalizeRecoveryCode(code)
+	for _, recoveryCode := range codes {
+		if !utils.HashPasswordCompare(normalized, recoveryCode.CodeHash) {
+			continue
+		}
+		if err := u.userRepository.UseRecoveryCode(ctx, recoveryCode.ID); err != nil {
+			return err
+		}
+		if err := u.redisRepository.RemoveResource(ctx, attemptsKey); err != nil {
+			u.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to reset recovery code attempts")
+		}
+		return nil
+	}
+	go func() {
+		if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_UNAUTHORIZED_RECOVERY_INVALID.Error(), userId)); err != nil {
+			u.logger.Error().Err(err).Msg("failed to emit log")
+		}
+	}()
+	return dto.Err_UNAUTHORIZED_RECOVERY_INVALID
+}
+
+// either the password or a current totp code, so a lost authenticator does not lock the user in
+func (u *userService) reauthenticateTwoFactor(ctx context.Context, user *dto.UserProfile, password, code string) error {
+	if password == "" {
+		return u.VerifyTOTP(ctx, code, user.ID)
+	}
+	if !utils.HashPasswordCompare(password, user.Password) {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_UNAUTHORIZED_PASSWORD_WRONG.Error(), user.ID)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_UNAUTHORIZED_PASSWORD_WRONG
+	}
+	return nil
+}
+
+// returns the codes to show once and their hashes to store, hashed like passwords
+func (u *userService) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	codeHashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		raw, err := randomRecoveryCode()
+		if err != nil {
+			u.emitTOTPError(err)
+			return nil, nil, dto.Err_INTERNAL_TOTP_SECRET
+		}
+		codeHash, err := utils.HashPassword(raw)
+		if err != nil {
+			go func() {
+				if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("Hash Password Error: %v", err.Error())); err != nil {
+					u.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return nil, nil, err
+		}
+		codes = append(codes, raw[:recoveryCodeLength/2]+"-"+raw[recoveryCodeLength/2:])
+		codeHashes = append(codeHashes, codeHash)
+	}
+	return codes, codeHashes, nil
+}
+
+func randomRecoveryCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	code := make([]byte, recoveryCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = recoveryCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// codes are accepted with or without the separator and in any case
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(recoveryCodeNormalizer.Replace(code))
+}
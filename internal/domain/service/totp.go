@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/pkg/secret"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"github.com/spf13/viper"
+)
+
+const (
+	totpPeriod = 30
+	totpSkew   = 1
+	totpQRSize = 256
+)
+
+// a new secret is kept in redis until a code from it is confirmed, the stored secret stays active meanwhile
+func (u *userService) SetupTOTP(ctx context.Context, userId string) (dto.SetupTOTPResponse, error) {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return dto.SetupTOTPResponse{}, err
+	}
+	enrolled, err := u.twoFactorEnrolled(ctx, user)
+	if err != nil {
+		return dto.SetupTOTPResponse{}, err
+	}
+	if enrolled {
+		return dto.SetupTOTPResponse{}, dto.Err_CONFLICT_TWO_FACTOR_ENABLED
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      viper.GetString("app.two_factor.issuer"),
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		u.emitTOTPError(err)
+		return dto.SetupTOTPResponse{}, dto.Err_INTERNAL_TOTP_SECRET
+	}
+	qrCode, err := totpQRCode(key)
+	if err != nil {
+		u.emitTOTPError(err)
+		return dto.SetupTOTPResponse{}, dto.Err_INTERNAL_TOTP_SECRET
+	}
+	sealed, err := u.sealTOTPSecret(userId, key.Secret())
+	if err != nil {
+		return dto.SetupTOTPResponse{}, err
+	}
+	// the pending secret is sealed as well, redis is not trusted with it either
+	if err := u.redisRepository.SetResource(ctx, totpEnrollmentKey(userId), base64.StdEncoding.EncodeToString(sealed), viper.GetDuration("app.two_factor.enrollment_ttl")); err != nil {
+		return dto.SetupTOTPResponse{}, err
+	}
+	return dto.SetupTOTPResponse{
+		Secret:     key.Secret(),
+		OtpauthURI: key.URL(),
+		QRCode:     qrCode,
+	}, nil
+}
+
+func (u *userService) EnableTOTP(ctx context.Context, req *dto.EnableTOTPRequest, userId string) (dto.RecoveryCodesResponse, error) {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	enrolled, err := u.twoFactorEnrolled(ctx, user)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	if enrolled {
+		return dto.RecoveryCodesResponse{}, dto.Err_CONFLICT_TWO_FACTOR_ENABLED
+	}
+	pending, err := u.redisRepository.GetResource(ctx, totpEnrollmentKey(userId))
+	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.RecoveryCodesResponse{}, dto.Err_NOTFOUND_TOTP_ENROLLMENT
+		}
+		return dto.RecoveryCodesResponse{}, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(pending)
+	if err != nil {
+		u.emitTOTPError(err)
+		return dto.RecoveryCodesResponse{}, dto.Err_INTERNAL_TOTP_SECRET
+	}
+	totpSecret, err := u.openTOTPSecret(userId, sealed)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	// the first code proves the authenticator holds the secret before it is required at login
+	if err := u.checkTOTPCode(ctx, userId, totpSecret, req.Code); err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+
+	updated := user.User
+	updated.TwoFactorEnabled = true
+	event, err := u.newUserUpdatedEvent(&updated)
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	codes, codeHashes, err := u.generateRecoveryCodes()
+	if err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	if err := u.userRepository.UpdateTOTPSecret(ctx, userId, sealed, codeHashes, event); err != nil {
+		return dto.RecoveryCodesResponse{}, err
+	}
+	u.invalidateProfile(ctx, userId)
+	if err := u.redisRepository.RemoveResource(ctx, totpEnrollmentKey(userId)); err != nil {
+		u.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to remove totp enrollment")
+	}
+	return dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// the recovery codes are dropped together with the secret
+func (u *userService) DisableTOTP(ctx context.Context, req *dto.DisableTOTPRequest, userId string) error {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return dto.Err_CONFLICT_TWO_FACTOR_DISABLED
+	}
+	if err := u.reauthenticateTwoFactor(ctx, user, req.Password, req.Code); err != nil {
+		return err
+	}
+
+	updated := user.User
+	updated.TwoFactorEnabled = false
+	event, err := u.newUserUpdatedEvent(&updated)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepository.UpdateTOTPSecret(ctx, userId, nil, nil, event); err != nil {
+		return err
+	}
+	u.invalidateProfile(ctx, userId)
+	return nil
+}
+
+// checks a code against the enrolled secret, used at login through grpc
+func (u *userService) VerifyTOTP(ctx context.Context, code string, userId string) error {
+	sealed, err := u.userRepository.QueryTOTPSecret(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if sealed == nil {
+		user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled {
+			return dto.Err_CONFLICT_TWO_FACTOR_REENROLL
+		}
+		return dto.Err_CONFLICT_TWO_FACTOR_DISABLED
+	}
+	totpSecret, err := u.openTOTPSecret(userId, sealed)
+	if err != nil {
+		return err
+	}
+	return u.checkTOTPCode(ctx, userId, totpSecret, code)
+}
+
+// users switched on by the old toggle have the flag without a secret, they enroll again instead of being
+// blocked as already enabled. the flag is left as it is until then, so the event bus never disagrees with the row
+func (u *userService) twoFactorEnrolled(ctx context.Context, user *dto.UserProfile) (bool, error) {
+	if !user.TwoFactorEnabled {
+		return false, nil
+	}
+	sealed, err := u.userRepository.QueryTOTPSecret(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	return sealed != nil, nil
+}
+
+func (u *userService) checkTOTPCode(ctx context.Context, userId, totpSecret, code string) error {
+	// counted before the comparison, six digits are guessable without a limit
+	attemptsKey := fmt.Sprintf("totpAttempts:%s", userId)
+	attempts, err := u.redisRepository.IncrementResource(ctx, attemptsKey, viper.GetDuration("app.two_factor.attempt_window"))
+	if err != nil {
+		return err
+	}
+	if attempts > viper.GetInt64("app.two_factor.max_attempts") {
+		go func() {
+			if err := u.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS
+	}
+
+	// a code stays valid for the whole skew window, remembering the last one stops it from being replayed
+	usedKey := fmt.Sprintf("totpUsedCode:%s", userId)
+	lastUsed, err := u.redisRepository.GetResource(ctx, usedKey)
+	if err != nil && err != dto.Err_NOTFOUND_KEY_NOTFOUND {
+		return err
+	}
+	valid, _ := totp.ValidateCustom(code, totpSecret, time.Now().UTC(), totp.ValidateOpts{
+		Period:    totpPeriod,
+		Skew:      totpSkew,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if !valid || code == lastUsed {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_UNAUTHORIZED_OTP_INVALID.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_UNAUTHORIZED_OTP_INVALID
+	}
+
+	if err := u.redisRepository.SetResource(ctx, usedKey, code, (2*totpSkew+1)*totpPeriod*time.Second); err != nil {
+		return err
+	}
+	if err := u.redisRepository.RemoveResource(ctx, attemptsKey); err != nil {
+		u.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to reset totp attempts")
+	}
+	return nil
+}
+
+// the user id is bound as additional data so a sealed secret copied to another row does not open
+func (u *userService) sealTOTPSecret(userId, totpSecret string) ([]byte, error) {
+	key, err := secret.ParseKey(viper.GetString("app.two_factor.encryption_key"))
+	if err != nil {
+		u.emitTOTPError(err)
+		return nil, dto.Err_INTERNAL_TOTP_SECRET
+	}
+	sealed, err := secret.Seal(key, []byte(totpSecret), []byte(userId))
+	if err != nil {
+		u.emitTOTPError(err)
+		return nil, dto.Err_INTERNAL_TOTP_SECRET
+	}
+	return sealed, nil
+}
+
+func (u *userService) openTOTPSecret(userId string, sealed []byte) (string, error) {
+	key, err := secret.ParseKey(viper.GetString("app.two_factor.encryption_key"))
+	if err != nil {
+		u.emitTOTPError(err)
+		return "", dto.Err_INTERNAL_TOTP_SECRET
+	}
+	totpSecret, err := secret.Open(key, sealed, []byte(userId))
+	if err != nil {
+		u.emitTOTPError(err)
+		return "", dto.Err_INTERNAL_TOTP_SECRET
+	}
+	return string(totpSecret), nil
+}
+
+func (u *userService) emitTOTPError(err error) {
+	go func() {
+		if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. err: %v", dto.Err_INTERNAL_TOTP_SECRET.Error(), err)); err != nil {
+			u.logger.Error().Err(err).Msg("failed to emit log")
+		}
+	}()
+}
+
+func totpQRCode(key *otp.Key) (string, error) {
+	img, err := key.Image(totpQRSize, totpQRSize)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func totpEnrollmentKey(userId string) string {
+	return fmt.Sprintf("totpEnrollment:%s", userId)
+}
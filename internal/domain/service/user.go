@@ -2,43 +2,82 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"mime/multipart"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/repository"
 	"github.com/micros-template/user-service/internal/infrastructure/logger"
-	_mq "github.com/micros-template/user-service/internal/infrastructure/message-queue"
+	"github.com/micros-template/user-service/pkg/avatar"
 	"github.com/micros-template/user-service/pkg/constant"
+	"github.com/micros-template/user-service/pkg/password"
+	"github.com/micros-template/user-service/pkg/timeout"
 
-	"github.com/micros-template/event-bus-client/pkg/event"
-
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/micros-template/proto-file/pkg/fpb"
-	"github.com/micros-template/proto-user/pkg/upb"
 	_dto "github.com/micros-template/sharedlib/dto"
+	"github.com/micros-template/sharedlib/model"
 	"github.com/micros-template/sharedlib/utils"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 )
 
+// bounds the re-reads of updateUserGuarded on a busy row
+const maxGuardedUpdateAttempts = 3
+
 type (
 	UserService interface {
-		GetProfile(userId string) (dto.GetProfileResponse, error)
-		UpdateUser(req *dto.UpdateUserRequest, userId string) error
-		UpdateEmail(req *dto.UpdateEmailRequest, userId string) error
-		UpdatePassword(req *dto.UpdatePasswordRequest, userId string) error
-		DeleteUser(req *dto.DeleteUserRequest, userId string) error
+		GetProfile(ctx context.Context, userId string) (dto.GetProfileResponse, error)
+		ListUsers(ctx context.Context, req *dto.ListUsersRequest) (dto.ListUsersResponse, error)
+		UpdateUser(ctx context.Context, req *dto.UpdateUserRequest, userId string) (dto.GetProfileResponse, error)
+		UpdateAvatar(ctx context.Context, file *multipart.FileHeader, userId string) error
+		DeleteAvatar(ctx context.Context, userId string) error
+		GetAvatarURL(ctx context.Context, userId string, size string) (string, error)
+		GetDefaultAvatar(ctx context.Context, userId string) ([]byte, error)
+		CheckUsernameAvailability(ctx context.Context, username string, userId string) (dto.UsernameAvailabilityResponse, error)
+		UpdateEmail(ctx context.Context, req *dto.UpdateEmailRequest, userId string) error
+		VerifyEmailChange(ctx context.Context, req *dto.VerifyEmailChangeRequest, userId string) error
+		UpdatePhoneNumber(ctx context.Context, phoneNumber string, userId string) error
+		VerifyPhoneNumber(ctx context.Context, req *dto.VerifyPhoneNumberRequest, userId string) error
+		SetupTOTP(ctx context.Context, userId string) (dto.SetupTOTPResponse, error)
+		EnableTOTP(ctx context.Context, req *dto.EnableTOTPRequest, userId string) (dto.RecoveryCodesResponse, error)
+		DisableTOTP(ctx context.Context, req *dto.DisableTOTPRequest, userId string) error
+		VerifyTOTP(ctx context.Context, code string, userId string) error
+		RegenerateRecoveryCodes(ctx context.Context, req *dto.RegenerateRecoveryCodesRequest, userId string) (dto.RecoveryCodesResponse, error)
+		CountRecoveryCodes(ctx context.Context, userId string) (dto.RecoveryCodesCountResponse, error)
+		UseRecoveryCode(ctx context.Context, code string, userId string) error
+		BeginPasskeyRegistration(ctx context.Context, userId string) (*protocol.CredentialCreation, error)
+		FinishPasskeyRegistration(ctx context.Context, req *dto.FinishPasskeyRegistrationRequest, userId string) (dto.PasskeyResponse, error)
+		ListPasskeys(ctx context.Context, userId string) ([]dto.PasskeyResponse, error)
+		RenamePasskey(ctx context.Context, req *dto.RenamePasskeyRequest, id int64, userId string) error
+		DeletePasskey(ctx context.Context, id int64, userId string) error
+		GetWebAuthnCredentials(ctx context.Context, userId string) ([]dto.WebAuthnCredential, error)
+		UpdateWebAuthnCredentialUsage(ctx context.Context, usage *dto.WebAuthnCredentialUsage) error
+		UpdatePassword(ctx context.Context, req *dto.UpdatePasswordRequest, userId string) error
+		DeleteUser(ctx context.Context, req *dto.DeleteUserRequest, userId string) error
+		ConfirmDeleteUser(ctx context.Context, req *dto.ConfirmDeleteUserRequest, userId string) error
+		RestoreUser(ctx context.Context, userId string) error
+		PurgeDeletedUsers(ctx context.Context) (int, error)
 	}
 	userService struct {
-		userRepository     repository.UserRepository
-		logger             zerolog.Logger
-		fileServiceClient  fpb.FileServiceClient
-		redisRepository    repository.RedisRepository
-		notificationStream _mq.Nats
-		eventEmitter       event.Emitter
-		logEmitter         logger.LoggerInfra
+		userRepository    repository.UserRepository
+		logger            zerolog.Logger
+		fileServiceClient fpb.FileServiceClient
+		redisRepository   repository.RedisRepository
+		outboxRepository  repository.OutboxRepository
+		profileCache      repository.ProfileCacheRepository
+		logEmitter        logger.LoggerInfra
 	}
 )
 
@@ -46,27 +85,29 @@ func NewUserService(userRepo repository.UserRepository,
 	logger zerolog.Logger,
 	fileServiceClient fpb.FileServiceClient,
 	redisRepository repository.RedisRepository,
-	notificationStream _mq.Nats,
-	eventEmitter event.Emitter,
+	outboxRepository repository.OutboxRepository,
+	profileCache repository.ProfileCacheRepository,
 	logEmitter logger.LoggerInfra,
 ) UserService {
 	return &userService{
-		userRepository:     userRepo,
-		logger:             logger,
-		fileServiceClient:  fileServiceClient,
-		redisRepository:    redisRepository,
-		notificationStream: notificationStream,
-		eventEmitter:       eventEmitter,
-		logEmitter:         logEmitter,
+		userRepository:    userRepo,
+		logger:            logger,
+		fileServiceClient: fileServiceClient,
+		redisRepository:   redisRepository,
+		outboxRepository:  outboxRepository,
+		profileCache:      profileCache,
+		logEmitter:        logEmitter,
 	}
 }
 
-func (u *userService) DeleteUser(req *dto.DeleteUserRequest, userId string) error {
-	//  [IMPROVE] -> send email for delete verification
-	user, err := u.userRepository.QueryUserByUserId(userId)
+func (u *userService) DeleteUser(ctx context.Context, req *dto.DeleteUserRequest, userId string) error {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(user, req.ExpectedVersions); err != nil {
+		return err
+	}
 	ok := utils.HashPasswordCompare(req.Password, user.Password)
 	if !ok {
 		go func() {
@@ -76,18 +117,77 @@ func (u *userService) DeleteUser(req *dto.DeleteUserRequest, userId string) erro
 		}()
 		return dto.Err_UNAUTHORIZED_PASSWORD_WRONG
 	}
-	if err := u.userRepository.DeleteUser(userId); err != nil {
+	deletionToken, err := utils.RandomString64()
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_GENERATE_TOKEN.Error()); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_GENERATE_TOKEN
+	}
+
+	key := fmt.Sprintf("deleteAccountToken:%s", userId)
+	if err := u.redisRepository.SetResource(ctx, key, deletionToken, 30*time.Minute); err != nil {
 		return err
 	}
-	go func() {
-		u.eventEmitter.DeleteUser(context.Background(), &upb.UserId{
-			UserId: userId,
-		})
-	}()
+
+	link := deleteConfirmationLink(deletionToken)
+	return u.publishMail(ctx, userId, &_dto.MailNotificationMessage{
+		Receiver: []string{user.Email},
+		MsgType:  "deleteAccount",
+		Message:  link,
+	})
+}
+
+// app.delete_confirmation_url is a page of the web client, it asks the signed in user to confirm and
+// posts the token to POST /delete/confirm. opening the link alone never deletes, mail scanners follow links
+func deleteConfirmationLink(token string) string {
+	return viper.GetString("app.delete_confirmation_url") + "?" + url.Values{"token": {token}}.Encode()
+}
+
+func (u *userService) ConfirmDeleteUser(ctx context.Context, req *dto.ConfirmDeleteUserRequest, userId string) error {
+	// the token is consumed before the delete, so a link can be used once even if the delete fails
+	key := fmt.Sprintf("deleteAccountToken:%s", userId)
+	deletionToken, err := u.redisRepository.TakeResource(ctx, key)
+	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.Err_UNAUTHORIZED_TOKEN_INVALID
+		}
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(deletionToken), []byte(req.Token)) != 1 {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_UNAUTHORIZED_TOKEN_INVALID.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_UNAUTHORIZED_TOKEN_INVALID
+	}
+	// soft delete, the event is pushed once the purger removes the row for good
+	if err := u.userRepository.DeleteUser(ctx, userId); err != nil {
+		return err
+	}
+	u.invalidateProfile(ctx, userId)
 	return nil
 }
 
-func (u *userService) UpdatePassword(req *dto.UpdatePasswordRequest, userId string) error {
+func (u *userService) RestoreUser(ctx context.Context, userId string) error {
+	deletedAfter := time.Now().Add(-viper.GetDuration("app.soft_delete.grace_period"))
+	return u.userRepository.RestoreUser(ctx, userId, deletedAfter)
+}
+
+func (u *userService) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	deletedBefore := time.Now().Add(-viper.GetDuration("app.soft_delete.grace_period"))
+	// the deleted events are stored in the outbox within the purge transaction
+	userIds, err := u.userRepository.PurgeDeletedUsers(ctx, deletedBefore, newUserDeletedMessage)
+	if err != nil {
+		return 0, err
+	}
+	return len(userIds), nil
+}
+
+func (u *userService) UpdatePassword(ctx context.Context, req *dto.UpdatePasswordRequest, userId string) error {
 	if req.NewPassword != req.ConfirmNewPassword {
 		go func() {
 			if err := u.logEmitter.EmitLog("ERR", dto.Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH.Error()); err != nil {
@@ -96,7 +196,7 @@ func (u *userService) UpdatePassword(req *dto.UpdatePasswordRequest, userId stri
 		}()
 		return dto.Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH
 	}
-	user, err := u.userRepository.QueryUserByUserId(userId)
+	user, err := u.userRepository.QueryUserByUserId(ctx, userId)
 	if err != nil {
 		return err
 	}
@@ -109,6 +209,27 @@ func (u *userService) UpdatePassword(req *dto.UpdatePasswordRequest, userId stri
 		}()
 		return dto.Err_UNAUTHORIZED_PASSWORD_WRONG
 	}
+	violations := newPasswordPolicy().Check(req.NewPassword, user.Email, user.FullName)
+	historySize := passwordHistorySize()
+	reused, err := u.isPasswordReused(ctx, user, req.NewPassword, historySize)
+	if err != nil {
+		return err
+	}
+	if reused {
+		violations = append(violations, password.ReuseViolation())
+	}
+	if len(violations) > 0 {
+		rules := make([]string, 0, len(violations))
+		for _, v := range violations {
+			rules = append(rules, string(v.Rule))
+		}
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("password policy violated. rules: %s, userId: %s", strings.Join(rules, ","), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return &dto.PasswordPolicyError{Violations: violations}
+	}
 	newPassword, err := utils.HashPassword(req.NewPassword)
 	if err != nil {
 		go func() {
@@ -120,26 +241,18 @@ func (u *userService) UpdatePassword(req *dto.UpdatePasswordRequest, userId stri
 	}
 	us := *user
 	us.Password = newPassword
-	if err := u.userRepository.UpdateUser(&us); err != nil {
-		return err
-	}
-	go func() {
-		u.eventEmitter.UpdateUser(context.Background(), &upb.User{
-			Id:               us.ID,
-			FullName:         us.FullName,
-			Image:            us.Image,
-			Email:            us.Email,
-			Password:         us.Password,
-			Verified:         us.Verified,
-			TwoFactorEnabled: us.TwoFactorEnabled,
-		})
-	}()
+	event, err := u.newUserUpdatedEvent(&us)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepository.UpdateUserPassword(ctx, userId, newPassword, user.Password, historySize-1, event); err != nil {
+		return err
+	}
+	u.invalidateProfile(ctx, userId)
 	return nil
 }
 
-func (u *userService) UpdateEmail(req *dto.UpdateEmailRequest, userId string) error {
-	ctx := context.Background()
-
+func (u *userService) UpdateEmail(ctx context.Context, req *dto.UpdateEmailRequest, userId string) error {
 	verificationToken, err := utils.RandomString64()
 	if err != nil {
 		go func() {
@@ -161,142 +274,742 @@ func (u *userService) UpdateEmail(req *dto.UpdateEmailRequest, userId string) er
 	}
 
 	link := fmt.Sprintf("%s/%suserid=%s&changeEmailToken=%s", viper.GetString("app.auth_url"), viper.GetString("app.verification_url"), userId, verificationToken)
-	subject := fmt.Sprintf("%s.%s", viper.GetString("jetstream.notification.subject.mail"), userId)
-	msg := &_dto.MailNotificationMessage{
+	return u.publishMail(ctx, userId, &_dto.MailNotificationMessage{
 		Receiver: []string{req.Email},
 		MsgType:  "changeEmail",
 		Message:  link,
+	})
+}
+
+func (u *userService) VerifyEmailChange(ctx context.Context, req *dto.VerifyEmailChangeRequest, userId string) error {
+	// the token is consumed before anything is written, so a link can be used once even if a later step fails
+	tokenKey := fmt.Sprintf("changeEmailToken:%s", userId)
+	verificationToken, err := u.redisRepository.TakeResource(ctx, tokenKey)
+	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.Err_UNAUTHORIZED_TOKEN_INVALID
+		}
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(verificationToken), []byte(req.Token)) != 1 {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_UNAUTHORIZED_TOKEN_INVALID.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_UNAUTHORIZED_TOKEN_INVALID
 	}
-	marshalledMsg, err := json.Marshal(msg)
+
+	emailKey := fmt.Sprintf("newEmail:%s", userId)
+	newEmail, err := u.redisRepository.TakeResource(ctx, emailKey)
 	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.Err_UNAUTHORIZED_TOKEN_INVALID
+		}
+		return err
+	}
+
+	existing, err := u.userRepository.QueryUserByEmail(ctx, newEmail)
+	if err != nil && err != dto.Err_NOTFOUND_USER_NOT_FOUND {
+		return err
+	}
+	if existing != nil && existing.ID != userId {
 		go func() {
-			if err := u.logEmitter.EmitLog("ERR", "marshal data error"); err != nil {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_CONFLICT_EMAIL_EXIST.Error(), userId)); err != nil {
 				u.logger.Error().Err(err).Msg("failed to emit log")
 			}
 		}()
+		return dto.Err_CONFLICT_EMAIL_EXIST
+	}
+
+	if err := u.updateUserGuarded(ctx, userId, func(us *model.User) { us.Email = newEmail }); err != nil {
 		return err
 	}
-	_, err = u.notificationStream.Publish(ctx, subject, []byte(marshalledMsg))
+	u.invalidateProfile(ctx, userId)
+	return nil
+}
+
+// phoneNumber is expected in E.164 form
+func (u *userService) UpdatePhoneNumber(ctx context.Context, phoneNumber string, userId string) error {
+	// every code costs an sms, so requests are capped per user
+	sends, err := u.redisRepository.IncrementResource(ctx, fmt.Sprintf("phoneOtpSends:%s", userId), viper.GetDuration("app.phone_verification.send_window"))
 	if err != nil {
+		return err
+	}
+	if sends > viper.GetInt64("app.phone_verification.max_sends") {
 		go func() {
-			if err := u.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_PUBLISH_MESSAGE.Error()); err != nil {
+			if err := u.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_TOO_MANY_REQUESTS_OTP_SENT.Error(), userId)); err != nil {
 				u.logger.Error().Err(err).Msg("failed to emit log")
 			}
 		}()
-		return dto.Err_INTERNAL_PUBLISH_MESSAGE
+		return dto.Err_TOO_MANY_REQUESTS_OTP_SENT
 	}
-	return nil
+
+	code, err := newOTP()
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", dto.Err_INTERNAL_GENERATE_TOKEN.Error()); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_INTERNAL_GENERATE_TOKEN
+	}
+
+	ttl := viper.GetDuration("app.phone_verification.otp_ttl")
+	if err := u.redisRepository.SetResource(ctx, fmt.Sprintf("newPhone:%s", userId), phoneNumber, ttl); err != nil {
+		return err
+	}
+	if err := u.redisRepository.SetResource(ctx, fmt.Sprintf("phoneOtp:%s", userId), code, ttl); err != nil {
+		return err
+	}
+	// a new code gets a fresh set of attempts
+	if err := u.redisRepository.RemoveResource(ctx, fmt.Sprintf("phoneOtpAttempts:%s", userId)); err != nil {
+		return err
+	}
+
+	return u.publishSMS(ctx, userId, &_dto.MailNotificationMessage{
+		Receiver: []string{phoneNumber},
+		MsgType:  "verifyPhone",
+		Message:  code,
+	})
 }
 
-func (u *userService) UpdateUser(req *dto.UpdateUserRequest, userId string) error {
-	user, err := u.userRepository.QueryUserByUserId(userId)
+func (u *userService) VerifyPhoneNumber(ctx context.Context, req *dto.VerifyPhoneNumberRequest, userId string) error {
+	codeKey := fmt.Sprintf("phoneOtp:%s", userId)
+	phoneKey := fmt.Sprintf("newPhone:%s", userId)
+	code, err := u.redisRepository.GetResource(ctx, codeKey)
 	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.Err_UNAUTHORIZED_OTP_INVALID
+		}
 		return err
 	}
-	us := *user
-	trimmedName := strings.TrimSpace(req.FullName)
-	if trimmedName != user.FullName {
-		us.FullName = trimmedName
+
+	// counted before the comparison, six digits are guessable without a limit
+	ttl := viper.GetDuration("app.phone_verification.otp_ttl")
+	attempts, err := u.redisRepository.IncrementResource(ctx, fmt.Sprintf("phoneOtpAttempts:%s", userId), ttl)
+	if err != nil {
+		return err
+	}
+	if attempts > viper.GetInt64("app.phone_verification.max_attempts") {
+		u.removePhoneVerification(ctx, codeKey, phoneKey)
+		go func() {
+			if err := u.logEmitter.EmitLog("WARN", fmt.Sprintf("%s. user_id: %s", dto.Err_TOO_MANY_REQUESTS_OTP_ATTEMPTS.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_TOO_MANY_REQUESTS_OTP_ATTEMPTS
 	}
-	if req.TwoFactorEnabled != user.TwoFactorEnabled {
-		us.TwoFactorEnabled = req.TwoFactorEnabled
+	if subtle.ConstantTimeCompare([]byte(code), []byte(req.Code)) != 1 {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s", dto.Err_UNAUTHORIZED_OTP_INVALID.Error(), userId)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return dto.Err_UNAUTHORIZED_OTP_INVALID
 	}
-	ctx := context.Background()
 
-	if req.Image != nil && req.Image.Filename != "" {
-		ext := utils.GetFileNameExtension(req.Image.Filename)
-		if ext != "jpg" && ext != "jpeg" && ext != "png" {
-			go func() {
-				if err := u.logEmitter.EmitLog("ERR", dto.Err_BAD_REQUEST_WRONG_EXTENSION.Error()); err != nil {
-					u.logger.Error().Err(err).Msg("failed to emit log")
-				}
-			}()
-			return dto.Err_BAD_REQUEST_WRONG_EXTENSION
+	phoneNumber, err := u.redisRepository.GetResource(ctx, phoneKey)
+	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.Err_UNAUTHORIZED_OTP_INVALID
 		}
-		if req.Image.Size > constant.MAX_UPLOAD_SIZE {
-			go func() {
-				if err := u.logEmitter.EmitLog("ERR", dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED.Error()); err != nil {
-					u.logger.Error().Err(err).Msg("failed to emit log")
-				}
-			}()
-			return dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED
+		return err
+	}
+
+	if err := u.updatePhoneNumberGuarded(ctx, userId, phoneNumber); err != nil {
+		return err
+	}
+	u.invalidateProfile(ctx, userId)
+	u.removePhoneVerification(ctx, codeKey, phoneKey, fmt.Sprintf("phoneOtpAttempts:%s", userId))
+	return nil
+}
+
+// the whole profile row is written, so the write is guarded by the version that was read like updateUserGuarded
+func (u *userService) updatePhoneNumberGuarded(ctx context.Context, userId, phoneNumber string) error {
+	for attempt := 1; ; attempt++ {
+		user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+		if err != nil {
+			return err
 		}
-		image, err := utils.FileToByte(req.Image)
+		updated := *user
+		updated.PhoneNumber = &phoneNumber
+		updated.PhoneVerified = true
+		// nil keeps the stored variants
+		updated.ImageVariants = nil
+		event, err := u.newUserUpdatedEvent(&updated.User)
 		if err != nil {
-			go func() {
-				if err := u.logEmitter.EmitLog("ERR", "error converting image to byte"); err != nil {
-					u.logger.Error().Err(err).Msg("failed to emit log")
-				}
-			}()
-			return dto.Err_INTERNAL_CONVERT_IMAGE
+			return err
+		}
+		profileEvent, err := u.newUserProfileEvent(&updated)
+		if err != nil {
+			return err
+		}
+		err = u.userRepository.UpdateProfile(ctx, &updated, user.Version, event, profileEvent)
+		if err != dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH || attempt == maxGuardedUpdateAttempts {
+			return err
+		}
+	}
+}
+
+func (u *userService) removePhoneVerification(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := u.redisRepository.RemoveResource(ctx, key); err != nil {
+			u.logger.Warn().Err(err).Str("key", key).Msg("failed to remove phone verification resource")
 		}
-		imageReq := &fpb.Image{
-			Image: image,
-			Ext:   ext,
+	}
+}
+
+// uniformly distributed six digit code
+func newOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func (u *userService) publishSMS(ctx context.Context, userId string, msg *_dto.MailNotificationMessage) error {
+	outboxMsg, err := newSMSMessage(userId, msg)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", "marshal data error"); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return err
+	}
+	return u.outboxRepository.Enqueue(ctx, outboxMsg)
+}
+
+func (u *userService) publishMail(ctx context.Context, userId string, msg *_dto.MailNotificationMessage) error {
+	outboxMsg, err := newMailMessage(userId, msg)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", "marshal data error"); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return err
+	}
+	// mail is delivered by the outbox relay, so a nats outage does not lose it
+	return u.outboxRepository.Enqueue(ctx, outboxMsg)
+}
+
+// a failed invalidation only leaves a stale profile until the ttl expires, so it does not fail the request
+func (u *userService) invalidateProfile(ctx context.Context, userId string) {
+	if err := u.profileCache.Invalidate(ctx, userId); err != nil {
+		u.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to invalidate cached profile")
+	}
+}
+
+// read-modify-write for paths without an If-Match from the caller. The write is guarded by the
+// version that was read, a concurrent update is re-read and modify applied again instead of being overwritten
+func (u *userService) updateUserGuarded(ctx context.Context, userId string, modify func(us *model.User)) error {
+	for attempt := 1; ; attempt++ {
+		profile, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+		if err != nil {
+			return err
 		}
-		resp, err := u.fileServiceClient.SaveProfileImage(ctx, imageReq)
+		us := profile.User
+		modify(&us)
+		event, err := u.newUserUpdatedEvent(&us)
 		if err != nil {
-			go func() {
-				if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("Error uploading image to file service. err: %v", err.Error())); err != nil {
-					u.logger.Error().Err(err).Msg("failed to emit log")
-				}
-			}()
 			return err
 		}
-		us.Image = utils.StringPtr(resp.GetName())
+		err = u.userRepository.UpdateUser(ctx, &us, profile.Version, event)
+		if err != dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH || attempt == maxGuardedUpdateAttempts {
+			return err
+		}
 	}
-	err = u.userRepository.UpdateUser(&us)
-	if err == nil && req.Image != nil && req.Image.Filename != "" {
-		if _, err := u.fileServiceClient.RemoveProfileImage(ctx, &fpb.ImageName{Name: *user.Image}); err != nil {
-			go func() {
-				if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("Error remove image via file service. err: %v", err.Error())); err != nil {
-					u.logger.Error().Err(err).Msg("failed to emit log")
-				}
-			}()
+}
+
+func (u *userService) newUserUpdatedEvent(user *model.User) (*dto.OutboxMessage, error) {
+	event, err := newUserEventMessage(constant.EVENT_UPDATE_USER, user)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", "marshal data error"); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, err
+	}
+	return event, nil
+}
+
+func (u *userService) newUserProfileEvent(profile *dto.UserProfile) (*dto.OutboxMessage, error) {
+	event, err := newUserProfileMessage(profile)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", "marshal data error"); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, err
+	}
+	return event, nil
+}
+
+func (u *userService) UpdateUser(ctx context.Context, req *dto.UpdateUserRequest, userId string) (dto.GetProfileResponse, error) {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return dto.GetProfileResponse{}, err
+	}
+	// fails before an image is uploaded for nothing
+	if err := checkVersion(user, req.ExpectedVersions); err != nil {
+		return dto.GetProfileResponse{}, err
+	}
+	us := user.User
+	// nil means the field was absent from the request, it keeps the stored value
+	if req.FullName != nil {
+		us.FullName = strings.TrimSpace(*req.FullName)
+	}
+	fields, err := applyProfileFields(user.ProfileFields, req)
+	if err != nil {
+		return dto.GetProfileResponse{}, err
+	}
+	var variants dto.ImageVariants
+	if req.Image != nil && req.Image.Filename != "" {
+		variants, err = u.uploadProfileImage(ctx, req.Image)
+		if err != nil {
+			return dto.GetProfileResponse{}, err
+		}
+		us.Image = primaryImage(variants)
+	} else if req.RemoveImage && (user.Image != nil || len(user.ImageVariants) > 0) {
+		variants = dto.ImageVariants{}
+		us.Image = nil
+	}
+	updated := &dto.UserProfile{User: us, ImageVariants: user.ImageVariants, ProfileFields: fields, Version: user.Version}
+	if variants != nil {
+		updated.ImageVariants = variants
+	}
+	// an empty or unchanged patch does not publish an event
+	if variants == nil && us == user.User && sameProfileFields(fields, user.ProfileFields) {
+		return toProfileResponse(updated), nil
+	}
+
+	event, err := u.newUserUpdatedEvent(&us)
+	if err != nil {
+		return dto.GetProfileResponse{}, err
+	}
+	outbox := []*dto.OutboxMessage{event}
+	if !sameProfileFields(fields, user.ProfileFields) {
+		profileEvent, err := u.newUserProfileEvent(updated)
+		if err != nil {
+			return dto.GetProfileResponse{}, err
 		}
-	} else if err != nil {
+		outbox = append(outbox, profileEvent)
+	}
+	// the write only applies to the version that was read, a concurrent update fails instead of being overwritten.
+	// nil variants keep the stored ones
+	write := &dto.UserProfile{User: us, ImageVariants: variants, ProfileFields: fields}
+	if err := u.userRepository.UpdateProfile(ctx, write, user.Version, outbox...); err != nil {
 		go func() {
 			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("update user failed. err: %v", err.Error())); err != nil {
 				u.logger.Error().Err(err).Msg("failed to emit log")
 			}
 		}()
-		if req.Image != nil && req.Image.Filename != "" {
-			if _, err := u.fileServiceClient.RemoveProfileImage(ctx, &fpb.ImageName{Name: *us.Image}); err != nil {
-				go func() {
-					if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("Error remove image via file service. err: %v", err.Error())); err != nil {
-						u.logger.Error().Err(err).Msg("failed to emit log")
-					}
-				}()
+		if variants != nil {
+			u.removeProfileImages(ctx, nil, variants)
+		}
+		return dto.GetProfileResponse{}, err
+	}
+	u.invalidateProfile(ctx, userId)
+	if variants != nil {
+		u.removeProfileImages(ctx, user.Image, user.ImageVariants)
+	}
+	updated.Version++
+	return toProfileResponse(updated), nil
+}
+
+// present members replace the stored value, a nil value clears it
+func applyProfileFields(fields dto.ProfileFields, req *dto.UpdateUserRequest) (dto.ProfileFields, error) {
+	for _, f := range []struct {
+		dst   **string
+		patch dto.PatchString
+	}{
+		{&fields.Username, req.Username},
+		{&fields.Bio, req.Bio},
+		{&fields.Locale, req.Locale},
+		{&fields.Timezone, req.Timezone},
+	} {
+		if f.patch.Set {
+			*f.dst = f.patch.Value
+		}
+	}
+	// the number can only be removed here, adding or changing it goes through the sms verification
+	if req.PhoneNumber.Set && req.PhoneNumber.Value == nil {
+		fields.PhoneNumber = nil
+		fields.PhoneVerified = false
+	}
+	if req.DateOfBirth.Set {
+		fields.DateOfBirth = nil
+		if req.DateOfBirth.Value != nil {
+			date, err := time.Parse(dto.DateLayout, *req.DateOfBirth.Value)
+			if err != nil {
+				return dto.ProfileFields{}, err
 			}
+			fields.DateOfBirth = &date
 		}
+	}
+	return fields, nil
+}
+
+func sameProfileFields(a, b dto.ProfileFields) bool {
+	if a.PhoneVerified != b.PhoneVerified {
+		return false
+	}
+	if (a.DateOfBirth == nil) != (b.DateOfBirth == nil) || (a.DateOfBirth != nil && !a.DateOfBirth.Equal(*b.DateOfBirth)) {
+		return false
+	}
+	return equalPtr(a.Username, b.Username) && equalPtr(a.Bio, b.Bio) && equalPtr(a.Locale, b.Locale) &&
+		equalPtr(a.Timezone, b.Timezone) && equalPtr(a.PhoneNumber, b.PhoneNumber)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func checkVersion(user *dto.UserProfile, expectedVersions []int64) error {
+	if len(expectedVersions) > 0 && !slices.Contains(expectedVersions, user.Version) {
+		return dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH
+	}
+	return nil
+}
+
+func (u *userService) UpdateAvatar(ctx context.Context, file *multipart.FileHeader, userId string) error {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
 		return err
 	}
-	// push event bus in goroutine
-	go func() {
-		u.eventEmitter.UpdateUser(context.Background(), &upb.User{
-			Id:               us.ID,
-			FullName:         us.FullName,
-			Image:            us.Image,
-			Email:            us.Email,
-			Password:         us.Password,
-			Verified:         us.Verified,
-			TwoFactorEnabled: us.TwoFactorEnabled,
-		})
-	}()
+	variants, err := u.uploadProfileImage(ctx, file)
+	if err != nil {
+		return err
+	}
+	us := user.User
+	us.Image = primaryImage(variants)
+	if err := u.replaceProfileImage(ctx, &us, variants, user.Version); err != nil {
+		// the new files are not referenced by any row
+		u.removeProfileImages(ctx, nil, variants)
+		return err
+	}
+	u.removeProfileImages(ctx, user.Image, user.ImageVariants)
+	return nil
+}
+
+func (u *userService) DeleteAvatar(ctx context.Context, userId string) error {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if user.Image == nil && len(user.ImageVariants) == 0 {
+		return nil
+	}
+	us := user.User
+	us.Image = nil
+	if err := u.replaceProfileImage(ctx, &us, dto.ImageVariants{}, user.Version); err != nil {
+		return err
+	}
+	// the row no longer points at the files, so they are removed only after the update committed
+	u.removeProfileImages(ctx, user.Image, user.ImageVariants)
+	return nil
+}
+
+func (u *userService) GetAvatarURL(ctx context.Context, userId string, size string) (string, error) {
+	if size == "" {
+		size = strconv.Itoa(avatar.Sizes[len(avatar.Sizes)-1])
+	}
+	if n, err := strconv.Atoi(size); err != nil || !slices.Contains(avatar.Sizes, n) {
+		return "", dto.Err_BAD_REQUEST_INVALID_AVATAR_SIZE
+	}
+	user, err := u.profileCache.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	// images uploaded before variants existed only have the primary name
+	name, ok := user.ImageVariants[size]
+	if !ok && user.Image != nil {
+		name, ok = *user.Image, true
+	}
+	if !ok || name == "" {
+		return defaultAvatarURL(userId), nil
+	}
+	return viper.GetString("app.avatar_base_url") + url.PathEscape(name), nil
+}
+
+func (u *userService) GetDefaultAvatar(ctx context.Context, userId string) ([]byte, error) {
+	user, err := u.profileCache.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return avatar.Default(user.ID), nil
+}
+
+func defaultAvatarURL(userId string) string {
+	return viper.GetString("app.default_avatar_base_url") + url.PathEscape(userId)
+}
+
+func (u *userService) replaceProfileImage(ctx context.Context, user *model.User, variants dto.ImageVariants, expectedVersion int64) error {
+	event, err := u.newUserUpdatedEvent(user)
+	if err != nil {
+		return err
+	}
+	if err := u.userRepository.UpdateUserImage(ctx, user, variants, expectedVersion, event); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("update user image failed. err: %v", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return err
+	}
+	u.invalidateProfile(ctx, user.ID)
 	return nil
 }
 
-func (u *userService) GetProfile(userId string) (dto.GetProfileResponse, error) {
-	user, err := u.userRepository.QueryUserByUserId(userId)
+// checks the declared name and size before the file is read, the content itself is validated by the avatar pipeline
+func (u *userService) uploadProfileImage(ctx context.Context, file *multipart.FileHeader) (dto.ImageVariants, error) {
+	ext := utils.GetFileNameExtension(file.Filename)
+	if ext != "jpg" && ext != "jpeg" && ext != "png" {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", dto.Err_BAD_REQUEST_WRONG_EXTENSION.Error()); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_BAD_REQUEST_WRONG_EXTENSION
+	}
+	if file.Size > constant.MAX_UPLOAD_SIZE {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED.Error()); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED
+	}
+	image, err := utils.FileToByte(file)
+	if err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", "error converting image to byte"); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, dto.Err_INTERNAL_CONVERT_IMAGE
+	}
+	return u.saveProfileImageVariants(ctx, image)
+}
+
+// every variant is uploaded as its own file, a failed upload removes the ones already stored
+func (u *userService) saveProfileImageVariants(ctx context.Context, image []byte) (dto.ImageVariants, error) {
+	processed, err := avatar.Process(image)
+	if err != nil {
+		var mapped error
+		switch {
+		case errors.Is(err, avatar.ErrUnsupportedType):
+			mapped = dto.Err_BAD_REQUEST_UNSUPPORTED_IMAGE
+		case errors.Is(err, avatar.ErrCorrupt):
+			mapped = dto.Err_BAD_REQUEST_CORRUPT_IMAGE
+		case errors.Is(err, avatar.ErrTooLarge):
+			mapped = dto.Err_BAD_REQUEST_IMAGE_DIMENSIONS_EXCEEDED
+		default:
+			mapped = dto.Err_INTERNAL_CONVERT_IMAGE
+		}
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. err: %v", mapped.Error(), err)); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+		return nil, mapped
+	}
+
+	variants := make(dto.ImageVariants, len(processed))
+	for _, variant := range processed {
+		fileCtx, cancel := timeout.WithConfig(ctx, constant.TIMEOUT_FILE_SERVICE)
+		resp, err := u.fileServiceClient.SaveProfileImage(fileCtx, &fpb.Image{
+			Image: variant.Data,
+			Ext:   variant.Ext,
+		})
+		cancel()
+		if err != nil {
+			go func() {
+				if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("Error uploading image to file service. err: %v", err.Error())); err != nil {
+					u.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			u.removeProfileImages(ctx, nil, variants)
+			return nil, err
+		}
+		variants[strconv.Itoa(variant.Size)] = resp.GetName()
+	}
+	return variants, nil
+}
+
+// the largest variant is the one stored in the image column
+func primaryImage(variants dto.ImageVariants) *string {
+	return utils.StringPtr(variants[strconv.Itoa(avatar.Sizes[len(avatar.Sizes)-1])])
+}
+
+// the primary image is usually one of the variants, each file is removed once
+func (u *userService) removeProfileImages(ctx context.Context, image *string, variants dto.ImageVariants) {
+	removed := make(map[string]bool, len(variants)+1)
+	if image != nil && *image != "" {
+		removed[*image] = true
+		u.removeProfileImage(ctx, *image)
+	}
+	for _, name := range variants {
+		if name == "" || removed[name] {
+			continue
+		}
+		removed[name] = true
+		u.removeProfileImage(ctx, name)
+	}
+}
+
+// the cleanup outlives a cancelled request, otherwise the replaced image would be orphaned in the file service
+func (u *userService) removeProfileImage(ctx context.Context, name string) {
+	ctx, cancel := timeout.WithConfig(context.WithoutCancel(ctx), constant.TIMEOUT_FILE_SERVICE)
+	defer cancel()
+	if _, err := u.fileServiceClient.RemoveProfileImage(ctx, &fpb.ImageName{Name: name}); err != nil {
+		go func() {
+			if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("Error remove image via file service. err: %v", err.Error())); err != nil {
+				u.logger.Error().Err(err).Msg("failed to emit log")
+			}
+		}()
+	}
+}
+
+func (u *userService) GetProfile(ctx context.Context, userId string) (dto.GetProfileResponse, error) {
+	user, err := u.profileCache.QueryProfileByUserId(ctx, userId)
 	if err != nil {
 		return dto.GetProfileResponse{}, err
 	}
+	return toProfileResponse(user), nil
+}
+
+func toProfileResponse(user *dto.UserProfile) dto.GetProfileResponse {
 	profile := dto.GetProfileResponse{
 		FullName:         user.FullName,
 		Image:            user.Image,
 		Email:            user.Email,
 		Verified:         user.Verified,
 		TwoFactorEnabled: user.TwoFactorEnabled,
+		ImageVariants:    user.ImageVariants,
+		Username:         user.Username,
+		Bio:              user.Bio,
+		Locale:           user.Locale,
+		Timezone:         user.Timezone,
+		PhoneNumber:      user.PhoneNumber,
+		PhoneVerified:    user.PhoneVerified,
+		Version:          user.Version,
+	}
+	if user.DateOfBirth != nil {
+		dateOfBirth := user.DateOfBirth.Format(dto.DateLayout)
+		profile.DateOfBirth = &dateOfBirth
+	}
+	// clients render the generated avatar like any other image instead of drawing their own placeholder.
+	// rows written before empty images were stored as NULL hold an empty string
+	if profile.Image == nil || *profile.Image == "" {
+		image := defaultAvatarURL(user.ID)
+		profile.Image = &image
+		profile.DefaultImage = true
+	}
+	return profile
+}
+
+// the caller's own username counts as available so a client can validate an unchanged form
+func (u *userService) CheckUsernameAvailability(ctx context.Context, username string, userId string) (dto.UsernameAvailabilityResponse, error) {
+	taken, err := u.userRepository.IsUsernameTaken(ctx, username, userId)
+	if err != nil {
+		return dto.UsernameAvailabilityResponse{}, err
+	}
+	return dto.UsernameAvailabilityResponse{Username: username, Available: !taken}, nil
+}
+
+func (u *userService) ListUsers(ctx context.Context, req *dto.ListUsersRequest) (dto.ListUsersResponse, error) {
+	q := &dto.ListUsersQuery{
+		Limit:            req.Limit,
+		Verified:         req.Verified,
+		TwoFactorEnabled: req.TwoFactorEnabled,
+		CreatedFrom:      req.CreatedFrom,
+		CreatedTo:        req.CreatedTo,
+		Email:            strings.TrimSpace(req.Email),
+		Name:             strings.TrimSpace(req.Name),
+		SortBy:           req.SortBy,
+		SortDesc:         req.SortOrder != "asc",
+	}
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+	if q.SortBy == "" {
+		q.SortBy = "created_at"
+	}
+	if req.Cursor != "" {
+		cursor, err := decodeListUsersCursor(req.Cursor)
+		if err != nil || cursor.SortBy != q.SortBy || cursor.Desc != q.SortDesc {
+			go func() {
+				if err := u.logEmitter.EmitLog("ERR", dto.Err_BAD_REQUEST_INVALID_CURSOR.Error()); err != nil {
+					u.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.ListUsersResponse{}, dto.Err_BAD_REQUEST_INVALID_CURSOR
+		}
+		q.After = cursor
+	}
+
+	// fetch one extra row to know whether there is a next page
+	limit := q.Limit
+	q.Limit++
+	users, err := u.userRepository.ListUsers(ctx, q)
+	if err != nil {
+		return dto.ListUsersResponse{}, err
+	}
+	res := dto.ListUsersResponse{Users: users}
+	if uint64(len(users)) > limit {
+		res.Users = users[:limit]
+		last := res.Users[limit-1]
+		cursor := &dto.ListUsersCursor{SortBy: q.SortBy, Desc: q.SortDesc, ID: last.ID}
+		switch q.SortBy {
+		case "full_name":
+			cursor.Value = last.FullName
+		case "email":
+			cursor.Value = last.Email
+		default:
+			cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		next, err := encodeListUsersCursor(cursor)
+		if err != nil {
+			go func() {
+				if err := u.logEmitter.EmitLog("ERR", "marshal data error"); err != nil {
+					u.logger.Error().Err(err).Msg("failed to emit log")
+				}
+			}()
+			return dto.ListUsersResponse{}, err
+		}
+		res.NextCursor = &next
+	}
+	return res, nil
+}
+
+func encodeListUsersCursor(cursor *dto.ListUsersCursor) (string, error) {
+	b, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeListUsersCursor(raw string) (*dto.ListUsersCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, err
+	}
+	var cursor dto.ListUsersCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" {
+		return nil, dto.Err_BAD_REQUEST_INVALID_CURSOR
 	}
-	return profile, nil
+	return &cursor, nil
 }
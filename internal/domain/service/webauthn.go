@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/spf13/viper"
+)
+
+// adapts a user and its passkeys to what the webauthn library expects
+type webAuthnUser struct {
+	id          string
+	name        string
+	displayName string
+	credentials []dto.WebAuthnCredential
+}
+
+// the user id doubles as the user handle, the auth service maps it back at login
+func (w *webAuthnUser) WebAuthnID() []byte {
+	return []byte(w.id)
+}
+
+func (w *webAuthnUser) WebAuthnName() string {
+	return w.name
+}
+
+func (w *webAuthnUser) WebAuthnDisplayName() string {
+	return w.displayName
+}
+
+func (w *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(w.credentials))
+	for _, credential := range w.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+		for _, transport := range credential.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:       credential.AAGUID,
+				SignCount:    credential.SignCount,
+				CloneWarning: credential.CloneWarning,
+			},
+		})
+	}
+	return credentials
+}
+
+// the challenge is kept in redis until the authenticator answers, existing passkeys are excluded
+// so the same authenticator is not registered twice
+func (u *userService) BeginPasskeyRegistration(ctx context.Context, userId string) (*protocol.CredentialCreation, error) {
+	user, err := u.userRepository.QueryProfileByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := u.userRepository.QueryWebAuthnCredentials(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	relyingParty, err := u.newWebAuthn()
+	if err != nil {
+		return nil, err
+	}
+
+	waUser := &webAuthnUser{id: userId, name: user.Email, displayName: user.FullName, credentials: credentials}
+	creation, session, err := relyingParty.BeginRegistration(waUser,
+		webauthn.WithExclusions(webauthn.Credentials(waUser.WebAuthnCredentials()).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		u.emitWebAuthnError(err)
+		return nil, dto.Err_INTERNAL_WEBAUTHN
+	}
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		u.emitWebAuthnError(err)
+		return nil, dto.Err_INTERNAL_WEBAUTHN
+	}
+	if err := u.redisRepository.SetResource(ctx, passkeyRegistrationKey(userId), string(sessionData), viper.GetDuration("app.webauthn.session_ttl")); err != nil {
+		return nil, err
+	}
+	return creation, nil
+}
+
+func (u *userService) FinishPasskeyRegistration(ctx context.Context, req *dto.FinishPasskeyRegistrationRequest, userId string) (dto.PasskeyResponse, error) {
+	sessionData, err := u.redisRepository.GetResource(ctx, passkeyRegistrationKey(userId))
+	if err != nil {
+		if err == dto.Err_NOTFOUND_KEY_NOTFOUND {
+			return dto.PasskeyResponse{}, dto.Err_NOTFOUND_PASSKEY_SESSION
+		}
+		return dto.PasskeyResponse{}, err
+	}
+	// a challenge is answered once, a failed attempt has to begin again
+	if err := u.redisRepository.RemoveResource(ctx, passkeyRegistrationKey(userId)); err != nil {
+		u.logger.Warn().Err(err).Str("user_id", userId).Msg("failed to remove passkey registration")
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(sessionData), &session); err != nil {
+		u.emitWebAuthnError(err)
+		return dto.PasskeyResponse{}, dto.Err_INTERNAL_WEBAUTHN
+	}
+	relyingParty, err := u.newWebAuthn()
+	if err != nil {
+		return dto.PasskeyResponse{}, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		u.emitInvalidPasskey(userId, err)
+		return dto.PasskeyResponse{}, dto.Err_BAD_REQUEST_INVALID_PASSKEY
+	}
+	credential, err := relyingParty.CreateCredential(&webAuthnUser{id: userId}, session, parsed)
+	if err != nil {
+		u.emitInvalidPasskey(userId, err)
+		return dto.PasskeyResponse{}, dto.Err_BAD_REQUEST_INVALID_PASSKEY
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	stored := &dto.WebAuthnCredential{
+		UserID:          userId,
+		CredentialID:    credential.ID,
+		Name:            req.Name,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		Transports:      transports,
+		SignCount:       credential.Authenticator.SignCount,
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := u.userRepository.CreateWebAuthnCredential(ctx, stored); err != nil {
+		return dto.PasskeyResponse{}, err
+	}
+	return toPasskeyResponse(stored), nil
+}
+
+func (u *userService) ListPasskeys(ctx context.Context, userId string) ([]dto.PasskeyResponse, error) {
+	credentials, err := u.userRepository.QueryWebAuthnCredentials(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	passkeys := make([]dto.PasskeyResponse, 0, len(credentials))
+	for i := range credentials {
+		passkeys = append(passkeys, toPasskeyResponse(&credentials[i]))
+	}
+	return passkeys, nil
+}
+
+func (u *userService) RenamePasskey(ctx context.Context, req *dto.RenamePasskeyRequest, id int64, userId string) error {
+	return u.userRepository.RenameWebAuthnCredential(ctx, userId, id, req.Name)
+}
+
+func (u *userService) DeletePasskey(ctx context.Context, id int64, userId string) error {
+	return u.userRepository.DeleteWebAuthnCredential(ctx, userId, id)
+}
+
+// the auth service verifies assertions itself and only needs the stored credentials, used through grpc
+func (u *userService) GetWebAuthnCredentials(ctx context.Context, userId string) ([]dto.WebAuthnCredential, error) {
+	return u.userRepository.QueryWebAuthnCredentials(ctx, userId)
+}
+
+func (u *userService) UpdateWebAuthnCredentialUsage(ctx context.Context, usage *dto.WebAuthnCredentialUsage) error {
+	return u.userRepository.UpdateWebAuthnCredentialUsage(ctx, usage)
+}
+
+func (u *userService) newWebAuthn() (*webauthn.WebAuthn, error) {
+	relyingParty, err := webauthn.New(&webauthn.Config{
+		RPID:          viper.GetString("app.webauthn.rp_id"),
+		RPDisplayName: viper.GetString("app.webauthn.rp_display_name"),
+		RPOrigins:     viper.GetStringSlice("app.webauthn.rp_origins"),
+		Timeouts: webauthn.TimeoutsConfig{
+			Registration: webauthn.TimeoutConfig{
+				Enforce:    true,
+				Timeout:    viper.GetDuration("app.webauthn.session_ttl"),
+				TimeoutUVD: viper.GetDuration("app.webauthn.session_ttl"),
+			},
+		},
+	})
+	if err != nil {
+		u.emitWebAuthnError(err)
+		return nil, dto.Err_INTERNAL_WEBAUTHN
+	}
+	return relyingParty, nil
+}
+
+func (u *userService) emitWebAuthnError(err error) {
+	go func() {
+		if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. err: %v", dto.Err_INTERNAL_WEBAUTHN.Error(), err)); err != nil {
+			u.logger.Error().Err(err).Msg("failed to emit log")
+		}
+	}()
+}
+
+func (u *userService) emitInvalidPasskey(userId string, err error) {
+	go func() {
+		if err := u.logEmitter.EmitLog("ERR", fmt.Sprintf("%s. user_id: %s. err: %v", dto.Err_BAD_REQUEST_INVALID_PASSKEY.Error(), userId, err)); err != nil {
+			u.logger.Error().Err(err).Msg("failed to emit log")
+		}
+	}()
+}
+
+func toPasskeyResponse(credential *dto.WebAuthnCredential) dto.PasskeyResponse {
+	return dto.PasskeyResponse{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Transports: credential.Transports,
+		Synced:     credential.BackupState,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
+
+func passkeyRegistrationKey(userId string) string {
+	return fmt.Sprintf("passkeyRegistration:%s", userId)
+}
@@ -12,7 +12,9 @@ type (
 	RedisCache interface {
 		Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 		Get(ctx context.Context, key string) (string, error)
+		GetDel(ctx context.Context, key string) (string, error)
 		Delete(ctx context.Context, key string) error
+		Incr(ctx context.Context, key string, expiration time.Duration) (int64, error)
 	}
 	redisCache struct {
 		redisClient *redis.Client
@@ -49,6 +51,19 @@ func (r *redisCache) Get(ctx context.Context, key string) (string, error) {
 	return val, nil
 }
 
+func (r *redisCache) GetDel(ctx context.Context, key string) (string, error) {
+	val, err := r.redisClient.GetDel(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			r.logger.Warn().Str("key", key).Msg("key not found in redis")
+			return "", err
+		}
+		r.logger.Error().Err(err).Str("key", key).Msg("failed to get and delete value from redis")
+		return "", err
+	}
+	return val, nil
+}
+
 func (r *redisCache) Delete(ctx context.Context, key string) error {
 	err := r.redisClient.Del(ctx, key).Err()
 	if err != nil {
@@ -57,3 +72,15 @@ func (r *redisCache) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+// the expiry is only set by the increment that creates the key, so the window does not slide
+func (r *redisCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	pipe := r.redisClient.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error().Err(err).Str("key", key).Msg("failed to increment value in redis")
+		return 0, err
+	}
+	return incr.Val(), nil
+}
@@ -0,0 +1,285 @@
+package migration
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+
+	"github.com/rs/zerolog"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+type (
+	Migrator interface {
+		Up(ctx context.Context) ([]Migration, error)
+		Down(ctx context.Context, steps int) ([]Migration, error)
+		To(ctx context.Context, version int64) ([]Migration, error)
+		Status(ctx context.Context) ([]Status, error)
+	}
+	Migration struct {
+		Version int64
+		Name    string
+		up      string
+		down    string
+	}
+	Status struct {
+		Version   int64
+		Name      string
+		AppliedAt *time.Time
+	}
+	migrator struct {
+		pgx        _db.Querier
+		migrations []Migration
+		logger     zerolog.Logger
+	}
+)
+
+var (
+	ErrUnknownVersion  = errors.New("unknown migration version")
+	ErrIrreversible    = errors.New("migration has no down script")
+	ErrInvalidFileName = errors.New("invalid migration file name")
+
+	fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+// serializes migrations across replicas that boot at the same time
+const lockKey int64 = 0x75736572
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations(
+  version BIGINT PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+func New(pgx _db.Querier, logger zerolog.Logger) (Migrator, error) {
+	return NewFromFS(pgx, files, "sql", logger)
+}
+
+func NewFromFS(pgx _db.Querier, fsys fs.FS, dir string, logger zerolog.Logger) (Migrator, error) {
+	migrations, err := load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{
+		pgx:        pgx,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
+
+func (m *migrator) Up(ctx context.Context) ([]Migration, error) {
+	if len(m.migrations) == 0 {
+		return nil, nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+func (m *migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	var reverted []Migration
+	for _, version := range versions[:min(steps, len(versions))] {
+		migration, ok := m.find(version)
+		if !ok {
+			return reverted, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+		if err := m.revert(ctx, migration); err != nil {
+			return reverted, err
+		}
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+func (m *migrator) To(ctx context.Context, version int64) ([]Migration, error) {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	// revert newest first, then apply oldest first
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= version {
+			continue
+		}
+		if err := m.revert(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > version {
+			continue
+		}
+		if err := m.apply(ctx, migration); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func (m *migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := Status{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			s.AppliedAt = &appliedAt
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+func (m *migrator) apply(ctx context.Context, migration Migration) error {
+	return _db.WithTx(ctx, m.pgx, func(q _db.Querier) error {
+		if _, err := q.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+			return err
+		}
+		// another replica may have applied it while this one waited for the lock
+		var exists bool
+		if err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)", migration.Version).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+		if _, err := q.Exec(ctx, migration.up); err != nil {
+			return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if _, err := q.Exec(ctx, "INSERT INTO schema_migrations (version,name) VALUES ($1,$2)", migration.Version, migration.Name); err != nil {
+			return err
+		}
+		m.logger.Info().Int64("version", migration.Version).Str("name", migration.Name).Msg("migration applied")
+		return nil
+	})
+}
+
+func (m *migrator) revert(ctx context.Context, migration Migration) error {
+	if migration.down == "" {
+		return fmt.Errorf("%w: %d_%s", ErrIrreversible, migration.Version, migration.Name)
+	}
+	return _db.WithTx(ctx, m.pgx, func(q _db.Querier) error {
+		if _, err := q.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+			return err
+		}
+		tag, err := q.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		if _, err := q.Exec(ctx, migration.down); err != nil {
+			return fmt.Errorf("revert migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		m.logger.Info().Int64("version", migration.Version).Str("name", migration.Name).Msg("migration reverted")
+		return nil
+	})
+}
+
+func (m *migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if _, err := m.pgx.Exec(ctx, createSchemaMigrations); err != nil {
+		return nil, err
+	}
+	rows, err := m.pgx.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+func (m *migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFileName, entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox(
+  id BIGSERIAL PRIMARY KEY,
+  subject VARCHAR(255) NOT NULL,
+  payload BYTEA NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  sent_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox(next_attempt_at) WHERE sent_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS image_variants JSONB NOT NULL DEFAULT '{}'::jsonb;
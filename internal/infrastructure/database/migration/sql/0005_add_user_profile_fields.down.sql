@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS users_username_key;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS date_of_birth,
+  DROP COLUMN IF EXISTS phone_number,
+  DROP COLUMN IF EXISTS timezone,
+  DROP COLUMN IF EXISTS locale,
+  DROP COLUMN IF EXISTS bio,
+  DROP COLUMN IF EXISTS username;
@@ -0,0 +1,9 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS username VARCHAR(30),
+  ADD COLUMN IF NOT EXISTS bio TEXT,
+  ADD COLUMN IF NOT EXISTS locale VARCHAR(35),
+  ADD COLUMN IF NOT EXISTS timezone VARCHAR(64),
+  ADD COLUMN IF NOT EXISTS phone_number VARCHAR(16),
+  ADD COLUMN IF NOT EXISTS date_of_birth DATE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users(LOWER(username));
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS recovery_codes(
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  used_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS recovery_codes_unused_idx ON recovery_codes(user_id) WHERE used_at IS NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS webauthn_credentials(
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  credential_id BYTEA NOT NULL,
+  name VARCHAR(64) NOT NULL,
+  public_key BYTEA NOT NULL,
+  attestation_type VARCHAR(32) NOT NULL DEFAULT '',
+  aaguid BYTEA,
+  transports TEXT[] NOT NULL DEFAULT '{}',
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  clone_warning BOOLEAN NOT NULL DEFAULT FALSE,
+  backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+  backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  last_used_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS webauthn_credentials_credential_id_key ON webauthn_credentials(credential_id);
+CREATE INDEX IF NOT EXISTS webauthn_credentials_user_id_idx ON webauthn_credentials(user_id);
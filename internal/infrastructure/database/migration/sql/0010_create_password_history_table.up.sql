@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS password_history(
+  id BIGSERIAL PRIMARY KEY,
+  user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  password_hash TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS password_history_user_id_idx ON password_history(user_id, id DESC);
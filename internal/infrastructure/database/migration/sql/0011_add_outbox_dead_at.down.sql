@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox(next_attempt_at) WHERE sent_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS dead_at;
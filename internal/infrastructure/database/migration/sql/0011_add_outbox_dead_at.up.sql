@@ -0,0 +1,4 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP;
+
+DROP INDEX IF EXISTS outbox_pending_idx;
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox(next_attempt_at) WHERE sent_at IS NULL AND dead_at IS NULL;
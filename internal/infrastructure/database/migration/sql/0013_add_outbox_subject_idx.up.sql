@@ -0,0 +1,2 @@
+-- the relay looks up earlier pending messages of the same subject to keep them in order
+CREATE INDEX IF NOT EXISTS outbox_pending_subject_idx ON outbox(subject, id) WHERE sent_at IS NULL AND dead_at IS NULL;
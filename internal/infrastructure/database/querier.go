@@ -10,8 +10,10 @@ import (
 
 type (
 	Querier interface {
+		Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 		QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 		Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+		Begin(ctx context.Context) (pgx.Tx, error)
 	}
 
 	pgxQuerier struct {
@@ -23,9 +25,18 @@ func NewQuerier(pool *pgxpool.Pool) Querier {
 	return &pgxQuerier{pgx: pool}
 }
 
+func (p *pgxQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return p.pgx.Query(ctx, sql, args...)
+}
 func (p *pgxQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
 	return p.pgx.QueryRow(ctx, sql, args...)
 }
 func (p *pgxQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
 	return p.pgx.Exec(ctx, sql, args...)
 }
+func (p *pgxQuerier) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.pgx.Begin(ctx)
+}
+func (p *pgxQuerier) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	return p.pgx.BeginTx(ctx, txOptions)
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	TxManager interface {
+		WithTx(ctx context.Context, fn func(q Querier) error, opts ...TxOption) error
+	}
+	TxOption func(*pgx.TxOptions)
+
+	txManager struct {
+		pgx Querier
+	}
+	// querier bound to an open transaction, nested WithTx calls on it create savepoints
+	txQuerier struct {
+		pgx.Tx
+	}
+	txBeginner interface {
+		BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	}
+)
+
+var ErrTransaction = errors.New("transaction failed")
+
+func NewTxManager(pgx Querier) TxManager {
+	return &txManager{pgx: pgx}
+}
+
+func (t *txManager) WithTx(ctx context.Context, fn func(q Querier) error, opts ...TxOption) error {
+	return WithTx(ctx, t.pgx, fn, opts...)
+}
+
+func WithIsolation(level pgx.TxIsoLevel) TxOption {
+	return func(o *pgx.TxOptions) {
+		o.IsoLevel = level
+	}
+}
+
+func ReadOnly() TxOption {
+	return func(o *pgx.TxOptions) {
+		o.AccessMode = pgx.ReadOnly
+	}
+}
+
+// run fn in a transaction on q, commit when fn returns nil and roll back otherwise.
+// when q is already a transaction the work runs in a savepoint and options are inherited from the outer transaction
+func WithTx(ctx context.Context, q Querier, fn func(q Querier) error, opts ...TxOption) error {
+	tx, err := begin(ctx, q, opts...)
+	if err != nil {
+		return fmt.Errorf("%w: begin: %v", ErrTransaction, err)
+	}
+	defer func() {
+		// rollback after commit is a no-op returning ErrTxClosed
+		_ = tx.Rollback(ctx)
+	}()
+	if err := fn(&txQuerier{Tx: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: commit: %v", ErrTransaction, err)
+	}
+	return nil
+}
+
+func begin(ctx context.Context, q Querier, opts ...TxOption) (pgx.Tx, error) {
+	if outer, ok := q.(*txQuerier); ok {
+		return outer.Tx.Begin(ctx)
+	}
+	var txOptions pgx.TxOptions
+	for _, opt := range opts {
+		opt(&txOptions)
+	}
+	if beginner, ok := q.(txBeginner); ok {
+		return beginner.BeginTx(ctx, txOptions)
+	}
+	if txOptions != (pgx.TxOptions{}) {
+		return nil, errors.New("querier does not support transaction options")
+	}
+	return q.Begin(ctx)
+}
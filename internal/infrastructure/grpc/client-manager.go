@@ -4,18 +4,23 @@ import (
 	"log"
 	"sync"
 
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 type GRPCClientManager struct {
 	connections map[string]*grpc.ClientConn
+	metrics     *metrics.Metrics
 	mu          sync.Mutex
 }
 
-func NewGRPCClientManager() *GRPCClientManager {
+func NewGRPCClientManager(m *metrics.Metrics) *GRPCClientManager {
 	return &GRPCClientManager{
 		connections: make(map[string]*grpc.ClientConn),
+		metrics:     m,
 	}
 }
 
@@ -27,7 +32,11 @@ func (m *GRPCClientManager) GetConnection(address string) *grpc.ClientConn {
 		return conn
 	}
 
-	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(m.metrics.UnaryClientInterceptor()),
+	)
 	if err != nil {
 		log.Fatalf("Failed to connect to gRPC server at %s: %v", address, err)
 	}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	_grpc "github.com/micros-template/user-service/internal/infrastructure/grpc"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type (
+	Checker interface {
+		Ready(ctx context.Context) Report
+	}
+	Check struct {
+		Name  string
+		Probe func(ctx context.Context) error
+	}
+	Report struct {
+		Status       string                `json:"status"`
+		Dependencies map[string]Dependency `json:"dependencies"`
+	}
+	Dependency struct {
+		Status  string `json:"status"`
+		Latency string `json:"latency"`
+		Error   string `json:"error,omitempty"`
+	}
+	checker struct {
+		checks  []Check
+		timeout time.Duration
+	}
+)
+
+func New(pool *pgxpool.Pool, redisClient *redis.Client, nc *nats.Conn, manager *_grpc.GRPCClientManager) Checker {
+	fileService := viper.GetString("app.grpc.service.file_service")
+	return NewFromChecks(viper.GetDuration("app.health.check_timeout"),
+		Check{Name: "postgres", Probe: pool.Ping},
+		Check{Name: "redis", Probe: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+		Check{Name: "nats", Probe: func(ctx context.Context) error {
+			if status := nc.Status(); status != nats.CONNECTED {
+				return fmt.Errorf("connection status %s", status)
+			}
+			return nil
+		}},
+		Check{Name: "file_service", Probe: func(ctx context.Context) error {
+			return channelReady(ctx, manager, fileService)
+		}},
+	)
+}
+
+func NewFromChecks(timeout time.Duration, checks ...Check) Checker {
+	return &checker{checks: checks, timeout: timeout}
+}
+
+// every dependency is probed concurrently, one slow dependency only costs its own timeout
+func (c *checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusUp, Dependencies: make(map[string]Dependency, len(c.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dep := probe(ctx, check, c.timeout)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[check.Name] = dep
+			if dep.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+func probe(ctx context.Context, check Check, timeout time.Duration) Dependency {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := check.Probe(ctx)
+	dep := Dependency{Status: StatusUp, Latency: time.Since(start).String()}
+	if err != nil {
+		dep.Status = StatusDown
+		dep.Error = err.Error()
+	}
+	return dep
+}
+
+// an idle channel is asked to connect, so the first probe after boot reports the real state instead of idle
+func channelReady(ctx context.Context, manager *_grpc.GRPCClientManager, address string) error {
+	conn := manager.GetConnection(address)
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return errors.New("channel is shut down")
+		case connectivity.Idle:
+			conn.Connect()
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("channel state %s: %w", state, ctx.Err())
+		}
+	}
+}
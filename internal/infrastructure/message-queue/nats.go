@@ -3,9 +3,16 @@ package messagequeue
 import (
 	"context"
 
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nats.go/jetstream"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
@@ -16,17 +23,19 @@ type (
 		GetJetStream() jetstream.JetStream
 	}
 	natsInstance struct {
-		nc     *nats.Conn
-		js     jetstream.JetStream
-		logger zerolog.Logger
+		nc      *nats.Conn
+		js      jetstream.JetStream
+		logger  zerolog.Logger
+		metrics *metrics.Metrics
 	}
 )
 
-func NewNatsInfrastructure(nc *nats.Conn, logger zerolog.Logger, js jetstream.JetStream) Nats {
+func NewNatsInfrastructure(nc *nats.Conn, logger zerolog.Logger, js jetstream.JetStream, m *metrics.Metrics) Nats {
 	return &natsInstance{
-		nc:     nc,
-		logger: logger,
-		js:     js,
+		nc:      nc,
+		logger:  logger,
+		js:      js,
+		metrics: m,
 	}
 }
 
@@ -57,8 +66,25 @@ func (n *natsInstance) CreateOrUpdateNewStream(ctx context.Context, jsConfig *je
 }
 
 func (n *natsInstance) Publish(ctx context.Context, subject string, payload []byte) (*jetstream.PubAck, error) {
-	ack, err := n.js.Publish(ctx, subject, payload)
+	ctx, span := otel.Tracer("github.com/micros-template/user-service/internal/infrastructure/message-queue").Start(ctx, "publish "+subject,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			attribute.String("messaging.destination.name", subject),
+		),
+	)
+	defer span.End()
+
+	// consumers continue the trace from the message headers
+	msg := nats.NewMsg(subject)
+	msg.Data = payload
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(msg.Header))
+
+	ack, err := n.js.PublishMsg(ctx, msg)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		n.metrics.PublishFailed(subject)
 		return nil, err
 	}
 	return ack, nil
@@ -21,3 +21,17 @@ func NewNotificationStream(js jetstream.JetStream) {
 		log.Printf("Failed to create or update JetStream stream: %v", err)
 	}
 }
+
+func NewEventBusStream(js jetstream.JetStream) {
+	cfg := &jetstream.StreamConfig{
+		Name:        viper.GetString("jetstream.event.stream.name"),
+		Description: viper.GetString("jetstream.event.stream.description"),
+		Subjects:    []string{viper.GetString("jetstream.event.subject.global")},
+		MaxBytes:    6 * 1024 * 1024,
+		Storage:     jetstream.FileStorage,
+	}
+	_, err := js.CreateOrUpdateStream(context.Background(), *cfg)
+	if err != nil {
+		log.Printf("Failed to create or update JetStream Event Bus stream: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "user_service"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	grpcRequests    *prometheus.CounterVec
+	grpcDuration    *prometheus.HistogramVec
+	grpcClient      *prometheus.HistogramVec
+	redisDuration   *prometheus.HistogramVec
+	publishFailures *prometheus.CounterVec
+	cacheLookups    *prometheus.CounterVec
+	outboxDead      *prometheus.CounterVec
+}
+
+// pool may be nil, the pgxpool collector is only registered when it is set
+func New(pool *pgxpool.Pool) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route, method and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled, by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "gRPC request latency, by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		grpcClient: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_duration_seconds",
+			Help:      "Outgoing gRPC call latency (file service), by target, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"target", "method", "code"}),
+		redisDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "redis_command_duration_seconds",
+			Help:      "Redis command latency, by command and result.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command", "result"}),
+		publishFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jetstream_publish_failures_total",
+			Help:      "JetStream publishes that returned an error, by subject prefix.",
+		}, []string{"subject"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Read-through cache lookups, by cache and result (hit or miss).",
+		}, []string{"cache", "result"}),
+		outboxDead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "outbox_dead_lettered_total",
+			Help:      "Outbox messages given up after max attempts, by subject prefix.",
+		}, []string{"subject"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcRequests,
+		m.grpcDuration,
+		m.grpcClient,
+		m.redisDuration,
+		m.publishFailures,
+		m.cacheLookups,
+		m.outboxDead,
+	)
+	if pool != nil {
+		m.registry.MustRegister(newPoolCollector(pool.Stat))
+	}
+	return m
+}
+
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) HTTPMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		// the route template keeps path params like user ids out of the label set
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := ctx.Request.Method
+		m.httpRequests.WithLabelValues(method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+		m.httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.grpcRequests.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		m.grpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		m.grpcClient.WithLabelValues(cc.Target(), method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (m *Metrics) RedisHook() redis.Hook {
+	return &redisHook{metrics: m}
+}
+
+// subjects carry ids after the first token, only the prefix is used as label
+func (m *Metrics) PublishFailed(subject string) {
+	prefix, _, _ := strings.Cut(subject, ".")
+	m.publishFailures.WithLabelValues(prefix).Inc()
+}
+
+func (m *Metrics) OutboxDeadLettered(subject string) {
+	prefix, _, _ := strings.Cut(subject, ".")
+	m.outboxDead.WithLabelValues(prefix).Inc()
+}
+
+func (m *Metrics) CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+type redisHook struct {
+	metrics *Metrics
+}
+
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.metrics.redisDuration.WithLabelValues(cmd.Name(), redisResult(err)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.metrics.redisDuration.WithLabelValues("pipeline", redisResult(err)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func redisResult(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, redis.Nil):
+		return "miss"
+	default:
+		return "error"
+	}
+}
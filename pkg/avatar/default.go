@@ -0,0 +1,52 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+)
+
+const (
+	defaultSize    = 512
+	identiconGrid  = 5
+	identiconCell  = defaultSize / (identiconGrid + 1)
+	identiconInset = (defaultSize - identiconCell*identiconGrid) / 2
+)
+
+// readable on the light background of the identicon
+var palette = []string{
+	"#1E88E5", "#3949AB", "#5E35B1", "#8E24AA", "#D81B60", "#E53935",
+	"#F4511E", "#6D4C41", "#546E7A", "#00897B", "#43A047", "#00838F",
+}
+
+// the color and the pattern only come from the hash of the seed, nothing of the profile shows up in it
+// and a rename keeps the same avatar. the output is the same for the same seed, it can be served again instead of being stored
+func Default(seed string) []byte {
+	sum := sha256.Sum256([]byte(seed))
+	color := palette[int(sum[0])%len(palette)]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, defaultSize, defaultSize, defaultSize, defaultSize)
+	buf.WriteString(`<rect width="100%" height="100%" fill="#F0F0F0"/>`)
+	fmt.Fprintf(&buf, `<g fill="%s">`, color)
+	// only the left half and the middle column come from the hash, the right half mirrors it
+	for row := range identiconGrid {
+		for col := range (identiconGrid + 1) / 2 {
+			bit := row*identiconGrid + col
+			if sum[1+bit/8]&(1<<(bit%8)) == 0 {
+				continue
+			}
+			writeCell(&buf, row, col)
+			if mirror := identiconGrid - 1 - col; mirror != col {
+				writeCell(&buf, row, mirror)
+			}
+		}
+	}
+	buf.WriteString(`</g></svg>`)
+	return buf.Bytes()
+}
+
+func writeCell(buf *bytes.Buffer, row, col int) {
+	fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d"/>`,
+		identiconInset+col*identiconCell, identiconInset+row*identiconCell, identiconCell, identiconCell)
+}
@@ -0,0 +1,107 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+// every upload is stored in these square sizes, the largest one is the primary image
+var Sizes = []int{64, 256, 512}
+
+const (
+	// decoding allocates width*height*4 bytes, checked from the header before the pixels are read
+	MaxPixels    = 40_000_000
+	MaxDimension = 10_000
+	jpegQuality  = 85
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrCorrupt         = errors.New("image cannot be decoded")
+	ErrTooLarge        = errors.New("image dimensions exceed the limit")
+)
+
+type Variant struct {
+	Size int
+	Ext  string
+	Data []byte
+}
+
+// the content type comes from the magic bytes, never from the file name.
+// re-encoding drops EXIF, GPS and every other metadata block, so orientation is applied to the pixels first
+func Process(data []byte) ([]Variant, error) {
+	var ext string
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		ext = "jpg"
+	case "image/png":
+		ext = "png"
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrCorrupt
+	}
+	if cfg.Width > MaxDimension || cfg.Height > MaxDimension || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	var src image.Image
+	if ext == "jpg" {
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		src, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if ext == "jpg" {
+		src = orient(src, exifOrientation(data))
+	}
+	src = cropSquare(src)
+
+	variants := make([]Variant, 0, len(Sizes))
+	for _, size := range Sizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+		var buf bytes.Buffer
+		if ext == "jpg" {
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{Size: size, Ext: ext, Data: buf.Bytes()})
+	}
+	return variants, nil
+}
+
+func cropSquare(src image.Image) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	rect := image.Rect(x, y, x+side, y+side)
+	if sub, ok := src.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), src, rect.Min, draw.Src)
+	return dst
+}
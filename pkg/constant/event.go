@@ -0,0 +1,23 @@
+package constant
+
+const (
+	EVENT_INSERT_USER = "insert_user"
+	EVENT_UPDATE_USER = "update_user"
+	// published on its own subject, the shared user events have no room for the profile fields
+	EVENT_UPDATE_USER_PROFILE = "update_user_profile"
+)
+
+const (
+	EVENT_FIELD_FULL_NAME          = "full_name"
+	EVENT_FIELD_IMAGE              = "image"
+	EVENT_FIELD_EMAIL              = "email"
+	EVENT_FIELD_VERIFIED           = "verified"
+	EVENT_FIELD_TWO_FACTOR_ENABLED = "two_factor_enabled"
+	EVENT_FIELD_USERNAME           = "username"
+	EVENT_FIELD_BIO                = "bio"
+	EVENT_FIELD_LOCALE             = "locale"
+	EVENT_FIELD_TIMEZONE           = "timezone"
+	EVENT_FIELD_PHONE_NUMBER       = "phone_number"
+	EVENT_FIELD_PHONE_VERIFIED     = "phone_verified"
+	EVENT_FIELD_DATE_OF_BIRTH      = "date_of_birth"
+)
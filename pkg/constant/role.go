@@ -0,0 +1,3 @@
+package constant
+
+const ROLE_ADMIN = "admin"
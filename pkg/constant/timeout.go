@@ -0,0 +1,9 @@
+package constant
+
+const (
+	TIMEOUT_HTTP         = "app.timeout.http"
+	TIMEOUT_GRPC         = "app.timeout.grpc"
+	TIMEOUT_DATABASE     = "app.timeout.database"
+	TIMEOUT_CACHE        = "app.timeout.cache"
+	TIMEOUT_FILE_SERVICE = "app.timeout.file_service"
+)
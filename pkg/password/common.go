@@ -0,0 +1,27 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// one password per line, lowercase
+//
+//go:embed common-passwords.txt
+var commonPasswordList string
+
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordList, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			set[line] = struct{}{}
+		}
+	}
+	return set
+}()
+
+// matched case-insensitively, "Password1" is as guessable as "password1"
+func IsCommon(password string) bool {
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
@@ -0,0 +1,108 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Rule string
+
+const (
+	RuleMinLength    Rule = "min_length"
+	RuleUppercase    Rule = "uppercase"
+	RuleLowercase    Rule = "lowercase"
+	RuleDigit        Rule = "digit"
+	RuleSymbol       Rule = "symbol"
+	RuleCommon       Rule = "common"
+	RulePersonalInfo Rule = "personal_info"
+	RuleReuse        Rule = "reuse"
+)
+
+// parts of an email or name shorter than this match too many passwords by accident
+const minPersonalTokenLength = 3
+
+type (
+	Policy struct {
+		MinLength       int
+		RequireUpper    bool
+		RequireLower    bool
+		RequireDigit    bool
+		RequireSymbol   bool
+		BanCommon       bool
+		BanPersonalInfo bool
+	}
+
+	Violation struct {
+		Rule    Rule   `json:"rule" example:"min_length"`
+		Message string `json:"message" example:"must be at least 8 characters"`
+	}
+)
+
+// every rule the password breaks, in a stable order. personal is the owner's email and name,
+// reuse is not checked here because it needs the stored hashes
+func (p Policy) Check(password string, personal ...string) []Violation {
+	var violations []Violation
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, Violation{Rule: RuleMinLength, Message: fmt.Sprintf("must be at least %d characters", p.MinLength)})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, Violation{Rule: RuleUppercase, Message: "must contain an uppercase letter"})
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, Violation{Rule: RuleLowercase, Message: "must contain a lowercase letter"})
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, Violation{Rule: RuleDigit, Message: "must contain a digit"})
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, Violation{Rule: RuleSymbol, Message: "must contain a symbol"})
+	}
+	if p.BanCommon && IsCommon(password) {
+		violations = append(violations, Violation{Rule: RuleCommon, Message: "is too common"})
+	}
+	if p.BanPersonalInfo && containsPersonalInfo(password, personal) {
+		violations = append(violations, Violation{Rule: RulePersonalInfo, Message: "must not contain your email or name"})
+	}
+	return violations
+}
+
+func ReuseViolation() Violation {
+	return Violation{Rule: RuleReuse, Message: "must not match a recently used password"}
+}
+
+// the local part of an email and every word of it or of the name are matched case-insensitively,
+// the email domain is left out since it is shared by many users
+func containsPersonalInfo(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(value)
+		if local, _, ok := strings.Cut(value, "@"); ok {
+			value = local
+		}
+		tokens := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, token := range append(tokens, value) {
+			if utf8.RuneCountInString(token) >= minPersonalTokenLength && strings.Contains(lowered, token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package timeout
+
+import (
+	"context"
+
+	"github.com/spf13/viper"
+)
+
+// derive a context bounded by the duration configured under key, an unset or zero duration only keeps the parent deadline
+func WithConfig(ctx context.Context, key string) (context.Context, context.CancelFunc) {
+	d := viper.GetDuration(key)
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
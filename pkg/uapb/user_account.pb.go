@@ -0,0 +1,851 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: user_account.proto
+
+package uapb
+
+import (
+	upb "github.com/micros-template/proto-user/pkg/upb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyEmailChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailChangeRequest) Reset() {
+	*x = VerifyEmailChangeRequest{}
+	mi := &file_user_account_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailChangeRequest) ProtoMessage() {}
+
+func (x *VerifyEmailChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailChangeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailChangeRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyEmailChangeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VerifyEmailChangeRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type GetUsersByIdsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByIdsRequest) Reset() {
+	*x = GetUsersByIdsRequest{}
+	mi := &file_user_account_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByIdsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByIdsRequest) ProtoMessage() {}
+
+func (x *GetUsersByIdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByIdsRequest.ProtoReflect.Descriptor instead.
+func (*GetUsersByIdsRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUsersByIdsRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type GetUsersByIdsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*upb.User            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsersByIdsResponse) Reset() {
+	*x = GetUsersByIdsResponse{}
+	mi := &file_user_account_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsersByIdsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsersByIdsResponse) ProtoMessage() {}
+
+func (x *GetUsersByIdsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsersByIdsResponse.ProtoReflect.Descriptor instead.
+func (*GetUsersByIdsResponse) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUsersByIdsResponse) GetUsers() []*upb.User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type GetUserByEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserByEmailRequest) Reset() {
+	*x = GetUserByEmailRequest{}
+	mi := &file_user_account_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserByEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByEmailRequest) ProtoMessage() {}
+
+func (x *GetUserByEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByEmailRequest.ProtoReflect.Descriptor instead.
+func (*GetUserByEmailRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUserByEmailRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type VerifyTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTOTPRequest) Reset() {
+	*x = VerifyTOTPRequest{}
+	mi := &file_user_account_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTOTPRequest) ProtoMessage() {}
+
+func (x *VerifyTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTOTPRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyTOTPRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VerifyTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type UseRecoveryCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UseRecoveryCodeRequest) Reset() {
+	*x = UseRecoveryCodeRequest{}
+	mi := &file_user_account_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UseRecoveryCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UseRecoveryCodeRequest) ProtoMessage() {}
+
+func (x *UseRecoveryCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UseRecoveryCodeRequest.ProtoReflect.Descriptor instead.
+func (*UseRecoveryCodeRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UseRecoveryCodeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UseRecoveryCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type WebAuthnCredential struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CredentialId    []byte                 `protobuf:"bytes,1,opt,name=credential_id,json=credentialId,proto3" json:"credential_id,omitempty"`
+	PublicKey       []byte                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	AttestationType string                 `protobuf:"bytes,3,opt,name=attestation_type,json=attestationType,proto3" json:"attestation_type,omitempty"`
+	Aaguid          []byte                 `protobuf:"bytes,4,opt,name=aaguid,proto3" json:"aaguid,omitempty"`
+	Transports      []string               `protobuf:"bytes,5,rep,name=transports,proto3" json:"transports,omitempty"`
+	SignCount       uint32                 `protobuf:"varint,6,opt,name=sign_count,json=signCount,proto3" json:"sign_count,omitempty"`
+	CloneWarning    bool                   `protobuf:"varint,7,opt,name=clone_warning,json=cloneWarning,proto3" json:"clone_warning,omitempty"`
+	BackupEligible  bool                   `protobuf:"varint,8,opt,name=backup_eligible,json=backupEligible,proto3" json:"backup_eligible,omitempty"`
+	BackupState     bool                   `protobuf:"varint,9,opt,name=backup_state,json=backupState,proto3" json:"backup_state,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *WebAuthnCredential) Reset() {
+	*x = WebAuthnCredential{}
+	mi := &file_user_account_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebAuthnCredential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebAuthnCredential) ProtoMessage() {}
+
+func (x *WebAuthnCredential) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebAuthnCredential.ProtoReflect.Descriptor instead.
+func (*WebAuthnCredential) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WebAuthnCredential) GetCredentialId() []byte {
+	if x != nil {
+		return x.CredentialId
+	}
+	return nil
+}
+
+func (x *WebAuthnCredential) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *WebAuthnCredential) GetAttestationType() string {
+	if x != nil {
+		return x.AttestationType
+	}
+	return ""
+}
+
+func (x *WebAuthnCredential) GetAaguid() []byte {
+	if x != nil {
+		return x.Aaguid
+	}
+	return nil
+}
+
+func (x *WebAuthnCredential) GetTransports() []string {
+	if x != nil {
+		return x.Transports
+	}
+	return nil
+}
+
+func (x *WebAuthnCredential) GetSignCount() uint32 {
+	if x != nil {
+		return x.SignCount
+	}
+	return 0
+}
+
+func (x *WebAuthnCredential) GetCloneWarning() bool {
+	if x != nil {
+		return x.CloneWarning
+	}
+	return false
+}
+
+func (x *WebAuthnCredential) GetBackupEligible() bool {
+	if x != nil {
+		return x.BackupEligible
+	}
+	return false
+}
+
+func (x *WebAuthnCredential) GetBackupState() bool {
+	if x != nil {
+		return x.BackupState
+	}
+	return false
+}
+
+type GetWebAuthnCredentialsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWebAuthnCredentialsRequest) Reset() {
+	*x = GetWebAuthnCredentialsRequest{}
+	mi := &file_user_account_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWebAuthnCredentialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWebAuthnCredentialsRequest) ProtoMessage() {}
+
+func (x *GetWebAuthnCredentialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWebAuthnCredentialsRequest.ProtoReflect.Descriptor instead.
+func (*GetWebAuthnCredentialsRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetWebAuthnCredentialsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetWebAuthnCredentialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserHandle    []byte                 `protobuf:"bytes,1,opt,name=user_handle,json=userHandle,proto3" json:"user_handle,omitempty"`
+	Credentials   []*WebAuthnCredential  `protobuf:"bytes,2,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWebAuthnCredentialsResponse) Reset() {
+	*x = GetWebAuthnCredentialsResponse{}
+	mi := &file_user_account_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWebAuthnCredentialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWebAuthnCredentialsResponse) ProtoMessage() {}
+
+func (x *GetWebAuthnCredentialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWebAuthnCredentialsResponse.ProtoReflect.Descriptor instead.
+func (*GetWebAuthnCredentialsResponse) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetWebAuthnCredentialsResponse) GetUserHandle() []byte {
+	if x != nil {
+		return x.UserHandle
+	}
+	return nil
+}
+
+func (x *GetWebAuthnCredentialsResponse) GetCredentials() []*WebAuthnCredential {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+type UpdateWebAuthnSignCountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CredentialId  []byte                 `protobuf:"bytes,2,opt,name=credential_id,json=credentialId,proto3" json:"credential_id,omitempty"`
+	SignCount     uint32                 `protobuf:"varint,3,opt,name=sign_count,json=signCount,proto3" json:"sign_count,omitempty"`
+	CloneWarning  bool                   `protobuf:"varint,4,opt,name=clone_warning,json=cloneWarning,proto3" json:"clone_warning,omitempty"`
+	BackupState   bool                   `protobuf:"varint,5,opt,name=backup_state,json=backupState,proto3" json:"backup_state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWebAuthnSignCountRequest) Reset() {
+	*x = UpdateWebAuthnSignCountRequest{}
+	mi := &file_user_account_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWebAuthnSignCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWebAuthnSignCountRequest) ProtoMessage() {}
+
+func (x *UpdateWebAuthnSignCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWebAuthnSignCountRequest.ProtoReflect.Descriptor instead.
+func (*UpdateWebAuthnSignCountRequest) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateWebAuthnSignCountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateWebAuthnSignCountRequest) GetCredentialId() []byte {
+	if x != nil {
+		return x.CredentialId
+	}
+	return nil
+}
+
+func (x *UpdateWebAuthnSignCountRequest) GetSignCount() uint32 {
+	if x != nil {
+		return x.SignCount
+	}
+	return 0
+}
+
+func (x *UpdateWebAuthnSignCountRequest) GetCloneWarning() bool {
+	if x != nil {
+		return x.CloneWarning
+	}
+	return false
+}
+
+func (x *UpdateWebAuthnSignCountRequest) GetBackupState() bool {
+	if x != nil {
+		return x.BackupState
+	}
+	return false
+}
+
+// profile fields upb.User has no room for. also the payload published on
+// <event_bus>.user_profile.<user_id> whenever one of them changes
+type UserProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      *string                `protobuf:"bytes,2,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	Bio           *string                `protobuf:"bytes,3,opt,name=bio,proto3,oneof" json:"bio,omitempty"`
+	Locale        *string                `protobuf:"bytes,4,opt,name=locale,proto3,oneof" json:"locale,omitempty"`
+	Timezone      *string                `protobuf:"bytes,5,opt,name=timezone,proto3,oneof" json:"timezone,omitempty"`
+	PhoneNumber   *string                `protobuf:"bytes,6,opt,name=phone_number,json=phoneNumber,proto3,oneof" json:"phone_number,omitempty"`
+	PhoneVerified bool                   `protobuf:"varint,7,opt,name=phone_verified,json=phoneVerified,proto3" json:"phone_verified,omitempty"`
+	// YYYY-MM-DD
+	DateOfBirth   *string `protobuf:"bytes,8,opt,name=date_of_birth,json=dateOfBirth,proto3,oneof" json:"date_of_birth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserProfile) Reset() {
+	*x = UserProfile{}
+	mi := &file_user_account_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfile) ProtoMessage() {}
+
+func (x *UserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_user_account_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
+func (*UserProfile) Descriptor() ([]byte, []int) {
+	return file_user_account_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UserProfile) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UserProfile) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *UserProfile) GetBio() string {
+	if x != nil && x.Bio != nil {
+		return *x.Bio
+	}
+	return ""
+}
+
+func (x *UserProfile) GetLocale() string {
+	if x != nil && x.Locale != nil {
+		return *x.Locale
+	}
+	return ""
+}
+
+func (x *UserProfile) GetTimezone() string {
+	if x != nil && x.Timezone != nil {
+		return *x.Timezone
+	}
+	return ""
+}
+
+func (x *UserProfile) GetPhoneNumber() string {
+	if x != nil && x.PhoneNumber != nil {
+		return *x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *UserProfile) GetPhoneVerified() bool {
+	if x != nil {
+		return x.PhoneVerified
+	}
+	return false
+}
+
+func (x *UserProfile) GetDateOfBirth() string {
+	if x != nil && x.DateOfBirth != nil {
+		return *x.DateOfBirth
+	}
+	return ""
+}
+
+var File_user_account_proto protoreflect.FileDescriptor
+
+const file_user_account_proto_rawDesc = "" +
+	"\n" +
+	"\x12user_account.proto\x12\x04uapb\x1a\n" +
+	"user.proto\"I\n" +
+	"\x18VerifyEmailChangeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\"1\n" +
+	"\x14GetUsersByIdsRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\"8\n" +
+	"\x15GetUsersByIdsResponse\x12\x1f\n" +
+	"\x05users\x18\x01 \x03(\v2\t.upb.UserR\x05users\"-\n" +
+	"\x15GetUserByEmailRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"@\n" +
+	"\x11VerifyTOTPRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"E\n" +
+	"\x16UseRecoveryCodeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\xcb\x02\n" +
+	"\x12WebAuthnCredential\x12#\n" +
+	"\rcredential_id\x18\x01 \x01(\fR\fcredentialId\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\fR\tpublicKey\x12)\n" +
+	"\x10attestation_type\x18\x03 \x01(\tR\x0fattestationType\x12\x16\n" +
+	"\x06aaguid\x18\x04 \x01(\fR\x06aaguid\x12\x1e\n" +
+	"\n" +
+	"transports\x18\x05 \x03(\tR\n" +
+	"transports\x12\x1d\n" +
+	"\n" +
+	"sign_count\x18\x06 \x01(\rR\tsignCount\x12#\n" +
+	"\rclone_warning\x18\a \x01(\bR\fcloneWarning\x12'\n" +
+	"\x0fbackup_eligible\x18\b \x01(\bR\x0ebackupEligible\x12!\n" +
+	"\fbackup_state\x18\t \x01(\bR\vbackupState\"8\n" +
+	"\x1dGetWebAuthnCredentialsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"}\n" +
+	"\x1eGetWebAuthnCredentialsResponse\x12\x1f\n" +
+	"\vuser_handle\x18\x01 \x01(\fR\n" +
+	"userHandle\x12:\n" +
+	"\vcredentials\x18\x02 \x03(\v2\x18.uapb.WebAuthnCredentialR\vcredentials\"\xc5\x01\n" +
+	"\x1eUpdateWebAuthnSignCountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcredential_id\x18\x02 \x01(\fR\fcredentialId\x12\x1d\n" +
+	"\n" +
+	"sign_count\x18\x03 \x01(\rR\tsignCount\x12#\n" +
+	"\rclone_warning\x18\x04 \x01(\bR\fcloneWarning\x12!\n" +
+	"\fbackup_state\x18\x05 \x01(\bR\vbackupState\"\xdb\x02\n" +
+	"\vUserProfile\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\busername\x18\x02 \x01(\tH\x00R\busername\x88\x01\x01\x12\x15\n" +
+	"\x03bio\x18\x03 \x01(\tH\x01R\x03bio\x88\x01\x01\x12\x1b\n" +
+	"\x06locale\x18\x04 \x01(\tH\x02R\x06locale\x88\x01\x01\x12\x1f\n" +
+	"\btimezone\x18\x05 \x01(\tH\x03R\btimezone\x88\x01\x01\x12&\n" +
+	"\fphone_number\x18\x06 \x01(\tH\x04R\vphoneNumber\x88\x01\x01\x12%\n" +
+	"\x0ephone_verified\x18\a \x01(\bR\rphoneVerified\x12'\n" +
+	"\rdate_of_birth\x18\b \x01(\tH\x05R\vdateOfBirth\x88\x01\x01B\v\n" +
+	"\t_usernameB\x06\n" +
+	"\x04_bioB\t\n" +
+	"\a_localeB\v\n" +
+	"\t_timezoneB\x0f\n" +
+	"\r_phone_numberB\x10\n" +
+	"\x0e_date_of_birth2\xea\x04\n" +
+	"\x12UserAccountService\x12B\n" +
+	"\x11VerifyEmailChange\x12\x1e.uapb.VerifyEmailChangeRequest\x1a\v.upb.Status\"\x00\x12'\n" +
+	"\vGetUserById\x12\v.upb.UserId\x1a\t.upb.User\"\x00\x12J\n" +
+	"\rGetUsersByIds\x12\x1a.uapb.GetUsersByIdsRequest\x1a\x1b.uapb.GetUsersByIdsResponse\"\x00\x12:\n" +
+	"\x0eGetUserByEmail\x12\x1b.uapb.GetUserByEmailRequest\x1a\t.upb.User\"\x00\x124\n" +
+	"\n" +
+	"VerifyTOTP\x12\x17.uapb.VerifyTOTPRequest\x1a\v.upb.Status\"\x00\x12>\n" +
+	"\x0fUseRecoveryCode\x12\x1c.uapb.UseRecoveryCodeRequest\x1a\v.upb.Status\"\x00\x12e\n" +
+	"\x16GetWebAuthnCredentials\x12#.uapb.GetWebAuthnCredentialsRequest\x1a$.uapb.GetWebAuthnCredentialsResponse\"\x00\x12N\n" +
+	"\x17UpdateWebAuthnSignCount\x12$.uapb.UpdateWebAuthnSignCountRequest\x1a\v.upb.Status\"\x00\x122\n" +
+	"\x0eGetUserProfile\x12\v.upb.UserId\x1a\x11.uapb.UserProfile\"\x00B2Z0github.com/micros-template/user-service/pkg/uapbb\x06proto3"
+
+var (
+	file_user_account_proto_rawDescOnce sync.Once
+	file_user_account_proto_rawDescData []byte
+)
+
+func file_user_account_proto_rawDescGZIP() []byte {
+	file_user_account_proto_rawDescOnce.Do(func() {
+		file_user_account_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_user_account_proto_rawDesc), len(file_user_account_proto_rawDesc)))
+	})
+	return file_user_account_proto_rawDescData
+}
+
+var file_user_account_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_user_account_proto_goTypes = []any{
+	(*VerifyEmailChangeRequest)(nil),       // 0: uapb.VerifyEmailChangeRequest
+	(*GetUsersByIdsRequest)(nil),           // 1: uapb.GetUsersByIdsRequest
+	(*GetUsersByIdsResponse)(nil),          // 2: uapb.GetUsersByIdsResponse
+	(*GetUserByEmailRequest)(nil),          // 3: uapb.GetUserByEmailRequest
+	(*VerifyTOTPRequest)(nil),              // 4: uapb.VerifyTOTPRequest
+	(*UseRecoveryCodeRequest)(nil),         // 5: uapb.UseRecoveryCodeRequest
+	(*WebAuthnCredential)(nil),             // 6: uapb.WebAuthnCredential
+	(*GetWebAuthnCredentialsRequest)(nil),  // 7: uapb.GetWebAuthnCredentialsRequest
+	(*GetWebAuthnCredentialsResponse)(nil), // 8: uapb.GetWebAuthnCredentialsResponse
+	(*UpdateWebAuthnSignCountRequest)(nil), // 9: uapb.UpdateWebAuthnSignCountRequest
+	(*UserProfile)(nil),                    // 10: uapb.UserProfile
+	(*upb.User)(nil),                       // 11: upb.User
+	(*upb.UserId)(nil),                     // 12: upb.UserId
+	(*upb.Status)(nil),                     // 13: upb.Status
+}
+var file_user_account_proto_depIdxs = []int32{
+	11, // 0: uapb.GetUsersByIdsResponse.users:type_name -> upb.User
+	6,  // 1: uapb.GetWebAuthnCredentialsResponse.credentials:type_name -> uapb.WebAuthnCredential
+	0,  // 2: uapb.UserAccountService.VerifyEmailChange:input_type -> uapb.VerifyEmailChangeRequest
+	12, // 3: uapb.UserAccountService.GetUserById:input_type -> upb.UserId
+	1,  // 4: uapb.UserAccountService.GetUsersByIds:input_type -> uapb.GetUsersByIdsRequest
+	3,  // 5: uapb.UserAccountService.GetUserByEmail:input_type -> uapb.GetUserByEmailRequest
+	4,  // 6: uapb.UserAccountService.VerifyTOTP:input_type -> uapb.VerifyTOTPRequest
+	5,  // 7: uapb.UserAccountService.UseRecoveryCode:input_type -> uapb.UseRecoveryCodeRequest
+	7,  // 8: uapb.UserAccountService.GetWebAuthnCredentials:input_type -> uapb.GetWebAuthnCredentialsRequest
+	9,  // 9: uapb.UserAccountService.UpdateWebAuthnSignCount:input_type -> uapb.UpdateWebAuthnSignCountRequest
+	12, // 10: uapb.UserAccountService.GetUserProfile:input_type -> upb.UserId
+	13, // 11: uapb.UserAccountService.VerifyEmailChange:output_type -> upb.Status
+	11, // 12: uapb.UserAccountService.GetUserById:output_type -> upb.User
+	2,  // 13: uapb.UserAccountService.GetUsersByIds:output_type -> uapb.GetUsersByIdsResponse
+	11, // 14: uapb.UserAccountService.GetUserByEmail:output_type -> upb.User
+	13, // 15: uapb.UserAccountService.VerifyTOTP:output_type -> upb.Status
+	13, // 16: uapb.UserAccountService.UseRecoveryCode:output_type -> upb.Status
+	8,  // 17: uapb.UserAccountService.GetWebAuthnCredentials:output_type -> uapb.GetWebAuthnCredentialsResponse
+	13, // 18: uapb.UserAccountService.UpdateWebAuthnSignCount:output_type -> upb.Status
+	10, // 19: uapb.UserAccountService.GetUserProfile:output_type -> uapb.UserProfile
+	11, // [11:20] is the sub-list for method output_type
+	2,  // [2:11] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_user_account_proto_init() }
+func file_user_account_proto_init() {
+	if File_user_account_proto != nil {
+		return
+	}
+	file_user_account_proto_msgTypes[10].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_account_proto_rawDesc), len(file_user_account_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_user_account_proto_goTypes,
+		DependencyIndexes: file_user_account_proto_depIdxs,
+		MessageInfos:      file_user_account_proto_msgTypes,
+	}.Build()
+	File_user_account_proto = out.File
+	file_user_account_proto_goTypes = nil
+	file_user_account_proto_depIdxs = nil
+}
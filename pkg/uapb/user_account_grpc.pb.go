@@ -0,0 +1,426 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: user_account.proto
+
+package uapb
+
+import (
+	context "context"
+	upb "github.com/micros-template/proto-user/pkg/upb"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserAccountService_VerifyEmailChange_FullMethodName       = "/uapb.UserAccountService/VerifyEmailChange"
+	UserAccountService_GetUserById_FullMethodName             = "/uapb.UserAccountService/GetUserById"
+	UserAccountService_GetUsersByIds_FullMethodName           = "/uapb.UserAccountService/GetUsersByIds"
+	UserAccountService_GetUserByEmail_FullMethodName          = "/uapb.UserAccountService/GetUserByEmail"
+	UserAccountService_VerifyTOTP_FullMethodName              = "/uapb.UserAccountService/VerifyTOTP"
+	UserAccountService_UseRecoveryCode_FullMethodName         = "/uapb.UserAccountService/UseRecoveryCode"
+	UserAccountService_GetWebAuthnCredentials_FullMethodName  = "/uapb.UserAccountService/GetWebAuthnCredentials"
+	UserAccountService_UpdateWebAuthnSignCount_FullMethodName = "/uapb.UserAccountService/UpdateWebAuthnSignCount"
+	UserAccountService_GetUserProfile_FullMethodName          = "/uapb.UserAccountService/GetUserProfile"
+)
+
+// UserAccountServiceClient is the client API for UserAccountService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UserAccountServiceClient interface {
+	VerifyEmailChange(ctx context.Context, in *VerifyEmailChangeRequest, opts ...grpc.CallOption) (*upb.Status, error)
+	GetUserById(ctx context.Context, in *upb.UserId, opts ...grpc.CallOption) (*upb.User, error)
+	GetUsersByIds(ctx context.Context, in *GetUsersByIdsRequest, opts ...grpc.CallOption) (*GetUsersByIdsResponse, error)
+	GetUserByEmail(ctx context.Context, in *GetUserByEmailRequest, opts ...grpc.CallOption) (*upb.User, error)
+	VerifyTOTP(ctx context.Context, in *VerifyTOTPRequest, opts ...grpc.CallOption) (*upb.Status, error)
+	UseRecoveryCode(ctx context.Context, in *UseRecoveryCodeRequest, opts ...grpc.CallOption) (*upb.Status, error)
+	GetWebAuthnCredentials(ctx context.Context, in *GetWebAuthnCredentialsRequest, opts ...grpc.CallOption) (*GetWebAuthnCredentialsResponse, error)
+	UpdateWebAuthnSignCount(ctx context.Context, in *UpdateWebAuthnSignCountRequest, opts ...grpc.CallOption) (*upb.Status, error)
+	GetUserProfile(ctx context.Context, in *upb.UserId, opts ...grpc.CallOption) (*UserProfile, error)
+}
+
+type userAccountServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserAccountServiceClient(cc grpc.ClientConnInterface) UserAccountServiceClient {
+	return &userAccountServiceClient{cc}
+}
+
+func (c *userAccountServiceClient) VerifyEmailChange(ctx context.Context, in *VerifyEmailChangeRequest, opts ...grpc.CallOption) (*upb.Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(upb.Status)
+	err := c.cc.Invoke(ctx, UserAccountService_VerifyEmailChange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) GetUserById(ctx context.Context, in *upb.UserId, opts ...grpc.CallOption) (*upb.User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(upb.User)
+	err := c.cc.Invoke(ctx, UserAccountService_GetUserById_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) GetUsersByIds(ctx context.Context, in *GetUsersByIdsRequest, opts ...grpc.CallOption) (*GetUsersByIdsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsersByIdsResponse)
+	err := c.cc.Invoke(ctx, UserAccountService_GetUsersByIds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) GetUserByEmail(ctx context.Context, in *GetUserByEmailRequest, opts ...grpc.CallOption) (*upb.User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(upb.User)
+	err := c.cc.Invoke(ctx, UserAccountService_GetUserByEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) VerifyTOTP(ctx context.Context, in *VerifyTOTPRequest, opts ...grpc.CallOption) (*upb.Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(upb.Status)
+	err := c.cc.Invoke(ctx, UserAccountService_VerifyTOTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) UseRecoveryCode(ctx context.Context, in *UseRecoveryCodeRequest, opts ...grpc.CallOption) (*upb.Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(upb.Status)
+	err := c.cc.Invoke(ctx, UserAccountService_UseRecoveryCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) GetWebAuthnCredentials(ctx context.Context, in *GetWebAuthnCredentialsRequest, opts ...grpc.CallOption) (*GetWebAuthnCredentialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebAuthnCredentialsResponse)
+	err := c.cc.Invoke(ctx, UserAccountService_GetWebAuthnCredentials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) UpdateWebAuthnSignCount(ctx context.Context, in *UpdateWebAuthnSignCountRequest, opts ...grpc.CallOption) (*upb.Status, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(upb.Status)
+	err := c.cc.Invoke(ctx, UserAccountService_UpdateWebAuthnSignCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userAccountServiceClient) GetUserProfile(ctx context.Context, in *upb.UserId, opts ...grpc.CallOption) (*UserProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserProfile)
+	err := c.cc.Invoke(ctx, UserAccountService_GetUserProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserAccountServiceServer is the server API for UserAccountService service.
+// All implementations must embed UnimplementedUserAccountServiceServer
+// for forward compatibility.
+type UserAccountServiceServer interface {
+	VerifyEmailChange(context.Context, *VerifyEmailChangeRequest) (*upb.Status, error)
+	GetUserById(context.Context, *upb.UserId) (*upb.User, error)
+	GetUsersByIds(context.Context, *GetUsersByIdsRequest) (*GetUsersByIdsResponse, error)
+	GetUserByEmail(context.Context, *GetUserByEmailRequest) (*upb.User, error)
+	VerifyTOTP(context.Context, *VerifyTOTPRequest) (*upb.Status, error)
+	UseRecoveryCode(context.Context, *UseRecoveryCodeRequest) (*upb.Status, error)
+	GetWebAuthnCredentials(context.Context, *GetWebAuthnCredentialsRequest) (*GetWebAuthnCredentialsResponse, error)
+	UpdateWebAuthnSignCount(context.Context, *UpdateWebAuthnSignCountRequest) (*upb.Status, error)
+	GetUserProfile(context.Context, *upb.UserId) (*UserProfile, error)
+	mustEmbedUnimplementedUserAccountServiceServer()
+}
+
+// UnimplementedUserAccountServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserAccountServiceServer struct{}
+
+func (UnimplementedUserAccountServiceServer) VerifyEmailChange(context.Context, *VerifyEmailChangeRequest) (*upb.Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmailChange not implemented")
+}
+func (UnimplementedUserAccountServiceServer) GetUserById(context.Context, *upb.UserId) (*upb.User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserById not implemented")
+}
+func (UnimplementedUserAccountServiceServer) GetUsersByIds(context.Context, *GetUsersByIdsRequest) (*GetUsersByIdsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsersByIds not implemented")
+}
+func (UnimplementedUserAccountServiceServer) GetUserByEmail(context.Context, *GetUserByEmailRequest) (*upb.User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByEmail not implemented")
+}
+func (UnimplementedUserAccountServiceServer) VerifyTOTP(context.Context, *VerifyTOTPRequest) (*upb.Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyTOTP not implemented")
+}
+func (UnimplementedUserAccountServiceServer) UseRecoveryCode(context.Context, *UseRecoveryCodeRequest) (*upb.Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UseRecoveryCode not implemented")
+}
+func (UnimplementedUserAccountServiceServer) GetWebAuthnCredentials(context.Context, *GetWebAuthnCredentialsRequest) (*GetWebAuthnCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebAuthnCredentials not implemented")
+}
+func (UnimplementedUserAccountServiceServer) UpdateWebAuthnSignCount(context.Context, *UpdateWebAuthnSignCountRequest) (*upb.Status, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWebAuthnSignCount not implemented")
+}
+func (UnimplementedUserAccountServiceServer) GetUserProfile(context.Context, *upb.UserId) (*UserProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserProfile not implemented")
+}
+func (UnimplementedUserAccountServiceServer) mustEmbedUnimplementedUserAccountServiceServer() {}
+func (UnimplementedUserAccountServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeUserAccountServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserAccountServiceServer will
+// result in compilation errors.
+type UnsafeUserAccountServiceServer interface {
+	mustEmbedUnimplementedUserAccountServiceServer()
+}
+
+func RegisterUserAccountServiceServer(s grpc.ServiceRegistrar, srv UserAccountServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserAccountServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserAccountService_ServiceDesc, srv)
+}
+
+func _UserAccountService_VerifyEmailChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).VerifyEmailChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_VerifyEmailChange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).VerifyEmailChange(ctx, req.(*VerifyEmailChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_GetUserById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(upb.UserId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).GetUserById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_GetUserById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).GetUserById(ctx, req.(*upb.UserId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_GetUsersByIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsersByIdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).GetUsersByIds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_GetUsersByIds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).GetUsersByIds(ctx, req.(*GetUsersByIdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_GetUserByEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserByEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).GetUserByEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_GetUserByEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).GetUserByEmail(ctx, req.(*GetUserByEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_VerifyTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).VerifyTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_VerifyTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).VerifyTOTP(ctx, req.(*VerifyTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_UseRecoveryCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UseRecoveryCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).UseRecoveryCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_UseRecoveryCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).UseRecoveryCode(ctx, req.(*UseRecoveryCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_GetWebAuthnCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebAuthnCredentialsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).GetWebAuthnCredentials(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_GetWebAuthnCredentials_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).GetWebAuthnCredentials(ctx, req.(*GetWebAuthnCredentialsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_UpdateWebAuthnSignCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWebAuthnSignCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).UpdateWebAuthnSignCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_UpdateWebAuthnSignCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).UpdateWebAuthnSignCount(ctx, req.(*UpdateWebAuthnSignCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UserAccountService_GetUserProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(upb.UserId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserAccountServiceServer).GetUserProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserAccountService_GetUserProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserAccountServiceServer).GetUserProfile(ctx, req.(*upb.UserId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserAccountService_ServiceDesc is the grpc.ServiceDesc for UserAccountService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserAccountService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "uapb.UserAccountService",
+	HandlerType: (*UserAccountServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyEmailChange",
+			Handler:    _UserAccountService_VerifyEmailChange_Handler,
+		},
+		{
+			MethodName: "GetUserById",
+			Handler:    _UserAccountService_GetUserById_Handler,
+		},
+		{
+			MethodName: "GetUsersByIds",
+			Handler:    _UserAccountService_GetUsersByIds_Handler,
+		},
+		{
+			MethodName: "GetUserByEmail",
+			Handler:    _UserAccountService_GetUserByEmail_Handler,
+		},
+		{
+			MethodName: "VerifyTOTP",
+			Handler:    _UserAccountService_VerifyTOTP_Handler,
+		},
+		{
+			MethodName: "UseRecoveryCode",
+			Handler:    _UserAccountService_UseRecoveryCode_Handler,
+		},
+		{
+			MethodName: "GetWebAuthnCredentials",
+			Handler:    _UserAccountService_GetWebAuthnCredentials_Handler,
+		},
+		{
+			MethodName: "UpdateWebAuthnSignCount",
+			Handler:    _UserAccountService_UpdateWebAuthnSignCount_Handler,
+		},
+		{
+			MethodName: "GetUserProfile",
+			Handler:    _UserAccountService_GetUserProfile_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "user_account.proto",
+}
@@ -72,7 +72,7 @@ func (c *GRPCCreateUserITSuite) SetupSuite() {
 		SharedNetwork:           c.network.Name,
 		ImageName:               viper.GetString("container.postgresql_image"),
 		ContainerName:           "test_auth_db",
-		SQLInitScriptPath:       viper.GetString("script.init_sql"),
+		SQLInitScriptPath:       viper.GetString("script.auth_init_sql"),
 		SQLInitInsideScriptPath: "/docker-entrypoint-initdb.d/init-db.sql",
 		WaitingSignal:           "database system is ready to accept connections",
 		Env: map[string]string{
@@ -71,7 +71,7 @@ func (d *GRPCDeleteUserITSuite) SetupSuite() {
 		SharedNetwork:           d.network.Name,
 		ImageName:               viper.GetString("container.postgresql_image"),
 		ContainerName:           "test_auth_db",
-		SQLInitScriptPath:       viper.GetString("script.init_sql"),
+		SQLInitScriptPath:       viper.GetString("script.auth_init_sql"),
 		SQLInitInsideScriptPath: "/docker-entrypoint-initdb.d/init-db.sql",
 		WaitingSignal:           "database system is ready to accept connections",
 		Env: map[string]string{
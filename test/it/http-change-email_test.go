@@ -78,7 +78,7 @@ func (c *HTTPChangeEmailITSuite) SetupSuite() {
 		SharedNetwork:           c.network.Name,
 		ImageName:               viper.GetString("container.postgresql_image"),
 		ContainerName:           "test_auth_db",
-		SQLInitScriptPath:       viper.GetString("script.init_sql"),
+		SQLInitScriptPath:       viper.GetString("script.auth_init_sql"),
 		SQLInitInsideScriptPath: "/docker-entrypoint-initdb.d/init-db.sql",
 		WaitingSignal:           "database system is ready to accept connections",
 		Env: map[string]string{
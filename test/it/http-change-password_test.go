@@ -79,7 +79,7 @@ func (c *HTTPChangePasswordITSuite) SetupSuite() {
 		SharedNetwork:           c.network.Name,
 		ImageName:               viper.GetString("container.postgresql_image"),
 		ContainerName:           "test_auth_db",
-		SQLInitScriptPath:       viper.GetString("script.init_sql"),
+		SQLInitScriptPath:       viper.GetString("script.auth_init_sql"),
 		SQLInitInsideScriptPath: "/docker-entrypoint-initdb.d/init-db.sql",
 		WaitingSignal:           "database system is ready to accept connections",
 		Env: map[string]string{
@@ -343,8 +343,8 @@ func (c *HTTPChangePasswordITSuite) TestChangePasswordIT_Success() {
 
 	encoder := gin.H{
 		"password":             "password123",
-		"new_password":         "password321",
-		"confirm_new_password": "password321",
+		"new_password":         "Password321",
+		"confirm_new_password": "Password321",
 	}
 	_ = json.NewEncoder(reqBody).Encode(encoder)
 
@@ -634,3 +634,86 @@ func (c *HTTPChangePasswordITSuite) TestChangePasswordIT_WrongPassword() {
 	c.NoError(err)
 	c.Contains(string(byteBody), dto.Err_UNAUTHORIZED_PASSWORD_WRONG.Error())
 }
+
+func (c *HTTPChangePasswordITSuite) TestChangePasswordIT_PolicyViolation() {
+	// register
+	email := fmt.Sprintf("test+%d@example.com", time.Now().UnixNano())
+	request := helper.Register(email, c.T())
+
+	client := http.Client{}
+	response, err := client.Do(request)
+	c.NoError(err)
+
+	byteBody, err := io.ReadAll(response.Body)
+	c.NoError(err)
+
+	c.Equal(http.StatusCreated, response.StatusCode)
+	c.Contains(string(byteBody), "Register Success. Check your email for verification.")
+	if err := response.Body.Close(); err != nil {
+		c.T().Errorf("error closing response body: %v", err)
+	}
+	time.Sleep(time.Second) //give a time for auth_db update the user
+
+	regex := `http://localhost:9090/api/v1/auth/verify-email\?userid=[^&]+&token=[^"']+`
+	link := helper.RetrieveDataFromEmail(email, regex, "mail", c.T())
+
+	verifyRequest, err := http.NewRequest(http.MethodGet, link, nil)
+	c.NoError(err)
+
+	verifyResponse, err := client.Do(verifyRequest)
+	c.NoError(err)
+
+	verifyBody, err := io.ReadAll(verifyResponse.Body)
+	c.NoError(err)
+
+	c.Equal(http.StatusOK, verifyResponse.StatusCode)
+	c.Contains(string(verifyBody), "Verification Success")
+
+	time.Sleep(time.Second) //give a time for auth_db update the user
+
+	// login
+	request = helper.Login(email, c.T())
+
+	client = http.Client{}
+	response, err = client.Do(request)
+	c.NoError(err)
+
+	byteBody, err = io.ReadAll(response.Body)
+
+	c.Equal(http.StatusOK, response.StatusCode)
+	c.NoError(err)
+	c.Contains(string(byteBody), "Login Success")
+
+	var respData map[string]interface{}
+	err = json.Unmarshal(byteBody, &respData)
+	c.NoError(err)
+
+	jwt, ok := respData["data"].(string)
+	c.True(ok, "expected jwt token in data field")
+
+	// change password req
+	reqBody := &bytes.Buffer{}
+
+	encoder := gin.H{
+		"password":             "password123",
+		"new_password":         "password123",
+		"confirm_new_password": "password123",
+	}
+	_ = json.NewEncoder(reqBody).Encode(encoder)
+
+	request, err = http.NewRequest(http.MethodPatch, "http://localhost:9090/api/v1/user/password", reqBody)
+	request.Header.Set("Authorization", "Bearer "+jwt)
+	c.NoError(err)
+
+	client = http.Client{}
+	response, err = client.Do(request)
+	c.NoError(err)
+
+	byteBody, err = io.ReadAll(response.Body)
+
+	c.Equal(http.StatusBadRequest, response.StatusCode)
+	c.NoError(err)
+	c.Contains(string(byteBody), `"rule":"uppercase"`)
+	c.Contains(string(byteBody), `"rule":"common"`)
+	c.Contains(string(byteBody), `"rule":"reuse"`)
+}
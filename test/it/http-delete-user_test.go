@@ -79,7 +79,7 @@ func (d *HTTPDeleteUserITSuite) SetupSuite() {
 		SharedNetwork:           d.network.Name,
 		ImageName:               viper.GetString("container.postgresql_image"),
 		ContainerName:           "test_auth_db",
-		SQLInitScriptPath:       viper.GetString("script.init_sql"),
+		SQLInitScriptPath:       viper.GetString("script.auth_init_sql"),
 		SQLInitInsideScriptPath: "/docker-entrypoint-initdb.d/init-db.sql",
 		WaitingSignal:           "database system is ready to accept connections",
 		Env: map[string]string{
@@ -348,6 +348,7 @@ func (d *HTTPDeleteUserITSuite) TestDeleteUserIT_Success() {
 
 	request, err = http.NewRequest(http.MethodDelete, "http://localhost:9090/api/v1/user/", reqBody)
 	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("If-Match", "*")
 
 	d.NoError(err)
 
@@ -359,7 +360,7 @@ func (d *HTTPDeleteUserITSuite) TestDeleteUserIT_Success() {
 
 	d.Equal(http.StatusOK, response.StatusCode)
 	d.NoError(err)
-	d.Contains(string(byteBody), dto.SUCCESS_DELETE_USER)
+	d.Contains(string(byteBody), dto.SUCCESS_REQUEST_DELETE)
 
 }
 func (d *HTTPDeleteUserITSuite) TestDeleteUserIT_WrongPassword() {
@@ -429,6 +430,7 @@ func (d *HTTPDeleteUserITSuite) TestDeleteUserIT_WrongPassword() {
 
 	request, err = http.NewRequest(http.MethodDelete, "http://localhost:9090/api/v1/user/", reqBody)
 	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("If-Match", "*")
 
 	d.NoError(err)
 
@@ -78,7 +78,7 @@ func (u *HTTPUpdateUserITSuite) SetupSuite() {
 		SharedNetwork:           u.network.Name,
 		ImageName:               viper.GetString("container.postgresql_image"),
 		ContainerName:           "test_auth_db",
-		SQLInitScriptPath:       viper.GetString("script.init_sql"),
+		SQLInitScriptPath:       viper.GetString("script.auth_init_sql"),
 		SQLInitInsideScriptPath: "/docker-entrypoint-initdb.d/init-db.sql",
 		WaitingSignal:           "database system is ready to accept connections",
 		Env: map[string]string{
@@ -348,6 +348,7 @@ func (u *HTTPUpdateUserITSuite) TestUpdateUserIT_Success() {
 	request, err = http.NewRequest(http.MethodPatch, "http://localhost:9090/api/v1/user/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("If-Match", "*")
 	u.NoError(err)
 
 	client = http.Client{}
@@ -553,6 +554,7 @@ func (u *HTTPUpdateUserITSuite) TestUpdateUserIT_ImageWrongExtension() {
 	request, err = http.NewRequest(http.MethodPatch, "http://localhost:9090/api/v1/user/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("If-Match", "*")
 
 	u.NoError(err)
 
@@ -641,6 +643,7 @@ func (u *HTTPUpdateUserITSuite) TestUpdateUserIT_ImageLimitSizeExceeded() {
 	request, err = http.NewRequest(http.MethodPatch, "http://localhost:9090/api/v1/user/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("If-Match", "*")
 
 	u.NoError(err)
 
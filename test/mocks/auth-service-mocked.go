@@ -11,14 +11,14 @@ type MockAuthService struct {
 	mock.Mock
 }
 
-func (m *MockAuthService) CreateUser(user *upb.User) (*upb.Status, error) {
-	args := m.Called(user)
+func (m *MockAuthService) CreateUser(ctx context.Context, user *upb.User) (*upb.Status, error) {
+	args := m.Called(ctx, user)
 	status, _ := args.Get(0).(*upb.Status)
 	return status, args.Error(1)
 }
 
-func (m *MockAuthService) UpdateUser(ctx context.Context, user *upb.User) error {
-	args := m.Called(ctx, user)
+func (m *MockAuthService) UpdateUser(ctx context.Context, user *upb.User, expectedVersion int64) error {
+	args := m.Called(ctx, user, expectedVersion)
 	return args.Error(0)
 }
 
@@ -26,3 +26,21 @@ func (m *MockAuthService) DeleteUser(ctx context.Context, user *upb.UserId) erro
 	args := m.Called(ctx, user)
 	return args.Error(0)
 }
+
+func (m *MockAuthService) GetUserById(ctx context.Context, userId *upb.UserId) (*upb.User, error) {
+	args := m.Called(ctx, userId)
+	user, _ := args.Get(0).(*upb.User)
+	return user, args.Error(1)
+}
+
+func (m *MockAuthService) GetUsersByIds(ctx context.Context, userIds []string) ([]*upb.User, error) {
+	args := m.Called(ctx, userIds)
+	users, _ := args.Get(0).([]*upb.User)
+	return users, args.Error(1)
+}
+
+func (m *MockAuthService) GetUserByEmail(ctx context.Context, email string) (*upb.User, error) {
+	args := m.Called(ctx, email)
+	user, _ := args.Get(0).(*upb.User)
+	return user, args.Error(1)
+}
@@ -1,11 +1,3 @@
-CREATE TABLE IF NOT EXISTS users(
-  id VARCHAR(36) PRIMARY KEY,
-  full_name VARCHAR(255) NOT NULL,
-  email VARCHAR(255) UNIQUE,
-  image TEXT,
-  password TEXT NOT NULL,
-  verified BOOLEAN NOT NULL DEFAULT FALSE,
-  two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE,
-  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
+-- the user service creates its schema with the embedded migrations on startup
+-- (database.auto_migrate in config.test.yaml), this script runs before them so
+-- it must not create or seed tables
@@ -0,0 +1,88 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+	"github.com/micros-template/user-service/pkg/uapb"
+
+	"github.com/micros-template/proto-event/pkg/epb"
+	"github.com/micros-template/proto-event/pkg/uepb"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/proto"
+)
+
+type OutboxRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *OutboxRepositoryMock) Enqueue(ctx context.Context, msgs ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(msgs...)
+	args := m.Called(ctx, msgs)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) ClaimPending(ctx context.Context, limit uint64, maxAttempts int, leaseUntil time.Time) ([]dto.OutboxMessage, error) {
+	args := m.Called(ctx, limit, maxAttempts, leaseUntil)
+	msgs, _ := args.Get(0).([]dto.OutboxMessage)
+	return msgs, args.Error(1)
+}
+
+func (m *OutboxRepositoryMock) MarkSent(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) Release(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *OutboxRepositoryMock) DeadLetterExhausted(ctx context.Context, maxAttempts int) ([]dto.OutboxMessage, error) {
+	args := m.Called(ctx, maxAttempts)
+	msgs, _ := args.Get(0).([]dto.OutboxMessage)
+	return msgs, args.Error(1)
+}
+
+// event payload must never carry the password hash, fail loudly in every test that publishes one
+func mustNotCarryPassword(msgs ...*dto.OutboxMessage) {
+	for _, msg := range msgs {
+		var event epb.EventMessage
+		if err := proto.Unmarshal(msg.Payload, &event); err != nil {
+			continue
+		}
+		userEvent := event.GetUserEvent()
+		if userEvent.GetUserCreated().GetPassword() != "" || userEvent.GetUserUpdated().GetPassword() != "" {
+			panic("password hash reached the event outbox")
+		}
+	}
+}
+
+func DecodeUserEvent(msg *dto.OutboxMessage) *uepb.UserEvent {
+	var event epb.EventMessage
+	if err := proto.Unmarshal(msg.Payload, &event); err != nil {
+		return nil
+	}
+	return event.GetUserEvent()
+}
+
+func DecodeUserProfile(msg *dto.OutboxMessage) *uapb.UserProfile {
+	var profile uapb.UserProfile
+	if err := proto.Unmarshal(msg.Payload, &profile); err != nil {
+		return nil
+	}
+	return &profile
+}
+
+// expectations stay on the same mock, so calls made through a tx-bound repository are asserted as usual
+func (m *OutboxRepositoryMock) WithQuerier(q _db.Querier) repository.OutboxRepository {
+	return m
+}
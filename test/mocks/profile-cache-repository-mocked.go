@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ProfileCacheRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ProfileCacheRepositoryMock) QueryProfileByUserId(ctx context.Context, userId string) (*dto.UserProfile, error) {
+	args := m.Called(ctx, userId)
+	profile, _ := args.Get(0).(*dto.UserProfile)
+	return profile, args.Error(1)
+}
+
+func (m *ProfileCacheRepositoryMock) Invalidate(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
@@ -21,7 +21,17 @@ func (m *MockRedisCache) Get(ctx context.Context, key string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockRedisCache) GetDel(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockRedisCache) Delete(ctx context.Context, key string) error {
 	args := m.Called(ctx, key)
 	return args.Error(0)
 }
+
+func (m *MockRedisCache) Incr(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	args := m.Called(ctx, key, expiration)
+	return args.Get(0).(int64), args.Error(1)
+}
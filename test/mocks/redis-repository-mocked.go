@@ -15,3 +15,23 @@ func (m *MockRedisRepository) SetResource(ctx context.Context, s1 string, s2 str
 	args := m.Called(ctx, s1, s2, t)
 	return args.Error(0)
 }
+
+func (m *MockRedisRepository) GetResource(ctx context.Context, s1 string) (string, error) {
+	args := m.Called(ctx, s1)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRedisRepository) TakeResource(ctx context.Context, s1 string) (string, error) {
+	args := m.Called(ctx, s1)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRedisRepository) RemoveResource(ctx context.Context, s1 string) error {
+	args := m.Called(ctx, s1)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) IncrementResource(ctx context.Context, s1 string, t time.Duration) (int64, error) {
+	args := m.Called(ctx, s1, t)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TxManagerMock struct {
+	mock.Mock
+	// querier handed to fn, nil unless a test needs to inspect it
+	Querier _db.Querier
+}
+
+// fn runs only when the expectation returns no error, mirroring a transaction that failed to begin
+func (m *TxManagerMock) WithTx(ctx context.Context, fn func(q _db.Querier) error, opts ..._db.TxOption) error {
+	args := m.Called(ctx, opts)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m.Querier)
+}
@@ -1,6 +1,13 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+
 	"github.com/micros-template/sharedlib/model"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,29 +16,153 @@ type UserRepositoryMock struct {
 	mock.Mock
 }
 
-func (m *UserRepositoryMock) CreateNewUser(user *model.User) error {
-	args := m.Called(user)
+func (m *UserRepositoryMock) CreateNewUser(ctx context.Context, user *model.User, outbox ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(outbox...)
+	args := m.Called(ctx, user, outbox)
 	return args.Error(0)
 }
 
-func (m *UserRepositoryMock) QueryUserByEmail(email string) (*model.User, error) {
-	args := m.Called(email)
+func (m *UserRepositoryMock) QueryUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
 	user, _ := args.Get(0).(*model.User)
 	return user, args.Error(1)
 }
 
-func (m *UserRepositoryMock) QueryUserByUserId(userId string) (*model.User, error) {
-	args := m.Called(userId)
+func (m *UserRepositoryMock) QueryUserByUserId(ctx context.Context, userId string) (*model.User, error) {
+	args := m.Called(ctx, userId)
 	user, _ := args.Get(0).(*model.User)
 	return user, args.Error(1)
 }
 
-func (m *UserRepositoryMock) UpdateUser(user *model.User) error {
-	args := m.Called(user)
+func (m *UserRepositoryMock) QueryProfileByUserId(ctx context.Context, userId string) (*dto.UserProfile, error) {
+	args := m.Called(ctx, userId)
+	profile, _ := args.Get(0).(*dto.UserProfile)
+	return profile, args.Error(1)
+}
+
+func (m *UserRepositoryMock) QueryUsersByIds(ctx context.Context, userIds []string) ([]*model.User, error) {
+	args := m.Called(ctx, userIds)
+	users, _ := args.Get(0).([]*model.User)
+	return users, args.Error(1)
+}
+
+func (m *UserRepositoryMock) UpdateUser(ctx context.Context, user *model.User, expectedVersion int64, outbox ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(outbox...)
+	args := m.Called(ctx, user, expectedVersion, outbox)
 	return args.Error(0)
 }
 
-func (m *UserRepositoryMock) DeleteUser(userId string) error {
-	args := m.Called(userId)
+func (m *UserRepositoryMock) UpdateUserImage(ctx context.Context, user *model.User, variants dto.ImageVariants, expectedVersion int64, outbox ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(outbox...)
+	args := m.Called(ctx, user, variants, expectedVersion, outbox)
 	return args.Error(0)
 }
+
+func (m *UserRepositoryMock) UpdateProfile(ctx context.Context, profile *dto.UserProfile, expectedVersion int64, outbox ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(outbox...)
+	args := m.Called(ctx, profile, expectedVersion, outbox)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) IsUsernameTaken(ctx context.Context, username string, excludeUserId string) (bool, error) {
+	args := m.Called(ctx, username, excludeUserId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *UserRepositoryMock) QueryTOTPSecret(ctx context.Context, userId string) ([]byte, error) {
+	args := m.Called(ctx, userId)
+	sealedSecret, _ := args.Get(0).([]byte)
+	return sealedSecret, args.Error(1)
+}
+
+func (m *UserRepositoryMock) UpdateTOTPSecret(ctx context.Context, userId string, sealedSecret []byte, recoveryCodeHashes []string, outbox ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(outbox...)
+	args := m.Called(ctx, userId, sealedSecret, recoveryCodeHashes, outbox)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) QueryRecoveryCodes(ctx context.Context, userId string) ([]dto.RecoveryCode, error) {
+	args := m.Called(ctx, userId)
+	codes, _ := args.Get(0).([]dto.RecoveryCode)
+	return codes, args.Error(1)
+}
+
+func (m *UserRepositoryMock) CountRecoveryCodes(ctx context.Context, userId string) (int, error) {
+	args := m.Called(ctx, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *UserRepositoryMock) ReplaceRecoveryCodes(ctx context.Context, userId string, codeHashes []string) error {
+	args := m.Called(ctx, userId, codeHashes)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) UseRecoveryCode(ctx context.Context, codeId int64) error {
+	args := m.Called(ctx, codeId)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) QueryWebAuthnCredentials(ctx context.Context, userId string) ([]dto.WebAuthnCredential, error) {
+	args := m.Called(ctx, userId)
+	credentials, _ := args.Get(0).([]dto.WebAuthnCredential)
+	return credentials, args.Error(1)
+}
+
+func (m *UserRepositoryMock) CreateWebAuthnCredential(ctx context.Context, credential *dto.WebAuthnCredential) error {
+	args := m.Called(ctx, credential)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) RenameWebAuthnCredential(ctx context.Context, userId string, id int64, name string) error {
+	args := m.Called(ctx, userId, id, name)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) DeleteWebAuthnCredential(ctx context.Context, userId string, id int64) error {
+	args := m.Called(ctx, userId, id)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) UpdateWebAuthnCredentialUsage(ctx context.Context, usage *dto.WebAuthnCredentialUsage) error {
+	args := m.Called(ctx, usage)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) QueryPasswordHistory(ctx context.Context, userId string, limit int) ([]string, error) {
+	args := m.Called(ctx, userId, limit)
+	hashes, _ := args.Get(0).([]string)
+	return hashes, args.Error(1)
+}
+
+func (m *UserRepositoryMock) UpdateUserPassword(ctx context.Context, userId, passwordHash, previousHash string, keep int, outbox ...*dto.OutboxMessage) error {
+	mustNotCarryPassword(outbox...)
+	args := m.Called(ctx, userId, passwordHash, previousHash, keep, outbox)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) DeleteUser(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) RestoreUser(ctx context.Context, userId string, deletedAfter time.Time) error {
+	args := m.Called(ctx, userId, deletedAfter)
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time, newEvent func(userId string) (*dto.OutboxMessage, error)) ([]string, error) {
+	args := m.Called(ctx, deletedBefore, newEvent)
+	userIds, _ := args.Get(0).([]string)
+	return userIds, args.Error(1)
+}
+
+func (m *UserRepositoryMock) ListUsers(ctx context.Context, q *dto.ListUsersQuery) ([]dto.UserListItem, error) {
+	args := m.Called(ctx, q)
+	users, _ := args.Get(0).([]dto.UserListItem)
+	return users, args.Error(1)
+}
+
+// expectations stay on the same mock, so calls made through a tx-bound repository are asserted as usual
+func (m *UserRepositoryMock) WithQuerier(q _db.Querier) repository.UserRepository {
+	return m
+}
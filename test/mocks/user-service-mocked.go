@@ -1,8 +1,12 @@
 package mocks
 
 import (
+	"context"
+	"mime/multipart"
+
 	"github.com/micros-template/user-service/internal/domain/dto"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -10,27 +14,163 @@ type UserServiceMock struct {
 	mock.Mock
 }
 
-func (m *UserServiceMock) GetProfile(userId string) (dto.GetProfileResponse, error) {
-	args := m.Called(userId)
+func (m *UserServiceMock) GetProfile(ctx context.Context, userId string) (dto.GetProfileResponse, error) {
+	args := m.Called(ctx, userId)
 	return args.Get(0).(dto.GetProfileResponse), args.Error(1)
 }
 
-func (m *UserServiceMock) UpdateUser(req *dto.UpdateUserRequest, userId string) error {
-	args := m.Called(req, userId)
+func (m *UserServiceMock) ListUsers(ctx context.Context, req *dto.ListUsersRequest) (dto.ListUsersResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(dto.ListUsersResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) UpdateUser(ctx context.Context, req *dto.UpdateUserRequest, userId string) (dto.GetProfileResponse, error) {
+	args := m.Called(ctx, req, userId)
+	return args.Get(0).(dto.GetProfileResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) UpdateAvatar(ctx context.Context, file *multipart.FileHeader, userId string) error {
+	args := m.Called(ctx, file, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) DeleteAvatar(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) GetAvatarURL(ctx context.Context, userId string, size string) (string, error) {
+	args := m.Called(ctx, userId, size)
+	return args.String(0), args.Error(1)
+}
+
+func (m *UserServiceMock) GetDefaultAvatar(ctx context.Context, userId string) ([]byte, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *UserServiceMock) CheckUsernameAvailability(ctx context.Context, username string, userId string) (dto.UsernameAvailabilityResponse, error) {
+	args := m.Called(ctx, username, userId)
+	return args.Get(0).(dto.UsernameAvailabilityResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) UpdatePhoneNumber(ctx context.Context, phoneNumber string, userId string) error {
+	args := m.Called(ctx, phoneNumber, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) VerifyPhoneNumber(ctx context.Context, req *dto.VerifyPhoneNumberRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) SetupTOTP(ctx context.Context, userId string) (dto.SetupTOTPResponse, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).(dto.SetupTOTPResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) EnableTOTP(ctx context.Context, req *dto.EnableTOTPRequest, userId string) (dto.RecoveryCodesResponse, error) {
+	args := m.Called(ctx, req, userId)
+	return args.Get(0).(dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) DisableTOTP(ctx context.Context, req *dto.DisableTOTPRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) VerifyTOTP(ctx context.Context, code string, userId string) error {
+	args := m.Called(ctx, code, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) RegenerateRecoveryCodes(ctx context.Context, req *dto.RegenerateRecoveryCodesRequest, userId string) (dto.RecoveryCodesResponse, error) {
+	args := m.Called(ctx, req, userId)
+	return args.Get(0).(dto.RecoveryCodesResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) CountRecoveryCodes(ctx context.Context, userId string) (dto.RecoveryCodesCountResponse, error) {
+	args := m.Called(ctx, userId)
+	return args.Get(0).(dto.RecoveryCodesCountResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) UseRecoveryCode(ctx context.Context, code string, userId string) error {
+	args := m.Called(ctx, code, userId)
 	return args.Error(0)
 }
 
-func (m *UserServiceMock) UpdateEmail(req *dto.UpdateEmailRequest, userId string) error {
-	args := m.Called(req, userId)
+func (m *UserServiceMock) BeginPasskeyRegistration(ctx context.Context, userId string) (*protocol.CredentialCreation, error) {
+	args := m.Called(ctx, userId)
+	creation, _ := args.Get(0).(*protocol.CredentialCreation)
+	return creation, args.Error(1)
+}
+
+func (m *UserServiceMock) FinishPasskeyRegistration(ctx context.Context, req *dto.FinishPasskeyRegistrationRequest, userId string) (dto.PasskeyResponse, error) {
+	args := m.Called(ctx, req, userId)
+	return args.Get(0).(dto.PasskeyResponse), args.Error(1)
+}
+
+func (m *UserServiceMock) ListPasskeys(ctx context.Context, userId string) ([]dto.PasskeyResponse, error) {
+	args := m.Called(ctx, userId)
+	passkeys, _ := args.Get(0).([]dto.PasskeyResponse)
+	return passkeys, args.Error(1)
+}
+
+func (m *UserServiceMock) RenamePasskey(ctx context.Context, req *dto.RenamePasskeyRequest, id int64, userId string) error {
+	args := m.Called(ctx, req, id, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) DeletePasskey(ctx context.Context, id int64, userId string) error {
+	args := m.Called(ctx, id, userId)
 	return args.Error(0)
 }
 
-func (m *UserServiceMock) UpdatePassword(req *dto.UpdatePasswordRequest, userId string) error {
-	args := m.Called(req, userId)
+func (m *UserServiceMock) GetWebAuthnCredentials(ctx context.Context, userId string) ([]dto.WebAuthnCredential, error) {
+	args := m.Called(ctx, userId)
+	credentials, _ := args.Get(0).([]dto.WebAuthnCredential)
+	return credentials, args.Error(1)
+}
+
+func (m *UserServiceMock) UpdateWebAuthnCredentialUsage(ctx context.Context, usage *dto.WebAuthnCredentialUsage) error {
+	args := m.Called(ctx, usage)
 	return args.Error(0)
 }
 
-func (m *UserServiceMock) DeleteUser(req *dto.DeleteUserRequest, userId string) error {
-	args := m.Called(req, userId)
+func (m *UserServiceMock) UpdateEmail(ctx context.Context, req *dto.UpdateEmailRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
 	return args.Error(0)
 }
+
+func (m *UserServiceMock) VerifyEmailChange(ctx context.Context, req *dto.VerifyEmailChangeRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) UpdatePassword(ctx context.Context, req *dto.UpdatePasswordRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) DeleteUser(ctx context.Context, req *dto.DeleteUserRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) ConfirmDeleteUser(ctx context.Context, req *dto.ConfirmDeleteUserRequest, userId string) error {
+	args := m.Called(ctx, req, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) RestoreUser(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
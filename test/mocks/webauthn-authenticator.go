@@ -0,0 +1,100 @@
+package mocks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+)
+
+// SoftwareAuthenticator answers registration ceremonies the way navigator.credentials.create would,
+// with a P-256 key kept in memory and a "none" attestation
+type SoftwareAuthenticator struct {
+	Origin       string
+	CredentialID []byte
+	PrivateKey   *ecdsa.PrivateKey
+}
+
+func NewSoftwareAuthenticator(origin string) *SoftwareAuthenticator {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	credentialId := make([]byte, 16)
+	if _, err := rand.Read(credentialId); err != nil {
+		panic(err)
+	}
+	return &SoftwareAuthenticator{Origin: origin, CredentialID: credentialId, PrivateKey: privateKey}
+}
+
+// Register returns the PublicKeyCredential json for the creation options
+func (a *SoftwareAuthenticator) Register(creation *protocol.CredentialCreation) []byte {
+	return a.register(creation.Response.Challenge.String(), creation.Response.RelyingParty.ID)
+}
+
+// RegisterWithChallenge signs an arbitrary challenge, for responses the server must reject
+func (a *SoftwareAuthenticator) RegisterWithChallenge(creation *protocol.CredentialCreation, challenge []byte) []byte {
+	return a.register(base64.RawURLEncoding.EncodeToString(challenge), creation.Response.RelyingParty.ID)
+}
+
+func (a *SoftwareAuthenticator) register(challenge, rpId string) []byte {
+	clientData, err := json.Marshal(map[string]any{
+		"type":      "webauthn.create",
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.PrivateKey.X.FillBytes(make([]byte, 32)),
+		-3: a.PrivateKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	rpIdHash := sha256.Sum256([]byte(rpId))
+	authData := append([]byte{}, rpIdHash[:]...)
+	// user present, user verified, attested credential data
+	authData = append(authData, 0x45)
+	authData = binary.BigEndian.AppendUint32(authData, 0)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.CredentialID)))
+	authData = append(authData, a.CredentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	credential, err := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.CredentialID),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+			"transports":        []string{"internal"},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return credential
+}
@@ -0,0 +1,159 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/pkg/uapb"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type GetUserHandlerSuite struct {
+	suite.Suite
+	accountHandler  handler.AccountGrpcHandler
+	mockAuthService *mocks.MockAuthService
+	mockUserService *mocks.UserServiceMock
+}
+
+func (g *GetUserHandlerSuite) SetupSuite() {
+	mockedAuthService := new(mocks.MockAuthService)
+	mockedUserService := new(mocks.UserServiceMock)
+	g.mockAuthService = mockedAuthService
+	g.mockUserService = mockedUserService
+	g.accountHandler = *handler.NewAccountGrpcHandler(mockedUserService, mockedAuthService)
+}
+
+func (g *GetUserHandlerSuite) SetupTest() {
+	g.mockAuthService.ExpectedCalls = nil
+	g.mockAuthService.Calls = nil
+	g.mockUserService.ExpectedCalls = nil
+	g.mockUserService.Calls = nil
+}
+
+func TestGetUserHandlerSuite(t *testing.T) {
+	suite.Run(t, &GetUserHandlerSuite{})
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserById_Success() {
+	req := &upb.UserId{UserId: "user-id-123"}
+	expected := &upb.User{Id: "user-id-123", Email: "john@example.com"}
+	g.mockAuthService.On("GetUserById", mock.Anything, req).Return(expected, nil)
+
+	user, err := g.accountHandler.GetUserById(context.Background(), req)
+
+	g.NoError(err)
+	g.Equal(expected, user)
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserById_NotFound() {
+	req := &upb.UserId{UserId: "user-id-123"}
+	g.mockAuthService.On("GetUserById", mock.Anything, req).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	user, err := g.accountHandler.GetUserById(context.Background(), req)
+
+	g.Nil(user)
+	g.Equal(codes.NotFound, status.Code(err))
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserById_InvalidInput() {
+	user, err := g.accountHandler.GetUserById(context.Background(), &upb.UserId{})
+
+	g.Nil(user)
+	g.Equal(codes.InvalidArgument, status.Code(err))
+	g.mockAuthService.AssertNotCalled(g.T(), "GetUserById", mock.Anything, mock.Anything)
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUsersByIds_Success() {
+	ids := []string{"user-1", "user-2"}
+	users := []*upb.User{{Id: "user-1"}, {Id: "user-2"}}
+	g.mockAuthService.On("GetUsersByIds", mock.Anything, ids).Return(users, nil)
+
+	res, err := g.accountHandler.GetUsersByIds(context.Background(), &uapb.GetUsersByIdsRequest{UserIds: ids})
+
+	g.NoError(err)
+	g.Equal(users, res.GetUsers())
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUsersByIds_Empty() {
+	res, err := g.accountHandler.GetUsersByIds(context.Background(), &uapb.GetUsersByIdsRequest{})
+
+	g.Nil(res)
+	g.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUsersByIds_InternalError() {
+	ids := []string{"user-1"}
+	g.mockAuthService.On("GetUsersByIds", mock.Anything, ids).Return(nil, dto.Err_INTERNAL_FAILED_QUERY_USERS)
+
+	res, err := g.accountHandler.GetUsersByIds(context.Background(), &uapb.GetUsersByIdsRequest{UserIds: ids})
+
+	g.Nil(res)
+	g.Equal(codes.Internal, status.Code(err))
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserByEmail_NotFound() {
+	g.mockAuthService.On("GetUserByEmail", mock.Anything, "john@example.com").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	user, err := g.accountHandler.GetUserByEmail(context.Background(), &uapb.GetUserByEmailRequest{Email: "john@example.com"})
+
+	g.Nil(user)
+	g.Equal(codes.NotFound, status.Code(err))
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserByEmail_Success() {
+	expected := &upb.User{Id: "user-id-123", Email: "john@example.com"}
+	g.mockAuthService.On("GetUserByEmail", mock.Anything, "john@example.com").Return(expected, nil)
+
+	user, err := g.accountHandler.GetUserByEmail(context.Background(), &uapb.GetUserByEmailRequest{Email: "john@example.com"})
+
+	g.NoError(err)
+	g.Equal(expected, user)
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserProfile_Success() {
+	username := "john.doe"
+	phone := "+6281234567890"
+	dateOfBirth := "1990-01-31"
+	g.mockUserService.On("GetProfile", mock.Anything, "user-id-123").Return(dto.GetProfileResponse{
+		FullName:      "John Doe",
+		Username:      &username,
+		PhoneNumber:   &phone,
+		PhoneVerified: true,
+		DateOfBirth:   &dateOfBirth,
+	}, nil)
+
+	profile, err := g.accountHandler.GetUserProfile(context.Background(), &upb.UserId{UserId: "user-id-123"})
+
+	g.NoError(err)
+	g.Equal("user-id-123", profile.GetId())
+	g.Equal("john.doe", profile.GetUsername())
+	g.Nil(profile.Bio)
+	g.Equal("+6281234567890", profile.GetPhoneNumber())
+	g.True(profile.GetPhoneVerified())
+	g.Equal("1990-01-31", profile.GetDateOfBirth())
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserProfile_NotFound() {
+	g.mockUserService.On("GetProfile", mock.Anything, "user-id-123").Return(dto.GetProfileResponse{}, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	profile, err := g.accountHandler.GetUserProfile(context.Background(), &upb.UserId{UserId: "user-id-123"})
+
+	g.Nil(profile)
+	g.Equal(codes.NotFound, status.Code(err))
+}
+
+func (g *GetUserHandlerSuite) TestAccountHandler_GetUserProfile_InvalidInput() {
+	profile, err := g.accountHandler.GetUserProfile(context.Background(), &upb.UserId{})
+
+	g.Nil(profile)
+	g.Equal(codes.InvalidArgument, status.Code(err))
+	g.mockUserService.AssertNotCalled(g.T(), "GetProfile", mock.Anything, mock.Anything)
+}
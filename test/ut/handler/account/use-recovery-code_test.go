@@ -0,0 +1,72 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/pkg/uapb"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type UseRecoveryCodeHandlerSuite struct {
+	suite.Suite
+	accountHandler  handler.AccountGrpcHandler
+	mockUserService *mocks.UserServiceMock
+}
+
+func (u *UseRecoveryCodeHandlerSuite) SetupSuite() {
+	mockedUserService := new(mocks.UserServiceMock)
+	u.mockUserService = mockedUserService
+	u.accountHandler = *handler.NewAccountGrpcHandler(mockedUserService, new(mocks.MockAuthService))
+}
+
+func (u *UseRecoveryCodeHandlerSuite) SetupTest() {
+	u.mockUserService.ExpectedCalls = nil
+	u.mockUserService.Calls = nil
+}
+
+func TestUseRecoveryCodeHandlerSuite(t *testing.T) {
+	suite.Run(t, &UseRecoveryCodeHandlerSuite{})
+}
+
+func (u *UseRecoveryCodeHandlerSuite) TestAccountHandler_UseRecoveryCode_Success() {
+	u.mockUserService.On("UseRecoveryCode", mock.Anything, "k7m2p-x9q4r", "user-id-123").Return(nil)
+
+	s, err := u.accountHandler.UseRecoveryCode(context.Background(), &uapb.UseRecoveryCodeRequest{UserId: "user-id-123", Code: "k7m2p-x9q4r"})
+
+	u.NoError(err)
+	u.Equal(&upb.Status{Success: true}, s)
+}
+
+func (u *UseRecoveryCodeHandlerSuite) TestAccountHandler_UseRecoveryCode_InvalidInput() {
+	s, err := u.accountHandler.UseRecoveryCode(context.Background(), &uapb.UseRecoveryCodeRequest{UserId: "user-id-123"})
+
+	u.Nil(s)
+	u.Equal(codes.InvalidArgument, status.Code(err))
+	u.mockUserService.AssertNotCalled(u.T(), "UseRecoveryCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UseRecoveryCodeHandlerSuite) TestAccountHandler_UseRecoveryCode_ErrorCodes() {
+	for err, code := range map[error]codes.Code{
+		dto.Err_UNAUTHORIZED_RECOVERY_INVALID:   codes.Unauthenticated,
+		dto.Err_NOTFOUND_USER_NOT_FOUND:         codes.NotFound,
+		dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS: codes.ResourceExhausted,
+		dto.Err_INTERNAL_FAILED_QUERY_RECOVERY:  codes.Internal,
+	} {
+		u.SetupTest()
+		u.mockUserService.On("UseRecoveryCode", mock.Anything, "k7m2p-x9q4r", "user-id-123").Return(err)
+
+		s, grpcErr := u.accountHandler.UseRecoveryCode(context.Background(), &uapb.UseRecoveryCodeRequest{UserId: "user-id-123", Code: "k7m2p-x9q4r"})
+
+		u.Nil(s)
+		u.Equal(code, status.Code(grpcErr), err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/pkg/uapb"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type VerifyEmailChangeHandlerSuite struct {
+	suite.Suite
+	accountHandler  handler.AccountGrpcHandler
+	mockUserService *mocks.UserServiceMock
+}
+
+func (v *VerifyEmailChangeHandlerSuite) SetupSuite() {
+	mockedUserService := new(mocks.UserServiceMock)
+	v.mockUserService = mockedUserService
+
+	grpcServer := grpc.NewServer()
+	handler.RegisterAccountService(grpcServer, mockedUserService, new(mocks.MockAuthService))
+	v.accountHandler = *handler.NewAccountGrpcHandler(mockedUserService, new(mocks.MockAuthService))
+}
+
+func (v *VerifyEmailChangeHandlerSuite) SetupTest() {
+	v.mockUserService.ExpectedCalls = nil
+	v.mockUserService.Calls = nil
+}
+
+func TestVerifyEmailChangeHandlerSuite(t *testing.T) {
+	suite.Run(t, &VerifyEmailChangeHandlerSuite{})
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestAccountHandler_VerifyEmailChange_Success() {
+	req := &uapb.VerifyEmailChangeRequest{UserId: "user-id-123", Token: "valid-token"}
+	v.mockUserService.On("VerifyEmailChange", mock.Anything, &dto.VerifyEmailChangeRequest{Token: "valid-token"}, "user-id-123").Return(nil)
+
+	s, err := v.accountHandler.VerifyEmailChange(context.Background(), req)
+
+	v.NoError(err)
+	v.Equal(&upb.Status{Success: true}, s)
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestAccountHandler_VerifyEmailChange_InvalidInput() {
+	req := &uapb.VerifyEmailChangeRequest{UserId: "user-id-123"}
+
+	s, err := v.accountHandler.VerifyEmailChange(context.Background(), req)
+
+	v.Nil(s)
+	v.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestAccountHandler_VerifyEmailChange_InvalidToken() {
+	req := &uapb.VerifyEmailChangeRequest{UserId: "user-id-123", Token: "wrong-token"}
+	v.mockUserService.On("VerifyEmailChange", mock.Anything, &dto.VerifyEmailChangeRequest{Token: "wrong-token"}, "user-id-123").Return(dto.Err_UNAUTHORIZED_TOKEN_INVALID)
+
+	s, err := v.accountHandler.VerifyEmailChange(context.Background(), req)
+
+	v.Nil(s)
+	v.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestAccountHandler_VerifyEmailChange_EmailTaken() {
+	req := &uapb.VerifyEmailChangeRequest{UserId: "user-id-123", Token: "valid-token"}
+	v.mockUserService.On("VerifyEmailChange", mock.Anything, &dto.VerifyEmailChangeRequest{Token: "valid-token"}, "user-id-123").Return(dto.Err_CONFLICT_EMAIL_EXIST)
+
+	s, err := v.accountHandler.VerifyEmailChange(context.Background(), req)
+
+	v.Nil(s)
+	v.Equal(codes.AlreadyExists, status.Code(err))
+}
@@ -0,0 +1,74 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/pkg/uapb"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type VerifyTOTPHandlerSuite struct {
+	suite.Suite
+	accountHandler  handler.AccountGrpcHandler
+	mockUserService *mocks.UserServiceMock
+}
+
+func (v *VerifyTOTPHandlerSuite) SetupSuite() {
+	mockedUserService := new(mocks.UserServiceMock)
+	v.mockUserService = mockedUserService
+	v.accountHandler = *handler.NewAccountGrpcHandler(mockedUserService, new(mocks.MockAuthService))
+}
+
+func (v *VerifyTOTPHandlerSuite) SetupTest() {
+	v.mockUserService.ExpectedCalls = nil
+	v.mockUserService.Calls = nil
+}
+
+func TestVerifyTOTPHandlerSuite(t *testing.T) {
+	suite.Run(t, &VerifyTOTPHandlerSuite{})
+}
+
+func (v *VerifyTOTPHandlerSuite) TestAccountHandler_VerifyTOTP_Success() {
+	v.mockUserService.On("VerifyTOTP", mock.Anything, "123456", "user-id-123").Return(nil)
+
+	s, err := v.accountHandler.VerifyTOTP(context.Background(), &uapb.VerifyTOTPRequest{UserId: "user-id-123", Code: "123456"})
+
+	v.NoError(err)
+	v.Equal(&upb.Status{Success: true}, s)
+}
+
+func (v *VerifyTOTPHandlerSuite) TestAccountHandler_VerifyTOTP_InvalidInput() {
+	s, err := v.accountHandler.VerifyTOTP(context.Background(), &uapb.VerifyTOTPRequest{UserId: "user-id-123"})
+
+	v.Nil(s)
+	v.Equal(codes.InvalidArgument, status.Code(err))
+	v.mockUserService.AssertNotCalled(v.T(), "VerifyTOTP", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (v *VerifyTOTPHandlerSuite) TestAccountHandler_VerifyTOTP_ErrorCodes() {
+	for err, code := range map[error]codes.Code{
+		dto.Err_UNAUTHORIZED_OTP_INVALID:        codes.Unauthenticated,
+		dto.Err_NOTFOUND_USER_NOT_FOUND:         codes.NotFound,
+		dto.Err_CONFLICT_TWO_FACTOR_DISABLED:    codes.FailedPrecondition,
+		dto.Err_CONFLICT_TWO_FACTOR_REENROLL:    codes.FailedPrecondition,
+		dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS: codes.ResourceExhausted,
+		dto.Err_INTERNAL_TOTP_SECRET:            codes.Internal,
+	} {
+		v.SetupTest()
+		v.mockUserService.On("VerifyTOTP", mock.Anything, "123456", "user-id-123").Return(err)
+
+		s, grpcErr := v.accountHandler.VerifyTOTP(context.Background(), &uapb.VerifyTOTPRequest{UserId: "user-id-123", Code: "123456"})
+
+		v.Nil(s)
+		v.Equal(code, status.Code(grpcErr), err.Error())
+	}
+}
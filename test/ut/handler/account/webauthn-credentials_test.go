@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/pkg/uapb"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type WebAuthnCredentialsHandlerSuite struct {
+	suite.Suite
+	accountHandler  handler.AccountGrpcHandler
+	mockUserService *mocks.UserServiceMock
+}
+
+func (w *WebAuthnCredentialsHandlerSuite) SetupSuite() {
+	mockedUserService := new(mocks.UserServiceMock)
+	w.mockUserService = mockedUserService
+	w.accountHandler = *handler.NewAccountGrpcHandler(mockedUserService, new(mocks.MockAuthService))
+}
+
+func (w *WebAuthnCredentialsHandlerSuite) SetupTest() {
+	w.mockUserService.ExpectedCalls = nil
+	w.mockUserService.Calls = nil
+}
+
+func TestWebAuthnCredentialsHandlerSuite(t *testing.T) {
+	suite.Run(t, &WebAuthnCredentialsHandlerSuite{})
+}
+
+func (w *WebAuthnCredentialsHandlerSuite) TestAccountHandler_GetWebAuthnCredentials_Success() {
+	w.mockUserService.On("GetWebAuthnCredentials", mock.Anything, "user-id-123").Return([]dto.WebAuthnCredential{
+		{ID: 1, CredentialID: []byte("credential"), PublicKey: []byte("public-key"), AttestationType: "none", Transports: []string{"internal"}, SignCount: 3, BackupEligible: true},
+	}, nil)
+
+	res, err := w.accountHandler.GetWebAuthnCredentials(context.Background(), &uapb.GetWebAuthnCredentialsRequest{UserId: "user-id-123"})
+
+	w.NoError(err)
+	w.Equal([]byte("user-id-123"), res.GetUserHandle())
+	w.Len(res.GetCredentials(), 1)
+	w.Equal([]byte("credential"), res.GetCredentials()[0].GetCredentialId())
+	w.Equal(uint32(3), res.GetCredentials()[0].GetSignCount())
+	w.True(res.GetCredentials()[0].GetBackupEligible())
+}
+
+func (w *WebAuthnCredentialsHandlerSuite) TestAccountHandler_GetWebAuthnCredentials_InvalidInput() {
+	res, err := w.accountHandler.GetWebAuthnCredentials(context.Background(), &uapb.GetWebAuthnCredentialsRequest{})
+
+	w.Nil(res)
+	w.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (w *WebAuthnCredentialsHandlerSuite) TestAccountHandler_UpdateWebAuthnSignCount_Success() {
+	w.mockUserService.On("UpdateWebAuthnCredentialUsage", mock.Anything, &dto.WebAuthnCredentialUsage{
+		UserID: "user-id-123", CredentialID: []byte("credential"), SignCount: 4, BackupState: true,
+	}).Return(nil)
+
+	s, err := w.accountHandler.UpdateWebAuthnSignCount(context.Background(), &uapb.UpdateWebAuthnSignCountRequest{
+		UserId: "user-id-123", CredentialId: []byte("credential"), SignCount: 4, BackupState: true,
+	})
+
+	w.NoError(err)
+	w.Equal(&upb.Status{Success: true}, s)
+}
+
+func (w *WebAuthnCredentialsHandlerSuite) TestAccountHandler_UpdateWebAuthnSignCount_NotFound() {
+	w.mockUserService.On("UpdateWebAuthnCredentialUsage", mock.Anything, mock.Anything).Return(dto.Err_NOTFOUND_PASSKEY)
+
+	s, err := w.accountHandler.UpdateWebAuthnSignCount(context.Background(), &uapb.UpdateWebAuthnSignCountRequest{
+		UserId: "user-id-123", CredentialId: []byte("credential"), SignCount: 4,
+	})
+
+	w.Nil(s)
+	w.Equal(codes.NotFound, status.Code(err))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/micros-template/user-service/test/mocks"
 
 	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -49,7 +50,7 @@ func (c *CreateUserHandlerSuite) TestAuthHandler_CreateUserHandler_Success() {
 		Success: true,
 	}
 
-	c.mockAuthService.On("CreateUser", user).Return(expectedStatus, nil)
+	c.mockAuthService.On("CreateUser", mock.Anything, user).Return(expectedStatus, nil)
 
 	status, err := c.authHandler.CreateUser(ctx, user)
 
@@ -67,7 +68,7 @@ func (c *CreateUserHandlerSuite) TestAuthHandler_CreateUserHandler_Error() {
 	}
 	expectedError := dto.Err_INTERNAL_FAILED_INSERT_USER
 
-	c.mockAuthService.On("CreateUser", user).Return(nil, expectedError)
+	c.mockAuthService.On("CreateUser", mock.Anything, user).Return(nil, expectedError)
 
 	status, err := c.authHandler.CreateUser(ctx, user)
 
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	grpcStatus "google.golang.org/grpc/status"
 )
 
@@ -39,6 +40,8 @@ func (c *UpdateUserHandlerSuite) SetupTest() {
 func TestUpdateUserHandlerSuite(t *testing.T) {
 	suite.Run(t, &UpdateUserHandlerSuite{})
 }
+
+// without x-expected-version the update is unconditional
 func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_Success() {
 	ctx := context.Background()
 	user := &upb.User{
@@ -50,7 +53,7 @@ func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_Success() {
 		Success: true,
 	}
 
-	c.mockAuthService.On("UpdateUser", mock.Anything, user).Return(nil)
+	c.mockAuthService.On("UpdateUser", mock.Anything, user, int64(0)).Return(nil)
 
 	status, err := c.authHandler.UpdateUser(ctx, user)
 
@@ -68,7 +71,7 @@ func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_UserNotFound(
 	}
 	expectedError := dto.Err_NOTFOUND_USER_NOT_FOUND
 
-	c.mockAuthService.On("UpdateUser", mock.Anything, user).Return(expectedError)
+	c.mockAuthService.On("UpdateUser", mock.Anything, user, int64(0)).Return(expectedError)
 
 	status, err := c.authHandler.UpdateUser(ctx, user)
 
@@ -88,7 +91,7 @@ func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_Error() {
 	}
 	expectedError := dto.Err_INTERNAL_FAILED_INSERT_USER
 
-	c.mockAuthService.On("UpdateUser", mock.Anything, user).Return(expectedError)
+	c.mockAuthService.On("UpdateUser", mock.Anything, user, int64(0)).Return(expectedError)
 
 	status, err := c.authHandler.UpdateUser(ctx, user)
 
@@ -98,3 +101,40 @@ func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_Error() {
 	c.Equal(expectedGrpcErr.Error(), err.Error())
 	c.mockAuthService.AssertExpectations(c.T())
 }
+
+func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_WithExpectedVersion() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-expected-version", "4"))
+	user := &upb.User{Id: "user-123", FullName: "Test User"}
+
+	c.mockAuthService.On("UpdateUser", mock.Anything, user, int64(4)).Return(nil)
+
+	status, err := c.authHandler.UpdateUser(ctx, user)
+
+	c.NoError(err)
+	c.Equal(&upb.Status{Success: true}, status)
+	c.mockAuthService.AssertExpectations(c.T())
+}
+
+func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_VersionMismatch() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-expected-version", "4"))
+	user := &upb.User{Id: "user-123", FullName: "Test User"}
+
+	c.mockAuthService.On("UpdateUser", mock.Anything, user, int64(4)).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+
+	status, err := c.authHandler.UpdateUser(ctx, user)
+
+	c.Nil(status)
+	c.Equal(codes.FailedPrecondition, grpcStatus.Code(err))
+	c.mockAuthService.AssertExpectations(c.T())
+}
+
+func (c *UpdateUserHandlerSuite) TestAuthHandler_UpdateUserHandler_InvalidExpectedVersion() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-expected-version", "abc"))
+	user := &upb.User{Id: "user-123", FullName: "Test User"}
+
+	status, err := c.authHandler.UpdateUser(ctx, user)
+
+	c.Nil(status)
+	c.Equal(codes.InvalidArgument, grpcStatus.Code(err))
+	c.mockAuthService.AssertNotCalled(c.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReadyzHandlerSuite struct {
+	suite.Suite
+}
+
+func (r *ReadyzHandlerSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestReadyzHandlerSuite(t *testing.T) {
+	suite.Run(t, &ReadyzHandlerSuite{})
+}
+
+func (r *ReadyzHandlerSuite) serve(hh handler.HealthHandler, path string) *httptest.ResponseRecorder {
+	router := gin.New()
+	handler.RegisterHealthRoutes(router, hh)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func (r *ReadyzHandlerSuite) TestHealthHandler_Readyz_AllUp() {
+	hh := handler.NewHealthHandler(health.NewFromChecks(0,
+		health.Check{Name: "postgres", Probe: func(ctx context.Context) error { return nil }},
+		health.Check{Name: "redis", Probe: func(ctx context.Context) error { return nil }},
+	))
+
+	w := r.serve(hh, "/readyz")
+
+	r.Equal(http.StatusOK, w.Code)
+	var report health.Report
+	r.NoError(json.Unmarshal(w.Body.Bytes(), &report))
+	r.Equal(health.StatusUp, report.Status)
+	r.Len(report.Dependencies, 2)
+	r.NotEmpty(report.Dependencies["postgres"].Latency)
+}
+
+func (r *ReadyzHandlerSuite) TestHealthHandler_Readyz_DependencyDown() {
+	hh := handler.NewHealthHandler(health.NewFromChecks(0,
+		health.Check{Name: "postgres", Probe: func(ctx context.Context) error { return nil }},
+		health.Check{Name: "nats", Probe: func(ctx context.Context) error { return errors.New("connection status CLOSED") }},
+	))
+
+	w := r.serve(hh, "/readyz")
+
+	r.Equal(http.StatusServiceUnavailable, w.Code)
+	var report health.Report
+	r.NoError(json.Unmarshal(w.Body.Bytes(), &report))
+	r.Equal(health.StatusDown, report.Status)
+	r.Equal(health.StatusUp, report.Dependencies["postgres"].Status)
+	r.Equal(health.StatusDown, report.Dependencies["nats"].Status)
+	r.Equal("connection status CLOSED", report.Dependencies["nats"].Error)
+}
+
+func (r *ReadyzHandlerSuite) TestHealthHandler_Livez_IgnoresDependencies() {
+	hh := handler.NewHealthHandler(health.NewFromChecks(0,
+		health.Check{Name: "postgres", Probe: func(ctx context.Context) error { return errors.New("down") }},
+	))
+
+	w := r.serve(hh, "/livez")
+
+	r.Equal(http.StatusOK, w.Code)
+	r.JSONEq(`{"status":"up"}`, w.Body.String())
+}
@@ -47,7 +47,7 @@ func TestChangeEmailHandlerSuite(t *testing.T) {
 
 func (c *ChangeEmailHandlerSuite) TestUserHandler_ChangeEmail_Success() {
 
-	c.mockUserService.On("UpdateEmail", &dto.UpdateEmailRequest{Email: "newemail@example.com"}, "12345").Return(nil)
+	c.mockUserService.On("UpdateEmail", mock.Anything, &dto.UpdateEmailRequest{Email: "newemail@example.com"}, "12345").Return(nil)
 
 	body := `{"email":"newemail@example.com"}`
 
@@ -64,7 +64,7 @@ func (c *ChangeEmailHandlerSuite) TestUserHandler_ChangeEmail_Success() {
 	c.Contains(w.Body.String(), "200")
 	c.Contains(w.Body.String(), dto.SUCCESS_UPDATE_EMAIL)
 
-	c.mockUserService.AssertCalled(c.T(), "UpdateEmail", &dto.UpdateEmailRequest{Email: "newemail@example.com"}, "12345")
+	c.mockUserService.AssertCalled(c.T(), "UpdateEmail", mock.Anything, &dto.UpdateEmailRequest{Email: "newemail@example.com"}, "12345")
 }
 
 func (c *ChangeEmailHandlerSuite) TestUserHandler_ChangeEmail_MissingUserId() {
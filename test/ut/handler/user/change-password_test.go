@@ -9,6 +9,7 @@ import (
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/pkg/password"
 	"github.com/micros-template/user-service/test/mocks"
 
 	"github.com/gin-gonic/gin"
@@ -53,7 +54,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_Success() {
 		NewPassword:        "new-password",
 		ConfirmNewPassword: "new-password",
 	}
-	c.mockUserService.On("UpdatePassword", u, "12345").Return(nil)
+	c.mockUserService.On("UpdatePassword", mock.Anything, u, "12345").Return(nil)
 
 	b := strings.NewReader(`{
 		"password": "old-password",
@@ -72,7 +73,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_Success() {
 	c.Equal(http.StatusOK, w.Code)
 	c.Contains(w.Body.String(), "200")
 	c.Contains(w.Body.String(), dto.SUCCESS_UPDATE_PASSWORD)
-	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", u, "12345")
+	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", mock.Anything, u, "12345")
 }
 
 func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_MissingUserId() {
@@ -126,7 +127,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_PasswordAndC
 		ConfirmNewPassword: "new-passwor",
 	}
 
-	c.mockUserService.On("UpdatePassword", u, "12345").Return(dto.Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH)
+	c.mockUserService.On("UpdatePassword", mock.Anything, u, "12345").Return(dto.Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH)
 
 	b := strings.NewReader(`{
 		"password": "old-password",
@@ -145,7 +146,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_PasswordAndC
 	c.Equal(http.StatusBadRequest, w.Code)
 	c.Contains(w.Body.String(), "400")
 	c.Contains(w.Body.String(), dto.Err_BAD_REQUEST_PASSWORD_CONFIRM_PASSWORD_DOESNT_MATCH.Error())
-	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", u, "12345")
+	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", mock.Anything, u, "12345")
 }
 
 func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_WrongPassword() {
@@ -156,7 +157,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_WrongPasswor
 		ConfirmNewPassword: "new-password",
 	}
 
-	c.mockUserService.On("UpdatePassword", u, "12345").Return(dto.Err_UNAUTHORIZED_PASSWORD_WRONG)
+	c.mockUserService.On("UpdatePassword", mock.Anything, u, "12345").Return(dto.Err_UNAUTHORIZED_PASSWORD_WRONG)
 
 	b := strings.NewReader(`{
 		"password": "old-password",
@@ -175,7 +176,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_WrongPasswor
 	c.Equal(http.StatusUnauthorized, w.Code)
 	c.Contains(w.Body.String(), "401")
 	c.Contains(w.Body.String(), dto.Err_UNAUTHORIZED_PASSWORD_WRONG.Error())
-	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", u, "12345")
+	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", mock.Anything, u, "12345")
 }
 
 func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_UserNotFound() {
@@ -186,7 +187,7 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_UserNotFound
 		ConfirmNewPassword: "new-password",
 	}
 
-	c.mockUserService.On("UpdatePassword", u, "12345").Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
+	c.mockUserService.On("UpdatePassword", mock.Anything, u, "12345").Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
 
 	b := strings.NewReader(`{
 		"password": "old-password",
@@ -205,5 +206,43 @@ func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_UserNotFound
 	c.Equal(http.StatusNotFound, w.Code)
 	c.Contains(w.Body.String(), "404")
 	c.Contains(w.Body.String(), dto.Err_NOTFOUND_USER_NOT_FOUND.Error())
-	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", u, "12345")
+	c.mockUserService.AssertCalled(c.T(), "UpdatePassword", mock.Anything, u, "12345")
+}
+
+func (c *ChangePasswordHandlerSuite) TestUserHandler_ChangePassword_PolicyViolation() {
+
+	u := &dto.UpdatePasswordRequest{
+		Password:           "old-password",
+		NewPassword:        "new-password",
+		ConfirmNewPassword: "new-password",
+	}
+
+	c.mockUserService.On("UpdatePassword", mock.Anything, u, "12345").Return(&dto.PasswordPolicyError{Violations: []password.Violation{
+		{Rule: password.RuleUppercase, Message: "must contain an uppercase letter"},
+		password.ReuseViolation(),
+	}})
+
+	b := strings.NewReader(`{
+		"password": "old-password",
+		"new_password": "new-password",
+		"confirm_new_password": "new-password"
+	}`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/change-password", b)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	c.userHandler.ChangePassword(ctx)
+
+	c.Equal(http.StatusBadRequest, w.Code)
+	c.JSONEq(`{
+		"status_code": 400,
+		"message": "password does not meet the policy",
+		"errors": [
+			{"rule": "uppercase", "message": "must contain an uppercase letter"},
+			{"rule": "reuse", "message": "must not match a recently used password"}
+		]
+	}`, w.Body.String())
 }
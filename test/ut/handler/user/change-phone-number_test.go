@@ -0,0 +1,88 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChangePhoneNumberHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (c *ChangePhoneNumberHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	c.mockUserService = mockedUserService
+	c.mockLogEmitter = mockedLogEmitterService
+	c.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (c *ChangePhoneNumberHandlerSuite) SetupTest() {
+	c.mockUserService.ExpectedCalls = nil
+	c.mockLogEmitter.ExpectedCalls = nil
+	c.mockUserService.Calls = nil
+	c.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestChangePhoneNumberHandlerSuite(t *testing.T) {
+	suite.Run(t, &ChangePhoneNumberHandlerSuite{})
+}
+
+func (c *ChangePhoneNumberHandlerSuite) TestUserHandler_ChangePhoneNumber_Success() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/phone", strings.NewReader(`{"phone_number":"+62 812-3456-7890"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	// the service receives the number in E.164 form
+	c.mockUserService.On("UpdatePhoneNumber", mock.Anything, "+6281234567890", "12345").Return(nil)
+	c.userHandler.ChangePhoneNumber(ctx)
+
+	c.Equal(http.StatusOK, w.Code)
+	c.Contains(w.Body.String(), dto.SUCCESS_UPDATE_PHONE)
+	c.mockUserService.AssertExpectations(c.T())
+}
+
+func (c *ChangePhoneNumberHandlerSuite) TestUserHandler_ChangePhoneNumber_InvalidNumber() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/phone", strings.NewReader(`{"phone_number":"081234"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	c.userHandler.ChangePhoneNumber(ctx)
+
+	c.Equal(http.StatusBadRequest, w.Code)
+	c.Contains(w.Body.String(), `"phone_number":"must be an E.164 number`)
+	c.mockUserService.AssertNotCalled(c.T(), "UpdatePhoneNumber", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (c *ChangePhoneNumberHandlerSuite) TestUserHandler_ChangePhoneNumber_TooManyRequests() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPatch, "/phone", strings.NewReader(`{"phone_number":"+6281234567890"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	c.mockUserService.On("UpdatePhoneNumber", mock.Anything, "+6281234567890", "12345").Return(dto.Err_TOO_MANY_REQUESTS_OTP_SENT)
+	c.userHandler.ChangePhoneNumber(ctx)
+
+	c.Equal(http.StatusTooManyRequests, w.Code)
+}
@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckUsernameAvailabilityHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (c *CheckUsernameAvailabilityHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	c.mockUserService = mockedUserService
+	c.mockLogEmitter = mockedLogEmitterService
+	c.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (c *CheckUsernameAvailabilityHandlerSuite) SetupTest() {
+	c.mockUserService.ExpectedCalls = nil
+	c.mockLogEmitter.ExpectedCalls = nil
+	c.mockUserService.Calls = nil
+	c.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestCheckUsernameAvailabilityHandlerSuite(t *testing.T) {
+	suite.Run(t, &CheckUsernameAvailabilityHandlerSuite{})
+}
+
+func (c *CheckUsernameAvailabilityHandlerSuite) TestUserHandler_CheckUsernameAvailability_Available() {
+	c.mockUserService.On("CheckUsernameAvailability", mock.Anything, "john.doe", "12345").
+		Return(dto.UsernameAvailabilityResponse{Username: "john.doe", Available: true}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/username/availability?username=john.doe", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.CheckUsernameAvailability(ctx)
+
+	c.Equal(http.StatusOK, w.Code)
+	c.Contains(w.Body.String(), `"available":true`)
+	c.Equal("no-store", w.Header().Get("Cache-Control"))
+	c.mockUserService.AssertExpectations(c.T())
+}
+
+func (c *CheckUsernameAvailabilityHandlerSuite) TestUserHandler_CheckUsernameAvailability_InvalidUsername() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/username/availability?username=Admin", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.CheckUsernameAvailability(ctx)
+
+	c.Equal(http.StatusBadRequest, w.Code)
+	c.Contains(w.Body.String(), `"username":"is reserved"`)
+	c.mockUserService.AssertNotCalled(c.T(), "CheckUsernameAvailability", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (c *CheckUsernameAvailabilityHandlerSuite) TestUserHandler_CheckUsernameAvailability_MissingUsername() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/username/availability", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.CheckUsernameAvailability(ctx)
+
+	c.Equal(http.StatusBadRequest, w.Code)
+	c.Contains(w.Body.String(), `"username":"is required"`)
+}
+
+func (c *CheckUsernameAvailabilityHandlerSuite) TestUserHandler_CheckUsernameAvailability_MissingUserId() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/username/availability?username=john.doe", nil)
+	c.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+	c.userHandler.CheckUsernameAvailability(ctx)
+
+	c.Equal(http.StatusUnauthorized, w.Code)
+
+	time.Sleep(time.Second)
+	c.mockLogEmitter.AssertExpectations(c.T())
+}
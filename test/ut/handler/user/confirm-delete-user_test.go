@@ -0,0 +1,96 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConfirmDeleteUserHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (c *ConfirmDeleteUserHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitter := new(mocks.LoggerInfraMock)
+	c.mockUserService = mockedUserService
+	c.mockLogEmitter = mockedLogEmitter
+	c.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitter, logger)
+}
+
+func (c *ConfirmDeleteUserHandlerSuite) SetupTest() {
+	c.mockUserService.ExpectedCalls = nil
+	c.mockLogEmitter.ExpectedCalls = nil
+
+	c.mockUserService.Calls = nil
+	c.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestConfirmDeleteUserHandlerSuite(t *testing.T) {
+	suite.Run(t, &ConfirmDeleteUserHandlerSuite{})
+}
+
+func (c *ConfirmDeleteUserHandlerSuite) TestUserHandler_ConfirmDeleteUser_Success() {
+	reqBody := `{"token":"valid-token"}`
+	c.mockUserService.On("ConfirmDeleteUser", mock.Anything, &dto.ConfirmDeleteUserRequest{Token: "valid-token"}, "12345").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/delete/confirm", strings.NewReader(reqBody))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	c.userHandler.ConfirmDeleteUser(ctx)
+
+	c.Equal(http.StatusOK, w.Code)
+	c.Contains(w.Body.String(), dto.SUCCESS_DELETE_USER)
+}
+
+func (c *ConfirmDeleteUserHandlerSuite) TestUserHandler_ConfirmDeleteUser_InvalidInput() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/delete/confirm", strings.NewReader(`{}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	c.userHandler.ConfirmDeleteUser(ctx)
+
+	c.Equal(http.StatusBadRequest, w.Code)
+	c.Contains(w.Body.String(), "invalid input")
+
+	time.Sleep(time.Second)
+	c.mockLogEmitter.AssertExpectations(c.T())
+}
+
+func (c *ConfirmDeleteUserHandlerSuite) TestUserHandler_ConfirmDeleteUser_InvalidToken() {
+	reqBody := `{"token":"wrong-token"}`
+	c.mockUserService.On("ConfirmDeleteUser", mock.Anything, mock.AnythingOfType("*dto.ConfirmDeleteUserRequest"), "12345").Return(dto.Err_UNAUTHORIZED_TOKEN_INVALID)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/delete/confirm", strings.NewReader(reqBody))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	c.userHandler.ConfirmDeleteUser(ctx)
+
+	c.Equal(http.StatusUnauthorized, w.Code)
+	c.Contains(w.Body.String(), dto.Err_UNAUTHORIZED_TOKEN_INVALID.Error())
+}
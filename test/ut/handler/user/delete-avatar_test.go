@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeleteAvatarHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (c *DeleteAvatarHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	c.mockUserService = mockedUserService
+	c.mockLogEmitter = mockedLogEmitterService
+	c.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (c *DeleteAvatarHandlerSuite) SetupTest() {
+	c.mockUserService.ExpectedCalls = nil
+	c.mockLogEmitter.ExpectedCalls = nil
+	c.mockUserService.Calls = nil
+	c.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestDeleteAvatarHandlerSuite(t *testing.T) {
+	suite.Run(t, &DeleteAvatarHandlerSuite{})
+}
+
+func (c *DeleteAvatarHandlerSuite) TestUserHandler_DeleteAvatar_Success() {
+	c.mockUserService.On("DeleteAvatar", mock.Anything, "12345").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/avatar", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.DeleteAvatar(ctx)
+
+	c.Equal(http.StatusOK, w.Code)
+	c.Contains(w.Body.String(), dto.SUCCESS_DELETE_AVATAR)
+	c.mockUserService.AssertExpectations(c.T())
+}
+
+func (c *DeleteAvatarHandlerSuite) TestUserHandler_DeleteAvatar_MissingUserId() {
+	c.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/avatar", nil)
+	c.userHandler.DeleteAvatar(ctx)
+
+	c.Equal(http.StatusUnauthorized, w.Code)
+	c.mockUserService.AssertNotCalled(c.T(), "DeleteAvatar", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	c.mockLogEmitter.AssertExpectations(c.T())
+}
+
+func (c *DeleteAvatarHandlerSuite) TestUserHandler_DeleteAvatar_InternalError() {
+	c.mockUserService.On("DeleteAvatar", mock.Anything, "12345").Return(dto.Err_INTERNAL_FAILED_UPDATE_USER)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodDelete, "/avatar", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.DeleteAvatar(ctx)
+
+	c.Equal(http.StatusInternalServerError, w.Code)
+}
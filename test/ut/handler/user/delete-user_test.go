@@ -3,6 +3,7 @@ package handler_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -49,20 +50,21 @@ func TestDeleteUserHandlerSuite(t *testing.T) {
 func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_Success() {
 	userId := "12345"
 	reqBody := `{"password":"secret"}`
-	d.mockUserService.On("DeleteUser", mock.AnythingOfType("*dto.DeleteUserRequest"), userId).Return(nil)
+	d.mockUserService.On("DeleteUser", mock.Anything, mock.AnythingOfType("*dto.DeleteUserRequest"), userId).Return(nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", strings.NewReader(reqBody))
 	ctx.Request.Header.Set("Content-Type", "application/json")
 	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Request.Header.Set("If-Match", `"1"`)
 
 	// Act
 	d.userHandler.DeleteUser(ctx)
 
 	// Assert
 	d.Equal(http.StatusOK, w.Code)
-	d.Contains(w.Body.String(), dto.SUCCESS_DELETE_USER)
+	d.Contains(w.Body.String(), dto.SUCCESS_REQUEST_DELETE)
 }
 
 func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_MissingUserId() {
@@ -87,6 +89,7 @@ func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_InvalidInput() {
 	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", nil)
 	ctx.Request.Header.Set("Content-Type", "application/json")
 	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Request.Header.Set("If-Match", `"1"`)
 
 	d.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 	d.userHandler.DeleteUser(ctx)
@@ -101,7 +104,7 @@ func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_InvalidInput() {
 func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_WrongPassword() {
 	userId := "12345"
 	reqBody := `{"password":"wrong-password"}`
-	d.mockUserService.On("DeleteUser", mock.AnythingOfType("*dto.DeleteUserRequest"), userId).
+	d.mockUserService.On("DeleteUser", mock.Anything, mock.AnythingOfType("*dto.DeleteUserRequest"), userId).
 		Return(dto.Err_UNAUTHORIZED_PASSWORD_WRONG)
 
 	w := httptest.NewRecorder()
@@ -109,6 +112,7 @@ func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_WrongPassword() {
 	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", strings.NewReader(reqBody))
 	ctx.Request.Header.Set("Content-Type", "application/json")
 	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Request.Header.Set("If-Match", `"1"`)
 
 	d.userHandler.DeleteUser(ctx)
 
@@ -119,7 +123,7 @@ func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_WrongPassword() {
 func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_UserNotFound() {
 	userId := "12345"
 	reqBody := `{"password":"secret"}`
-	d.mockUserService.On("DeleteUser", mock.AnythingOfType("*dto.DeleteUserRequest"), userId).
+	d.mockUserService.On("DeleteUser", mock.Anything, mock.AnythingOfType("*dto.DeleteUserRequest"), userId).
 		Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
 
 	w := httptest.NewRecorder()
@@ -127,9 +131,83 @@ func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_UserNotFound() {
 	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", strings.NewReader(reqBody))
 	ctx.Request.Header.Set("Content-Type", "application/json")
 	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Request.Header.Set("If-Match", `"1"`)
 
 	d.userHandler.DeleteUser(ctx)
 
 	d.Equal(http.StatusNotFound, w.Code)
 	d.Contains(w.Body.String(), dto.Err_NOTFOUND_USER_NOT_FOUND.Error())
 }
+
+func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_MissingIfMatch() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", strings.NewReader(`{"password":"secret"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	d.userHandler.DeleteUser(ctx)
+
+	d.Equal(http.StatusPreconditionRequired, w.Code)
+	d.mockUserService.AssertNotCalled(d.T(), "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_VersionMismatch() {
+	d.mockUserService.On("DeleteUser", mock.Anything, mock.MatchedBy(func(req *dto.DeleteUserRequest) bool {
+		return slices.Equal(req.ExpectedVersions, []int64{7})
+	}), "12345").Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", strings.NewReader(`{"password":"secret"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Request.Header.Set("If-Match", `"7"`)
+
+	d.userHandler.DeleteUser(ctx)
+
+	d.Equal(http.StatusPreconditionFailed, w.Code)
+	d.Contains(w.Body.String(), dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH.Error())
+	d.mockUserService.AssertExpectations(d.T())
+}
+
+func (d *DeleteUserHandlerSuite) TestUserHandler_DeleteUser_IfMatch() {
+	for _, tc := range []struct {
+		name     string
+		header   string
+		versions []int64
+		code     int
+	}{
+		{name: "single", header: `"7"`, versions: []int64{7}, code: http.StatusOK},
+		{name: "list", header: `"5", "7"`, versions: []int64{5, 7}, code: http.StatusOK},
+		{name: "list without spaces", header: `"5","7"`, versions: []int64{5, 7}, code: http.StatusOK},
+		{name: "weak tags skipped", header: `W/"5", "7"`, versions: []int64{7}, code: http.StatusOK},
+		{name: "invalid tags skipped", header: `"abc", "7", "0"`, versions: []int64{7}, code: http.StatusOK},
+		{name: "wildcard", header: `*`, versions: nil, code: http.StatusOK},
+		{name: "wildcard in list", header: `"5", *`, versions: nil, code: http.StatusOK},
+		{name: "only weak tags", header: `W/"7"`, code: http.StatusPreconditionFailed},
+		{name: "only invalid tags", header: `7, "abc"`, code: http.StatusPreconditionFailed},
+	} {
+		d.SetupTest()
+		if tc.code == http.StatusOK {
+			d.mockUserService.On("DeleteUser", mock.Anything, mock.MatchedBy(func(req *dto.DeleteUserRequest) bool {
+				return slices.Equal(req.ExpectedVersions, tc.versions)
+			}), "12345").Return(nil).Once()
+		}
+
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest(http.MethodDelete, "/", strings.NewReader(`{"password":"secret"}`))
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+		ctx.Request.Header.Set("If-Match", tc.header)
+
+		d.userHandler.DeleteUser(ctx)
+
+		d.Equal(tc.code, w.Code, tc.name)
+		d.mockUserService.AssertExpectations(d.T())
+		if tc.code != http.StatusOK {
+			d.mockUserService.AssertNotCalled(d.T(), "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetAvatarHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (c *GetAvatarHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	c.mockUserService = mockedUserService
+	c.mockLogEmitter = mockedLogEmitterService
+	c.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (c *GetAvatarHandlerSuite) SetupTest() {
+	c.mockUserService.ExpectedCalls = nil
+	c.mockLogEmitter.ExpectedCalls = nil
+	c.mockUserService.Calls = nil
+	c.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestGetAvatarHandlerSuite(t *testing.T) {
+	suite.Run(t, &GetAvatarHandlerSuite{})
+}
+
+func (c *GetAvatarHandlerSuite) TestUserHandler_GetAvatar_Redirect() {
+	c.mockUserService.On("GetAvatarURL", mock.Anything, "12345", "64").Return("https://files.example.com/image/img_64.jpg", nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/avatar?size=64", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.GetAvatar(ctx)
+
+	c.Equal(http.StatusFound, w.Code)
+	c.Equal("https://files.example.com/image/img_64.jpg", w.Header().Get("Location"))
+	c.Equal("no-store", w.Header().Get("Cache-Control"))
+}
+
+func (c *GetAvatarHandlerSuite) TestUserHandler_GetAvatar_InvalidSize() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/avatar?size=100", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.GetAvatar(ctx)
+
+	c.Equal(http.StatusBadRequest, w.Code)
+	c.Contains(w.Body.String(), dto.Err_BAD_REQUEST_INVALID_AVATAR_SIZE.Error())
+	c.mockUserService.AssertNotCalled(c.T(), "GetAvatarURL", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (c *GetAvatarHandlerSuite) TestUserHandler_GetAvatar_NotFound() {
+	c.mockUserService.On("GetAvatarURL", mock.Anything, "12345", "").Return("", dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/avatar", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	c.userHandler.GetAvatar(ctx)
+
+	c.Equal(http.StatusNotFound, w.Code)
+	c.Contains(w.Body.String(), dto.Err_NOTFOUND_USER_NOT_FOUND.Error())
+}
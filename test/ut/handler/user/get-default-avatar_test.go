@@ -0,0 +1,73 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetDefaultAvatarHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (c *GetDefaultAvatarHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	c.mockUserService = mockedUserService
+	c.mockLogEmitter = mockedLogEmitterService
+	c.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (c *GetDefaultAvatarHandlerSuite) SetupTest() {
+	c.mockUserService.ExpectedCalls = nil
+	c.mockLogEmitter.ExpectedCalls = nil
+	c.mockUserService.Calls = nil
+	c.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestGetDefaultAvatarHandlerSuite(t *testing.T) {
+	suite.Run(t, &GetDefaultAvatarHandlerSuite{})
+}
+
+func (c *GetDefaultAvatarHandlerSuite) TestUserHandler_GetDefaultAvatar_Success() {
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)
+	c.mockUserService.On("GetDefaultAvatar", mock.Anything, "12345").Return(svg, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/avatar/default/12345", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "12345"}}
+	c.userHandler.GetDefaultAvatar(ctx)
+
+	c.Equal(http.StatusOK, w.Code)
+	c.Equal("image/svg+xml", w.Header().Get("Content-Type"))
+	c.Equal("nosniff", w.Header().Get("X-Content-Type-Options"))
+	c.Equal(svg, w.Body.Bytes())
+}
+
+func (c *GetDefaultAvatarHandlerSuite) TestUserHandler_GetDefaultAvatar_NotFound() {
+	c.mockUserService.On("GetDefaultAvatar", mock.Anything, "12345").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/avatar/default/12345", nil)
+	ctx.Params = gin.Params{{Key: "id", Value: "12345"}}
+	c.userHandler.GetDefaultAvatar(ctx)
+
+	c.Equal(http.StatusNotFound, w.Code)
+	c.Contains(w.Body.String(), dto.Err_NOTFOUND_USER_NOT_FOUND.Error())
+}
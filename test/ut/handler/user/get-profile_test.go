@@ -50,8 +50,9 @@ func (g *GetProfileHandlerSuite) TestUserHandler_GetProfile_Success() {
 		Email:            "test@example.com",
 		Verified:         false,
 		TwoFactorEnabled: false,
+		Version:          6,
 	}
-	g.mockUserService.On("GetProfile", "12345").Return(res, nil)
+	g.mockUserService.On("GetProfile", mock.Anything, "12345").Return(res, nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -63,7 +64,9 @@ func (g *GetProfileHandlerSuite) TestUserHandler_GetProfile_Success() {
 	g.Equal(http.StatusOK, w.Code)
 	g.Contains(w.Body.String(), "200")
 	g.Contains(w.Body.String(), dto.SUCCESS_GET_PROFILE)
-	g.mockUserService.AssertCalled(g.T(), "GetProfile", "12345")
+	g.Equal(`"6"`, w.Header().Get("ETag"))
+	g.NotContains(w.Body.String(), "version")
+	g.mockUserService.AssertCalled(g.T(), "GetProfile", mock.Anything, "12345")
 }
 
 func (g *GetProfileHandlerSuite) TestUserHandler_GetProfile_MissingUserId() {
@@ -83,7 +86,7 @@ func (g *GetProfileHandlerSuite) TestUserHandler_GetProfile_MissingUserId() {
 }
 
 func (g *GetProfileHandlerSuite) TestUserHandler_GetProfile_UserNotFound() {
-	g.mockUserService.On("GetProfile", "12345").Return(dto.GetProfileResponse{}, dto.Err_NOTFOUND_USER_NOT_FOUND)
+	g.mockUserService.On("GetProfile", mock.Anything, "12345").Return(dto.GetProfileResponse{}, dto.Err_NOTFOUND_USER_NOT_FOUND)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -95,5 +98,5 @@ func (g *GetProfileHandlerSuite) TestUserHandler_GetProfile_UserNotFound() {
 	g.Equal(http.StatusNotFound, w.Code)
 	g.Contains(w.Body.String(), "404")
 	g.Contains(w.Body.String(), dto.Err_NOTFOUND_USER_NOT_FOUND.Error())
-	g.mockUserService.AssertCalled(g.T(), "GetProfile", "12345")
+	g.mockUserService.AssertCalled(g.T(), "GetProfile", mock.Anything, "12345")
 }
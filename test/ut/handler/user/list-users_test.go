@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListUsersHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (l *ListUsersHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitter := new(mocks.LoggerInfraMock)
+	l.mockUserService = mockedUserService
+	l.mockLogEmitter = mockedLogEmitter
+	l.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitter, logger)
+}
+
+func (l *ListUsersHandlerSuite) SetupTest() {
+	l.mockUserService.ExpectedCalls = nil
+	l.mockLogEmitter.ExpectedCalls = nil
+
+	l.mockUserService.Calls = nil
+	l.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestListUsersHandlerSuite(t *testing.T) {
+	suite.Run(t, &ListUsersHandlerSuite{})
+}
+
+func (l *ListUsersHandlerSuite) TestUserHandler_ListUsers_Success() {
+	next := "next-cursor"
+	l.mockUserService.On("ListUsers", mock.Anything, mock.MatchedBy(func(req *dto.ListUsersRequest) bool {
+		return req.Limit == 10 && req.Verified != nil && *req.Verified && req.SortBy == "email"
+	})).Return(dto.ListUsersResponse{Users: []dto.UserListItem{{ID: "user-1"}}, NextCursor: &next}, nil)
+
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.GET("/admin/users", handler.RequireAdmin, l.userHandler.ListUsers)
+	req, _ := http.NewRequest(http.MethodGet, "/admin/users?limit=10&verified=true&sort_by=email", nil)
+	req.Header.Set("User-Data", `{"user_id":"admin-1","role":"admin"}`)
+
+	router.ServeHTTP(w, req)
+
+	l.Equal(http.StatusOK, w.Code)
+	l.Contains(w.Body.String(), dto.SUCCESS_LIST_USERS)
+	l.Contains(w.Body.String(), next)
+}
+
+func (l *ListUsersHandlerSuite) TestUserHandler_ListUsers_InvalidInput() {
+	l.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/admin/users?limit=1000", nil)
+
+	l.userHandler.ListUsers(ctx)
+
+	l.Equal(http.StatusBadRequest, w.Code)
+	l.Contains(w.Body.String(), "invalid input")
+
+	time.Sleep(time.Second)
+	l.mockLogEmitter.AssertExpectations(l.T())
+}
+
+func (l *ListUsersHandlerSuite) TestUserHandler_ListUsers_InvalidCursor() {
+	l.mockUserService.On("ListUsers", mock.Anything, mock.Anything).Return(dto.ListUsersResponse{}, dto.Err_BAD_REQUEST_INVALID_CURSOR)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/admin/users?cursor=bogus", nil)
+
+	l.userHandler.ListUsers(ctx)
+
+	l.Equal(http.StatusBadRequest, w.Code)
+	l.Contains(w.Body.String(), dto.Err_BAD_REQUEST_INVALID_CURSOR.Error())
+}
+
+func (l *ListUsersHandlerSuite) TestUserHandler_ListUsers_InternalError() {
+	l.mockUserService.On("ListUsers", mock.Anything, mock.Anything).Return(dto.ListUsersResponse{}, dto.Err_INTERNAL_FAILED_LIST_USERS)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/admin/users", nil)
+
+	l.userHandler.ListUsers(ctx)
+
+	l.Equal(http.StatusInternalServerError, w.Code)
+}
+
+func (l *ListUsersHandlerSuite) TestUserHandler_ListUsers_NotAdmin() {
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.GET("/admin/users", handler.RequireAdmin, l.userHandler.ListUsers)
+	req, _ := http.NewRequest(http.MethodGet, "/admin/users", nil)
+	req.Header.Set("User-Data", `{"user_id":"12345","role":"user"}`)
+
+	router.ServeHTTP(w, req)
+
+	l.Equal(http.StatusForbidden, w.Code)
+	l.mockUserService.AssertNotCalled(l.T(), "ListUsers", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,142 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasskeyHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (p *PasskeyHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	p.mockUserService = mockedUserService
+	p.mockLogEmitter = mockedLogEmitterService
+	p.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (p *PasskeyHandlerSuite) SetupTest() {
+	p.mockUserService.ExpectedCalls = nil
+	p.mockLogEmitter.ExpectedCalls = nil
+	p.mockUserService.Calls = nil
+	p.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestPasskeyHandlerSuite(t *testing.T) {
+	suite.Run(t, &PasskeyHandlerSuite{})
+}
+
+func (p *PasskeyHandlerSuite) newContext(method, path, body string, params ...gin.Param) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(method, path, strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	ctx.Params = params
+	return ctx, w
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_BeginPasskeyRegistration_Success() {
+	ctx, w := p.newContext(http.MethodPost, "/passkeys/register/begin", "")
+	p.mockUserService.On("BeginPasskeyRegistration", mock.Anything, "12345").Return(&protocol.CredentialCreation{
+		Response: protocol.PublicKeyCredentialCreationOptions{
+			RelyingParty: protocol.RelyingPartyEntity{ID: "localhost"},
+			Challenge:    protocol.URLEncodedBase64("challenge"),
+		},
+	}, nil)
+
+	p.userHandler.BeginPasskeyRegistration(ctx)
+
+	p.Equal(http.StatusOK, w.Code)
+	p.Equal("no-store", w.Header().Get("Cache-Control"))
+	p.Contains(w.Body.String(), `"publicKey":{"rp":{"name":"","id":"localhost"}`)
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_FinishPasskeyRegistration_Success() {
+	ctx, w := p.newContext(http.MethodPost, "/passkeys/register/finish", `{"name":"Laptop","credential":{"id":"abc"}}`)
+	p.mockUserService.On("FinishPasskeyRegistration", mock.Anything, mock.MatchedBy(func(req *dto.FinishPasskeyRegistrationRequest) bool {
+		return req.Name == "Laptop" && string(req.Credential) == `{"id":"abc"}`
+	}), "12345").Return(dto.PasskeyResponse{ID: 7, Name: "Laptop"}, nil)
+
+	p.userHandler.FinishPasskeyRegistration(ctx)
+
+	p.Equal(http.StatusOK, w.Code)
+	p.Contains(w.Body.String(), `"id":7`)
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_FinishPasskeyRegistration_Errors() {
+	for err, code := range map[error]int{
+		dto.Err_BAD_REQUEST_INVALID_PASSKEY:  http.StatusBadRequest,
+		dto.Err_NOTFOUND_PASSKEY_SESSION:     http.StatusNotFound,
+		dto.Err_CONFLICT_PASSKEY_EXIST:       http.StatusConflict,
+		dto.Err_INTERNAL_FAILED_SAVE_PASSKEY: http.StatusInternalServerError,
+	} {
+		p.SetupTest()
+		ctx, w := p.newContext(http.MethodPost, "/passkeys/register/finish", `{"name":"Laptop","credential":{"id":"abc"}}`)
+		p.mockUserService.On("FinishPasskeyRegistration", mock.Anything, mock.Anything, "12345").Return(dto.PasskeyResponse{}, err)
+
+		p.userHandler.FinishPasskeyRegistration(ctx)
+
+		p.Equal(code, w.Code, err.Error())
+	}
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_ListPasskeys_Success() {
+	ctx, w := p.newContext(http.MethodGet, "/passkeys", "")
+	p.mockUserService.On("ListPasskeys", mock.Anything, "12345").Return([]dto.PasskeyResponse{{ID: 1, Name: "Laptop"}}, nil)
+
+	p.userHandler.ListPasskeys(ctx)
+
+	p.Equal(http.StatusOK, w.Code)
+	p.Contains(w.Body.String(), `"name":"Laptop"`)
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_RenamePasskey_Success() {
+	ctx, w := p.newContext(http.MethodPatch, "/passkeys/1", `{"name":"YubiKey"}`, gin.Param{Key: "id", Value: "1"})
+	p.mockUserService.On("RenamePasskey", mock.Anything, &dto.RenamePasskeyRequest{Name: "YubiKey"}, int64(1), "12345").Return(nil)
+
+	p.userHandler.RenamePasskey(ctx)
+
+	p.Equal(http.StatusOK, w.Code)
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_RenamePasskey_InvalidId() {
+	ctx, w := p.newContext(http.MethodPatch, "/passkeys/abc", `{"name":"YubiKey"}`, gin.Param{Key: "id", Value: "abc"})
+	p.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	p.userHandler.RenamePasskey(ctx)
+	time.Sleep(time.Second)
+
+	p.Equal(http.StatusBadRequest, w.Code)
+	p.mockLogEmitter.AssertExpectations(p.T())
+	p.mockUserService.AssertNotCalled(p.T(), "RenamePasskey", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (p *PasskeyHandlerSuite) TestUserHandler_DeletePasskey_NotFound() {
+	ctx, w := p.newContext(http.MethodDelete, "/passkeys/1", "", gin.Param{Key: "id", Value: "1"})
+	p.mockUserService.On("DeletePasskey", mock.Anything, int64(1), "12345").Return(dto.Err_NOTFOUND_PASSKEY)
+
+	p.userHandler.DeletePasskey(ctx)
+
+	p.Equal(http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,108 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecoveryCodesHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (r *RecoveryCodesHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	r.mockUserService = mockedUserService
+	r.mockLogEmitter = mockedLogEmitterService
+	r.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (r *RecoveryCodesHandlerSuite) SetupTest() {
+	r.mockUserService.ExpectedCalls = nil
+	r.mockLogEmitter.ExpectedCalls = nil
+	r.mockUserService.Calls = nil
+	r.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestRecoveryCodesHandlerSuite(t *testing.T) {
+	suite.Run(t, &RecoveryCodesHandlerSuite{})
+}
+
+func (r *RecoveryCodesHandlerSuite) newContext(method, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(method, "/2fa/recovery-codes", strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	return ctx, w
+}
+
+func (r *RecoveryCodesHandlerSuite) TestUserHandler_RegenerateRecoveryCodes_Success() {
+	ctx, w := r.newContext(http.MethodPost, `{"code":"123456"}`)
+	r.mockUserService.On("RegenerateRecoveryCodes", mock.Anything, &dto.RegenerateRecoveryCodesRequest{Code: "123456"}, "12345").Return(dto.RecoveryCodesResponse{
+		RecoveryCodes: []string{"k7m2p-x9q4r"},
+	}, nil)
+
+	r.userHandler.RegenerateRecoveryCodes(ctx)
+
+	r.Equal(http.StatusOK, w.Code)
+	r.Equal("no-store", w.Header().Get("Cache-Control"))
+	r.Contains(w.Body.String(), `"recovery_codes":["k7m2p-x9q4r"]`)
+}
+
+func (r *RecoveryCodesHandlerSuite) TestUserHandler_RegenerateRecoveryCodes_MissingCodeAndPassword() {
+	ctx, w := r.newContext(http.MethodPost, `{}`)
+	r.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	r.userHandler.RegenerateRecoveryCodes(ctx)
+
+	r.Equal(http.StatusBadRequest, w.Code)
+	r.mockUserService.AssertNotCalled(r.T(), "RegenerateRecoveryCodes", mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	r.mockLogEmitter.AssertExpectations(r.T())
+}
+
+func (r *RecoveryCodesHandlerSuite) TestUserHandler_RegenerateRecoveryCodes_Errors() {
+	for err, code := range map[error]int{
+		dto.Err_UNAUTHORIZED_PASSWORD_WRONG:     http.StatusUnauthorized,
+		dto.Err_CONFLICT_TWO_FACTOR_DISABLED:    http.StatusConflict,
+		dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS: http.StatusTooManyRequests,
+		dto.Err_INTERNAL_FAILED_SAVE_RECOVERY:   http.StatusInternalServerError,
+	} {
+		r.SetupTest()
+		ctx, w := r.newContext(http.MethodPost, `{"password":"password123"}`)
+		r.mockUserService.On("RegenerateRecoveryCodes", mock.Anything, mock.Anything, "12345").Return(dto.RecoveryCodesResponse{}, err)
+
+		r.userHandler.RegenerateRecoveryCodes(ctx)
+
+		r.Equal(code, w.Code, err.Error())
+	}
+}
+
+func (r *RecoveryCodesHandlerSuite) TestUserHandler_CountRecoveryCodes_Success() {
+	ctx, w := r.newContext(http.MethodGet, "")
+	r.mockUserService.On("CountRecoveryCodes", mock.Anything, "12345").Return(dto.RecoveryCodesCountResponse{Remaining: 8}, nil)
+
+	r.userHandler.CountRecoveryCodes(ctx)
+
+	r.Equal(http.StatusOK, w.Code)
+	r.Contains(w.Body.String(), `"remaining":8`)
+}
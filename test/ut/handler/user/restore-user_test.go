@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RestoreUserHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (r *RestoreUserHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitter := new(mocks.LoggerInfraMock)
+	r.mockUserService = mockedUserService
+	r.mockLogEmitter = mockedLogEmitter
+	r.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitter, logger)
+}
+
+func (r *RestoreUserHandlerSuite) SetupTest() {
+	r.mockUserService.ExpectedCalls = nil
+	r.mockLogEmitter.ExpectedCalls = nil
+
+	r.mockUserService.Calls = nil
+	r.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestRestoreUserHandlerSuite(t *testing.T) {
+	suite.Run(t, &RestoreUserHandlerSuite{})
+}
+
+func (r *RestoreUserHandlerSuite) TestUserHandler_RestoreUser_Success() {
+	r.mockUserService.On("RestoreUser", mock.Anything, "12345").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/restore", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	r.userHandler.RestoreUser(ctx)
+
+	r.Equal(http.StatusOK, w.Code)
+	r.Contains(w.Body.String(), dto.SUCCESS_RESTORE_USER)
+}
+
+func (r *RestoreUserHandlerSuite) TestUserHandler_RestoreUser_MissingUserId() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/restore", nil)
+	r.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	r.userHandler.RestoreUser(ctx)
+
+	r.Equal(http.StatusUnauthorized, w.Code)
+	r.Contains(w.Body.String(), dto.Err_UNAUTHORIZED_USER_ID_NOTFOUND.Error())
+
+	time.Sleep(time.Second)
+	r.mockLogEmitter.AssertExpectations(r.T())
+}
+
+func (r *RestoreUserHandlerSuite) TestUserHandler_RestoreUser_WindowExpired() {
+	r.mockUserService.On("RestoreUser", mock.Anything, "12345").Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/restore", nil)
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	r.userHandler.RestoreUser(ctx)
+
+	r.Equal(http.StatusNotFound, w.Code)
+	r.Contains(w.Body.String(), dto.Err_NOTFOUND_USER_NOT_FOUND.Error())
+}
+
+func (r *RestoreUserHandlerSuite) TestUserHandler_AdminRestoreUser_Success() {
+	r.mockUserService.On("RestoreUser", mock.Anything, "target-user").Return(nil)
+
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.POST("/admin/users/:id/restore", handler.RequireAdmin, r.userHandler.AdminRestoreUser)
+	req, _ := http.NewRequest(http.MethodPost, "/admin/users/target-user/restore", nil)
+	req.Header.Set("User-Data", `{"user_id":"admin-1","role":"admin"}`)
+
+	router.ServeHTTP(w, req)
+
+	r.Equal(http.StatusOK, w.Code)
+	r.Contains(w.Body.String(), dto.SUCCESS_RESTORE_USER)
+}
+
+func (r *RestoreUserHandlerSuite) TestUserHandler_AdminRestoreUser_NotAdmin() {
+	w := httptest.NewRecorder()
+	router := gin.New()
+	router.POST("/admin/users/:id/restore", handler.RequireAdmin, r.userHandler.AdminRestoreUser)
+	req, _ := http.NewRequest(http.MethodPost, "/admin/users/target-user/restore", nil)
+	req.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	router.ServeHTTP(w, req)
+
+	r.Equal(http.StatusForbidden, w.Code)
+	r.Contains(w.Body.String(), dto.Err_FORBIDDEN_ADMIN_ONLY.Error())
+	r.mockUserService.AssertNotCalled(r.T(), "RestoreUser", mock.Anything, mock.Anything)
+}
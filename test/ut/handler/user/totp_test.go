@@ -0,0 +1,144 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (t *TOTPHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	t.mockUserService = mockedUserService
+	t.mockLogEmitter = mockedLogEmitterService
+	t.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (t *TOTPHandlerSuite) SetupTest() {
+	t.mockUserService.ExpectedCalls = nil
+	t.mockLogEmitter.ExpectedCalls = nil
+	t.mockUserService.Calls = nil
+	t.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestTOTPHandlerSuite(t *testing.T) {
+	suite.Run(t, &TOTPHandlerSuite{})
+}
+
+func (t *TOTPHandlerSuite) newContext(path, body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	return ctx, w
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_SetupTOTP_Success() {
+	ctx, w := t.newContext("/2fa/totp/setup", "")
+	t.mockUserService.On("SetupTOTP", mock.Anything, "12345").Return(dto.SetupTOTPResponse{
+		Secret:     "JBSWY3DPEHPK3PXP",
+		OtpauthURI: "otpauth://totp/dropboks:john@example.com?secret=JBSWY3DPEHPK3PXP",
+		QRCode:     "iVBORw0KGgo=",
+	}, nil)
+
+	t.userHandler.SetupTOTP(ctx)
+
+	t.Equal(http.StatusOK, w.Code)
+	t.Equal("no-store", w.Header().Get("Cache-Control"))
+	t.Contains(w.Body.String(), `"otpauth_uri":"otpauth://totp/`)
+	t.Contains(w.Body.String(), `"qr_code":"iVBORw0KGgo="`)
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_SetupTOTP_AlreadyEnabled() {
+	ctx, w := t.newContext("/2fa/totp/setup", "")
+	t.mockUserService.On("SetupTOTP", mock.Anything, "12345").Return(dto.SetupTOTPResponse{}, dto.Err_CONFLICT_TWO_FACTOR_ENABLED)
+
+	t.userHandler.SetupTOTP(ctx)
+
+	t.Equal(http.StatusConflict, w.Code)
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_EnableTOTP_Success() {
+	ctx, w := t.newContext("/2fa/totp/enable", `{"code":"123456"}`)
+	t.mockUserService.On("EnableTOTP", mock.Anything, &dto.EnableTOTPRequest{Code: "123456"}, "12345").Return(dto.RecoveryCodesResponse{
+		RecoveryCodes: []string{"k7m2p-x9q4r", "b3n8t-w6z2h"},
+	}, nil)
+
+	t.userHandler.EnableTOTP(ctx)
+
+	t.Equal(http.StatusOK, w.Code)
+	t.Equal("no-store", w.Header().Get("Cache-Control"))
+	t.Contains(w.Body.String(), dto.SUCCESS_ENABLE_TOTP)
+	t.Contains(w.Body.String(), `"recovery_codes":["k7m2p-x9q4r","b3n8t-w6z2h"]`)
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_EnableTOTP_Errors() {
+	for err, code := range map[error]int{
+		dto.Err_UNAUTHORIZED_OTP_INVALID:        http.StatusUnauthorized,
+		dto.Err_NOTFOUND_TOTP_ENROLLMENT:        http.StatusNotFound,
+		dto.Err_CONFLICT_TWO_FACTOR_ENABLED:     http.StatusConflict,
+		dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS: http.StatusTooManyRequests,
+		dto.Err_INTERNAL_TOTP_SECRET:            http.StatusInternalServerError,
+	} {
+		t.SetupTest()
+		ctx, w := t.newContext("/2fa/totp/enable", `{"code":"123456"}`)
+		t.mockUserService.On("EnableTOTP", mock.Anything, mock.Anything, "12345").Return(dto.RecoveryCodesResponse{}, err)
+
+		t.userHandler.EnableTOTP(ctx)
+
+		t.Equal(code, w.Code, err.Error())
+	}
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_DisableTOTP_WithPassword() {
+	ctx, w := t.newContext("/2fa/totp/disable", `{"password":"password123"}`)
+	t.mockUserService.On("DisableTOTP", mock.Anything, &dto.DisableTOTPRequest{Password: "password123"}, "12345").Return(nil)
+
+	t.userHandler.DisableTOTP(ctx)
+
+	t.Equal(http.StatusOK, w.Code)
+	t.Contains(w.Body.String(), dto.SUCCESS_DISABLE_TOTP)
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_DisableTOTP_MissingCodeAndPassword() {
+	ctx, w := t.newContext("/2fa/totp/disable", `{}`)
+	t.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	t.userHandler.DisableTOTP(ctx)
+
+	t.Equal(http.StatusBadRequest, w.Code)
+	t.mockUserService.AssertNotCalled(t.T(), "DisableTOTP", mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	t.mockLogEmitter.AssertExpectations(t.T())
+}
+
+func (t *TOTPHandlerSuite) TestUserHandler_DisableTOTP_WrongPassword() {
+	ctx, w := t.newContext("/2fa/totp/disable", `{"password":"wrong"}`)
+	t.mockUserService.On("DisableTOTP", mock.Anything, mock.Anything, "12345").Return(dto.Err_UNAUTHORIZED_PASSWORD_WRONG)
+
+	t.userHandler.DisableTOTP(ctx)
+
+	t.Equal(http.StatusUnauthorized, w.Code)
+}
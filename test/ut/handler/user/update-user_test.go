@@ -2,8 +2,12 @@ package handler_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"log"
+	"maps"
 	"mime/multipart"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -60,16 +64,19 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_Success() {
 	request := httptest.NewRequest(http.MethodPatch, "/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 	ctx.Request = request
 
-	u.mockUserService.On("UpdateUser", mock.Anything, "12345").Return(nil)
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.Anything, "12345").Return(dto.GetProfileResponse{FullName: "test-full-name", Version: 2}, nil)
 	u.userHandler.UpdateUser(ctx)
 
 	u.Equal(http.StatusOK, w.Code)
+	u.Equal(`"2"`, w.Header().Get("ETag"))
 	u.Contains(w.Body.String(), "success update profile data")
+	u.Contains(w.Body.String(), `"full_name":"test-full-name"`)
 
 	u.mockUserService.AssertExpectations(u.T())
 }
@@ -93,11 +100,29 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MissingUserId() {
 }
 
 func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_InvalidInput() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader("--broken\r\n"))
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	request.Header.Set("Content-Type", "multipart/form-data; boundary=missing")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+	ctx.Request = request
+	u.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusBadRequest, w.Code)
+	u.Contains(w.Body.String(), "invalid input")
 
+	time.Sleep(time.Second)
+	u.mockLogEmitter.AssertExpectations(u.T())
+}
+
+// two-factor authentication is only enabled through the totp enrollment
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MultipartTwoFactorEnabled() {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	_ = writer.WriteField("two_factor_enabled", "true")
-
 	if err := writer.Close(); err != nil {
 		log.Fatal("failed to close form writer")
 	}
@@ -107,16 +132,14 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_InvalidInput() {
 	ctx, _ := gin.CreateTestContext(w)
 	request.Header.Set("Content-Type", writer.FormDataContentType())
 	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
 	ctx.Request = request
-	u.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
 	u.userHandler.UpdateUser(ctx)
 
 	u.Equal(http.StatusBadRequest, w.Code)
-	u.Contains(w.Body.String(), "invalid input")
-
-	time.Sleep(time.Second)
-	u.mockLogEmitter.AssertExpectations(u.T())
+	u.Contains(w.Body.String(), dto.TwoFactorEnabledReadOnly)
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
 }
 
 func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_UserNotFound() {
@@ -131,12 +154,13 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_UserNotFound() {
 	request := httptest.NewRequest(http.MethodPatch, "/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 	ctx.Request = request
 
-	u.mockUserService.On("UpdateUser", mock.Anything, "12345").Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.Anything, "12345").Return(dto.GetProfileResponse{}, dto.Err_NOTFOUND_USER_NOT_FOUND)
 	u.userHandler.UpdateUser(ctx)
 
 	u.Equal(http.StatusNotFound, w.Code)
@@ -165,12 +189,13 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_ImageWrongExtension(
 	request := httptest.NewRequest(http.MethodPatch, "/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 	ctx.Request = request
 
-	u.mockUserService.On("UpdateUser", mock.Anything, "12345").Return(dto.Err_BAD_REQUEST_WRONG_EXTENSION)
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.Anything, "12345").Return(dto.GetProfileResponse{}, dto.Err_BAD_REQUEST_WRONG_EXTENSION)
 	u.userHandler.UpdateUser(ctx)
 
 	u.Equal(http.StatusBadRequest, w.Code)
@@ -196,12 +221,13 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_ImageLimitSizeExceed
 	request := httptest.NewRequest(http.MethodPatch, "/", reqBody)
 	request.Header.Set("Content-Type", formWriter.FormDataContentType())
 	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
 	ctx.Request = request
 
-	u.mockUserService.On("UpdateUser", mock.Anything, "12345").Return(dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED)
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.Anything, "12345").Return(dto.GetProfileResponse{}, dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED)
 	u.userHandler.UpdateUser(ctx)
 
 	u.Equal(http.StatusBadRequest, w.Code)
@@ -209,3 +235,263 @@ func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_ImageLimitSizeExceed
 
 	u.mockUserService.AssertExpectations(u.T())
 }
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MergePatch() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"full_name":"Jane Doe"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.MatchedBy(func(req *dto.UpdateUserRequest) bool {
+		return req.FullName != nil && *req.FullName == "Jane Doe" && !req.Username.Set && !req.RemoveImage
+	}), "12345").Return(dto.GetProfileResponse{FullName: "Jane Doe", TwoFactorEnabled: true}, nil)
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusOK, w.Code)
+	u.Contains(w.Body.String(), `"two_factor_enabled":true`)
+	u.mockUserService.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MergePatchRemoveImage() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"image":null}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.MatchedBy(func(req *dto.UpdateUserRequest) bool {
+		return req.RemoveImage && req.FullName == nil
+	}), "12345").Return(dto.GetProfileResponse{DefaultImage: true}, nil)
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusOK, w.Code)
+	u.mockUserService.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MergePatchInvalidFields() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"full_name":null,"two_factor_enabled":"yes","email":"x@example.com"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusBadRequest, w.Code)
+	var res dto.InvalidFieldsResponse
+	u.NoError(json.Unmarshal(w.Body.Bytes(), &res))
+	u.Equal(dto.FieldErrors{
+		"full_name":          "cannot be removed",
+		"two_factor_enabled": dto.TwoFactorEnabledReadOnly,
+		"email":              "unknown field",
+	}, res.Errors)
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MultipartBlankName() {
+	reqBody := &bytes.Buffer{}
+	formWriter := multipart.NewWriter(reqBody)
+	_ = formWriter.WriteField("full_name", "   ")
+	if err := formWriter.Close(); err != nil {
+		log.Fatal("failed to close form writer")
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "/", reqBody)
+	request.Header.Set("Content-Type", formWriter.FormDataContentType())
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusBadRequest, w.Code)
+	u.Contains(w.Body.String(), `"full_name":"must not be empty"`)
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_UnsupportedMediaType() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader("full_name=x"))
+	request.Header.Set("Content-Type", "text/plain")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+	u.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusUnsupportedMediaType, w.Code)
+
+	time.Sleep(time.Second)
+	u.mockLogEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MissingIfMatch() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"full_name":"Jane Doe"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusPreconditionRequired, w.Code)
+	u.Contains(w.Body.String(), dto.Err_PRECONDITION_REQUIRED_IF_MATCH.Error())
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_WeakETagRejected() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"full_name":"Jane Doe"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `W/"3"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusPreconditionFailed, w.Code)
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_VersionMismatch() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"full_name":"Jane Doe"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"3"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.MatchedBy(func(req *dto.UpdateUserRequest) bool {
+		return slices.Equal(req.ExpectedVersions, []int64{3})
+	}), "12345").Return(dto.GetProfileResponse{}, dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusPreconditionFailed, w.Code)
+	u.mockUserService.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MergePatchProfileFields() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"username":"John.Doe","bio":null,"locale":"en_us","phone_number":null}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	// values arrive canonicalized, a null clears the field and absent fields stay unset
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.MatchedBy(func(req *dto.UpdateUserRequest) bool {
+		return *req.Username.Value == "John.Doe" &&
+			req.Bio.Set && req.Bio.Value == nil &&
+			*req.Locale.Value == "en-US" &&
+			req.PhoneNumber.Set && req.PhoneNumber.Value == nil &&
+			!req.Timezone.Set && !req.DateOfBirth.Set
+	}), "12345").Return(dto.GetProfileResponse{}, nil)
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusOK, w.Code)
+	u.mockUserService.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MergePatchInvalidProfileFields() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"username":"a..b","locale":"not a locale!","timezone":"GMT+7","phone_number":"0812","date_of_birth":"2999-01-01","bio":1}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusBadRequest, w.Code)
+	var res dto.InvalidFieldsResponse
+	u.NoError(json.Unmarshal(w.Body.Bytes(), &res))
+	u.ElementsMatch([]string{"username", "locale", "timezone", "phone_number", "date_of_birth", "bio"}, slices.Collect(maps.Keys(res.Errors)))
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_MultipartClearsProfileField() {
+	reqBody := &bytes.Buffer{}
+	formWriter := multipart.NewWriter(reqBody)
+	_ = formWriter.WriteField("timezone", "")
+	_ = formWriter.WriteField("date_of_birth", "1990-01-31")
+	if err := formWriter.Close(); err != nil {
+		log.Fatal("failed to close form writer")
+	}
+
+	request := httptest.NewRequest(http.MethodPatch, "/", reqBody)
+	request.Header.Set("Content-Type", formWriter.FormDataContentType())
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.MatchedBy(func(req *dto.UpdateUserRequest) bool {
+		return req.Timezone.Set && req.Timezone.Value == nil && *req.DateOfBirth.Value == "1990-01-31" && !req.Username.Set
+	}), "12345").Return(dto.GetProfileResponse{}, nil)
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusOK, w.Code)
+	u.mockUserService.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_UsernameTaken() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"username":"taken"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.mockUserService.On("UpdateUser", mock.Anything, mock.Anything, "12345").Return(dto.GetProfileResponse{}, dto.Err_CONFLICT_USERNAME_EXIST)
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusConflict, w.Code)
+	u.Contains(w.Body.String(), dto.Err_CONFLICT_USERNAME_EXIST.Error())
+}
+
+func (u *UpdateUserHandlerSuite) TestUserHandler_UpdateUser_PhoneNumberNeedsVerification() {
+	request := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"phone_number":"+6281234567890"}`))
+	request.Header.Set("Content-Type", "application/merge-patch+json")
+	request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	request.Header.Set("If-Match", `"1"`)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = request
+
+	u.userHandler.UpdateUser(ctx)
+
+	u.Equal(http.StatusBadRequest, w.Code)
+	u.Contains(w.Body.String(), `"phone_number":"only removal is accepted`)
+	u.mockUserService.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything)
+}
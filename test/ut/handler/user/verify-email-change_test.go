@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifyEmailChangeHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (v *VerifyEmailChangeHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitter := new(mocks.LoggerInfraMock)
+	v.mockUserService = mockedUserService
+	v.mockLogEmitter = mockedLogEmitter
+	v.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitter, logger)
+}
+
+func (v *VerifyEmailChangeHandlerSuite) SetupTest() {
+	v.mockUserService.ExpectedCalls = nil
+	v.mockLogEmitter.ExpectedCalls = nil
+
+	v.mockUserService.Calls = nil
+	v.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestVerifyEmailChangeHandlerSuite(t *testing.T) {
+	suite.Run(t, &VerifyEmailChangeHandlerSuite{})
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestUserHandler_VerifyEmailChange_Success() {
+	reqBody := `{"token":"valid-token"}`
+	v.mockUserService.On("VerifyEmailChange", mock.Anything, &dto.VerifyEmailChangeRequest{Token: "valid-token"}, "12345").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/email/verify", strings.NewReader(reqBody))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	v.userHandler.VerifyEmailChange(ctx)
+
+	v.Equal(http.StatusOK, w.Code)
+	v.Contains(w.Body.String(), dto.SUCCESS_VERIFY_EMAIL)
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestUserHandler_VerifyEmailChange_InvalidToken() {
+	reqBody := `{"token":"wrong-token"}`
+	v.mockUserService.On("VerifyEmailChange", mock.Anything, mock.AnythingOfType("*dto.VerifyEmailChangeRequest"), "12345").Return(dto.Err_UNAUTHORIZED_TOKEN_INVALID)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/email/verify", strings.NewReader(reqBody))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	v.userHandler.VerifyEmailChange(ctx)
+
+	v.Equal(http.StatusUnauthorized, w.Code)
+	v.Contains(w.Body.String(), dto.Err_UNAUTHORIZED_TOKEN_INVALID.Error())
+}
+
+func (v *VerifyEmailChangeHandlerSuite) TestUserHandler_VerifyEmailChange_EmailTaken() {
+	reqBody := `{"token":"valid-token"}`
+	v.mockUserService.On("VerifyEmailChange", mock.Anything, mock.AnythingOfType("*dto.VerifyEmailChangeRequest"), "12345").Return(dto.Err_CONFLICT_EMAIL_EXIST)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodPost, "/email/verify", strings.NewReader(reqBody))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+
+	v.userHandler.VerifyEmailChange(ctx)
+
+	v.Equal(http.StatusConflict, w.Code)
+	v.Contains(w.Body.String(), dto.Err_CONFLICT_EMAIL_EXIST.Error())
+}
@@ -0,0 +1,105 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/handler"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifyPhoneNumberHandlerSuite struct {
+	suite.Suite
+	userHandler     handler.UserHandler
+	mockUserService *mocks.UserServiceMock
+	mockLogEmitter  *mocks.LoggerInfraMock
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) SetupSuite() {
+	logger := zerolog.Nop()
+	mockedUserService := new(mocks.UserServiceMock)
+	mockedLogEmitterService := new(mocks.LoggerInfraMock)
+	v.mockUserService = mockedUserService
+	v.mockLogEmitter = mockedLogEmitterService
+	v.userHandler = handler.NewUserHandler(mockedUserService, mockedLogEmitterService, logger)
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) SetupTest() {
+	v.mockUserService.ExpectedCalls = nil
+	v.mockLogEmitter.ExpectedCalls = nil
+	v.mockUserService.Calls = nil
+	v.mockLogEmitter.Calls = nil
+	gin.SetMode(gin.TestMode)
+}
+
+func TestVerifyPhoneNumberHandlerSuite(t *testing.T) {
+	suite.Run(t, &VerifyPhoneNumberHandlerSuite{})
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) newContext(body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/phone/verify", strings.NewReader(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.Header.Set("User-Data", `{"user_id":"12345"}`)
+	return ctx, w
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) TestUserHandler_VerifyPhoneNumber_Success() {
+	ctx, w := v.newContext(`{"code":"123456"}`)
+
+	v.mockUserService.On("VerifyPhoneNumber", mock.Anything, &dto.VerifyPhoneNumberRequest{Code: "123456"}, "12345").Return(nil)
+	v.userHandler.VerifyPhoneNumber(ctx)
+
+	v.Equal(http.StatusOK, w.Code)
+	v.Contains(w.Body.String(), dto.SUCCESS_VERIFY_PHONE)
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) TestUserHandler_VerifyPhoneNumber_MalformedCode() {
+	ctx, w := v.newContext(`{"code":"12a456"}`)
+	v.mockLogEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	v.userHandler.VerifyPhoneNumber(ctx)
+
+	v.Equal(http.StatusBadRequest, w.Code)
+	v.mockUserService.AssertNotCalled(v.T(), "VerifyPhoneNumber", mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	v.mockLogEmitter.AssertExpectations(v.T())
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) TestUserHandler_VerifyPhoneNumber_InvalidCode() {
+	ctx, w := v.newContext(`{"code":"654321"}`)
+
+	v.mockUserService.On("VerifyPhoneNumber", mock.Anything, mock.Anything, "12345").Return(dto.Err_UNAUTHORIZED_OTP_INVALID)
+	v.userHandler.VerifyPhoneNumber(ctx)
+
+	v.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) TestUserHandler_VerifyPhoneNumber_TooManyAttempts() {
+	ctx, w := v.newContext(`{"code":"654321"}`)
+
+	v.mockUserService.On("VerifyPhoneNumber", mock.Anything, mock.Anything, "12345").Return(dto.Err_TOO_MANY_REQUESTS_OTP_ATTEMPTS)
+	v.userHandler.VerifyPhoneNumber(ctx)
+
+	v.Equal(http.StatusTooManyRequests, w.Code)
+}
+
+func (v *VerifyPhoneNumberHandlerSuite) TestUserHandler_VerifyPhoneNumber_ConcurrentUpdate() {
+	ctx, w := v.newContext(`{"code":"123456"}`)
+
+	v.mockUserService.On("VerifyPhoneNumber", mock.Anything, mock.Anything, "12345").Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+	v.userHandler.VerifyPhoneNumber(ctx)
+
+	v.Equal(http.StatusConflict, w.Code)
+}
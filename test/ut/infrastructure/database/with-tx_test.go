@@ -0,0 +1,111 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_db "github.com/micros-template/user-service/internal/infrastructure/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type WithTxSuite struct {
+	suite.Suite
+	txManager _db.TxManager
+	mockPgx   pgxmock.PgxPoolIface
+}
+
+func (w *WithTxSuite) SetupTest() {
+	pgxMock, err := pgxmock.NewPool()
+	w.NoError(err)
+	w.mockPgx = pgxMock
+	w.txManager = _db.NewTxManager(pgxMock)
+}
+
+func (w *WithTxSuite) TearDownTest() {
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+}
+
+func TestWithTxSuite(t *testing.T) {
+	suite.Run(t, &WithTxSuite{})
+}
+
+func (w *WithTxSuite) TestWithTx_Commit() {
+	w.mockPgx.ExpectBegin()
+	w.mockPgx.ExpectExec("DELETE FROM users").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	w.mockPgx.ExpectCommit()
+
+	err := w.txManager.WithTx(context.Background(), func(q _db.Querier) error {
+		_, err := q.Exec(context.Background(), "DELETE FROM users")
+		return err
+	})
+	w.NoError(err)
+}
+
+func (w *WithTxSuite) TestWithTx_RollbackOnError() {
+	fnErr := errors.New("fn error")
+	w.mockPgx.ExpectBegin()
+	w.mockPgx.ExpectRollback()
+
+	err := w.txManager.WithTx(context.Background(), func(q _db.Querier) error {
+		return fnErr
+	})
+	w.ErrorIs(err, fnErr)
+	w.NotErrorIs(err, _db.ErrTransaction)
+}
+
+func (w *WithTxSuite) TestWithTx_IsolationLevel() {
+	w.mockPgx.ExpectBeginTx(pgx.TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly})
+	w.mockPgx.ExpectCommit()
+
+	err := w.txManager.WithTx(context.Background(), func(q _db.Querier) error {
+		return nil
+	}, _db.WithIsolation(pgx.Serializable), _db.ReadOnly())
+	w.NoError(err)
+}
+
+func (w *WithTxSuite) TestWithTx_NestedSavepoint() {
+	innerErr := errors.New("inner error")
+	// outer transaction, then a savepoint that is rolled back without aborting the outer one
+	w.mockPgx.ExpectBegin()
+	w.mockPgx.ExpectBegin()
+	w.mockPgx.ExpectRollback()
+	w.mockPgx.ExpectExec("UPDATE users").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	w.mockPgx.ExpectCommit()
+
+	err := w.txManager.WithTx(context.Background(), func(q _db.Querier) error {
+		err := _db.WithTx(context.Background(), q, func(q _db.Querier) error {
+			return innerErr
+		})
+		w.ErrorIs(err, innerErr)
+		_, err = q.Exec(context.Background(), "UPDATE users")
+		return err
+	})
+	w.NoError(err)
+}
+
+func (w *WithTxSuite) TestWithTx_BeginError() {
+	w.mockPgx.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+	called := false
+	err := w.txManager.WithTx(context.Background(), func(q _db.Querier) error {
+		called = true
+		return nil
+	})
+	w.ErrorIs(err, _db.ErrTransaction)
+	w.False(called)
+}
+
+func (w *WithTxSuite) TestWithTx_CommitError() {
+	w.mockPgx.ExpectBegin()
+	w.mockPgx.ExpectCommit().WillReturnError(errors.New("serialization failure"))
+	w.mockPgx.ExpectRollback()
+
+	err := w.txManager.WithTx(context.Background(), func(q _db.Querier) error {
+		return nil
+	})
+	w.ErrorIs(err, _db.ErrTransaction)
+}
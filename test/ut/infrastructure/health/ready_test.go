@@ -0,0 +1,57 @@
+package health_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/infrastructure/health"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReadySuite struct {
+	suite.Suite
+}
+
+func TestReadySuite(t *testing.T) {
+	suite.Run(t, &ReadySuite{})
+}
+
+func (r *ReadySuite) TestReady_SlowDependencyTimesOut() {
+	checker := health.NewFromChecks(50*time.Millisecond,
+		health.Check{Name: "file_service", Probe: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		health.Check{Name: "redis", Probe: func(ctx context.Context) error { return nil }},
+	)
+
+	start := time.Now()
+	report := checker.Ready(context.Background())
+
+	r.Less(time.Since(start), time.Second)
+	r.Equal(health.StatusDown, report.Status)
+	r.Equal(health.StatusDown, report.Dependencies["file_service"].Status)
+	r.Equal(context.DeadlineExceeded.Error(), report.Dependencies["file_service"].Error)
+	r.Equal(health.StatusUp, report.Dependencies["redis"].Status)
+}
+
+func (r *ReadySuite) TestReady_ChecksRunConcurrently() {
+	probe := func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}
+	checker := health.NewFromChecks(time.Second,
+		health.Check{Name: "postgres", Probe: probe},
+		health.Check{Name: "redis", Probe: probe},
+		health.Check{Name: "nats", Probe: probe},
+	)
+
+	start := time.Now()
+	report := checker.Ready(context.Background())
+
+	r.Less(time.Since(start), 250*time.Millisecond)
+	r.Equal(health.StatusUp, report.Status)
+	r.Len(report.Dependencies, 3)
+}
@@ -0,0 +1,84 @@
+package messagequeue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_mq "github.com/micros-template/user-service/internal/infrastructure/message-queue"
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// only PublishMsg is exercised, the embedded interface panics on anything else
+type jetStreamStub struct {
+	jetstream.JetStream
+	msg *nats.Msg
+	err error
+}
+
+func (j *jetStreamStub) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	j.msg = msg
+	if j.err != nil {
+		return nil, j.err
+	}
+	return &jetstream.PubAck{Stream: "eventbus"}, nil
+}
+
+type PublishSuite struct {
+	suite.Suite
+	js       *jetStreamStub
+	metrics  *metrics.Metrics
+	recorder *tracetest.SpanRecorder
+	nats     _mq.Nats
+}
+
+func (p *PublishSuite) SetupTest() {
+	p.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(p.recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	p.js = &jetStreamStub{}
+	p.metrics = metrics.New(nil)
+	p.nats = _mq.NewNatsInfrastructure(nil, zerolog.Nop(), p.js, p.metrics)
+}
+
+func TestPublishSuite(t *testing.T) {
+	suite.Run(t, &PublishSuite{})
+}
+
+func (p *PublishSuite) TestPublish_InjectsTraceContext() {
+	ack, err := p.nats.Publish(context.Background(), "eventbus.user.updated", []byte("{}"))
+	p.NoError(err)
+	p.Equal("eventbus", ack.Stream)
+
+	spans := p.recorder.Ended()
+	p.Len(spans, 1)
+	p.Equal("publish eventbus.user.updated", spans[0].Name())
+	p.Equal([]byte("{}"), p.js.msg.Data)
+	p.Contains(p.js.msg.Header.Get("Traceparent"), spans[0].SpanContext().TraceID().String())
+}
+
+func (p *PublishSuite) TestPublish_FailureRecorded() {
+	p.js.err = errors.New("no responders")
+
+	_, err := p.nats.Publish(context.Background(), "notification.email.verify", []byte("{}"))
+	p.Error(err)
+
+	spans := p.recorder.Ended()
+	p.Len(spans, 1)
+	p.Equal(codes.Error, spans[0].Status().Code)
+	count, err := testutil.GatherAndCount(p.metrics.Registry(), "user_service_jetstream_publish_failures_total")
+	p.NoError(err)
+	p.Equal(1, count)
+}
@@ -0,0 +1,97 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MetricsSuite struct {
+	suite.Suite
+	metrics *metrics.Metrics
+}
+
+func (m *MetricsSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	m.metrics = metrics.New(nil)
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, &MetricsSuite{})
+}
+
+func (m *MetricsSuite) TestHTTPMiddleware_UsesRouteTemplate() {
+	r := gin.New()
+	r.Use(m.metrics.HTTPMiddleware())
+	r.GET("/admin/users/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	for _, path := range []string{"/admin/users/a", "/admin/users/b", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP user_service_http_requests_total HTTP requests handled, by route, method and status code.
+# TYPE user_service_http_requests_total counter
+user_service_http_requests_total{code="200",method="GET",route="/admin/users/:id"} 2
+user_service_http_requests_total{code="404",method="GET",route="unmatched"} 1
+`
+	m.NoError(testutil.GatherAndCompare(m.metrics.Registry(), strings.NewReader(expected), "user_service_http_requests_total"))
+}
+
+func (m *MetricsSuite) TestUnaryServerInterceptor_RecordsStatusCode() {
+	interceptor := m.metrics.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUserById"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	})
+	m.Error(err)
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	m.NoError(err)
+
+	expected := `
+# HELP user_service_grpc_requests_total gRPC requests handled, by method and status code.
+# TYPE user_service_grpc_requests_total counter
+user_service_grpc_requests_total{code="NotFound",method="/user.UserService/GetUserById"} 1
+user_service_grpc_requests_total{code="OK",method="/user.UserService/GetUserById"} 1
+`
+	m.NoError(testutil.GatherAndCompare(m.metrics.Registry(), strings.NewReader(expected), "user_service_grpc_requests_total"))
+}
+
+func (m *MetricsSuite) TestPublishFailed_LabelsSubjectPrefix() {
+	m.metrics.PublishFailed("notification.email.verify")
+	m.metrics.PublishFailed("notification.email.reset")
+	m.metrics.PublishFailed("eventbus.user.updated")
+
+	expected := `
+# HELP user_service_jetstream_publish_failures_total JetStream publishes that returned an error, by subject prefix.
+# TYPE user_service_jetstream_publish_failures_total counter
+user_service_jetstream_publish_failures_total{subject="eventbus"} 1
+user_service_jetstream_publish_failures_total{subject="notification"} 2
+`
+	m.NoError(testutil.GatherAndCompare(m.metrics.Registry(), strings.NewReader(expected), "user_service_jetstream_publish_failures_total"))
+}
+
+func (m *MetricsSuite) TestHandler_ServesRegistry() {
+	m.metrics.PublishFailed("eventbus.user.updated")
+
+	rec := httptest.NewRecorder()
+	m.metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	m.Equal(http.StatusOK, rec.Code)
+	m.Contains(rec.Body.String(), "user_service_jetstream_publish_failures_total")
+	m.Contains(rec.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,157 @@
+package migration_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/micros-template/user-service/internal/infrastructure/database/migration"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type MigratorSuite struct {
+	suite.Suite
+	migrator migration.Migrator
+	mockPgx  pgxmock.PgxPoolIface
+}
+
+var fixtures = fstest.MapFS{
+	"sql/0001_create_users_table.up.sql":    {Data: []byte("CREATE TABLE users(id TEXT)")},
+	"sql/0001_create_users_table.down.sql":  {Data: []byte("DROP TABLE users")},
+	"sql/0002_create_outbox_table.up.sql":   {Data: []byte("CREATE TABLE outbox(id BIGINT)")},
+	"sql/0002_create_outbox_table.down.sql": {Data: []byte("DROP TABLE outbox")},
+}
+
+func (m *MigratorSuite) SetupTest() {
+	pgxMock, err := pgxmock.NewPool()
+	m.NoError(err)
+	m.mockPgx = pgxMock
+	m.migrator, err = migration.NewFromFS(pgxMock, fixtures, "sql", zerolog.Nop())
+	m.NoError(err)
+}
+
+func (m *MigratorSuite) TearDownTest() {
+	m.NoError(m.mockPgx.ExpectationsWereMet())
+}
+
+func TestMigratorSuite(t *testing.T) {
+	suite.Run(t, &MigratorSuite{})
+}
+
+func (m *MigratorSuite) expectApplied(versions ...int64) {
+	m.mockPgx.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).
+		WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	rows := pgxmock.NewRows([]string{"version", "applied_at"})
+	for _, v := range versions {
+		rows.AddRow(v, time.Now())
+	}
+	m.mockPgx.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).WillReturnRows(rows)
+}
+
+func (m *MigratorSuite) expectUp(version int64, name, script string, alreadyApplied bool) {
+	m.mockPgx.ExpectBegin()
+	m.mockPgx.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).WithArgs(pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	m.mockPgx.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)")).WithArgs(version).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(alreadyApplied))
+	if !alreadyApplied {
+		m.mockPgx.ExpectExec(regexp.QuoteMeta(script)).WillReturnResult(pgxmock.NewResult("CREATE", 0))
+		m.mockPgx.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version,name) VALUES ($1,$2)")).WithArgs(version, name).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	}
+	m.mockPgx.ExpectCommit()
+}
+
+func (m *MigratorSuite) expectDown(version int64, script string) {
+	m.mockPgx.ExpectBegin()
+	m.mockPgx.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).WithArgs(pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	m.mockPgx.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).WithArgs(version).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	m.mockPgx.ExpectExec(regexp.QuoteMeta(script)).WillReturnResult(pgxmock.NewResult("DROP", 0))
+	m.mockPgx.ExpectCommit()
+}
+
+func (m *MigratorSuite) TestMigrator_Up_FreshDatabase() {
+	m.expectApplied()
+	m.expectUp(1, "create_users_table", "CREATE TABLE users(id TEXT)", false)
+	m.expectUp(2, "create_outbox_table", "CREATE TABLE outbox(id BIGINT)", false)
+
+	done, err := m.migrator.Up(context.Background())
+	m.NoError(err)
+	m.Len(done, 2)
+	m.Equal(int64(1), done[0].Version)
+	m.Equal(int64(2), done[1].Version)
+}
+
+func (m *MigratorSuite) TestMigrator_Up_SkipsApplied() {
+	m.expectApplied(1)
+	m.expectUp(2, "create_outbox_table", "CREATE TABLE outbox(id BIGINT)", false)
+
+	done, err := m.migrator.Up(context.Background())
+	m.NoError(err)
+	m.Len(done, 1)
+	m.Equal("create_outbox_table", done[0].Name)
+}
+
+func (m *MigratorSuite) TestMigrator_Up_AppliedByAnotherReplica() {
+	m.expectApplied(1)
+	m.expectUp(2, "create_outbox_table", "CREATE TABLE outbox(id BIGINT)", true)
+
+	_, err := m.migrator.Up(context.Background())
+	m.NoError(err)
+}
+
+func (m *MigratorSuite) TestMigrator_Down_LatestFirst() {
+	m.expectApplied(1, 2)
+	m.expectDown(2, "DROP TABLE outbox")
+
+	done, err := m.migrator.Down(context.Background(), 1)
+	m.NoError(err)
+	m.Len(done, 1)
+	m.Equal(int64(2), done[0].Version)
+}
+
+func (m *MigratorSuite) TestMigrator_To_Zero() {
+	m.expectApplied(1, 2)
+	m.expectDown(2, "DROP TABLE outbox")
+	m.expectDown(1, "DROP TABLE users")
+
+	done, err := m.migrator.To(context.Background(), 0)
+	m.NoError(err)
+	m.Len(done, 2)
+}
+
+func (m *MigratorSuite) TestMigrator_To_UnknownVersion() {
+	done, err := m.migrator.To(context.Background(), 3)
+	m.ErrorIs(err, migration.ErrUnknownVersion)
+	m.Empty(done)
+}
+
+func (m *MigratorSuite) TestMigrator_Status() {
+	m.expectApplied(1)
+
+	status, err := m.migrator.Status(context.Background())
+	m.NoError(err)
+	m.Len(status, 2)
+	m.NotNil(status[0].AppliedAt)
+	m.Nil(status[1].AppliedAt)
+}
+
+func (m *MigratorSuite) TestMigrator_InvalidFileName() {
+	_, err := migration.NewFromFS(m.mockPgx, fstest.MapFS{
+		"sql/create_users.sql": {Data: []byte("CREATE TABLE users(id TEXT)")},
+	}, "sql", zerolog.Nop())
+	m.ErrorIs(err, migration.ErrInvalidFileName)
+}
+
+func (m *MigratorSuite) TestMigrator_EmbeddedMigrations() {
+	migrator, err := migration.New(m.mockPgx, zerolog.Nop())
+	m.NoError(err)
+	m.NotNil(migrator)
+}
@@ -0,0 +1,32 @@
+package avatar_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/micros-template/user-service/pkg/avatar"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DefaultSuite struct {
+	suite.Suite
+}
+
+func TestDefaultSuite(t *testing.T) {
+	suite.Run(t, &DefaultSuite{})
+}
+
+func (d *DefaultSuite) TestDefault_Deterministic() {
+	d.Equal(avatar.Default("user-1"), avatar.Default("user-1"))
+	d.NotEqual(avatar.Default("user-1"), avatar.Default("user-2"))
+}
+
+func (d *DefaultSuite) TestDefault_IdenticonSVG() {
+	svg := string(avatar.Default("user-1"))
+	d.True(strings.HasPrefix(svg, "<svg "))
+	d.NotContains(svg, "<text")
+	d.Contains(svg, "<g fill=")
+	d.NoError(xml.Unmarshal([]byte(svg), new(struct{})))
+}
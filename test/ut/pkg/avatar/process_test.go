@@ -0,0 +1,144 @@
+package avatar_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/micros-template/user-service/pkg/avatar"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProcessSuite struct {
+	suite.Suite
+}
+
+func TestProcessSuite(t *testing.T) {
+	suite.Run(t, &ProcessSuite{})
+}
+
+func (p *ProcessSuite) TestProcess_JPEGVariants() {
+	variants, err := avatar.Process(p.jpeg(800, 600, 1))
+	p.NoError(err)
+	p.Len(variants, len(avatar.Sizes))
+	for i, variant := range variants {
+		p.Equal(avatar.Sizes[i], variant.Size)
+		p.Equal("jpg", variant.Ext)
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(variant.Data))
+		p.NoError(err)
+		p.Equal("jpeg", format)
+		p.Equal(variant.Size, cfg.Width)
+		p.Equal(variant.Size, cfg.Height)
+	}
+}
+
+func (p *ProcessSuite) TestProcess_PNGKeepsFormat() {
+	var buf bytes.Buffer
+	p.NoError(png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 100, 300))))
+
+	variants, err := avatar.Process(buf.Bytes())
+	p.NoError(err)
+	for _, variant := range variants {
+		p.Equal("png", variant.Ext)
+		_, format, err := image.DecodeConfig(bytes.NewReader(variant.Data))
+		p.NoError(err)
+		p.Equal("png", format)
+	}
+}
+
+func (p *ProcessSuite) TestProcess_StripsExif() {
+	data := p.jpeg(64, 64, 1)
+	p.Contains(string(data), "GPSLatitude")
+
+	variants, err := avatar.Process(data)
+	p.NoError(err)
+	for _, variant := range variants {
+		p.NotContains(string(variant.Data), "Exif")
+		p.NotContains(string(variant.Data), "GPSLatitude")
+	}
+}
+
+func (p *ProcessSuite) TestProcess_AppliesOrientation() {
+	// left half red, right half blue; orientation 6 means rotate 90 degrees clockwise,
+	// so after the square crop red ends up on top
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for x := range 200 {
+		for y := range 100 {
+			c := color.RGBA{B: 255, A: 255}
+			if x < 100 {
+				c = color.RGBA{R: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	variants, err := avatar.Process(p.withExif(p.encode(img), 6))
+	p.NoError(err)
+
+	out, err := jpeg.Decode(bytes.NewReader(variants[0].Data))
+	p.NoError(err)
+	r, _, b, _ := out.At(32, 5).RGBA()
+	p.Greater(r, b)
+	r, _, b, _ = out.At(32, 58).RGBA()
+	p.Greater(b, r)
+}
+
+func (p *ProcessSuite) TestProcess_UnsupportedType() {
+	var buf bytes.Buffer
+	p.NoError(gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.Black}), nil))
+
+	_, err := avatar.Process(buf.Bytes())
+	p.ErrorIs(err, avatar.ErrUnsupportedType)
+
+	_, err = avatar.Process([]byte("<svg xmlns='http://www.w3.org/2000/svg'/>"))
+	p.ErrorIs(err, avatar.ErrUnsupportedType)
+}
+
+func (p *ProcessSuite) TestProcess_Corrupt() {
+	data := p.jpeg(32, 32, 1)
+	_, err := avatar.Process(data[:len(data)/2])
+	p.ErrorIs(err, avatar.ErrCorrupt)
+}
+
+func (p *ProcessSuite) TestProcess_DecompressionBomb() {
+	// a png header announcing 20000x20000 pixels, the pixel data is never read
+	var buf bytes.Buffer
+	p.NoError(png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 20000)
+	binary.BigEndian.PutUint32(data[20:], 20000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, err := avatar.Process(data)
+	p.ErrorIs(err, avatar.ErrTooLarge)
+}
+
+func (p *ProcessSuite) jpeg(width, height, orientation int) []byte {
+	return p.withExif(p.encode(image.NewRGBA(image.Rect(0, 0, width, height))), orientation)
+}
+
+func (p *ProcessSuite) encode(img image.Image) []byte {
+	var buf bytes.Buffer
+	p.NoError(jpeg.Encode(&buf, img, nil))
+	return buf.Bytes()
+}
+
+// inserts an APP1 segment with the orientation tag and some gps text right after SOI
+func (p *ProcessSuite) withExif(data []byte, orientation int) []byte {
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, byte(orientation), 0, 0, 0, 0, 0, 0}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	payload = append(payload, []byte("GPSLatitude 52.37")...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
@@ -0,0 +1,75 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/micros-template/user-service/pkg/password"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicySuite struct {
+	suite.Suite
+	policy password.Policy
+}
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, &PolicySuite{})
+}
+
+func (p *PolicySuite) SetupTest() {
+	p.policy = password.Policy{
+		MinLength:       8,
+		RequireUpper:    true,
+		RequireLower:    true,
+		RequireDigit:    true,
+		RequireSymbol:   true,
+		BanCommon:       true,
+		BanPersonalInfo: true,
+	}
+}
+
+func rules(violations []password.Violation) []password.Rule {
+	var r []password.Rule
+	for _, v := range violations {
+		r = append(r, v.Rule)
+	}
+	return r
+}
+
+func (p *PolicySuite) TestCheck_Valid() {
+	p.Empty(p.policy.Check("Tr1cky-Horse", "john.doe@example.com", "John Doe"))
+}
+
+func (p *PolicySuite) TestCheck_CharacterClasses() {
+	p.Equal([]password.Rule{password.RuleMinLength, password.RuleUppercase, password.RuleDigit, password.RuleSymbol}, rules(p.policy.Check("tricky")))
+	p.Equal([]password.Rule{password.RuleLowercase}, rules(p.policy.Check("TR1CKY-HORSE")))
+	p.Equal([]password.Rule{password.RuleMinLength}, rules(p.policy.Check("Ab1-")))
+}
+
+func (p *PolicySuite) TestCheck_MinLengthCountsCharacters() {
+	p.policy = password.Policy{MinLength: 8}
+	p.Empty(p.policy.Check("ÄÖÜäöüßé"))
+	p.Equal("must be at least 8 characters", p.policy.Check("ÄÖÜ")[0].Message)
+}
+
+func (p *PolicySuite) TestCheck_Common() {
+	p.policy = password.Policy{BanCommon: true}
+	p.Equal([]password.Rule{password.RuleCommon}, rules(p.policy.Check("Password123")))
+	p.Equal([]password.Rule{password.RuleCommon}, rules(p.policy.Check("P@ssw0rd")))
+	p.Empty(p.policy.Check("Tr1cky-Horse"))
+	p.True(password.IsCommon("QWERTY"))
+}
+
+func (p *PolicySuite) TestCheck_PersonalInfo() {
+	p.policy = password.Policy{BanPersonalInfo: true}
+	for _, pw := range []string{"Johnny-2024!", "xX-DOE-Xx", "john.doe99", "JohnDoe"} {
+		p.Equal([]password.Rule{password.RulePersonalInfo}, rules(p.policy.Check(pw, "john.doe@example.com", "John Doe")), pw)
+	}
+	// the domain and short name parts are not personal
+	p.Empty(p.policy.Check("example.com-Al1", "al@example.com", "Al Li"))
+}
+
+func (p *PolicySuite) TestCheck_Disabled() {
+	p.Empty(password.Policy{}.Check("a", "a@example.com", "a"))
+}
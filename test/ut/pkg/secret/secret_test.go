@@ -0,0 +1,74 @@
+package secret_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/micros-template/user-service/pkg/secret"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SecretSuite struct {
+	suite.Suite
+	key []byte
+}
+
+func TestSecretSuite(t *testing.T) {
+	suite.Run(t, &SecretSuite{})
+}
+
+func (s *SecretSuite) SetupTest() {
+	s.key = bytes.Repeat([]byte{7}, secret.KeySize)
+}
+
+func (s *SecretSuite) TestParseKey() {
+	key, err := secret.ParseKey(base64.StdEncoding.EncodeToString(s.key))
+	s.NoError(err)
+	s.Equal(s.key, key)
+
+	_, err = secret.ParseKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	s.Equal(secret.ErrInvalidKey, err)
+	_, err = secret.ParseKey("not base64!")
+	s.Equal(secret.ErrInvalidKey, err)
+}
+
+func (s *SecretSuite) TestSealOpen_RoundTrip() {
+	sealed, err := secret.Seal(s.key, []byte("JBSWY3DPEHPK3PXP"), []byte("user-1"))
+	s.NoError(err)
+	s.NotContains(string(sealed), "JBSWY3DPEHPK3PXP")
+
+	plaintext, err := secret.Open(s.key, sealed, []byte("user-1"))
+	s.NoError(err)
+	s.Equal("JBSWY3DPEHPK3PXP", string(plaintext))
+}
+
+func (s *SecretSuite) TestSeal_RandomNonce() {
+	a, err := secret.Seal(s.key, []byte("value"), nil)
+	s.NoError(err)
+	b, err := secret.Seal(s.key, []byte("value"), nil)
+	s.NoError(err)
+	s.NotEqual(a, b)
+}
+
+func (s *SecretSuite) TestOpen_WrongAdditionalData() {
+	sealed, err := secret.Seal(s.key, []byte("value"), []byte("user-1"))
+	s.NoError(err)
+
+	_, err = secret.Open(s.key, sealed, []byte("user-2"))
+	s.Equal(secret.ErrDecryptionFailure, err)
+}
+
+func (s *SecretSuite) TestOpen_WrongKey() {
+	sealed, err := secret.Seal(s.key, []byte("value"), nil)
+	s.NoError(err)
+
+	_, err = secret.Open(bytes.Repeat([]byte{8}, secret.KeySize), sealed, nil)
+	s.Equal(secret.ErrDecryptionFailure, err)
+}
+
+func (s *SecretSuite) TestOpen_Malformed() {
+	_, err := secret.Open(s.key, []byte("short"), nil)
+	s.Equal(secret.ErrMalformedSealed, err)
+}
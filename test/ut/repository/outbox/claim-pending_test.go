@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClaimPendingRepositorySuite struct {
+	suite.Suite
+	outboxRepository repository.OutboxRepository
+	mockPgx          pgxmock.PgxPoolIface
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (o *ClaimPendingRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	o.NoError(err)
+	logEmitter := new(mk.LoggerInfraMock)
+	o.mockPgx = pgxMock
+	o.logEmitter = logEmitter
+	o.outboxRepository = repository.NewOutboxRepository(pgxMock, logEmitter, logger)
+}
+
+func (o *ClaimPendingRepositorySuite) SetupTest() {
+	o.logEmitter.ExpectedCalls = nil
+	o.logEmitter.Calls = nil
+}
+
+func TestClaimPendingRepositorySuite(t *testing.T) {
+	suite.Run(t, &ClaimPendingRepositorySuite{})
+}
+
+const claimPendingQuery = `UPDATE outbox SET attempts = attempts \+ 1, next_attempt_at = \$1 WHERE id IN \(SELECT id FROM outbox o WHERE sent_at IS NULL AND dead_at IS NULL AND attempts < \$2 AND next_attempt_at <= CURRENT_TIMESTAMP AND NOT EXISTS \(SELECT 1 FROM outbox prev WHERE prev.subject = o.subject AND prev.id < o.id AND prev.sent_at IS NULL AND prev.dead_at IS NULL AND \(prev.attempts >= \$3 OR prev.next_attempt_at > CURRENT_TIMESTAMP\)\) ORDER BY id LIMIT \$4 FOR UPDATE SKIP LOCKED\) RETURNING id, subject, payload, attempts`
+
+func (o *ClaimPendingRepositorySuite) TestOutboxRepository_ClaimPending_Success() {
+	leaseUntil := time.Now().Add(30 * time.Second)
+	o.mockPgx.ExpectQuery(claimPendingQuery).
+		WithArgs(leaseUntil, 10, 10, uint64(100)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "subject", "payload", "attempts"}).
+			AddRow(int64(1), "eventbus.user.userid-1", []byte("first"), 1).
+			AddRow(int64(2), "eventbus.user.userid-2", []byte("second"), 3))
+
+	msgs, err := o.outboxRepository.ClaimPending(context.Background(), 100, 10, leaseUntil)
+	o.NoError(err)
+	o.Len(msgs, 2)
+	o.Equal(int64(1), msgs[0].ID)
+	o.Equal("eventbus.user.userid-2", msgs[1].Subject)
+	o.Equal(3, msgs[1].Attempts)
+}
+
+func (o *ClaimPendingRepositorySuite) TestOutboxRepository_ClaimPending_QueryError() {
+	leaseUntil := time.Now().Add(30 * time.Second)
+	o.mockPgx.ExpectQuery(claimPendingQuery).
+		WithArgs(leaseUntil, 10, 10, uint64(100)).
+		WillReturnError(errors.New("db error"))
+	o.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	msgs, err := o.outboxRepository.ClaimPending(context.Background(), 100, 10, leaseUntil)
+	o.Nil(msgs)
+	o.ErrorIs(err, dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX)
+	time.Sleep(time.Second)
+
+	o.logEmitter.AssertExpectations(o.T())
+}
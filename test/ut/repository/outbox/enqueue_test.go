@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EnqueueRepositorySuite struct {
+	suite.Suite
+	outboxRepository repository.OutboxRepository
+	mockPgx          pgxmock.PgxPoolIface
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (o *EnqueueRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	o.NoError(err)
+	logEmitter := new(mk.LoggerInfraMock)
+	o.mockPgx = pgxMock
+	o.logEmitter = logEmitter
+	o.outboxRepository = repository.NewOutboxRepository(pgxMock, logEmitter, logger)
+}
+
+func (o *EnqueueRepositorySuite) SetupTest() {
+	o.logEmitter.ExpectedCalls = nil
+	o.logEmitter.Calls = nil
+}
+
+func TestEnqueueRepositorySuite(t *testing.T) {
+	suite.Run(t, &EnqueueRepositorySuite{})
+}
+
+func (o *EnqueueRepositorySuite) TestOutboxRepository_Enqueue_Success() {
+	msgs := []*dto.OutboxMessage{
+		{Subject: "notification.mail.userid-1", Payload: []byte("first")},
+		{Subject: "notification.mail.userid-2", Payload: []byte("second")},
+	}
+	o.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+		WithArgs(msgs[0].Subject, msgs[0].Payload, msgs[1].Subject, msgs[1].Payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	err := o.outboxRepository.Enqueue(context.Background(), msgs...)
+	o.NoError(err)
+	o.NoError(o.mockPgx.ExpectationsWereMet())
+}
+
+func (o *EnqueueRepositorySuite) TestOutboxRepository_Enqueue_Empty() {
+	err := o.outboxRepository.Enqueue(context.Background())
+	o.NoError(err)
+	o.NoError(o.mockPgx.ExpectationsWereMet())
+}
+
+func (o *EnqueueRepositorySuite) TestOutboxRepository_Enqueue_Error() {
+	msg := &dto.OutboxMessage{Subject: "notification.mail.userid-1", Payload: []byte("first")}
+	o.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(msg.Subject, msg.Payload).
+		WillReturnError(errors.New("db error"))
+	o.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := o.outboxRepository.Enqueue(context.Background(), msg)
+	o.ErrorIs(err, dto.Err_INTERNAL_FAILED_INSERT_OUTBOX)
+	time.Sleep(time.Second)
+
+	o.logEmitter.AssertExpectations(o.T())
+}
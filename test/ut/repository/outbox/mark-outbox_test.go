@@ -0,0 +1,110 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MarkOutboxRepositorySuite struct {
+	suite.Suite
+	outboxRepository repository.OutboxRepository
+	mockPgx          pgxmock.PgxPoolIface
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (o *MarkOutboxRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	o.NoError(err)
+	logEmitter := new(mk.LoggerInfraMock)
+	o.mockPgx = pgxMock
+	o.logEmitter = logEmitter
+	o.outboxRepository = repository.NewOutboxRepository(pgxMock, logEmitter, logger)
+}
+
+func (o *MarkOutboxRepositorySuite) SetupTest() {
+	o.logEmitter.ExpectedCalls = nil
+	o.logEmitter.Calls = nil
+}
+
+func TestMarkOutboxRepositorySuite(t *testing.T) {
+	suite.Run(t, &MarkOutboxRepositorySuite{})
+}
+
+func (o *MarkOutboxRepositorySuite) TestOutboxRepository_MarkSent_Success() {
+	o.mockPgx.ExpectExec(`UPDATE outbox SET sent_at = CURRENT_TIMESTAMP, last_error = \$1 WHERE id = \$2`).
+		WithArgs(nil, int64(1)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := o.outboxRepository.MarkSent(context.Background(), 1)
+	o.NoError(err)
+	o.NoError(o.mockPgx.ExpectationsWereMet())
+}
+
+func (o *MarkOutboxRepositorySuite) TestOutboxRepository_MarkFailed_Error() {
+	nextAttemptAt := time.Now().Add(time.Minute)
+	o.mockPgx.ExpectExec(`UPDATE outbox SET last_error = \$1, next_attempt_at = \$2 WHERE id = \$3`).
+		WithArgs("nats unavailable", nextAttemptAt, int64(1)).
+		WillReturnError(errors.New("db error"))
+	o.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := o.outboxRepository.MarkFailed(context.Background(), 1, "nats unavailable", nextAttemptAt)
+	o.ErrorIs(err, dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX)
+	time.Sleep(time.Second)
+
+	o.logEmitter.AssertExpectations(o.T())
+}
+
+func (o *MarkOutboxRepositorySuite) TestOutboxRepository_Release_Success() {
+	o.mockPgx.ExpectExec(`UPDATE outbox SET attempts = attempts - 1, next_attempt_at = CURRENT_TIMESTAMP WHERE id = \$1`).
+		WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := o.outboxRepository.Release(context.Background(), 2)
+	o.NoError(err)
+	o.NoError(o.mockPgx.ExpectationsWereMet())
+}
+
+const deadLetterQuery = `UPDATE outbox SET dead_at = CURRENT_TIMESTAMP WHERE dead_at IS NULL AND sent_at IS NULL AND attempts >= \$1 AND next_attempt_at <= CURRENT_TIMESTAMP RETURNING id, subject, attempts, last_error`
+
+func (o *MarkOutboxRepositorySuite) TestOutboxRepository_DeadLetterExhausted_Success() {
+	lastError := "nats unavailable"
+	o.mockPgx.ExpectQuery(deadLetterQuery).
+		WithArgs(10).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "subject", "attempts", "last_error"}).
+			AddRow(int64(1), "eventbus.user.userid-1", 10, &lastError).
+			AddRow(int64(2), "notification.mail.userid-2", 10, nil))
+
+	msgs, err := o.outboxRepository.DeadLetterExhausted(context.Background(), 10)
+	o.NoError(err)
+	o.Len(msgs, 2)
+	o.Equal(int64(1), msgs[0].ID)
+	o.Equal("nats unavailable", *msgs[0].LastError)
+	o.Nil(msgs[1].LastError)
+	o.NoError(o.mockPgx.ExpectationsWereMet())
+}
+
+func (o *MarkOutboxRepositorySuite) TestOutboxRepository_DeadLetterExhausted_Error() {
+	o.mockPgx.ExpectQuery(deadLetterQuery).
+		WithArgs(10).
+		WillReturnError(errors.New("db error"))
+	o.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	msgs, err := o.outboxRepository.DeadLetterExhausted(context.Background(), 10)
+	o.Nil(msgs)
+	o.ErrorIs(err, dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX)
+	time.Sleep(time.Second)
+
+	o.logEmitter.AssertExpectations(o.T())
+}
@@ -0,0 +1,204 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryCachedProfileSuite struct {
+	suite.Suite
+	profileCache   repository.ProfileCacheRepository
+	userRepository *mk.UserRepositoryMock
+	redisClient    *mk.MockRedisCache
+	metrics        *metrics.Metrics
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (q *QueryCachedProfileSuite) SetupSuite() {
+	viper.Set("app.cache.profile_ttl", "10m")
+}
+
+func (q *QueryCachedProfileSuite) TearDownSuite() {
+	viper.Reset()
+}
+
+// a fresh repository and registry per test keeps the hit and miss counters isolated
+func (q *QueryCachedProfileSuite) SetupTest() {
+	q.userRepository = new(mk.UserRepositoryMock)
+	q.redisClient = new(mk.MockRedisCache)
+	q.metrics = metrics.New(nil)
+	q.logEmitter = new(mk.LoggerInfraMock)
+	q.profileCache = repository.NewProfileCacheRepository(q.userRepository, q.redisClient, q.metrics, q.logEmitter, zerolog.Nop())
+}
+
+func TestQueryCachedProfileSuite(t *testing.T) {
+	suite.Run(t, &QueryCachedProfileSuite{})
+}
+
+func (q *QueryCachedProfileSuite) assertLookups(hits, misses int) {
+	expected := `
+# HELP user_service_cache_lookups_total Read-through cache lookups, by cache and result (hit or miss).
+# TYPE user_service_cache_lookups_total counter
+`
+	if hits > 0 {
+		expected += fmt.Sprintf("user_service_cache_lookups_total{cache=\"profile\",result=\"hit\"} %d\n", hits)
+	}
+	if misses > 0 {
+		expected += fmt.Sprintf("user_service_cache_lookups_total{cache=\"profile\",result=\"miss\"} %d\n", misses)
+	}
+	q.NoError(testutil.GatherAndCompare(q.metrics.Registry(), strings.NewReader(expected), "user_service_cache_lookups_total"))
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_QueryProfileByUserId_Hit() {
+	q.redisClient.On("Get", mock.Anything, "userProfile:user-1").
+		Return(`{"id":"user-1","full_name":"John Doe","image":null,"email":"john@example.com","verified":true,"two_factor_enabled":false}`, nil).Once()
+
+	user, err := q.profileCache.QueryProfileByUserId(context.Background(), "user-1")
+	q.NoError(err)
+	q.Equal("John Doe", user.FullName)
+	q.Equal("john@example.com", user.Email)
+	q.assertLookups(1, 0)
+	q.userRepository.AssertNotCalled(q.T(), "QueryProfileByUserId", mock.Anything, mock.Anything)
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_QueryProfileByUserId_MissStoresWithoutPassword() {
+	user := &dto.UserProfile{User: model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com", Password: "$2a$10$hash", Verified: true}}
+	q.redisClient.On("Get", mock.Anything, "userProfile:user-1").Return("", redis.Nil).Once()
+	q.redisClient.On("Get", mock.Anything, "userProfileGeneration:user-1").Return("3", nil).Twice()
+	q.userRepository.On("QueryProfileByUserId", mock.Anything, "user-1").Return(user, nil).Once()
+	q.redisClient.On("Set", mock.Anything, "userProfile:user-1", mock.MatchedBy(func(value []byte) bool {
+		return strings.Contains(string(value), "John Doe") && !strings.Contains(string(value), "$2a$10$hash")
+	}), 10*time.Minute).Return(nil).Once()
+
+	res, err := q.profileCache.QueryProfileByUserId(context.Background(), "user-1")
+	q.NoError(err)
+	q.Equal("John Doe", res.FullName)
+	q.Empty(res.Password)
+	q.assertLookups(0, 1)
+	q.redisClient.AssertExpectations(q.T())
+	q.userRepository.AssertExpectations(q.T())
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_QueryProfileByUserId_RedisDown() {
+	user := &dto.UserProfile{User: model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com"}}
+	q.redisClient.On("Get", mock.Anything, mock.Anything).Return("", errors.New("connection refused"))
+	q.userRepository.On("QueryProfileByUserId", mock.Anything, "user-1").Return(user, nil).Once()
+
+	res, err := q.profileCache.QueryProfileByUserId(context.Background(), "user-1")
+	q.NoError(err)
+	q.Equal("John Doe", res.FullName)
+	q.redisClient.AssertNotCalled(q.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_QueryProfileByUserId_NotFound() {
+	q.redisClient.On("Get", mock.Anything, "userProfile:user-1").Return("", redis.Nil).Once()
+	q.redisClient.On("Get", mock.Anything, "userProfileGeneration:user-1").Return("", redis.Nil).Once()
+	q.userRepository.On("QueryProfileByUserId", mock.Anything, "user-1").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+
+	res, err := q.profileCache.QueryProfileByUserId(context.Background(), "user-1")
+	q.Nil(res)
+	q.ErrorIs(err, dto.Err_NOTFOUND_USER_NOT_FOUND)
+	q.redisClient.AssertNotCalled(q.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_QueryProfileByUserId_SingleflightMisses() {
+	user := &dto.UserProfile{User: model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com"}}
+	release := make(chan struct{})
+	q.redisClient.On("Get", mock.Anything, "userProfile:user-1").Return("", redis.Nil)
+	q.redisClient.On("Get", mock.Anything, "userProfileGeneration:user-1").Return("", redis.Nil)
+	q.userRepository.On("QueryProfileByUserId", mock.Anything, "user-1").
+		Run(func(mock.Arguments) { <-release }).
+		Return(user, nil).Once()
+	q.redisClient.On("Set", mock.Anything, "userProfile:user-1", mock.Anything, mock.Anything).Return(nil).Once()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := q.profileCache.QueryProfileByUserId(context.Background(), "user-1")
+			q.NoError(err)
+			q.Equal("John Doe", res.FullName)
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	q.userRepository.AssertNumberOfCalls(q.T(), "QueryProfileByUserId", 1)
+	q.assertLookups(0, 10)
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_Invalidate() {
+	q.redisClient.On("Incr", mock.Anything, "userProfileGeneration:user-1", 10*time.Minute).Return(int64(1), nil).Once()
+	q.redisClient.On("Delete", mock.Anything, "userProfile:user-1").Return(nil).Once()
+
+	err := q.profileCache.Invalidate(context.Background(), "user-1")
+	q.NoError(err)
+	q.redisClient.AssertExpectations(q.T())
+}
+
+func (q *QueryCachedProfileSuite) TestProfileCache_Invalidate_Error() {
+	q.redisClient.On("Incr", mock.Anything, "userProfileGeneration:user-1", 10*time.Minute).Return(int64(0), errors.New("connection refused")).Once()
+	q.redisClient.On("Delete", mock.Anything, "userProfile:user-1").Return(errors.New("connection refused")).Once()
+	q.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := q.profileCache.Invalidate(context.Background(), "user-1")
+	q.ErrorIs(err, dto.Err_INTERNAL_DELETE_RESOURCE)
+	time.Sleep(time.Second)
+
+	q.logEmitter.AssertExpectations(q.T())
+}
+
+// the load read the row before the update, its SET must not outlive the invalidation
+func (q *QueryCachedProfileSuite) TestProfileCache_InvalidateDuringLoad() {
+	stale := &dto.UserProfile{User: model.User{ID: "user-1", FullName: "John Doe"}, Version: 3}
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	q.redisClient.On("Get", mock.Anything, "userProfile:user-1").Return("", redis.Nil).Once()
+	q.redisClient.On("Get", mock.Anything, "userProfileGeneration:user-1").Return("", redis.Nil).Once()
+	q.userRepository.On("QueryProfileByUserId", mock.Anything, "user-1").
+		Run(func(mock.Arguments) {
+			close(loading)
+			<-release
+		}).
+		Return(stale, nil).Once()
+	q.redisClient.On("Incr", mock.Anything, "userProfileGeneration:user-1", 10*time.Minute).Return(int64(1), nil).Once()
+	q.redisClient.On("Delete", mock.Anything, "userProfile:user-1").Return(nil).Twice()
+	q.redisClient.On("Set", mock.Anything, "userProfile:user-1", mock.Anything, 10*time.Minute).Return(nil).Once()
+	q.redisClient.On("Get", mock.Anything, "userProfileGeneration:user-1").Return("1", nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res, err := q.profileCache.QueryProfileByUserId(context.Background(), "user-1")
+		q.NoError(err)
+		q.Equal(int64(3), res.Version)
+	}()
+	<-loading
+	q.NoError(q.profileCache.Invalidate(context.Background(), "user-1"))
+	close(release)
+	<-done
+
+	// once by the invalidation and once more by the load that overlapped it
+	q.redisClient.AssertNumberOfCalls(q.T(), "Delete", 2)
+	q.redisClient.AssertExpectations(q.T())
+}
@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetResourceRepositorySuite struct {
+	suite.Suite
+	redisRepository repository.RedisRepository
+	mockRedisClient *mk.MockRedisCache
+	logEmitter      *mk.LoggerInfraMock
+}
+
+func (g *GetResourceRepositorySuite) SetupSuite() {
+
+	logger := zerolog.Nop()
+	redisClient := new(mk.MockRedisCache)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	g.mockRedisClient = redisClient
+	g.logEmitter = mockLogEmitter
+	g.redisRepository = repository.NewRedisRepository(redisClient, mockLogEmitter, logger)
+}
+
+func (g *GetResourceRepositorySuite) SetupTest() {
+	g.mockRedisClient.ExpectedCalls = nil
+	g.mockRedisClient.Calls = nil
+	g.logEmitter.ExpectedCalls = nil
+	g.logEmitter.Calls = nil
+}
+
+func TestGetResourceRepositorySuite(t *testing.T) {
+	suite.Run(t, &GetResourceRepositorySuite{})
+}
+
+func (g *GetResourceRepositorySuite) TestResourceRepository_GetResource_Success() {
+	g.mockRedisClient.On("Get", mock.Anything, "resource-key").Return("resource-value", nil)
+
+	value, err := g.redisRepository.GetResource(context.Background(), "resource-key")
+
+	g.NoError(err)
+	g.Equal("resource-value", value)
+	g.mockRedisClient.AssertExpectations(g.T())
+}
+
+func (g *GetResourceRepositorySuite) TestResourceRepository_GetResource_NotFound() {
+	g.mockRedisClient.On("Get", mock.Anything, "resource-key").Return("", redis.Nil)
+
+	value, err := g.redisRepository.GetResource(context.Background(), "resource-key")
+
+	g.Equal(dto.Err_NOTFOUND_KEY_NOTFOUND, err)
+	g.Empty(value)
+}
+
+func (g *GetResourceRepositorySuite) TestResourceRepository_GetResource_Error() {
+	g.mockRedisClient.On("Get", mock.Anything, "resource-key").Return("", errors.New("connection refused"))
+	g.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_GET_RESOURCE.Error()).Return(nil)
+
+	value, err := g.redisRepository.GetResource(context.Background(), "resource-key")
+
+	g.Equal(dto.Err_INTERNAL_GET_RESOURCE, err)
+	g.Empty(value)
+
+	time.Sleep(time.Second)
+	g.logEmitter.AssertExpectations(g.T())
+}
@@ -0,0 +1,67 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type IncrementResourceRepositorySuite struct {
+	suite.Suite
+	redisRepository repository.RedisRepository
+	mockRedisClient *mk.MockRedisCache
+	logEmitter      *mk.LoggerInfraMock
+}
+
+func (s *IncrementResourceRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	redisClient := new(mk.MockRedisCache)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	s.mockRedisClient = redisClient
+	s.logEmitter = mockLogEmitter
+	s.redisRepository = repository.NewRedisRepository(redisClient, mockLogEmitter, logger)
+}
+
+func (s *IncrementResourceRepositorySuite) SetupTest() {
+	s.mockRedisClient.ExpectedCalls = nil
+	s.mockRedisClient.Calls = nil
+	s.logEmitter.ExpectedCalls = nil
+	s.logEmitter.Calls = nil
+}
+
+func TestIncrementResourceRepositorySuite(t *testing.T) {
+	suite.Run(t, &IncrementResourceRepositorySuite{})
+}
+
+func (s *IncrementResourceRepositorySuite) TestResourceRepository_IncrementResource_Success() {
+	s.mockRedisClient.On("Incr", mock.Anything, "counter-key", time.Hour).Return(int64(3), nil)
+
+	count, err := s.redisRepository.IncrementResource(context.Background(), "counter-key", time.Hour)
+
+	s.NoError(err)
+	s.Equal(int64(3), count)
+	s.mockRedisClient.AssertExpectations(s.T())
+}
+
+func (s *IncrementResourceRepositorySuite) TestResourceRepository_IncrementResource_Error() {
+	s.mockRedisClient.On("Incr", mock.Anything, "counter-key", time.Hour).Return(int64(0), errors.New("connection refused"))
+	s.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_SET_RESOURCE.Error()).Return(nil)
+
+	count, err := s.redisRepository.IncrementResource(context.Background(), "counter-key", time.Hour)
+
+	s.Equal(dto.Err_INTERNAL_SET_RESOURCE, err)
+	s.Zero(count)
+
+	time.Sleep(time.Second)
+	s.logEmitter.AssertExpectations(s.T())
+}
@@ -0,0 +1,66 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RemoveResourceRepositorySuite struct {
+	suite.Suite
+	redisRepository repository.RedisRepository
+	mockRedisClient *mk.MockRedisCache
+	logEmitter      *mk.LoggerInfraMock
+}
+
+func (r *RemoveResourceRepositorySuite) SetupSuite() {
+
+	logger := zerolog.Nop()
+	redisClient := new(mk.MockRedisCache)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	r.mockRedisClient = redisClient
+	r.logEmitter = mockLogEmitter
+	r.redisRepository = repository.NewRedisRepository(redisClient, mockLogEmitter, logger)
+}
+
+func (r *RemoveResourceRepositorySuite) SetupTest() {
+	r.mockRedisClient.ExpectedCalls = nil
+	r.mockRedisClient.Calls = nil
+	r.logEmitter.ExpectedCalls = nil
+	r.logEmitter.Calls = nil
+}
+
+func TestRemoveResourceRepositorySuite(t *testing.T) {
+	suite.Run(t, &RemoveResourceRepositorySuite{})
+}
+
+func (r *RemoveResourceRepositorySuite) TestResourceRepository_RemoveResource_Success() {
+	r.mockRedisClient.On("Delete", mock.Anything, "resource-key").Return(nil)
+
+	err := r.redisRepository.RemoveResource(context.Background(), "resource-key")
+
+	r.NoError(err)
+	r.mockRedisClient.AssertExpectations(r.T())
+}
+
+func (r *RemoveResourceRepositorySuite) TestResourceRepository_RemoveResource_Error() {
+	r.mockRedisClient.On("Delete", mock.Anything, "resource-key").Return(errors.New("connection refused"))
+	r.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_DELETE_RESOURCE.Error()).Return(nil)
+
+	err := r.redisRepository.RemoveResource(context.Background(), "resource-key")
+
+	r.Equal(dto.Err_INTERNAL_DELETE_RESOURCE, err)
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
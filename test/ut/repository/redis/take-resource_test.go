@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TakeResourceRepositorySuite struct {
+	suite.Suite
+	redisRepository repository.RedisRepository
+	mockRedisClient *mk.MockRedisCache
+	logEmitter      *mk.LoggerInfraMock
+}
+
+func (r *TakeResourceRepositorySuite) SetupSuite() {
+
+	logger := zerolog.Nop()
+	redisClient := new(mk.MockRedisCache)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	r.mockRedisClient = redisClient
+	r.logEmitter = mockLogEmitter
+	r.redisRepository = repository.NewRedisRepository(redisClient, mockLogEmitter, logger)
+}
+
+func (r *TakeResourceRepositorySuite) SetupTest() {
+	r.mockRedisClient.ExpectedCalls = nil
+	r.mockRedisClient.Calls = nil
+	r.logEmitter.ExpectedCalls = nil
+	r.logEmitter.Calls = nil
+}
+
+func TestTakeResourceRepositorySuite(t *testing.T) {
+	suite.Run(t, &TakeResourceRepositorySuite{})
+}
+
+func (r *TakeResourceRepositorySuite) TestResourceRepository_TakeResource_Success() {
+	r.mockRedisClient.On("GetDel", mock.Anything, "resource-key").Return("resource-value", nil)
+
+	value, err := r.redisRepository.TakeResource(context.Background(), "resource-key")
+
+	r.NoError(err)
+	r.Equal("resource-value", value)
+	r.mockRedisClient.AssertExpectations(r.T())
+}
+
+func (r *TakeResourceRepositorySuite) TestResourceRepository_TakeResource_NotFound() {
+	r.mockRedisClient.On("GetDel", mock.Anything, "resource-key").Return("", redis.Nil)
+
+	value, err := r.redisRepository.TakeResource(context.Background(), "resource-key")
+
+	r.Equal(dto.Err_NOTFOUND_KEY_NOTFOUND, err)
+	r.Empty(value)
+}
+
+func (r *TakeResourceRepositorySuite) TestResourceRepository_TakeResource_Error() {
+	r.mockRedisClient.On("GetDel", mock.Anything, "resource-key").Return("", errors.New("connection refused"))
+	r.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_GET_RESOURCE.Error()).Return(nil)
+
+	value, err := r.redisRepository.TakeResource(context.Background(), "resource-key")
+
+	r.Equal(dto.Err_INTERNAL_GET_RESOURCE, err)
+	r.Empty(value)
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -60,7 +61,7 @@ func (u *CreateNewUserRepositorySuite) TestUserRepository_CreateNewUser_Success(
 		WithArgs(user.ID, user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(user.ID))
 
-	err := u.userRepository.CreateNewUser(user)
+	err := u.userRepository.CreateNewUser(context.Background(), user)
 	u.NoError(err)
 	u.Equal(email, user.ID)
 }
@@ -84,10 +85,62 @@ func (u *CreateNewUserRepositorySuite) TestUserRepository_CreateNewUser_ScanErro
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(struct{}{}))
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userRepository.CreateNewUser(user)
+	err := u.userRepository.CreateNewUser(context.Background(), user)
 	u.Error(err)
 	u.ErrorIs(err, dto.Err_INTERNAL_FAILED_INSERT_USER)
 	time.Sleep(time.Second)
 
 	u.logEmitter.AssertExpectations(u.T())
 }
+
+func (u *CreateNewUserRepositorySuite) TestUserRepository_CreateNewUser_WithOutbox() {
+	user := &model.User{
+		ID:       "userid-outbox",
+		FullName: "test_user",
+		Email:    "outbox@example.com",
+		Password: "hashedpassword",
+	}
+	msg := &dto.OutboxMessage{Subject: "eventbus.user.userid-outbox", Payload: []byte("payload")}
+
+	insertQuery := `INSERT INTO users \(id,full_name,image,email,password,verified,two_factor_enabled\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7\) RETURNING id`
+	u.mockPgx.ExpectBegin()
+	u.mockPgx.ExpectQuery(insertQuery).
+		WithArgs(user.ID, user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(user.ID))
+	u.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(msg.Subject, msg.Payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	u.mockPgx.ExpectCommit()
+
+	err := u.userRepository.CreateNewUser(context.Background(), user, msg)
+	u.NoError(err)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+}
+
+func (u *CreateNewUserRepositorySuite) TestUserRepository_CreateNewUser_OutboxErrorRollsBack() {
+	user := &model.User{
+		ID:       "userid-outbox-error",
+		FullName: "test_user",
+		Email:    "outbox-error@example.com",
+		Password: "hashedpassword",
+	}
+	msg := &dto.OutboxMessage{Subject: "eventbus.user.userid-outbox-error", Payload: []byte("payload")}
+
+	insertQuery := `INSERT INTO users \(id,full_name,image,email,password,verified,two_factor_enabled\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7\) RETURNING id`
+	u.mockPgx.ExpectBegin()
+	u.mockPgx.ExpectQuery(insertQuery).
+		WithArgs(user.ID, user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(user.ID))
+	u.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(msg.Subject, msg.Payload).
+		WillReturnError(fmt.Errorf("db error"))
+	u.mockPgx.ExpectRollback()
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := u.userRepository.CreateNewUser(context.Background(), user, msg)
+	u.ErrorIs(err, dto.Err_INTERNAL_FAILED_INSERT_OUTBOX)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+	time.Sleep(time.Second)
+
+	u.logEmitter.AssertExpectations(u.T())
+}
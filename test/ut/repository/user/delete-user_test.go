@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"regexp"
 	"testing"
 	"time"
@@ -44,24 +45,24 @@ func TestDeleteUserRepositorySuite(t *testing.T) {
 
 func (d *DeleteUserRepositorySuite) TestUserRepository_DeleteUser_Success() {
 	userId := "user-123"
-	query := `DELETE FROM users WHERE id = $1`
+	query := `UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
 	d.mockPgx.ExpectExec(regexp.QuoteMeta(query)).
 		WithArgs(userId).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
-	err := d.userRepository.DeleteUser(userId)
+	err := d.userRepository.DeleteUser(context.Background(), userId)
 	d.NoError(err)
 
 }
 func (d *DeleteUserRepositorySuite) TestUserRepository_DeleteUser_UserNotFound() {
 	userId := "user-456"
-	query := `DELETE FROM users WHERE id = $1`
+	query := `UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
 	d.mockPgx.ExpectExec(regexp.QuoteMeta(query)).
 		WithArgs(userId).
 		WillReturnResult(pgxmock.NewResult("DELETE", 0))
 	d.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := d.userRepository.DeleteUser(userId)
+	err := d.userRepository.DeleteUser(context.Background(), userId)
 	d.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
 
 	time.Sleep(time.Second)
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type IsUsernameTakenRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (i *IsUsernameTakenRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	i.NoError(err)
+	i.mockPgx = pgxMock
+	i.logEmitter = mockLogEmitter
+	i.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (i *IsUsernameTakenRepositorySuite) SetupTest() {
+	i.logEmitter.ExpectedCalls = nil
+	i.logEmitter.Calls = nil
+}
+
+func TestIsUsernameTakenRepositorySuite(t *testing.T) {
+	suite.Run(t, &IsUsernameTakenRepositorySuite{})
+}
+
+const isUsernameTakenQuery = `SELECT EXISTS \( SELECT 1 FROM users WHERE LOWER\(username\) = LOWER\(\$1\) AND id <> \$2 \)`
+
+func (i *IsUsernameTakenRepositorySuite) TestUserRepository_IsUsernameTaken_Taken() {
+	i.mockPgx.ExpectQuery(isUsernameTakenQuery).
+		WithArgs("John.Doe", "user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	taken, err := i.userRepository.IsUsernameTaken(context.Background(), "John.Doe", "user-1")
+	i.NoError(err)
+	i.True(taken)
+	i.NoError(i.mockPgx.ExpectationsWereMet())
+}
+
+func (i *IsUsernameTakenRepositorySuite) TestUserRepository_IsUsernameTaken_Available() {
+	i.mockPgx.ExpectQuery(isUsernameTakenQuery).
+		WithArgs("john.doe", "user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	taken, err := i.userRepository.IsUsernameTaken(context.Background(), "john.doe", "user-1")
+	i.NoError(err)
+	i.False(taken)
+}
+
+func (i *IsUsernameTakenRepositorySuite) TestUserRepository_IsUsernameTaken_QueryError() {
+	i.mockPgx.ExpectQuery(isUsernameTakenQuery).
+		WithArgs("john.doe", "user-1").
+		WillReturnError(errors.New("connection reset"))
+	i.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	taken, err := i.userRepository.IsUsernameTaken(context.Background(), "john.doe", "user-1")
+	i.False(taken)
+	i.Equal(dto.Err_INTERNAL_FAILED_QUERY_USERNAME, err)
+
+	time.Sleep(time.Second)
+	i.logEmitter.AssertExpectations(i.T())
+}
@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListUsersRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (l *ListUsersRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	l.NoError(err)
+	l.mockPgx = pgxMock
+	l.logEmitter = mockLogEmitter
+	l.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (l *ListUsersRepositorySuite) SetupTest() {
+	l.logEmitter.ExpectedCalls = nil
+	l.logEmitter.Calls = nil
+}
+
+func TestListUsersRepositorySuite(t *testing.T) {
+	suite.Run(t, &ListUsersRepositorySuite{})
+}
+
+func (l *ListUsersRepositorySuite) TestUserRepository_ListUsers_Success() {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "verified", "two_factor_enabled", "created_at",
+	}).AddRow("user-1", "John Doe", nil, "john@example.com", true, false, createdAt)
+
+	query := `SELECT id, full_name, image, COALESCE\(email, ''\), verified, two_factor_enabled, COALESCE\(created_at, 'epoch'::timestamp\) FROM users WHERE deleted_at IS NULL ORDER BY COALESCE\(created_at, 'epoch'::timestamp\) DESC, id DESC LIMIT 21`
+	l.mockPgx.ExpectQuery(query).WillReturnRows(rows)
+
+	users, err := l.userRepository.ListUsers(context.Background(), &dto.ListUsersQuery{Limit: 21, SortBy: "created_at", SortDesc: true})
+	l.NoError(err)
+	l.Len(users, 1)
+	l.Equal("user-1", users[0].ID)
+	l.Equal(createdAt, users[0].CreatedAt)
+}
+
+func (l *ListUsersRepositorySuite) TestUserRepository_ListUsers_WithFiltersAndCursor() {
+	verified := true
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "verified", "two_factor_enabled", "created_at",
+	})
+
+	query := `SELECT id, full_name, image, COALESCE\(email, ''\), verified, two_factor_enabled, COALESCE\(created_at, 'epoch'::timestamp\) FROM users WHERE deleted_at IS NULL AND verified = \$1 AND email ILIKE \$2 AND \(COALESCE\(created_at, 'epoch'::timestamp\), id\) < \(\$3, \$4\) ORDER BY COALESCE\(created_at, 'epoch'::timestamp\) DESC, id DESC LIMIT 11`
+	l.mockPgx.ExpectQuery(query).
+		WithArgs(true, `%jo\_hn%`, createdAt, "user-1").
+		WillReturnRows(rows)
+
+	users, err := l.userRepository.ListUsers(context.Background(), &dto.ListUsersQuery{
+		Limit:    11,
+		Verified: &verified,
+		Email:    "jo_hn",
+		SortBy:   "created_at",
+		SortDesc: true,
+		After:    &dto.ListUsersCursor{SortBy: "created_at", Value: createdAt.Format(time.RFC3339Nano), ID: "user-1"},
+	})
+	l.NoError(err)
+	l.Empty(users)
+}
+
+func (l *ListUsersRepositorySuite) TestUserRepository_ListUsers_NullableSortKeyCoalesced() {
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "verified", "two_factor_enabled", "created_at",
+	})
+
+	// a user without an email sorts as '' instead of being dropped by the NULL row comparison
+	query := `SELECT id, full_name, image, COALESCE\(email, ''\), verified, two_factor_enabled, COALESCE\(created_at, 'epoch'::timestamp\) FROM users WHERE deleted_at IS NULL AND \(COALESCE\(email, ''\), id\) > \(\$1, \$2\) ORDER BY COALESCE\(email, ''\) ASC, id ASC LIMIT 11`
+	l.mockPgx.ExpectQuery(query).
+		WithArgs("", "user-1").
+		WillReturnRows(rows)
+
+	users, err := l.userRepository.ListUsers(context.Background(), &dto.ListUsersQuery{
+		Limit:  11,
+		SortBy: "email",
+		After:  &dto.ListUsersCursor{SortBy: "email", Value: "", ID: "user-1"},
+	})
+	l.NoError(err)
+	l.Empty(users)
+}
+
+func (l *ListUsersRepositorySuite) TestUserRepository_ListUsers_InvalidCursor() {
+	users, err := l.userRepository.ListUsers(context.Background(), &dto.ListUsersQuery{
+		Limit:  11,
+		SortBy: "created_at",
+		After:  &dto.ListUsersCursor{SortBy: "created_at", Value: "not-a-time", ID: "user-1"},
+	})
+	l.Nil(users)
+	l.ErrorIs(err, dto.Err_BAD_REQUEST_INVALID_CURSOR)
+}
+
+func (l *ListUsersRepositorySuite) TestUserRepository_ListUsers_QueryError() {
+	query := `SELECT id, full_name, image, COALESCE\(email, ''\), verified, two_factor_enabled, COALESCE\(created_at, 'epoch'::timestamp\) FROM users WHERE deleted_at IS NULL ORDER BY COALESCE\(email, ''\) ASC, id ASC LIMIT 21`
+	l.mockPgx.ExpectQuery(query).WillReturnError(fmt.Errorf("query execution failed"))
+	l.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	users, err := l.userRepository.ListUsers(context.Background(), &dto.ListUsersQuery{Limit: 21, SortBy: "email"})
+	l.Nil(users)
+	l.ErrorIs(err, dto.Err_INTERNAL_FAILED_LIST_USERS)
+
+	time.Sleep(time.Second)
+	l.logEmitter.AssertExpectations(l.T())
+}
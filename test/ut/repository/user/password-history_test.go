@@ -0,0 +1,147 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordHistoryRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (p *PasswordHistoryRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	p.NoError(err)
+	p.mockPgx = pgxMock
+	p.logEmitter = mockLogEmitter
+	p.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (p *PasswordHistoryRepositorySuite) SetupTest() {
+	p.logEmitter.ExpectedCalls = nil
+	p.logEmitter.Calls = nil
+}
+
+func TestPasswordHistoryRepositorySuite(t *testing.T) {
+	suite.Run(t, &PasswordHistoryRepositorySuite{})
+}
+
+const (
+	queryPasswordHistoryQuery  = `SELECT password_hash FROM password_history WHERE user_id = \$1 ORDER BY id DESC LIMIT 4`
+	updatePasswordQuery        = `UPDATE users SET password = \$1, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$2 AND deleted_at IS NULL`
+	insertPasswordHistoryQuery = `INSERT INTO password_history \(user_id,password_hash\) VALUES \(\$1,\$2\)`
+	prunePasswordHistoryQuery  = `DELETE FROM password_history WHERE user_id = \$1 AND id NOT IN \(SELECT id FROM password_history WHERE user_id = \$2 ORDER BY id DESC LIMIT \$3\)`
+)
+
+func (p *PasswordHistoryRepositorySuite) TestUserRepository_QueryPasswordHistory_Success() {
+	p.mockPgx.ExpectQuery(queryPasswordHistoryQuery).
+		WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"password_hash"}).AddRow("hash-2").AddRow("hash-1"))
+
+	hashes, err := p.userRepository.QueryPasswordHistory(context.Background(), "user-1", 4)
+	p.NoError(err)
+	p.Equal([]string{"hash-2", "hash-1"}, hashes)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+}
+
+func (p *PasswordHistoryRepositorySuite) TestUserRepository_QueryPasswordHistory_QueryError() {
+	p.mockPgx.ExpectQuery(queryPasswordHistoryQuery).
+		WithArgs("user-1").
+		WillReturnError(errors.New("connection reset"))
+	p.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_FAILED_QUERY_HISTORY.Error()).Return(nil)
+
+	_, err := p.userRepository.QueryPasswordHistory(context.Background(), "user-1", 4)
+	p.Equal(dto.Err_INTERNAL_FAILED_QUERY_HISTORY, err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
+
+func (p *PasswordHistoryRepositorySuite) TestUserRepository_UpdateUserPassword_Success() {
+	msg := &dto.OutboxMessage{Subject: "eventbus.user.user-1", Payload: []byte("payload")}
+
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectExec(updatePasswordQuery).
+		WithArgs("new-hash", "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	p.mockPgx.ExpectExec(insertPasswordHistoryQuery).
+		WithArgs("user-1", "old-hash").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	p.mockPgx.ExpectExec(prunePasswordHistoryQuery).
+		WithArgs("user-1", "user-1", 4).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	p.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(msg.Subject, msg.Payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	p.mockPgx.ExpectCommit()
+
+	err := p.userRepository.UpdateUserPassword(context.Background(), "user-1", "new-hash", "old-hash", 4, msg)
+	p.NoError(err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+}
+
+func (p *PasswordHistoryRepositorySuite) TestUserRepository_UpdateUserPassword_NoHistoryKept() {
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectExec(updatePasswordQuery).
+		WithArgs("new-hash", "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	p.mockPgx.ExpectExec(prunePasswordHistoryQuery).
+		WithArgs("user-1", "user-1", 0).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	p.mockPgx.ExpectCommit()
+
+	err := p.userRepository.UpdateUserPassword(context.Background(), "user-1", "new-hash", "old-hash", 0)
+	p.NoError(err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+}
+
+func (p *PasswordHistoryRepositorySuite) TestUserRepository_UpdateUserPassword_HistoryErrorRollsBack() {
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectExec(updatePasswordQuery).
+		WithArgs("new-hash", "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	p.mockPgx.ExpectExec(insertPasswordHistoryQuery).
+		WithArgs("user-1", "old-hash").
+		WillReturnError(errors.New("connection reset"))
+	p.mockPgx.ExpectRollback()
+	p.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_FAILED_SAVE_HISTORY.Error()).Return(nil)
+
+	err := p.userRepository.UpdateUserPassword(context.Background(), "user-1", "new-hash", "old-hash", 4)
+	p.Equal(dto.Err_INTERNAL_FAILED_SAVE_HISTORY, err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
+
+func (p *PasswordHistoryRepositorySuite) TestUserRepository_UpdateUserPassword_UserNotFound() {
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectExec(updatePasswordQuery).
+		WithArgs("new-hash", "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	p.mockPgx.ExpectRollback()
+	p.logEmitter.On("EmitLog", "ERR", "user not found. user_id: user-1").Return(nil)
+
+	err := p.userRepository.UpdateUserPassword(context.Background(), "user-1", "new-hash", "old-hash", 4)
+	p.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
@@ -0,0 +1,124 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PurgeDeletedUsersRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (p *PurgeDeletedUsersRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	p.NoError(err)
+	p.logEmitter = mockLogEmitter
+	p.mockPgx = pgxMock
+	p.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (p *PurgeDeletedUsersRepositorySuite) SetupTest() {
+	p.logEmitter.ExpectedCalls = nil
+	p.logEmitter.Calls = nil
+}
+
+func TestPurgeDeletedUsersRepositorySuite(t *testing.T) {
+	suite.Run(t, &PurgeDeletedUsersRepositorySuite{})
+}
+
+func (p *PurgeDeletedUsersRepositorySuite) TestUserRepository_PurgeDeletedUsers_Success() {
+	deletedBefore := time.Now().Add(-time.Hour)
+	query := `DELETE FROM users WHERE (deleted_at IS NOT NULL AND deleted_at <= $1) RETURNING id`
+	rows := pgxmock.NewRows([]string{"id"}).AddRow("user-1").AddRow("user-2")
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(deletedBefore).
+		WillReturnRows(rows)
+	p.mockPgx.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (subject,payload) VALUES ($1,$2),($3,$4)`)).
+		WithArgs("eventbus.user.user-1", []byte("user-1"), "eventbus.user.user-2", []byte("user-2")).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+	p.mockPgx.ExpectCommit()
+
+	userIds, err := p.userRepository.PurgeDeletedUsers(context.Background(), deletedBefore, newDeletedEvent)
+	p.NoError(err)
+	p.Equal([]string{"user-1", "user-2"}, userIds)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+}
+
+func (p *PurgeDeletedUsersRepositorySuite) TestUserRepository_PurgeDeletedUsers_NothingToPurge() {
+	deletedBefore := time.Now().Add(-time.Hour)
+	query := `DELETE FROM users WHERE (deleted_at IS NOT NULL AND deleted_at <= $1) RETURNING id`
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(deletedBefore).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}))
+	p.mockPgx.ExpectCommit()
+
+	userIds, err := p.userRepository.PurgeDeletedUsers(context.Background(), deletedBefore, newDeletedEvent)
+	p.NoError(err)
+	p.Empty(userIds)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+}
+
+func (p *PurgeDeletedUsersRepositorySuite) TestUserRepository_PurgeDeletedUsers_QueryError() {
+	deletedBefore := time.Now().Add(-time.Hour)
+	query := `DELETE FROM users WHERE (deleted_at IS NOT NULL AND deleted_at <= $1) RETURNING id`
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(deletedBefore).
+		WillReturnError(errors.New("db error"))
+	p.mockPgx.ExpectRollback()
+	p.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	userIds, err := p.userRepository.PurgeDeletedUsers(context.Background(), deletedBefore, newDeletedEvent)
+	p.Nil(userIds)
+	p.Equal(dto.Err_INTERNAL_FAILED_PURGE_USER, err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
+
+func (p *PurgeDeletedUsersRepositorySuite) TestUserRepository_PurgeDeletedUsers_OutboxError() {
+	deletedBefore := time.Now().Add(-time.Hour)
+	query := `DELETE FROM users WHERE (deleted_at IS NOT NULL AND deleted_at <= $1) RETURNING id`
+	p.mockPgx.ExpectBegin()
+	p.mockPgx.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(deletedBefore).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("user-1"))
+	p.mockPgx.ExpectExec(regexp.QuoteMeta(`INSERT INTO outbox (subject,payload) VALUES ($1,$2)`)).
+		WithArgs("eventbus.user.user-1", []byte("user-1")).
+		WillReturnError(errors.New("db error"))
+	p.mockPgx.ExpectRollback()
+	p.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	userIds, err := p.userRepository.PurgeDeletedUsers(context.Background(), deletedBefore, newDeletedEvent)
+	p.Nil(userIds)
+	p.Equal(dto.Err_INTERNAL_FAILED_INSERT_OUTBOX, err)
+	p.NoError(p.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
+
+func newDeletedEvent(userId string) (*dto.OutboxMessage, error) {
+	return &dto.OutboxMessage{Subject: "eventbus.user." + userId, Payload: []byte(userId)}, nil
+}
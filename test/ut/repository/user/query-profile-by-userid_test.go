@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryProfileByUserIdRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (q *QueryProfileByUserIdRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	q.NoError(err)
+	q.logEmitter = mockLogEmitter
+	q.mockPgx = pgxMock
+	q.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (q *QueryProfileByUserIdRepositorySuite) SetupTest() {
+	q.logEmitter.ExpectedCalls = nil
+	q.logEmitter.Calls = nil
+}
+
+func TestQueryProfileByUserIdRepositorySuite(t *testing.T) {
+	suite.Run(t, &QueryProfileByUserIdRepositorySuite{})
+}
+
+const queryProfileByUserId = `SELECT id, full_name, image, email, password, verified, two_factor_enabled, image_variants, version, username, bio, locale, timezone, phone_number, phone_verified, date_of_birth FROM users WHERE id = \$1 AND deleted_at IS NULL`
+
+func (q *QueryProfileByUserIdRepositorySuite) TestUserRepository_QueryProfileByUserId_Success() {
+	userId := "123"
+	image := "image_512.png"
+	username := "john.doe"
+	timezone := "Asia/Jakarta"
+	dateOfBirth := time.Date(1990, time.January, 31, 0, 0, 0, 0, time.UTC)
+	expected := &dto.UserProfile{
+		User: model.User{
+			ID:       userId,
+			FullName: "John Doe",
+			Image:    &image,
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+			Verified: true,
+		},
+		ImageVariants: dto.ImageVariants{"64": "image_64.png", "256": "image_256.png", "512": "image_512.png"},
+		ProfileFields: dto.ProfileFields{Username: &username, Timezone: &timezone, DateOfBirth: &dateOfBirth},
+		Version:       3,
+	}
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled", "image_variants", "version",
+		"username", "bio", "locale", "timezone", "phone_number", "phone_verified", "date_of_birth",
+	}).AddRow(
+		expected.ID,
+		expected.FullName,
+		expected.Image,
+		expected.Email,
+		expected.Password,
+		expected.Verified,
+		expected.TwoFactorEnabled,
+		expected.ImageVariants,
+		expected.Version,
+		expected.Username,
+		expected.Bio,
+		expected.Locale,
+		expected.Timezone,
+		expected.PhoneNumber,
+		expected.PhoneVerified,
+		expected.DateOfBirth,
+	)
+	q.mockPgx.ExpectQuery(queryProfileByUserId).WithArgs(userId).WillReturnRows(rows)
+
+	profile, err := q.userRepository.QueryProfileByUserId(context.Background(), userId)
+	q.NoError(err)
+	q.Equal(expected, profile)
+}
+
+func (q *QueryProfileByUserIdRepositorySuite) TestUserRepository_QueryProfileByUserId_NotFound() {
+	userId := "404"
+	q.mockPgx.ExpectQuery(queryProfileByUserId).WithArgs(userId).WillReturnError(pgx.ErrNoRows)
+	q.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	profile, err := q.userRepository.QueryProfileByUserId(context.Background(), userId)
+	q.Nil(profile)
+	q.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+
+	time.Sleep(time.Second)
+	q.logEmitter.AssertExpectations(q.T())
+}
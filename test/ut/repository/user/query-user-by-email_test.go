@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetUserByEmailRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (g *GetUserByEmailRepositorySuite) SetupSuite() {
+
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	g.NoError(err)
+	g.logEmitter = mockLogEmitter
+	g.mockPgx = pgxMock
+	g.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (g *GetUserByEmailRepositorySuite) SetupTest() {
+	g.logEmitter.ExpectedCalls = nil
+	g.logEmitter.Calls = nil
+}
+
+func TestGetUserByEmailRepositorySuite(t *testing.T) {
+	suite.Run(t, &GetUserByEmailRepositorySuite{})
+}
+
+func (g *GetUserByEmailRepositorySuite) TestUserRepository_GetUserByEmail_Success() {
+	email := "john@example.com"
+	image := "image.png"
+	expectedUser := &model.User{
+		ID:               "123",
+		FullName:         "John Doe",
+		Image:            &image,
+		Email:            email,
+		Password:         "hashedpassword",
+		Verified:         true,
+		TwoFactorEnabled: false,
+	}
+
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled",
+	}).AddRow(
+		expectedUser.ID,
+		expectedUser.FullName,
+		expectedUser.Image,
+		expectedUser.Email,
+		expectedUser.Password,
+		expectedUser.Verified,
+		expectedUser.TwoFactorEnabled,
+	)
+
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE email = \$1 AND deleted_at IS NULL`
+	g.mockPgx.ExpectQuery(query).WithArgs(email).WillReturnRows(rows)
+
+	user, err := g.userRepository.QueryUserByEmail(context.Background(), email)
+	g.NoError(err)
+	g.Equal(expectedUser, user)
+}
+
+func (g *GetUserByEmailRepositorySuite) TestUserRepository_GetUserByEmail_NotFound() {
+	email := "notfound@example.com"
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE email = \$1 AND deleted_at IS NULL`
+	g.mockPgx.ExpectQuery(query).WithArgs(email).WillReturnError(pgx.ErrNoRows)
+
+	user, err := g.userRepository.QueryUserByEmail(context.Background(), email)
+	g.Nil(user)
+	g.ErrorIs(err, dto.Err_NOTFOUND_USER_NOT_FOUND)
+}
+
+func (g *GetUserByEmailRepositorySuite) TestUserRepository_GetUserByEmail_ScanError() {
+	email := "john@example.com"
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled",
+	}).AddRow(
+		123, // should be string, but using int to cause scan error
+		"John Doe",
+		"image.png",
+		email,
+		"hashedpassword",
+		true,
+		false,
+	)
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE email = \$1 AND deleted_at IS NULL`
+	g.mockPgx.ExpectQuery(query).WithArgs(email).WillReturnRows(rows)
+	g.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	user, err := g.userRepository.QueryUserByEmail(context.Background(), email)
+	g.Nil(user)
+	g.ErrorIs(err, dto.Err_INTERNAL_FAILED_SCAN_USER)
+
+	time.Sleep(time.Second)
+	g.logEmitter.AssertExpectations(g.T())
+}
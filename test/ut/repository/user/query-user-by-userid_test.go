@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/micros-template/sharedlib/model"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -68,21 +70,21 @@ func (g *GetUserByIdRepositorySuite) TestUserRepository_GetUserById_Success() {
 		expectedUser.TwoFactorEnabled,
 	)
 
-	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1`
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1 AND deleted_at IS NULL`
 	g.mockPgx.ExpectQuery(query).WithArgs(userId).WillReturnRows(rows)
 
-	user, err := g.userRepository.QueryUserByUserId(userId)
+	user, err := g.userRepository.QueryUserByUserId(context.Background(), userId)
 	g.NoError(err)
 	g.Equal(expectedUser, user)
 }
 
 func (g *GetUserByIdRepositorySuite) TestAuthRepository_GetUserById_NotFound() {
 	userId := "notfound"
-	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1`
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1 AND deleted_at IS NULL`
 	g.mockPgx.ExpectQuery(query).WithArgs(userId).WillReturnError(pgx.ErrNoRows)
 	g.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
 
-	user, err := g.userRepository.QueryUserByUserId(userId)
+	user, err := g.userRepository.QueryUserByUserId(context.Background(), userId)
 	g.Nil(user)
 	g.ErrorIs(err, dto.Err_NOTFOUND_USER_NOT_FOUND)
 
@@ -103,11 +105,51 @@ func (g *GetUserByIdRepositorySuite) TestAuthRepository_GetUserById_ScanError()
 		true,
 		false,
 	)
-	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1`
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1 AND deleted_at IS NULL`
 	g.mockPgx.ExpectQuery(query).WithArgs(userId).WillReturnRows(rows)
 	g.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	user, err := g.userRepository.QueryUserByUserId(userId)
+	user, err := g.userRepository.QueryUserByUserId(context.Background(), userId)
+	g.Nil(user)
+	g.ErrorIs(err, dto.Err_INTERNAL_FAILED_SCAN_USER)
+
+	time.Sleep(time.Second)
+	g.logEmitter.AssertExpectations(g.T())
+}
+
+func (g *GetUserByIdRepositorySuite) TestUserRepository_GetUserById_DatabaseTimeout() {
+	viper.Set("app.timeout.database", "50ms")
+	defer viper.Reset()
+
+	userId := "123"
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1 AND deleted_at IS NULL`
+	g.mockPgx.ExpectQuery(query).WithArgs(userId).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled"})).
+		WillDelayFor(time.Second)
+	g.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	start := time.Now()
+	user, err := g.userRepository.QueryUserByUserId(context.Background(), userId)
+	g.Nil(user)
+	g.ErrorIs(err, dto.Err_INTERNAL_FAILED_SCAN_USER)
+	g.Less(time.Since(start), 500*time.Millisecond)
+
+	time.Sleep(time.Second)
+	g.logEmitter.AssertExpectations(g.T())
+}
+
+func (g *GetUserByIdRepositorySuite) TestUserRepository_GetUserById_CanceledContext() {
+	userId := "123"
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = \$1 AND deleted_at IS NULL`
+	g.mockPgx.ExpectQuery(query).WithArgs(userId).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled"})).
+		WillDelayFor(time.Second)
+	g.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	// a client disconnect cancels the request context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	user, err := g.userRepository.QueryUserByUserId(ctx, userId)
 	g.Nil(user)
 	g.ErrorIs(err, dto.Err_INTERNAL_FAILED_SCAN_USER)
 
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type QueryUsersByIdsRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (q *QueryUsersByIdsRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	q.NoError(err)
+	q.mockPgx = pgxMock
+	q.logEmitter = mockLogEmitter
+	q.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (q *QueryUsersByIdsRepositorySuite) SetupTest() {
+	q.logEmitter.ExpectedCalls = nil
+	q.logEmitter.Calls = nil
+}
+
+func TestQueryUsersByIdsRepositorySuite(t *testing.T) {
+	suite.Run(t, &QueryUsersByIdsRepositorySuite{})
+}
+
+func (q *QueryUsersByIdsRepositorySuite) TestUserRepository_QueryUsersByIds_Success() {
+	ids := []string{"user-1", "user-2"}
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled",
+	}).
+		AddRow("user-1", "John Doe", nil, "john@example.com", "hash-1", true, false).
+		AddRow("user-2", "Jane Doe", nil, "jane@example.com", "hash-2", false, false)
+
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = ANY\(\$1\) AND deleted_at IS NULL`
+	q.mockPgx.ExpectQuery(query).WithArgs(ids).WillReturnRows(rows)
+
+	users, err := q.userRepository.QueryUsersByIds(context.Background(), ids)
+	q.NoError(err)
+	q.Len(users, 2)
+	q.Equal("user-2", users[1].ID)
+}
+
+func (q *QueryUsersByIdsRepositorySuite) TestUserRepository_QueryUsersByIds_QueryError() {
+	ids := []string{"user-1"}
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = ANY\(\$1\) AND deleted_at IS NULL`
+	q.mockPgx.ExpectQuery(query).WithArgs(ids).WillReturnError(fmt.Errorf("query execution failed"))
+	q.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	users, err := q.userRepository.QueryUsersByIds(context.Background(), ids)
+	q.Nil(users)
+	q.ErrorIs(err, dto.Err_INTERNAL_FAILED_QUERY_USERS)
+
+	time.Sleep(time.Second)
+	q.logEmitter.AssertExpectations(q.T())
+}
+
+func (q *QueryUsersByIdsRepositorySuite) TestUserRepository_QueryUsersByIds_ScanError() {
+	ids := []string{"user-1"}
+	rows := pgxmock.NewRows([]string{
+		"id", "full_name", "image", "email", "password", "verified", "two_factor_enabled",
+	}).AddRow("user-1", "John Doe", nil, "john@example.com", "hash-1", "not-a-bool", false)
+
+	query := `SELECT id, full_name, image, email, password, verified, two_factor_enabled FROM users WHERE id = ANY\(\$1\) AND deleted_at IS NULL`
+	q.mockPgx.ExpectQuery(query).WithArgs(ids).WillReturnRows(rows)
+	q.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	users, err := q.userRepository.QueryUsersByIds(context.Background(), ids)
+	q.Nil(users)
+	q.ErrorIs(err, dto.Err_INTERNAL_FAILED_SCAN_USER)
+
+	time.Sleep(time.Second)
+	q.logEmitter.AssertExpectations(q.T())
+}
@@ -0,0 +1,145 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecoveryCodeRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (r *RecoveryCodeRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	r.NoError(err)
+	r.mockPgx = pgxMock
+	r.logEmitter = mockLogEmitter
+	r.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (r *RecoveryCodeRepositorySuite) SetupTest() {
+	r.logEmitter.ExpectedCalls = nil
+	r.logEmitter.Calls = nil
+}
+
+func TestRecoveryCodeRepositorySuite(t *testing.T) {
+	suite.Run(t, &RecoveryCodeRepositorySuite{})
+}
+
+const (
+	queryRecoveryCodesQuery = `SELECT id, code_hash FROM recovery_codes WHERE user_id = \$1 AND used_at IS NULL ORDER BY id`
+	countRecoveryCodesQuery = `SELECT COUNT\(\*\) FROM recovery_codes WHERE user_id = \$1 AND used_at IS NULL`
+	useRecoveryCodeQuery    = `UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE id = \$1 AND used_at IS NULL`
+)
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_QueryRecoveryCodes_Success() {
+	r.mockPgx.ExpectQuery(queryRecoveryCodesQuery).
+		WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "code_hash"}).
+			AddRow(int64(1), "hash-1").
+			AddRow(int64(2), "hash-2"))
+
+	codes, err := r.userRepository.QueryRecoveryCodes(context.Background(), "user-1")
+	r.NoError(err)
+	r.Equal([]dto.RecoveryCode{{ID: 1, CodeHash: "hash-1"}, {ID: 2, CodeHash: "hash-2"}}, codes)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+}
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_QueryRecoveryCodes_QueryError() {
+	r.mockPgx.ExpectQuery(queryRecoveryCodesQuery).
+		WithArgs("user-1").
+		WillReturnError(errors.New("connection reset"))
+	r.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_FAILED_QUERY_RECOVERY.Error()).Return(nil)
+
+	_, err := r.userRepository.QueryRecoveryCodes(context.Background(), "user-1")
+	r.Equal(dto.Err_INTERNAL_FAILED_QUERY_RECOVERY, err)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_CountRecoveryCodes_Success() {
+	r.mockPgx.ExpectQuery(countRecoveryCodesQuery).
+		WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(8))
+
+	remaining, err := r.userRepository.CountRecoveryCodes(context.Background(), "user-1")
+	r.NoError(err)
+	r.Equal(8, remaining)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+}
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_ReplaceRecoveryCodes_Success() {
+	r.mockPgx.ExpectBegin()
+	r.mockPgx.ExpectExec(`DELETE FROM recovery_codes WHERE user_id = \$1`).
+		WithArgs("user-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	r.mockPgx.ExpectExec(`INSERT INTO recovery_codes \(user_id,code_hash\) VALUES \(\$1,\$2\)`).
+		WithArgs("user-1", "hash-1").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	r.mockPgx.ExpectCommit()
+
+	err := r.userRepository.ReplaceRecoveryCodes(context.Background(), "user-1", []string{"hash-1"})
+	r.NoError(err)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+}
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_ReplaceRecoveryCodes_InsertError() {
+	r.mockPgx.ExpectBegin()
+	r.mockPgx.ExpectExec(`DELETE FROM recovery_codes WHERE user_id = \$1`).
+		WithArgs("user-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	r.mockPgx.ExpectExec(`INSERT INTO recovery_codes \(user_id,code_hash\) VALUES \(\$1,\$2\)`).
+		WithArgs("user-1", "hash-1").
+		WillReturnError(errors.New("connection reset"))
+	r.mockPgx.ExpectRollback()
+	r.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_FAILED_SAVE_RECOVERY.Error()).Return(nil)
+
+	err := r.userRepository.ReplaceRecoveryCodes(context.Background(), "user-1", []string{"hash-1"})
+	r.Equal(dto.Err_INTERNAL_FAILED_SAVE_RECOVERY, err)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_UseRecoveryCode_Success() {
+	r.mockPgx.ExpectExec(useRecoveryCodeQuery).
+		WithArgs(int64(1)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := r.userRepository.UseRecoveryCode(context.Background(), 1)
+	r.NoError(err)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+}
+
+func (r *RecoveryCodeRepositorySuite) TestUserRepository_UseRecoveryCode_AlreadyUsed() {
+	r.mockPgx.ExpectExec(useRecoveryCodeQuery).
+		WithArgs(int64(1)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	r.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := r.userRepository.UseRecoveryCode(context.Background(), 1)
+	r.Equal(dto.Err_UNAUTHORIZED_RECOVERY_INVALID, err)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
@@ -0,0 +1,90 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RestoreUserRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (r *RestoreUserRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	r.NoError(err)
+	r.logEmitter = mockLogEmitter
+	r.mockPgx = pgxMock
+	r.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (r *RestoreUserRepositorySuite) SetupTest() {
+	r.logEmitter.ExpectedCalls = nil
+	r.logEmitter.Calls = nil
+}
+
+func TestRestoreUserRepositorySuite(t *testing.T) {
+	suite.Run(t, &RestoreUserRepositorySuite{})
+}
+
+func (r *RestoreUserRepositorySuite) TestUserRepository_RestoreUser_Success() {
+	userId := "user-123"
+	deletedAfter := time.Now().Add(-time.Hour)
+	query := `UPDATE users SET deleted_at = $1, updated_at = CURRENT_TIMESTAMP WHERE (id = $2 AND deleted_at IS NOT NULL AND deleted_at > $3)`
+	r.mockPgx.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(nil, userId, deletedAfter).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := r.userRepository.RestoreUser(context.Background(), userId, deletedAfter)
+	r.NoError(err)
+	r.NoError(r.mockPgx.ExpectationsWereMet())
+}
+
+func (r *RestoreUserRepositorySuite) TestUserRepository_RestoreUser_WindowExpired() {
+	userId := "user-456"
+	deletedAfter := time.Now().Add(-time.Hour)
+	query := `UPDATE users SET deleted_at = $1, updated_at = CURRENT_TIMESTAMP WHERE (id = $2 AND deleted_at IS NOT NULL AND deleted_at > $3)`
+	r.mockPgx.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(nil, userId, deletedAfter).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	r.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := r.userRepository.RestoreUser(context.Background(), userId, deletedAfter)
+	r.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RestoreUserRepositorySuite) TestUserRepository_RestoreUser_ExecError() {
+	userId := "user-789"
+	deletedAfter := time.Now().Add(-time.Hour)
+	query := `UPDATE users SET deleted_at = $1, updated_at = CURRENT_TIMESTAMP WHERE (id = $2 AND deleted_at IS NOT NULL AND deleted_at > $3)`
+	r.mockPgx.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(nil, userId, deletedAfter).
+		WillReturnError(errors.New("db error"))
+	r.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := r.userRepository.RestoreUser(context.Background(), userId, deletedAfter)
+	r.Equal(dto.Err_INTERNAL_FAILED_RESTORE_USER, err)
+
+	time.Sleep(time.Second)
+	r.logEmitter.AssertExpectations(r.T())
+}
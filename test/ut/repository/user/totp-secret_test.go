@@ -0,0 +1,180 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPSecretRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (t *TOTPSecretRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	t.NoError(err)
+	t.mockPgx = pgxMock
+	t.logEmitter = mockLogEmitter
+	t.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (t *TOTPSecretRepositorySuite) SetupTest() {
+	t.logEmitter.ExpectedCalls = nil
+	t.logEmitter.Calls = nil
+}
+
+func TestTOTPSecretRepositorySuite(t *testing.T) {
+	suite.Run(t, &TOTPSecretRepositorySuite{})
+}
+
+const (
+	queryTOTPSecretQuery     = `SELECT totp_secret FROM users WHERE id = \$1 AND deleted_at IS NULL`
+	updateTOTPSecretQuery    = `UPDATE users SET totp_secret = \$1, two_factor_enabled = \$2, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$3 AND deleted_at IS NULL`
+	deleteRecoveryCodesQuery = `DELETE FROM recovery_codes WHERE user_id = \$1`
+)
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_QueryTOTPSecret_Success() {
+	sealed := []byte("sealed-secret")
+	t.mockPgx.ExpectQuery(queryTOTPSecretQuery).
+		WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"totp_secret"}).AddRow(sealed))
+
+	secret, err := t.userRepository.QueryTOTPSecret(context.Background(), "user-1")
+	t.NoError(err)
+	t.Equal(sealed, secret)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_QueryTOTPSecret_NotEnrolled() {
+	t.mockPgx.ExpectQuery(queryTOTPSecretQuery).
+		WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"totp_secret"}).AddRow(nil))
+
+	secret, err := t.userRepository.QueryTOTPSecret(context.Background(), "user-1")
+	t.NoError(err)
+	t.Nil(secret)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_QueryTOTPSecret_NotFound() {
+	t.mockPgx.ExpectQuery(queryTOTPSecretQuery).
+		WithArgs("user-1").
+		WillReturnError(pgx.ErrNoRows)
+	t.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	_, err := t.userRepository.QueryTOTPSecret(context.Background(), "user-1")
+	t.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	t.logEmitter.AssertExpectations(t.T())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_UpdateTOTPSecret_Enable() {
+	sealed := []byte("sealed-secret")
+	codeHashes := []string{"hash-1", "hash-2"}
+	event := &dto.OutboxMessage{Subject: "eventbus.user.updated", Payload: []byte("event")}
+
+	t.mockPgx.ExpectBegin()
+	t.mockPgx.ExpectExec(updateTOTPSecretQuery).
+		WithArgs(sealed, true, "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	t.mockPgx.ExpectExec(deleteRecoveryCodesQuery).
+		WithArgs("user-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	t.mockPgx.ExpectExec(`INSERT INTO recovery_codes \(user_id,code_hash\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+		WithArgs("user-1", "hash-1", "user-1", "hash-2").
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+	t.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(event.Subject, event.Payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	t.mockPgx.ExpectCommit()
+
+	err := t.userRepository.UpdateTOTPSecret(context.Background(), "user-1", sealed, codeHashes, event)
+	t.NoError(err)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_UpdateTOTPSecret_Disable() {
+	t.mockPgx.ExpectBegin()
+	t.mockPgx.ExpectExec(updateTOTPSecretQuery).
+		WithArgs([]byte(nil), false, "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	t.mockPgx.ExpectExec(deleteRecoveryCodesQuery).
+		WithArgs("user-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 10))
+	t.mockPgx.ExpectCommit()
+
+	err := t.userRepository.UpdateTOTPSecret(context.Background(), "user-1", nil, nil)
+	t.NoError(err)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_UpdateTOTPSecret_NotFound() {
+	t.mockPgx.ExpectBegin()
+	t.mockPgx.ExpectExec(updateTOTPSecretQuery).
+		WithArgs([]byte(nil), false, "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	t.mockPgx.ExpectRollback()
+	t.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := t.userRepository.UpdateTOTPSecret(context.Background(), "user-1", nil, nil)
+	t.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	t.logEmitter.AssertExpectations(t.T())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_UpdateTOTPSecret_ExecError() {
+	t.mockPgx.ExpectBegin()
+	t.mockPgx.ExpectExec(updateTOTPSecretQuery).
+		WithArgs([]byte(nil), false, "user-1").
+		WillReturnError(errors.New("connection reset"))
+	t.mockPgx.ExpectRollback()
+	t.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_FAILED_UPDATE_USER.Error()).Return(nil)
+
+	err := t.userRepository.UpdateTOTPSecret(context.Background(), "user-1", nil, nil)
+	t.Equal(dto.Err_INTERNAL_FAILED_UPDATE_USER, err)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	t.logEmitter.AssertExpectations(t.T())
+}
+
+func (t *TOTPSecretRepositorySuite) TestUserRepository_UpdateTOTPSecret_RecoveryCodesError() {
+	sealed := []byte("sealed-secret")
+
+	t.mockPgx.ExpectBegin()
+	t.mockPgx.ExpectExec(updateTOTPSecretQuery).
+		WithArgs(sealed, true, "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	t.mockPgx.ExpectExec(deleteRecoveryCodesQuery).
+		WithArgs("user-1").
+		WillReturnError(errors.New("connection reset"))
+	t.mockPgx.ExpectRollback()
+	t.logEmitter.On("EmitLog", "ERR", dto.Err_INTERNAL_FAILED_SAVE_RECOVERY.Error()).Return(nil)
+
+	err := t.userRepository.UpdateTOTPSecret(context.Background(), "user-1", sealed, []string{"hash-1"})
+	t.Equal(dto.Err_INTERNAL_FAILED_SAVE_RECOVERY, err)
+	t.NoError(t.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	t.logEmitter.AssertExpectations(t.T())
+}
@@ -0,0 +1,130 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateProfileRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (u *UpdateProfileRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	u.NoError(err)
+	u.mockPgx = pgxMock
+	u.logEmitter = mockLogEmitter
+	u.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (u *UpdateProfileRepositorySuite) SetupTest() {
+	u.logEmitter.ExpectedCalls = nil
+	u.logEmitter.Calls = nil
+}
+
+func TestUpdateProfileRepositorySuite(t *testing.T) {
+	suite.Run(t, &UpdateProfileRepositorySuite{})
+}
+
+const updateProfileQuery = `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, username = \$6, bio = \$7, locale = \$8, timezone = \$9, phone_number = \$10, phone_verified = \$11, date_of_birth = \$12, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$13 AND deleted_at IS NULL AND version = \$14`
+
+func newProfileFields() dto.ProfileFields {
+	username := "john.doe"
+	locale := "en-US"
+	timezone := "Asia/Jakarta"
+	dateOfBirth := time.Date(1990, time.January, 31, 0, 0, 0, 0, time.UTC)
+	return dto.ProfileFields{Username: &username, Locale: &locale, Timezone: &timezone, DateOfBirth: &dateOfBirth}
+}
+
+func (u *UpdateProfileRepositorySuite) TestUserRepository_UpdateProfile_Success() {
+	profile := &dto.UserProfile{
+		User:          model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com", Password: "hash"},
+		ProfileFields: newProfileFields(),
+	}
+	event := &dto.OutboxMessage{Subject: "eventbus.user.updated", Payload: []byte("event")}
+
+	u.mockPgx.ExpectBegin()
+	u.mockPgx.ExpectExec(updateProfileQuery).
+		WithArgs(profile.FullName, profile.Image, profile.Email, profile.Password, profile.Verified,
+			profile.Username, profile.Bio, profile.Locale, profile.Timezone, profile.PhoneNumber, profile.PhoneVerified, profile.DateOfBirth, profile.ID, int64(2)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	u.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(event.Subject, event.Payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	u.mockPgx.ExpectCommit()
+
+	err := u.userRepository.UpdateProfile(context.Background(), profile, 2, event)
+	u.NoError(err)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+}
+
+func (u *UpdateProfileRepositorySuite) TestUserRepository_UpdateProfile_WithImageVariants() {
+	image := "new_512.jpg"
+	profile := &dto.UserProfile{
+		User:          model.User{ID: "user-1", FullName: "John Doe", Image: &image, Email: "john@example.com", Password: "hash"},
+		ImageVariants: dto.ImageVariants{"512": "new_512.jpg"},
+	}
+
+	u.mockPgx.ExpectExec(`UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, image_variants = \$6, username = \$7, bio = \$8, locale = \$9, timezone = \$10, phone_number = \$11, phone_verified = \$12, date_of_birth = \$13, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$14 AND deleted_at IS NULL`).
+		WithArgs(profile.FullName, profile.Image, profile.Email, profile.Password, profile.Verified, profile.ImageVariants,
+			profile.Username, profile.Bio, profile.Locale, profile.Timezone, profile.PhoneNumber, profile.PhoneVerified, profile.DateOfBirth, profile.ID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := u.userRepository.UpdateProfile(context.Background(), profile, 0)
+	u.NoError(err)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+}
+
+func (u *UpdateProfileRepositorySuite) TestUserRepository_UpdateProfile_UsernameTaken() {
+	profile := &dto.UserProfile{
+		User:          model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com", Password: "hash"},
+		ProfileFields: newProfileFields(),
+	}
+
+	u.mockPgx.ExpectExec(updateProfileQuery).
+		WithArgs(profile.FullName, profile.Image, profile.Email, profile.Password, profile.Verified,
+			profile.Username, profile.Bio, profile.Locale, profile.Timezone, profile.PhoneNumber, profile.PhoneVerified, profile.DateOfBirth, profile.ID, int64(2)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "users_username_key"})
+	u.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := u.userRepository.UpdateProfile(context.Background(), profile, 2)
+	u.Equal(dto.Err_CONFLICT_USERNAME_EXIST, err)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdateProfileRepositorySuite) TestUserRepository_UpdateProfile_EmailTaken() {
+	profile := &dto.UserProfile{
+		User: model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com", Password: "hash"},
+	}
+
+	u.mockPgx.ExpectExec(updateProfileQuery).
+		WithArgs(profile.FullName, profile.Image, profile.Email, profile.Password, profile.Verified,
+			profile.Username, profile.Bio, profile.Locale, profile.Timezone, profile.PhoneNumber, profile.PhoneVerified, profile.DateOfBirth, profile.ID, int64(2)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"})
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := u.userRepository.UpdateProfile(context.Background(), profile, 2)
+	u.Equal(dto.Err_CONFLICT_EMAIL_EXIST, err)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
@@ -0,0 +1,95 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateUserImageRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (u *UpdateUserImageRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	u.NoError(err)
+	u.mockPgx = pgxMock
+	u.logEmitter = mockLogEmitter
+	u.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (u *UpdateUserImageRepositorySuite) SetupTest() {
+	u.logEmitter.ExpectedCalls = nil
+	u.logEmitter.Calls = nil
+}
+
+func TestUpdateUserImageRepositorySuite(t *testing.T) {
+	suite.Run(t, &UpdateUserImageRepositorySuite{})
+}
+
+const updateUserImageQuery = `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, image_variants = \$6, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$7 AND deleted_at IS NULL`
+
+func (u *UpdateUserImageRepositorySuite) TestUserRepository_UpdateUserImage_Success() {
+	image := "new_512.jpg"
+	user := &model.User{ID: "user-1", FullName: "John Doe", Image: &image, Email: "john@example.com", Password: "hash"}
+	variants := dto.ImageVariants{"64": "new_64.jpg", "256": "new_256.jpg", "512": "new_512.jpg"}
+
+	u.mockPgx.ExpectExec(updateUserImageQuery).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, variants, user.ID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := u.userRepository.UpdateUserImage(context.Background(), user, variants, 0)
+	u.NoError(err)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+}
+
+func (u *UpdateUserImageRepositorySuite) TestUserRepository_UpdateUserImage_WithOutbox() {
+	image := "new_512.jpg"
+	user := &model.User{ID: "user-1", FullName: "John Doe", Image: &image, Email: "john@example.com", Password: "hash"}
+	variants := dto.ImageVariants{"512": "new_512.jpg"}
+	event := &dto.OutboxMessage{Subject: "eventbus.user.updated", Payload: []byte("event")}
+
+	u.mockPgx.ExpectBegin()
+	u.mockPgx.ExpectExec(updateUserImageQuery).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, variants, user.ID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	u.mockPgx.ExpectExec(`INSERT INTO outbox \(subject,payload\) VALUES \(\$1,\$2\)`).
+		WithArgs(event.Subject, event.Payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	u.mockPgx.ExpectCommit()
+
+	err := u.userRepository.UpdateUserImage(context.Background(), user, variants, 0, event)
+	u.NoError(err)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+}
+
+func (u *UpdateUserImageRepositorySuite) TestUserRepository_UpdateUserImage_NotFound() {
+	user := &model.User{ID: "user-404", FullName: "John Doe", Email: "john@example.com", Password: "hash"}
+	variants := dto.ImageVariants{}
+
+	u.mockPgx.ExpectExec(updateUserImageQuery).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, variants, user.ID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := u.userRepository.UpdateUserImage(context.Background(), user, nil, 0)
+	u.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/micros-template/user-service/internal/domain/repository"
 	mk "github.com/micros-template/user-service/test/mocks"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/micros-template/sharedlib/model"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/rs/zerolog"
@@ -55,12 +57,12 @@ func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_Success() {
 		TwoFactorEnabled: true,
 	}
 
-	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, two_factor_enabled = \$6, updated_at = CURRENT_TIMESTAMP WHERE id = \$7`
+	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$6 AND deleted_at IS NULL`
 	u.mockPgx.ExpectExec(updateQuery).
-		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled, user.ID).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.ID).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-	err := u.userRepository.UpdateUser(user)
+	err := u.userRepository.UpdateUser(context.Background(), user, 0)
 	u.NoError(err)
 }
 
@@ -76,19 +78,48 @@ func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_NotFound() {
 		TwoFactorEnabled: false,
 	}
 
-	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, two_factor_enabled = \$6, updated_at = CURRENT_TIMESTAMP WHERE id = \$7`
+	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$6 AND deleted_at IS NULL`
 	u.mockPgx.ExpectExec(updateQuery).
-		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled, user.ID).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.ID).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userRepository.UpdateUser(user)
+	err := u.userRepository.UpdateUser(context.Background(), user, 0)
 	u.ErrorIs(err, dto.Err_NOTFOUND_USER_NOT_FOUND)
 
 	time.Sleep(time.Second)
 	u.logEmitter.AssertExpectations(u.T())
 }
 
+func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_ExpectedVersion() {
+	user := &model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com", Password: "hash"}
+
+	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$6 AND deleted_at IS NULL AND version = \$7`
+	u.mockPgx.ExpectExec(updateQuery).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.ID, int64(3)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := u.userRepository.UpdateUser(context.Background(), user, 3)
+	u.NoError(err)
+	u.NoError(u.mockPgx.ExpectationsWereMet())
+}
+
+func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_VersionMismatch() {
+	user := &model.User{ID: "user-1", FullName: "John Doe", Email: "john@example.com", Password: "hash"}
+
+	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$6 AND deleted_at IS NULL AND version = \$7`
+	u.mockPgx.ExpectExec(updateQuery).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.ID, int64(3)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	u.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := u.userRepository.UpdateUser(context.Background(), user, 3)
+	u.ErrorIs(err, dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
 func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_QueryError() {
 	email := "query-error@example.com"
 	user := &model.User{
@@ -101,15 +132,40 @@ func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_QueryError() {
 		TwoFactorEnabled: false,
 	}
 
-	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, two_factor_enabled = \$6, updated_at = CURRENT_TIMESTAMP WHERE id = \$7`
+	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$6 AND deleted_at IS NULL`
 	u.mockPgx.ExpectExec(updateQuery).
-		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.TwoFactorEnabled, user.ID).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.ID).
 		WillReturnError(fmt.Errorf("query execution failed"))
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userRepository.UpdateUser(user)
+	err := u.userRepository.UpdateUser(context.Background(), user, 0)
 	u.ErrorIs(err, dto.Err_INTERNAL_FAILED_UPDATE_USER)
 
 	time.Sleep(time.Second)
 	u.logEmitter.AssertExpectations(u.T())
 }
+
+func (u *UpdateUserRepositorySuite) TestUserRepository_UpdateUser_EmailConflict() {
+	email := "taken@example.com"
+	user := &model.User{
+		ID:               "user-123",
+		FullName:         "conflict_user",
+		Image:            nil,
+		Email:            email,
+		Password:         "password",
+		Verified:         true,
+		TwoFactorEnabled: false,
+	}
+
+	updateQuery := `UPDATE users SET full_name = \$1, image = \$2, email = \$3, password = \$4, verified = \$5, updated_at = CURRENT_TIMESTAMP, version = version \+ 1 WHERE id = \$6 AND deleted_at IS NULL`
+	u.mockPgx.ExpectExec(updateQuery).
+		WithArgs(user.FullName, user.Image, user.Email, user.Password, user.Verified, user.ID).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := u.userRepository.UpdateUser(context.Background(), user, 0)
+	u.ErrorIs(err, dto.Err_CONFLICT_EMAIL_EXIST)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
@@ -0,0 +1,134 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/repository"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebAuthnCredentialRepositorySuite struct {
+	suite.Suite
+	userRepository repository.UserRepository
+	mockPgx        pgxmock.PgxPoolIface
+	logEmitter     *mk.LoggerInfraMock
+}
+
+func (w *WebAuthnCredentialRepositorySuite) SetupSuite() {
+	logger := zerolog.Nop()
+	pgxMock, err := pgxmock.NewPool()
+	mockLogEmitter := new(mk.LoggerInfraMock)
+	w.NoError(err)
+	w.mockPgx = pgxMock
+	w.logEmitter = mockLogEmitter
+	w.userRepository = repository.NewUserRepository(pgxMock, mockLogEmitter, logger)
+}
+
+func (w *WebAuthnCredentialRepositorySuite) SetupTest() {
+	w.logEmitter.ExpectedCalls = nil
+	w.logEmitter.Calls = nil
+}
+
+func TestWebAuthnCredentialRepositorySuite(t *testing.T) {
+	suite.Run(t, &WebAuthnCredentialRepositorySuite{})
+}
+
+const (
+	queryWebAuthnCredentialsQuery = `SELECT id, user_id, credential_id, name, public_key, attestation_type, aaguid, transports, sign_count, clone_warning, backup_eligible, backup_state, created_at, last_used_at FROM webauthn_credentials WHERE user_id = \$1 ORDER BY id`
+	insertWebAuthnCredentialQuery = `INSERT INTO webauthn_credentials \(user_id,credential_id,name,public_key,attestation_type,aaguid,transports,sign_count,backup_eligible,backup_state\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10\) RETURNING id, created_at`
+	renameWebAuthnCredentialQuery = `UPDATE webauthn_credentials SET name = \$1 WHERE id = \$2 AND user_id = \$3`
+	deleteWebAuthnCredentialQuery = `DELETE FROM webauthn_credentials WHERE id = \$1 AND user_id = \$2`
+	updateWebAuthnUsageQuery      = `UPDATE webauthn_credentials SET sign_count = GREATEST\(sign_count, \$1\), clone_warning = clone_warning OR \$2, backup_state = \$3, last_used_at = CURRENT_TIMESTAMP WHERE credential_id = \$4 AND user_id = \$5`
+)
+
+func (w *WebAuthnCredentialRepositorySuite) TestUserRepository_QueryWebAuthnCredentials_Success() {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.mockPgx.ExpectQuery(queryWebAuthnCredentialsQuery).
+		WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "credential_id", "name", "public_key", "attestation_type", "aaguid", "transports",
+			"sign_count", "clone_warning", "backup_eligible", "backup_state", "created_at", "last_used_at"}).
+			AddRow(int64(1), "user-1", []byte("credential"), "Laptop", []byte("public-key"), "none", []byte(nil), []string{"internal"},
+				int64(3), false, true, true, createdAt, (*time.Time)(nil)))
+
+	credentials, err := w.userRepository.QueryWebAuthnCredentials(context.Background(), "user-1")
+	w.NoError(err)
+	w.Equal([]dto.WebAuthnCredential{{
+		ID: 1, UserID: "user-1", CredentialID: []byte("credential"), Name: "Laptop", PublicKey: []byte("public-key"),
+		AttestationType: "none", Transports: []string{"internal"}, SignCount: 3, BackupEligible: true, BackupState: true, CreatedAt: createdAt,
+	}}, credentials)
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+}
+
+func (w *WebAuthnCredentialRepositorySuite) TestUserRepository_CreateWebAuthnCredential_Success() {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	credential := &dto.WebAuthnCredential{UserID: "user-1", CredentialID: []byte("credential"), Name: "Laptop", PublicKey: []byte("public-key"), AttestationType: "none"}
+	w.mockPgx.ExpectQuery(insertWebAuthnCredentialQuery).
+		WithArgs("user-1", []byte("credential"), "Laptop", []byte("public-key"), "none", []byte(nil), []string{}, int64(0), false, false).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), createdAt))
+
+	err := w.userRepository.CreateWebAuthnCredential(context.Background(), credential)
+	w.NoError(err)
+	w.Equal(int64(7), credential.ID)
+	w.Equal(createdAt, credential.CreatedAt)
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+}
+
+func (w *WebAuthnCredentialRepositorySuite) TestUserRepository_CreateWebAuthnCredential_AlreadyRegistered() {
+	credential := &dto.WebAuthnCredential{UserID: "user-1", CredentialID: []byte("credential"), Name: "Laptop", PublicKey: []byte("public-key"), AttestationType: "none"}
+	w.mockPgx.ExpectQuery(insertWebAuthnCredentialQuery).
+		WithArgs("user-1", []byte("credential"), "Laptop", []byte("public-key"), "none", []byte(nil), []string{}, int64(0), false, false).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "webauthn_credentials_credential_id_key"})
+	w.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := w.userRepository.CreateWebAuthnCredential(context.Background(), credential)
+	w.Equal(dto.Err_CONFLICT_PASSKEY_EXIST, err)
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	w.logEmitter.AssertExpectations(w.T())
+}
+
+func (w *WebAuthnCredentialRepositorySuite) TestUserRepository_RenameWebAuthnCredential_Success() {
+	w.mockPgx.ExpectExec(renameWebAuthnCredentialQuery).
+		WithArgs("YubiKey", int64(1), "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := w.userRepository.RenameWebAuthnCredential(context.Background(), "user-1", 1, "YubiKey")
+	w.NoError(err)
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+}
+
+func (w *WebAuthnCredentialRepositorySuite) TestUserRepository_DeleteWebAuthnCredential_NotFound() {
+	w.mockPgx.ExpectExec(deleteWebAuthnCredentialQuery).
+		WithArgs(int64(1), "user-1").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	w.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := w.userRepository.DeleteWebAuthnCredential(context.Background(), "user-1", 1)
+	w.Equal(dto.Err_NOTFOUND_PASSKEY, err)
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+
+	time.Sleep(time.Second)
+	w.logEmitter.AssertExpectations(w.T())
+}
+
+func (w *WebAuthnCredentialRepositorySuite) TestUserRepository_UpdateWebAuthnCredentialUsage_Success() {
+	w.mockPgx.ExpectExec(updateWebAuthnUsageQuery).
+		WithArgs(int64(4), false, true, []byte("credential"), "user-1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	err := w.userRepository.UpdateWebAuthnCredentialUsage(context.Background(), &dto.WebAuthnCredentialUsage{
+		UserID: "user-1", CredentialID: []byte("credential"), SignCount: 4, BackupState: true,
+	})
+	w.NoError(err)
+	w.NoError(w.mockPgx.ExpectationsWereMet())
+}
@@ -1,10 +1,11 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
-	"time"
 
+	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/service"
 	"github.com/micros-template/user-service/test/mocks"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/micros-template/sharedlib/model"
 	"github.com/micros-template/sharedlib/utils"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -20,25 +22,26 @@ type CreateUserServiceSuite struct {
 	suite.Suite
 	authService    service.AuthService
 	userRepository *mocks.UserRepositoryMock
-	eventEmitter   *mocks.EmitterMock
+	profileCache   *mocks.ProfileCacheRepositoryMock
 }
 
 func (c *CreateUserServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mocks.UserRepositoryMock)
-	mockEventEmitter := new(mocks.EmitterMock)
+	mockProfileCache := new(mocks.ProfileCacheRepositoryMock)
 	logger := zerolog.Nop()
 	c.userRepository = mockUserRepo
-	c.eventEmitter = mockEventEmitter
-	c.authService = service.NewAuthService(mockUserRepo, mockEventEmitter, logger)
+	c.profileCache = mockProfileCache
+	c.authService = service.NewAuthService(mockUserRepo, mockProfileCache, logger)
+	viper.Set("jetstream.event.subject.event_bus", "eventbus")
 }
 
 func (c *CreateUserServiceSuite) SetupTest() {
 	c.userRepository.ExpectedCalls = nil
-	c.eventEmitter.ExpectedCalls = nil
+	c.profileCache.ExpectedCalls = nil
 
 	c.userRepository.Calls = nil
-	c.eventEmitter.Calls = nil
+	c.profileCache.Calls = nil
 }
 
 func TestCreateUserServiceSuite(t *testing.T) {
@@ -66,26 +69,52 @@ func (c *CreateUserServiceSuite) TestAuthService_CreateUser_Success() {
 		TwoFactorEnabled: false,
 	}
 
-	c.userRepository.On("CreateNewUser", expectedUser).Return(nil).Once()
-	c.eventEmitter.On("InsertUser", mock.Anything, mock.MatchedBy(func(u *upb.User) bool {
+	c.userRepository.On("CreateNewUser", mock.Anything, expectedUser, mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		if len(outbox) != 1 || outbox[0].Subject != "eventbus.user.123" {
+			return false
+		}
+		u := mocks.DecodeUserEvent(outbox[0]).GetUserCreated()
 		return u.GetId() == testUser.GetId() &&
 			u.GetFullName() == testUser.GetFullName() &&
 			u.GetImage() == testUser.GetImage() &&
 			u.GetEmail() == testUser.GetEmail() &&
-			u.GetPassword() == testUser.GetPassword() &&
+			u.GetPassword() == "" &&
 			u.GetVerified() == testUser.GetVerified() &&
 			u.GetTwoFactorEnabled() == testUser.GetTwoFactorEnabled()
-	})).Return(nil).Maybe()
+	})).Return(nil).Once()
 
-	status, err := c.authService.CreateUser(testUser)
+	status, err := c.authService.CreateUser(context.Background(), testUser)
 
 	c.NoError(err)
 	c.NotNil(status)
 	c.True(status.Success)
 	c.userRepository.AssertExpectations(c.T())
-	// eventEmitter is called in a goroutine, so we wait a bit
-	time.Sleep(time.Second)
-	c.eventEmitter.AssertCalled(c.T(), "InsertUser", mock.Anything, mock.AnythingOfType("*upb.User"))
+}
+
+func (c *CreateUserServiceSuite) TestAuthService_CreateUser_TwoFactorIgnored() {
+	testUser := &upb.User{Id: "123", FullName: "John Doe", Email: "john@example.com", TwoFactorEnabled: true}
+	c.userRepository.On("CreateNewUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+		return !u.TwoFactorEnabled
+	}), mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return !mocks.DecodeUserEvent(outbox[0]).GetUserCreated().GetTwoFactorEnabled()
+	})).Return(nil).Once()
+
+	_, err := c.authService.CreateUser(context.Background(), testUser)
+
+	c.NoError(err)
+	c.userRepository.AssertExpectations(c.T())
+}
+
+func (c *CreateUserServiceSuite) TestAuthService_CreateUser_EmptyImageStoredAsNull() {
+	testUser := &upb.User{Id: "123", FullName: "John Doe", Email: "john@example.com", Image: utils.StringPtr("")}
+	c.userRepository.On("CreateNewUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+		return u.Image == nil
+	}), mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
+
+	_, err := c.authService.CreateUser(context.Background(), testUser)
+
+	c.NoError(err)
+	c.userRepository.AssertExpectations(c.T())
 }
 
 func (c *CreateUserServiceSuite) TestAuthService_CreateUser_RepositoryError() {
@@ -110,15 +139,12 @@ func (c *CreateUserServiceSuite) TestAuthService_CreateUser_RepositoryError() {
 	}
 
 	repoErr := errors.New("repo error")
-	c.userRepository.On("CreateNewUser", expectedUser).Return(repoErr).Once()
+	c.userRepository.On("CreateNewUser", mock.Anything, expectedUser, mock.Anything).Return(repoErr).Once()
 
-	status, err := c.authService.CreateUser(testUser)
+	status, err := c.authService.CreateUser(context.Background(), testUser)
 
 	c.Error(err)
 	c.Nil(status)
 	c.Equal(repoErr, err)
 	c.userRepository.AssertExpectations(c.T())
-
-	time.Sleep(time.Second)
-	c.eventEmitter.AssertNotCalled(c.T(), "InsertUser", mock.Anything, mock.Anything)
 }
@@ -3,7 +3,6 @@ package service_test
 import (
 	"context"
 	"testing"
-	"time"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/service"
@@ -19,25 +18,25 @@ type DeleteUserAuthServiceSuite struct {
 	suite.Suite
 	authService    service.AuthService
 	userRepository *mocks.UserRepositoryMock
-	eventEmitter   *mocks.EmitterMock
+	profileCache   *mocks.ProfileCacheRepositoryMock
 }
 
 func (d *DeleteUserAuthServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mocks.UserRepositoryMock)
-	mockEventEmitter := new(mocks.EmitterMock)
+	mockProfileCache := new(mocks.ProfileCacheRepositoryMock)
 	logger := zerolog.Nop()
 	d.userRepository = mockUserRepo
-	d.eventEmitter = mockEventEmitter
-	d.authService = service.NewAuthService(mockUserRepo, mockEventEmitter, logger)
+	d.profileCache = mockProfileCache
+	d.authService = service.NewAuthService(mockUserRepo, mockProfileCache, logger)
 }
 
 func (d *DeleteUserAuthServiceSuite) SetupTest() {
 	d.userRepository.ExpectedCalls = nil
-	d.eventEmitter.ExpectedCalls = nil
+	d.profileCache.ExpectedCalls = nil
 
 	d.userRepository.Calls = nil
-	d.eventEmitter.Calls = nil
+	d.profileCache.Calls = nil
 }
 
 func TestDeleteUserAuthServiceSuite(t *testing.T) {
@@ -48,22 +47,21 @@ func (d *DeleteUserAuthServiceSuite) TestAuthService_DeleteUser_Success() {
 	u := &upb.UserId{
 		UserId: "user-id-123",
 	}
-	d.userRepository.On("DeleteUser", mock.Anything).Return(nil)
-	d.eventEmitter.On("DeleteUser", mock.Anything, u).Return(nil).Once()
+	d.userRepository.On("DeleteUser", mock.Anything, mock.Anything).Return(nil)
 
+	d.profileCache.On("Invalidate", mock.Anything, "user-id-123").Return(nil).Once()
 	err := d.authService.DeleteUser(context.TODO(), u)
 
 	d.NoError(err)
 
 	d.userRepository.AssertExpectations(d.T())
-	time.Sleep(time.Second)
-	d.eventEmitter.AssertExpectations(d.T())
+	d.profileCache.AssertExpectations(d.T())
 }
 func (d *DeleteUserAuthServiceSuite) TestAuthService_DeleteUser_userNotFound() {
 	u := &upb.UserId{
 		UserId: "user-id-123",
 	}
-	d.userRepository.On("DeleteUser", mock.Anything).Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
+	d.userRepository.On("DeleteUser", mock.Anything, mock.Anything).Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
 
 	err := d.authService.DeleteUser(context.TODO(), u)
 
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetUserAuthServiceSuite struct {
+	suite.Suite
+	authService    service.AuthService
+	userRepository *mocks.UserRepositoryMock
+	profileCache   *mocks.ProfileCacheRepositoryMock
+}
+
+func (g *GetUserAuthServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mocks.UserRepositoryMock)
+	mockProfileCache := new(mocks.ProfileCacheRepositoryMock)
+	logger := zerolog.Nop()
+	g.userRepository = mockUserRepo
+	g.profileCache = mockProfileCache
+	g.authService = service.NewAuthService(mockUserRepo, mockProfileCache, logger)
+}
+
+func (g *GetUserAuthServiceSuite) SetupTest() {
+	g.userRepository.ExpectedCalls = nil
+	g.profileCache.ExpectedCalls = nil
+
+	g.userRepository.Calls = nil
+	g.profileCache.Calls = nil
+}
+
+func TestGetUserAuthServiceSuite(t *testing.T) {
+	suite.Run(t, &GetUserAuthServiceSuite{})
+}
+
+func (g *GetUserAuthServiceSuite) TestAuthService_GetUserById_Success() {
+	image := "image.png"
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, "user-id-123").Return(&dto.UserProfile{
+		User: model.User{
+			ID:       "user-id-123",
+			FullName: "John Doe",
+			Image:    &image,
+			Email:    "john@example.com",
+			Password: "hashedpassword",
+			Verified: true,
+		},
+	}, nil)
+
+	user, err := g.authService.GetUserById(context.TODO(), &upb.UserId{UserId: "user-id-123"})
+
+	g.NoError(err)
+	g.Equal("user-id-123", user.GetId())
+	g.Equal("image.png", user.GetImage())
+	g.Empty(user.GetPassword())
+}
+
+func (g *GetUserAuthServiceSuite) TestAuthService_GetUserById_NotFound() {
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, "user-id-123").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	user, err := g.authService.GetUserById(context.TODO(), &upb.UserId{UserId: "user-id-123"})
+
+	g.Nil(user)
+	g.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+}
+
+func (g *GetUserAuthServiceSuite) TestAuthService_GetUsersByIds_Success() {
+	ids := []string{"user-1", "user-2"}
+	g.userRepository.On("QueryUsersByIds", mock.Anything, ids).Return([]*model.User{
+		{ID: "user-1", Email: "one@example.com", Password: "hash-1"},
+		{ID: "user-2", Email: "two@example.com", Password: "hash-2"},
+	}, nil)
+
+	users, err := g.authService.GetUsersByIds(context.TODO(), ids)
+
+	g.NoError(err)
+	g.Len(users, 2)
+	for _, user := range users {
+		g.Empty(user.GetPassword())
+	}
+}
+
+func (g *GetUserAuthServiceSuite) TestAuthService_GetUsersByIds_RepositoryError() {
+	ids := []string{"user-1"}
+	g.userRepository.On("QueryUsersByIds", mock.Anything, ids).Return(nil, dto.Err_INTERNAL_FAILED_QUERY_USERS)
+
+	users, err := g.authService.GetUsersByIds(context.TODO(), ids)
+
+	g.Nil(users)
+	g.Equal(dto.Err_INTERNAL_FAILED_QUERY_USERS, err)
+}
+
+func (g *GetUserAuthServiceSuite) TestAuthService_GetUserByEmail_Success() {
+	g.userRepository.On("QueryUserByEmail", mock.Anything, "john@example.com").Return(&model.User{
+		ID:       "user-id-123",
+		Email:    "john@example.com",
+		Password: "hashedpassword",
+	}, nil)
+
+	user, err := g.authService.GetUserByEmail(context.TODO(), "john@example.com")
+
+	g.NoError(err)
+	g.Equal("user-id-123", user.GetId())
+	g.Empty(user.GetPassword())
+}
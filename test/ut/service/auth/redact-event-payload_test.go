@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RedactEventPayloadSuite struct {
+	suite.Suite
+	authService    service.AuthService
+	userRepository *mocks.UserRepositoryMock
+	profileCache   *mocks.ProfileCacheRepositoryMock
+}
+
+func (r *RedactEventPayloadSuite) SetupSuite() {
+
+	mockUserRepo := new(mocks.UserRepositoryMock)
+	mockProfileCache := new(mocks.ProfileCacheRepositoryMock)
+	logger := zerolog.Nop()
+	r.userRepository = mockUserRepo
+	r.profileCache = mockProfileCache
+	r.authService = service.NewAuthService(mockUserRepo, mockProfileCache, logger)
+}
+
+func (r *RedactEventPayloadSuite) SetupTest() {
+	r.userRepository.ExpectedCalls = nil
+	r.profileCache.ExpectedCalls = nil
+
+	r.userRepository.Calls = nil
+	r.profileCache.Calls = nil
+}
+
+func (r *RedactEventPayloadSuite) TearDownTest() {
+	viper.Reset()
+}
+
+func TestRedactEventPayloadSuite(t *testing.T) {
+	suite.Run(t, &RedactEventPayloadSuite{})
+}
+
+func (r *RedactEventPayloadSuite) TestAuthService_CreateUser_DefaultPayloadWithoutPassword() {
+	image := "img.png"
+	user := &upb.User{
+		Id:       "user-123",
+		FullName: "John Doe",
+		Image:    &image,
+		Email:    "john@example.com",
+		Password: "$2a$10$hashedpassword",
+		Verified: true,
+	}
+	r.userRepository.On("CreateNewUser", mock.Anything, mock.AnythingOfType("*model.User"), mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
+
+	_, err := r.authService.CreateUser(context.Background(), user)
+	r.NoError(err)
+
+	r.userRepository.AssertExpectations(r.T())
+	outbox := r.userRepository.Calls[0].Arguments.Get(2).([]*dto.OutboxMessage)
+	payload := mocks.DecodeUserEvent(outbox[0]).GetUserCreated()
+	r.Empty(payload.GetPassword())
+	r.Equal("user-123", payload.GetId())
+	r.Equal("John Doe", payload.GetFullName())
+	r.Equal("img.png", payload.GetImage())
+	r.Equal("john@example.com", payload.GetEmail())
+	r.True(payload.GetVerified())
+}
+
+func (r *RedactEventPayloadSuite) TestAuthService_UpdateUser_ConfiguredFieldsOnly() {
+	viper.Set("jetstream.event.payload_fields.update_user", []string{"email", "password"})
+	user := &upb.User{
+		Id:       "user-123",
+		FullName: "John Doe",
+		Email:    "john@example.com",
+		Password: "$2a$10$hashedpassword",
+		Verified: true,
+	}
+	r.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123"}, Version: 1}, nil).Once()
+	r.userRepository.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User"), int64(1), mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
+
+	r.profileCache.On("Invalidate", mock.Anything, mock.Anything).Return(nil).Once()
+	err := r.authService.UpdateUser(context.TODO(), user, 0)
+	r.NoError(err)
+
+	r.userRepository.AssertExpectations(r.T())
+	r.profileCache.AssertExpectations(r.T())
+	outbox := r.userRepository.Calls[1].Arguments.Get(3).([]*dto.OutboxMessage)
+	payload := mocks.DecodeUserEvent(outbox[0]).GetUserUpdated()
+	r.Equal("user-123", payload.GetId())
+	r.Equal("john@example.com", payload.GetEmail())
+	r.Empty(payload.GetFullName())
+	r.False(payload.GetVerified())
+	r.Empty(payload.GetPassword())
+}
@@ -4,13 +4,15 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
+	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/service"
 	"github.com/micros-template/user-service/test/mocks"
 
 	"github.com/micros-template/proto-user/pkg/upb"
+	"github.com/micros-template/sharedlib/model"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -19,25 +21,26 @@ type UpdateUserAuthServiceSuite struct {
 	suite.Suite
 	authService    service.AuthService
 	userRepository *mocks.UserRepositoryMock
-	eventEmitter   *mocks.EmitterMock
+	profileCache   *mocks.ProfileCacheRepositoryMock
 }
 
 func (u *UpdateUserAuthServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mocks.UserRepositoryMock)
-	mockEventEmitter := new(mocks.EmitterMock)
+	mockProfileCache := new(mocks.ProfileCacheRepositoryMock)
 	logger := zerolog.Nop()
 	u.userRepository = mockUserRepo
-	u.eventEmitter = mockEventEmitter
-	u.authService = service.NewAuthService(mockUserRepo, mockEventEmitter, logger)
+	u.profileCache = mockProfileCache
+	u.authService = service.NewAuthService(mockUserRepo, mockProfileCache, logger)
+	viper.Set("jetstream.event.subject.event_bus", "eventbus")
 }
 
 func (u *UpdateUserAuthServiceSuite) SetupTest() {
 	u.userRepository.ExpectedCalls = nil
-	u.eventEmitter.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
 
 	u.userRepository.Calls = nil
-	u.eventEmitter.Calls = nil
+	u.profileCache.Calls = nil
 }
 
 func TestUpdateUserAuthServiceSuite(t *testing.T) {
@@ -55,15 +58,26 @@ func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_Success() {
 		Verified:         true,
 		TwoFactorEnabled: true,
 	}
-	u.userRepository.On("UpdateUser", mock.AnythingOfType("*model.User")).Return(nil).Once()
-	u.eventEmitter.On("UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User")).Return(nil).Once()
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123", TwoFactorEnabled: true}, Version: 5}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User"), int64(5), mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
 
-	err := u.authService.UpdateUser(context.TODO(), user)
+	u.profileCache.On("Invalidate", mock.Anything, "user-123").Return(nil).Once()
+	err := u.authService.UpdateUser(context.TODO(), user, 0)
 	u.NoError(err)
 	u.userRepository.AssertExpectations(u.T())
+	u.profileCache.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_ExpectedVersionMismatch() {
+	user := &upb.User{Id: "user-123", FullName: "John Doe", Email: "john@example.com"}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123"}, Version: 5}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User"), int64(4), mock.Anything).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH).Once()
 
-	time.Sleep(time.Second)
-	u.eventEmitter.AssertExpectations(u.T())
+	err := u.authService.UpdateUser(context.TODO(), user, 4)
+	u.Equal(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH, err)
+	// the caller's version is not retried, it has to read the user again
+	u.userRepository.AssertExpectations(u.T())
+	u.profileCache.AssertNotCalled(u.T(), "Invalidate", mock.Anything, mock.Anything)
 }
 
 func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_RepoError() {
@@ -79,14 +93,15 @@ func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_RepoError() {
 		TwoFactorEnabled: true,
 	}
 	expectedErr := errors.New("db error")
-	u.userRepository.On("UpdateUser", mock.AnythingOfType("*model.User")).Return(expectedErr).Once()
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123"}, Version: 5}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User"), int64(5), mock.Anything).Return(expectedErr).Once()
 
-	err := u.authService.UpdateUser(context.TODO(), user)
+	err := u.authService.UpdateUser(context.TODO(), user, 0)
 	u.ErrorIs(err, expectedErr)
 	u.userRepository.AssertExpectations(u.T())
 }
 
-func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_EventEmitterCalled() {
+func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_EventStoredInOutbox() {
 	image := "img.png"
 
 	user := &upb.User{
@@ -98,13 +113,58 @@ func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_EventEmitterCall
 		Verified:         true,
 		TwoFactorEnabled: true,
 	}
-	u.userRepository.On("UpdateUser", mock.AnythingOfType("*model.User")).Return(nil).Once()
-	u.eventEmitter.On("UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User")).Return(nil).Once()
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123"}, Version: 5}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.MatchedBy(func(us *model.User) bool {
+		// two-factor is only enabled by the enrollment, the caller's flag is ignored
+		return !us.TwoFactorEnabled
+	}), int64(5), mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		event := mocks.DecodeUserEvent(outbox[0]).GetUserUpdated()
+		return len(outbox) == 1 &&
+			outbox[0].Subject == "eventbus.user.user-123" &&
+			event.GetId() == "user-123" && !event.GetTwoFactorEnabled()
+	})).Return(nil).Once()
+
+	u.profileCache.On("Invalidate", mock.Anything, "user-123").Return(nil).Once()
+	err := u.authService.UpdateUser(context.TODO(), user, 0)
+	u.NoError(err)
+	u.userRepository.AssertExpectations(u.T())
+	u.profileCache.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_ConcurrentUpdateRetried() {
+	user := &upb.User{Id: "user-123", FullName: "John Doe", Email: "john@example.com"}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123"}, Version: 5}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.Anything, int64(5), mock.Anything).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH).Once()
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123", TwoFactorEnabled: true}, Version: 6}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.MatchedBy(func(us *model.User) bool {
+		// two-factor was enabled in between, the write keeps it
+		return us.TwoFactorEnabled
+	}), int64(6), mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, "user-123").Return(nil).Once()
+
+	err := u.authService.UpdateUser(context.TODO(), user, 0)
+	u.NoError(err)
+	u.userRepository.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_WithoutImageStoredAsNull() {
+	user := &upb.User{Id: "user-123", FullName: "John Doe", Email: "john@example.com"}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{User: model.User{ID: "user-123"}, Version: 5}, nil).Once()
+	u.userRepository.On("UpdateUser", mock.Anything, mock.MatchedBy(func(us *model.User) bool {
+		return us.Image == nil
+	}), int64(5), mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, "user-123").Return(nil).Once()
 
-	err := u.authService.UpdateUser(context.TODO(), user)
+	err := u.authService.UpdateUser(context.TODO(), user, 0)
 	u.NoError(err)
+	u.userRepository.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserAuthServiceSuite) TestAuthService_UpdateUser_UserNotFound() {
+	user := &upb.User{Id: "user-123", FullName: "John Doe", Email: "john@example.com"}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-123").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
 
-	// Wait for goroutine to finish (in real code, use sync.WaitGroup or channel)
-	time.Sleep(time.Second)
-	u.eventEmitter.AssertCalled(u.T(), "UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User"))
+	err := u.authService.UpdateUser(context.TODO(), user, 0)
+	u.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	u.userRepository.AssertNotCalled(u.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
@@ -0,0 +1,207 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/internal/infrastructure/metrics"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RelayPendingServiceSuite struct {
+	suite.Suite
+	outboxRelay      service.OutboxRelay
+	outboxRepository *mk.OutboxRepositoryMock
+	natsInfra        *mk.MockNatsInfra
+	logEmitter       *mk.LoggerInfraMock
+	metrics          *metrics.Metrics
+}
+
+func (r *RelayPendingServiceSuite) SetupSuite() {
+	viper.Set("app.outbox.batch_size", 100)
+	viper.Set("app.outbox.max_attempts", 10)
+	viper.Set("app.outbox.lease", "30s")
+	viper.Set("app.outbox.retry_backoff", "2s")
+	viper.Set("app.outbox.max_backoff", "5m")
+
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockNatsInfra := new(mk.MockNatsInfra)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	r.outboxRepository = mockOutboxRepository
+	r.natsInfra = mockNatsInfra
+	r.logEmitter = mockLogEmitter
+}
+
+func (r *RelayPendingServiceSuite) TearDownSuite() {
+	viper.Reset()
+}
+
+func (r *RelayPendingServiceSuite) SetupTest() {
+	r.outboxRepository.ExpectedCalls = nil
+	r.natsInfra.ExpectedCalls = nil
+	r.logEmitter.ExpectedCalls = nil
+
+	r.outboxRepository.Calls = nil
+	r.natsInfra.Calls = nil
+	r.logEmitter.Calls = nil
+
+	// a fresh registry per test keeps the dead-letter counter isolated
+	r.metrics = metrics.New(nil)
+	r.outboxRelay = service.NewOutboxRelay(r.outboxRepository, r.natsInfra, r.metrics, r.logEmitter, zerolog.Nop())
+}
+
+func TestRelayPendingServiceSuite(t *testing.T) {
+	suite.Run(t, &RelayPendingServiceSuite{})
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_Success() {
+	msgs := []dto.OutboxMessage{
+		{ID: 1, Subject: "eventbus.user.userid-1", Payload: []byte("first"), Attempts: 1},
+		{ID: 2, Subject: "notification.mail.userid-2", Payload: []byte("second"), Attempts: 1},
+	}
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(nil, nil)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return(msgs, nil)
+	r.natsInfra.On("Publish", mock.Anything, "eventbus.user.userid-1", []byte("first")).Return(&jetstream.PubAck{}, nil)
+	r.natsInfra.On("Publish", mock.Anything, "notification.mail.userid-2", []byte("second")).Return(&jetstream.PubAck{}, nil)
+	r.outboxRepository.On("MarkSent", mock.Anything, int64(1)).Return(nil)
+	r.outboxRepository.On("MarkSent", mock.Anything, int64(2)).Return(nil)
+
+	sent, err := r.outboxRelay.RelayPending(context.Background())
+	r.NoError(err)
+	r.Equal(2, sent)
+	r.natsInfra.AssertExpectations(r.T())
+	r.outboxRepository.AssertExpectations(r.T())
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_PublishError() {
+	msgs := []dto.OutboxMessage{
+		{ID: 1, Subject: "eventbus.user.userid-1", Payload: []byte("first"), Attempts: 3},
+	}
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(nil, nil)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return(msgs, nil)
+	r.natsInfra.On("Publish", mock.Anything, "eventbus.user.userid-1", []byte("first")).Return((*jetstream.PubAck)(nil), errors.New("nats unavailable"))
+	r.outboxRepository.On("MarkFailed", mock.Anything, int64(1), "nats unavailable", mock.MatchedBy(func(next time.Time) bool {
+		// third attempt backs off for 2s * 2^2
+		return next.After(time.Now().Add(7*time.Second)) && next.Before(time.Now().Add(9*time.Second))
+	})).Return(nil)
+	r.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	sent, err := r.outboxRelay.RelayPending(context.Background())
+	r.NoError(err)
+	r.Equal(0, sent)
+	r.outboxRepository.AssertExpectations(r.T())
+	r.outboxRepository.AssertNotCalled(r.T(), "MarkSent", mock.Anything, mock.Anything)
+	time.Sleep(time.Second)
+
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_FailedMessageHoldsBackSubject() {
+	msgs := []dto.OutboxMessage{
+		{ID: 1, Subject: "eventbus.user.userid-1", Payload: []byte("v1"), Attempts: 1},
+		{ID: 2, Subject: "eventbus.user.userid-1", Payload: []byte("v2"), Attempts: 1},
+		{ID: 3, Subject: "eventbus.user.userid-2", Payload: []byte("other"), Attempts: 1},
+	}
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(nil, nil)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return(msgs, nil)
+	r.natsInfra.On("Publish", mock.Anything, "eventbus.user.userid-1", []byte("v1")).Return((*jetstream.PubAck)(nil), errors.New("nats unavailable"))
+	r.natsInfra.On("Publish", mock.Anything, "eventbus.user.userid-2", []byte("other")).Return(&jetstream.PubAck{}, nil)
+	r.outboxRepository.On("MarkFailed", mock.Anything, int64(1), "nats unavailable", mock.AnythingOfType("time.Time")).Return(nil)
+	// the later update of the same user waits for the failed one instead of overtaking it
+	r.outboxRepository.On("Release", mock.Anything, int64(2)).Return(nil)
+	r.outboxRepository.On("MarkSent", mock.Anything, int64(3)).Return(nil)
+	r.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	sent, err := r.outboxRelay.RelayPending(context.Background())
+	r.NoError(err)
+	r.Equal(1, sent)
+	r.natsInfra.AssertExpectations(r.T())
+	r.natsInfra.AssertNotCalled(r.T(), "Publish", mock.Anything, "eventbus.user.userid-1", []byte("v2"))
+	r.outboxRepository.AssertExpectations(r.T())
+	time.Sleep(time.Second)
+
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_ClaimError() {
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(nil, nil)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return(nil, dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX)
+
+	sent, err := r.outboxRelay.RelayPending(context.Background())
+	r.ErrorIs(err, dto.Err_INTERNAL_FAILED_CLAIM_OUTBOX)
+	r.Equal(0, sent)
+	r.natsInfra.AssertNotCalled(r.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_LastAttemptFailed() {
+	msgs := []dto.OutboxMessage{
+		{ID: 1, Subject: "eventbus.user.userid-1", Payload: []byte("first"), Attempts: 10},
+	}
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(nil, nil)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return(msgs, nil)
+	r.natsInfra.On("Publish", mock.Anything, "eventbus.user.userid-1", []byte("first")).Return((*jetstream.PubAck)(nil), errors.New("nats unavailable"))
+	r.outboxRepository.On("MarkFailed", mock.Anything, int64(1), "nats unavailable", mock.MatchedBy(func(next time.Time) bool {
+		// no backoff, the row is due for the dead-letter sweep right away
+		return !next.After(time.Now())
+	})).Return(nil)
+	r.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := r.outboxRelay.RelayPending(context.Background())
+	r.NoError(err)
+	r.outboxRepository.AssertExpectations(r.T())
+	time.Sleep(time.Second)
+
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_DeadLettered() {
+	lastError := "nats unavailable"
+	dead := []dto.OutboxMessage{
+		{ID: 1, Subject: "eventbus.user.userid-1", Attempts: 10, LastError: &lastError},
+		{ID: 2, Subject: "notification.mail.userid-2", Attempts: 10},
+	}
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(dead, nil)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return([]dto.OutboxMessage{}, nil)
+	r.logEmitter.On("EmitLog", "ERR", "outbox message dead-lettered. id: 1, subject: eventbus.user.userid-1, attempts: 10, last_error: nats unavailable").Return(nil).Once()
+	r.logEmitter.On("EmitLog", "ERR", "outbox message dead-lettered. id: 2, subject: notification.mail.userid-2, attempts: 10, last_error: ").Return(nil).Once()
+
+	sent, err := r.outboxRelay.RelayPending(context.Background())
+	r.NoError(err)
+	r.Equal(0, sent)
+	r.outboxRepository.AssertExpectations(r.T())
+
+	expected := `
+# HELP user_service_outbox_dead_lettered_total Outbox messages given up after max attempts, by subject prefix.
+# TYPE user_service_outbox_dead_lettered_total counter
+user_service_outbox_dead_lettered_total{subject="eventbus"} 1
+user_service_outbox_dead_lettered_total{subject="notification"} 1
+`
+	r.NoError(testutil.GatherAndCompare(r.metrics.Registry(), strings.NewReader(expected), "user_service_outbox_dead_lettered_total"))
+	time.Sleep(time.Second)
+
+	r.logEmitter.AssertExpectations(r.T())
+}
+
+func (r *RelayPendingServiceSuite) TestOutboxRelay_RelayPending_DeadLetterError() {
+	r.outboxRepository.On("DeadLetterExhausted", mock.Anything, 10).Return(nil, dto.Err_INTERNAL_FAILED_UPDATE_OUTBOX)
+	r.outboxRepository.On("ClaimPending", mock.Anything, uint64(100), 10, mock.AnythingOfType("time.Time")).Return([]dto.OutboxMessage{}, nil)
+
+	// pending messages are still relayed, the sweep runs again next time
+	sent, err := r.outboxRelay.RelayPending(context.Background())
+	r.NoError(err)
+	r.Equal(0, sent)
+	r.outboxRepository.AssertExpectations(r.T())
+}
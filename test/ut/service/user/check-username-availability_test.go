@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CheckUsernameAvailabilityServiceSuite struct {
+	suite.Suite
+	userService    service.UserService
+	userRepository *mk.UserRepositoryMock
+}
+
+func (c *CheckUsernameAvailabilityServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	c.userRepository = mockUserRepo
+	c.userService = service.NewUserService(mockUserRepo, zerolog.Nop(), new(mk.MockFileServiceClient), new(mk.MockRedisRepository), new(mk.OutboxRepositoryMock), new(mk.ProfileCacheRepositoryMock), new(mk.LoggerInfraMock))
+}
+
+func (c *CheckUsernameAvailabilityServiceSuite) SetupTest() {
+	c.userRepository.ExpectedCalls = nil
+	c.userRepository.Calls = nil
+}
+
+func TestCheckUsernameAvailabilityServiceSuite(t *testing.T) {
+	suite.Run(t, &CheckUsernameAvailabilityServiceSuite{})
+}
+
+func (c *CheckUsernameAvailabilityServiceSuite) TestUserService_CheckUsernameAvailability_Taken() {
+	c.userRepository.On("IsUsernameTaken", mock.Anything, "john.doe", "user-1").Return(true, nil)
+
+	res, err := c.userService.CheckUsernameAvailability(context.Background(), "john.doe", "user-1")
+
+	c.NoError(err)
+	c.Equal(dto.UsernameAvailabilityResponse{Username: "john.doe", Available: false}, res)
+}
+
+func (c *CheckUsernameAvailabilityServiceSuite) TestUserService_CheckUsernameAvailability_Error() {
+	c.userRepository.On("IsUsernameTaken", mock.Anything, "john.doe", "user-1").Return(false, dto.Err_INTERNAL_FAILED_QUERY_USERNAME)
+
+	_, err := c.userService.CheckUsernameAvailability(context.Background(), "john.doe", "user-1")
+
+	c.Equal(dto.Err_INTERNAL_FAILED_QUERY_USERNAME, err)
+}
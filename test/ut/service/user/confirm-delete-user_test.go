@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ConfirmDeleteUserServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (c *ConfirmDeleteUserServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	c.userRepository = mockUserRepo
+	c.profileCache = mockProfileCache
+	c.fileService = mockFileService
+	c.outboxRepository = mockOutboxRepository
+	c.redisRepository = mockRedisRepository
+	c.logEmitter = mockLogEmitter
+	c.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (c *ConfirmDeleteUserServiceSuite) SetupTest() {
+	c.userRepository.ExpectedCalls = nil
+	c.profileCache.ExpectedCalls = nil
+	c.fileService.ExpectedCalls = nil
+	c.outboxRepository.ExpectedCalls = nil
+	c.redisRepository.ExpectedCalls = nil
+	c.logEmitter.ExpectedCalls = nil
+
+	c.userRepository.Calls = nil
+	c.profileCache.Calls = nil
+	c.fileService.Calls = nil
+	c.outboxRepository.Calls = nil
+	c.redisRepository.Calls = nil
+	c.logEmitter.Calls = nil
+}
+
+func TestConfirmDeleteUserServiceSuite(t *testing.T) {
+	suite.Run(t, &ConfirmDeleteUserServiceSuite{})
+}
+
+func (c *ConfirmDeleteUserServiceSuite) TestUserService_ConfirmDeleteUser_Success() {
+	req := &dto.ConfirmDeleteUserRequest{Token: "valid-token"}
+	c.redisRepository.On("TakeResource", mock.Anything, "deleteAccountToken:userid-123").Return("valid-token", nil).Once()
+	c.userRepository.On("DeleteUser", mock.Anything, "userid-123").Return(nil).Once()
+
+	c.profileCache.On("Invalidate", mock.Anything, "userid-123").Return(nil).Once()
+	err := c.userService.ConfirmDeleteUser(context.Background(), req, "userid-123")
+
+	c.NoError(err)
+	c.redisRepository.AssertExpectations(c.T())
+	c.userRepository.AssertExpectations(c.T())
+	c.profileCache.AssertExpectations(c.T())
+}
+
+func (c *ConfirmDeleteUserServiceSuite) TestUserService_ConfirmDeleteUser_TokenExpired() {
+	req := &dto.ConfirmDeleteUserRequest{Token: "valid-token"}
+	c.redisRepository.On("TakeResource", mock.Anything, "deleteAccountToken:userid-123").Return("", dto.Err_NOTFOUND_KEY_NOTFOUND).Once()
+
+	err := c.userService.ConfirmDeleteUser(context.Background(), req, "userid-123")
+
+	c.Equal(dto.Err_UNAUTHORIZED_TOKEN_INVALID, err)
+	c.userRepository.AssertNotCalled(c.T(), "DeleteUser", mock.Anything, mock.Anything)
+}
+
+func (c *ConfirmDeleteUserServiceSuite) TestUserService_ConfirmDeleteUser_TokenMismatch() {
+	req := &dto.ConfirmDeleteUserRequest{Token: "wrong-token"}
+	c.redisRepository.On("TakeResource", mock.Anything, "deleteAccountToken:userid-123").Return("valid-token", nil).Once()
+	c.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := c.userService.ConfirmDeleteUser(context.Background(), req, "userid-123")
+
+	c.Equal(dto.Err_UNAUTHORIZED_TOKEN_INVALID, err)
+	c.userRepository.AssertNotCalled(c.T(), "DeleteUser", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	c.logEmitter.AssertExpectations(c.T())
+}
+
+func (c *ConfirmDeleteUserServiceSuite) TestUserService_ConfirmDeleteUser_UserNotFound() {
+	req := &dto.ConfirmDeleteUserRequest{Token: "valid-token"}
+	c.redisRepository.On("TakeResource", mock.Anything, "deleteAccountToken:userid-123").Return("valid-token", nil).Once()
+	c.userRepository.On("DeleteUser", mock.Anything, "userid-123").Return(dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+
+	err := c.userService.ConfirmDeleteUser(context.Background(), req, "userid-123")
+
+	c.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	c.redisRepository.AssertNotCalled(c.T(), "RemoveResource", mock.Anything, mock.Anything)
+}
+
+// the token is consumed before the delete, a failed delete does not leave it usable for the rest of its ttl
+func (c *ConfirmDeleteUserServiceSuite) TestUserService_ConfirmDeleteUser_TokenNotReplayable() {
+	req := &dto.ConfirmDeleteUserRequest{Token: "valid-token"}
+	c.redisRepository.On("TakeResource", mock.Anything, "deleteAccountToken:userid-123").Return("valid-token", nil).Once()
+	c.userRepository.On("DeleteUser", mock.Anything, "userid-123").Return(dto.Err_INTERNAL_FAILED_DELETE_USER).Once()
+	c.redisRepository.On("TakeResource", mock.Anything, "deleteAccountToken:userid-123").Return("", dto.Err_NOTFOUND_KEY_NOTFOUND).Once()
+
+	err := c.userService.ConfirmDeleteUser(context.Background(), req, "userid-123")
+	c.Equal(dto.Err_INTERNAL_FAILED_DELETE_USER, err)
+
+	err = c.userService.ConfirmDeleteUser(context.Background(), req, "userid-123")
+	c.Equal(dto.Err_UNAUTHORIZED_TOKEN_INVALID, err)
+	c.redisRepository.AssertExpectations(c.T())
+	c.userRepository.AssertNumberOfCalls(c.T(), "DeleteUser", 1)
+}
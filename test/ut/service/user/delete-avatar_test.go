@@ -0,0 +1,118 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-file/pkg/fpb"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeleteAvatarServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (u *DeleteAvatarServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	u.userRepository = mockUserRepo
+	u.profileCache = mockProfileCache
+	u.fileService = mockFileService
+	u.outboxRepository = mockOutboxRepository
+	u.redisRepository = mockRedisRepository
+	u.logEmitter = mockLogEmitter
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (u *DeleteAvatarServiceSuite) SetupTest() {
+	u.userRepository.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
+	u.fileService.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
+	u.redisRepository.ExpectedCalls = nil
+	u.logEmitter.ExpectedCalls = nil
+
+	u.userRepository.Calls = nil
+	u.profileCache.Calls = nil
+	u.fileService.Calls = nil
+	u.outboxRepository.Calls = nil
+	u.redisRepository.Calls = nil
+	u.logEmitter.Calls = nil
+}
+
+func TestDeleteAvatarServiceSuite(t *testing.T) {
+	suite.Run(t, &DeleteAvatarServiceSuite{})
+}
+
+func (d *DeleteAvatarServiceSuite) TestUserService_DeleteAvatar_Success() {
+	userId := "user-123"
+	image := "img_512.jpg"
+	user := &dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe", Image: &image},
+		ImageVariants: dto.ImageVariants{"64": "img_64.jpg", "256": "img_256.jpg", "512": "img_512.jpg"},
+	}
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	d.userRepository.On("UpdateUserImage", mock.Anything, mock.MatchedBy(func(us *model.User) bool {
+		return us.Image == nil && us.FullName == "John Doe"
+	}), dto.ImageVariants{}, mock.Anything, mock.Anything).Return(nil).Once()
+	d.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	for _, name := range []string{"img_64.jpg", "img_256.jpg", "img_512.jpg"} {
+		d.fileService.On("RemoveProfileImage", mock.Anything, &fpb.ImageName{Name: name}).Return(nil, nil).Once()
+	}
+
+	err := d.userService.DeleteAvatar(context.Background(), userId)
+
+	d.NoError(err)
+	d.userRepository.AssertExpectations(d.T())
+	d.fileService.AssertExpectations(d.T())
+	d.fileService.AssertNumberOfCalls(d.T(), "RemoveProfileImage", 3)
+}
+
+func (d *DeleteAvatarServiceSuite) TestUserService_DeleteAvatar_NoAvatar() {
+	userId := "user-123"
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: model.User{ID: userId}}, nil)
+
+	err := d.userService.DeleteAvatar(context.Background(), userId)
+
+	d.NoError(err)
+	d.userRepository.AssertNotCalled(d.T(), "UpdateUserImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	d.fileService.AssertNotCalled(d.T(), "RemoveProfileImage", mock.Anything, mock.Anything)
+}
+
+func (d *DeleteAvatarServiceSuite) TestUserService_DeleteAvatar_UpdateFailsKeepsFiles() {
+	userId := "user-123"
+	image := "img_512.jpg"
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: model.User{ID: userId, Image: &image}}, nil)
+	d.userRepository.On("UpdateUserImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dto.Err_INTERNAL_FAILED_UPDATE_USER).Once()
+	d.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := d.userService.DeleteAvatar(context.Background(), userId)
+
+	d.Equal(dto.Err_INTERNAL_FAILED_UPDATE_USER, err)
+	d.fileService.AssertNotCalled(d.T(), "RemoveProfileImage", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	d.logEmitter.AssertExpectations(d.T())
+}
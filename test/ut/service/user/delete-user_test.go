@@ -1,6 +1,10 @@
 package service_test
 
 import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -8,54 +12,56 @@ import (
 	"github.com/micros-template/user-service/internal/domain/service"
 	mk "github.com/micros-template/user-service/test/mocks"
 
+	_dto "github.com/micros-template/sharedlib/dto"
 	"github.com/micros-template/sharedlib/model"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type DeleteUserServiceSuite struct {
 	suite.Suite
-	userService        service.UserService
-	userRepository     *mk.UserRepositoryMock
-	eventEmitter       *mk.EmitterMock
-	fileService        *mk.MockFileServiceClient
-	notificationStream *mk.MockNatsInfra
-	redisRepository    *mk.MockRedisRepository
-	logEmitter         *mk.LoggerInfraMock
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
 }
 
 func (d *DeleteUserServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mk.UserRepositoryMock)
-	mockEventEmitter := new(mk.EmitterMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
 	mockFileService := new(mk.MockFileServiceClient)
-	mockNotificationStream := new(mk.MockNatsInfra)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
 	mockRedisRepository := new(mk.MockRedisRepository)
 	mockLogEmitter := new(mk.LoggerInfraMock)
 
 	logger := zerolog.Nop()
 	d.userRepository = mockUserRepo
-	d.eventEmitter = mockEventEmitter
+	d.profileCache = mockProfileCache
 	d.fileService = mockFileService
-	d.notificationStream = mockNotificationStream
+	d.outboxRepository = mockOutboxRepository
 	d.redisRepository = mockRedisRepository
 	d.logEmitter = mockLogEmitter
-	d.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockNotificationStream, mockEventEmitter, mockLogEmitter)
+	d.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
 }
 
 func (d *DeleteUserServiceSuite) SetupTest() {
 	d.userRepository.ExpectedCalls = nil
-	d.eventEmitter.ExpectedCalls = nil
+	d.profileCache.ExpectedCalls = nil
 	d.fileService.ExpectedCalls = nil
-	d.notificationStream.ExpectedCalls = nil
+	d.outboxRepository.ExpectedCalls = nil
 	d.redisRepository.ExpectedCalls = nil
 	d.logEmitter.ExpectedCalls = nil
 
 	d.userRepository.Calls = nil
-	d.eventEmitter.Calls = nil
+	d.profileCache.Calls = nil
 	d.fileService.Calls = nil
-	d.notificationStream.Calls = nil
+	d.outboxRepository.Calls = nil
 	d.redisRepository.Calls = nil
 	d.logEmitter.Calls = nil
 }
@@ -76,24 +82,67 @@ func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_Success() {
 	req := dto.DeleteUserRequest{
 		Password: "password123",
 	}
-	d.userRepository.On("QueryUserByUserId", "userid-123").Return(&u, nil)
-	d.userRepository.On("DeleteUser", "userid-123").Return(nil)
-	d.eventEmitter.On("DeleteUser", mock.Anything, mock.Anything).Return(nil).Once()
-	err := d.userService.DeleteUser(&req, "userid-123")
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(&dto.UserProfile{User: u}, nil)
+	d.redisRepository.On("SetResource", mock.Anything, "deleteAccountToken:userid-123", mock.Anything, mock.Anything).Return(nil).Once()
+	d.outboxRepository.On("Enqueue", mock.Anything, mock.MatchedBy(func(msgs []*dto.OutboxMessage) bool {
+		return len(msgs) == 1 && strings.Contains(string(msgs[0].Payload), "deleteAccount")
+	})).Return(nil).Once()
+	err := d.userService.DeleteUser(context.Background(), &req, "userid-123")
 
 	d.NoError(err)
 	d.userRepository.AssertExpectations(d.T())
+	d.redisRepository.AssertExpectations(d.T())
+	d.outboxRepository.AssertExpectations(d.T())
+	d.userRepository.AssertNotCalled(d.T(), "DeleteUser", mock.Anything, mock.Anything)
+}
 
-	time.Sleep(time.Second)
-	d.eventEmitter.AssertExpectations(d.T())
+// any version listed in If-Match is accepted
+func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_AnyExpectedVersionMatches() {
+	u := model.User{
+		ID:       "userid-123",
+		Email:    "test@example.com",
+		Password: "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6",
+	}
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(&dto.UserProfile{User: u, Version: 4}, nil)
+	d.redisRepository.On("SetResource", mock.Anything, "deleteAccountToken:userid-123", mock.Anything, mock.Anything).Return(nil).Once()
+	d.outboxRepository.On("Enqueue", mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := d.userService.DeleteUser(context.Background(), &dto.DeleteUserRequest{Password: "password123", ExpectedVersions: []int64{3, 4}}, "userid-123")
+
+	d.NoError(err)
+	d.redisRepository.AssertExpectations(d.T())
+}
+
+func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_ConfirmationLink() {
+	viper.Set("app.delete_confirmation_url", "https://example.com/account/delete/confirm")
+	defer viper.Set("app.delete_confirmation_url", nil)
+	u := model.User{
+		ID:       "userid-123",
+		Email:    "test@example.com",
+		Password: "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6",
+	}
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(&dto.UserProfile{User: u}, nil)
+	d.redisRepository.On("SetResource", mock.Anything, "deleteAccountToken:userid-123", mock.Anything, mock.Anything).Return(nil).Once()
+	d.outboxRepository.On("Enqueue", mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := d.userService.DeleteUser(context.Background(), &dto.DeleteUserRequest{Password: "password123"}, "userid-123")
+
+	d.NoError(err)
+	token := d.redisRepository.Calls[0].Arguments.String(2)
+	msgs := d.outboxRepository.Calls[0].Arguments.Get(1).([]*dto.OutboxMessage)
+	var mail _dto.MailNotificationMessage
+	d.Require().NoError(json.Unmarshal(msgs[0].Payload, &mail))
+	// the token is what ConfirmDeleteUserRequest expects in its body
+	d.Equal("https://example.com/account/delete/confirm?token="+url.QueryEscape(token), mail.Message)
 }
+
 func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_UserNotFound() {
 	req := dto.DeleteUserRequest{
 		Password: "password123",
 	}
-	d.userRepository.On("QueryUserByUserId", "userid-123").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
 
-	err := d.userService.DeleteUser(&req, "userid-123")
+	err := d.userService.DeleteUser(context.Background(), &req, "userid-123")
 
 	d.Error(err)
 	d.userRepository.AssertExpectations(d.T())
@@ -112,9 +161,9 @@ func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_WrongPassword() {
 		Password: "password1234",
 	}
 	d.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
-	d.userRepository.On("QueryUserByUserId", "userid-123").Return(&u, nil)
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(&dto.UserProfile{User: u}, nil)
 
-	err := d.userService.DeleteUser(&req, "userid-123")
+	err := d.userService.DeleteUser(context.Background(), &req, "userid-123")
 
 	d.Error(err)
 	d.userRepository.AssertExpectations(d.T())
@@ -122,3 +171,37 @@ func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_WrongPassword() {
 	time.Sleep(time.Second)
 	d.logEmitter.AssertExpectations(d.T())
 }
+
+func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_RedisError() {
+	u := model.User{
+		ID:               "userid-123",
+		FullName:         "test_user",
+		Image:            new(string),
+		Email:            "test@example.com",
+		Password:         "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6",
+		Verified:         true,
+		TwoFactorEnabled: false,
+	}
+	req := dto.DeleteUserRequest{
+		Password: "password123",
+	}
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(&dto.UserProfile{User: u}, nil)
+	d.redisRepository.On("SetResource", mock.Anything, "deleteAccountToken:userid-123", mock.Anything, mock.Anything).Return(dto.Err_INTERNAL_SET_RESOURCE).Once()
+
+	err := d.userService.DeleteUser(context.Background(), &req, "userid-123")
+
+	d.Equal(dto.Err_INTERNAL_SET_RESOURCE, err)
+	d.outboxRepository.AssertNotCalled(d.T(), "Enqueue", mock.Anything, mock.Anything)
+}
+
+func (d *DeleteUserServiceSuite) TestUserService_DeleteUser_VersionMismatch() {
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, "userid-123").Return(&dto.UserProfile{
+		User:    model.User{ID: "userid-123", Email: "john@example.com"},
+		Version: 4,
+	}, nil)
+
+	err := d.userService.DeleteUser(context.Background(), &dto.DeleteUserRequest{Password: "secret", ExpectedVersions: []int64{3}}, "userid-123")
+
+	d.ErrorIs(err, dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+	d.redisRepository.AssertNotCalled(d.T(), "SetResource", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
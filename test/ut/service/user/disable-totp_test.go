@@ -0,0 +1,182 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DisableTOTPServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (d *DisableTOTPServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	d.userRepository = mockUserRepo
+	d.profileCache = mockProfileCache
+	d.fileService = mockFileService
+	d.outboxRepository = mockOutboxRepository
+	d.redisRepository = mockRedisRepository
+	d.logEmitter = mockLogEmitter
+	d.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.two_factor.encryption_key", testTOTPEncryptionKey)
+	viper.Set("app.two_factor.max_attempts", 5)
+	viper.Set("app.two_factor.attempt_window", "15m")
+}
+
+func (d *DisableTOTPServiceSuite) SetupTest() {
+	d.userRepository.ExpectedCalls = nil
+	d.profileCache.ExpectedCalls = nil
+	d.fileService.ExpectedCalls = nil
+	d.outboxRepository.ExpectedCalls = nil
+	d.redisRepository.ExpectedCalls = nil
+	d.logEmitter.ExpectedCalls = nil
+
+	d.userRepository.Calls = nil
+	d.profileCache.Calls = nil
+	d.fileService.Calls = nil
+	d.outboxRepository.Calls = nil
+	d.redisRepository.Calls = nil
+	d.logEmitter.Calls = nil
+}
+
+func TestDisableTOTPServiceSuite(t *testing.T) {
+	suite.Run(t, &DisableTOTPServiceSuite{})
+}
+
+func (d *DisableTOTPServiceSuite) expectDisabled(userId string) {
+	d.userRepository.On("UpdateTOTPSecret", mock.Anything, userId, []byte(nil), []string(nil), mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return len(outbox) == 1 && !mk.DecodeUserEvent(outbox[0]).GetUserUpdated().GetTwoFactorEnabled()
+	})).Return(nil).Once()
+	d.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_DisableTOTP_WithCode() {
+	userId := "user-123"
+	code := currentTestTOTPCode()
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, TwoFactorEnabled: true},
+	}, nil)
+	d.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(sealTestTOTPSecret(userId), nil)
+	d.redisRepository.On("IncrementResource", mock.Anything, "totpAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	d.redisRepository.On("GetResource", mock.Anything, "totpUsedCode:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND)
+	d.redisRepository.On("SetResource", mock.Anything, "totpUsedCode:"+userId, code, 90*time.Second).Return(nil)
+	d.redisRepository.On("RemoveResource", mock.Anything, "totpAttempts:"+userId).Return(nil)
+	d.expectDisabled(userId)
+
+	err := d.userService.DisableTOTP(context.Background(), &dto.DisableTOTPRequest{Code: code}, userId)
+
+	d.NoError(err)
+	d.userRepository.AssertExpectations(d.T())
+	d.profileCache.AssertExpectations(d.T())
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_DisableTOTP_WithPassword() {
+	userId := "user-123"
+	hash, _ := utils.HashPassword("password123")
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Password: hash, TwoFactorEnabled: true},
+	}, nil)
+	d.expectDisabled(userId)
+
+	err := d.userService.DisableTOTP(context.Background(), &dto.DisableTOTPRequest{Password: "password123"}, userId)
+
+	d.NoError(err)
+	d.userRepository.AssertNotCalled(d.T(), "QueryTOTPSecret", mock.Anything, mock.Anything)
+	d.userRepository.AssertExpectations(d.T())
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_DisableTOTP_WrongPassword() {
+	userId := "user-123"
+	hash, _ := utils.HashPassword("password123")
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Password: hash, TwoFactorEnabled: true},
+	}, nil)
+	d.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := d.userService.DisableTOTP(context.Background(), &dto.DisableTOTPRequest{Password: "wrong"}, userId)
+
+	d.Equal(dto.Err_UNAUTHORIZED_PASSWORD_WRONG, err)
+	d.userRepository.AssertNotCalled(d.T(), "UpdateTOTPSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	d.logEmitter.AssertExpectations(d.T())
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_DisableTOTP_NotEnabled() {
+	userId := "user-123"
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+
+	err := d.userService.DisableTOTP(context.Background(), &dto.DisableTOTPRequest{Password: "password123"}, userId)
+
+	d.Equal(dto.Err_CONFLICT_TWO_FACTOR_DISABLED, err)
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_VerifyTOTP_NotEnrolled() {
+	userId := "user-123"
+	d.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(nil, nil)
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+
+	err := d.userService.VerifyTOTP(context.Background(), "123456", userId)
+
+	d.Equal(dto.Err_CONFLICT_TWO_FACTOR_DISABLED, err)
+	d.redisRepository.AssertNotCalled(d.T(), "IncrementResource", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_VerifyTOTP_EnabledWithoutSecret() {
+	userId := "user-123"
+	d.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(nil, nil)
+	d.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, TwoFactorEnabled: true},
+	}, nil)
+
+	err := d.userService.VerifyTOTP(context.Background(), "123456", userId)
+
+	d.Equal(dto.Err_CONFLICT_TWO_FACTOR_REENROLL, err)
+	d.redisRepository.AssertNotCalled(d.T(), "IncrementResource", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (d *DisableTOTPServiceSuite) TestUserService_VerifyTOTP_Success() {
+	userId := "user-123"
+	code := currentTestTOTPCode()
+	d.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(sealTestTOTPSecret(userId), nil)
+	d.redisRepository.On("IncrementResource", mock.Anything, "totpAttempts:"+userId, 15*time.Minute).Return(int64(2), nil)
+	d.redisRepository.On("GetResource", mock.Anything, "totpUsedCode:"+userId).Return("000000", nil)
+	d.redisRepository.On("SetResource", mock.Anything, "totpUsedCode:"+userId, code, 90*time.Second).Return(nil)
+	d.redisRepository.On("RemoveResource", mock.Anything, "totpAttempts:"+userId).Return(nil)
+
+	err := d.userService.VerifyTOTP(context.Background(), code, userId)
+
+	d.NoError(err)
+	d.redisRepository.AssertExpectations(d.T())
+}
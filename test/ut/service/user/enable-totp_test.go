@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/pkg/secret"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/pquerna/otp/totp"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+type EnableTOTPServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (e *EnableTOTPServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	e.userRepository = mockUserRepo
+	e.profileCache = mockProfileCache
+	e.fileService = mockFileService
+	e.outboxRepository = mockOutboxRepository
+	e.redisRepository = mockRedisRepository
+	e.logEmitter = mockLogEmitter
+	e.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.two_factor.encryption_key", testTOTPEncryptionKey)
+	viper.Set("app.two_factor.max_attempts", 5)
+	viper.Set("app.two_factor.attempt_window", "15m")
+}
+
+func (e *EnableTOTPServiceSuite) SetupTest() {
+	e.userRepository.ExpectedCalls = nil
+	e.profileCache.ExpectedCalls = nil
+	e.fileService.ExpectedCalls = nil
+	e.outboxRepository.ExpectedCalls = nil
+	e.redisRepository.ExpectedCalls = nil
+	e.logEmitter.ExpectedCalls = nil
+
+	e.userRepository.Calls = nil
+	e.profileCache.Calls = nil
+	e.fileService.Calls = nil
+	e.outboxRepository.Calls = nil
+	e.redisRepository.Calls = nil
+	e.logEmitter.Calls = nil
+}
+
+func TestEnableTOTPServiceSuite(t *testing.T) {
+	suite.Run(t, &EnableTOTPServiceSuite{})
+}
+
+func sealTestTOTPSecret(userId string) []byte {
+	key, _ := secret.ParseKey(testTOTPEncryptionKey)
+	sealed, _ := secret.Seal(key, []byte(testTOTPSecret), []byte(userId))
+	return sealed
+}
+
+func currentTestTOTPCode() string {
+	code, _ := totp.GenerateCode(testTOTPSecret, time.Now().UTC())
+	return code
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_Success() {
+	userId := "user-123"
+	sealed := sealTestTOTPSecret(userId)
+	code := currentTestTOTPCode()
+
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, FullName: "John Doe", Email: "john@example.com"},
+	}, nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpEnrollment:"+userId).Return(base64.StdEncoding.EncodeToString(sealed), nil)
+	e.redisRepository.On("IncrementResource", mock.Anything, "totpAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpUsedCode:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND)
+	e.redisRepository.On("SetResource", mock.Anything, "totpUsedCode:"+userId, code, 90*time.Second).Return(nil)
+	e.redisRepository.On("RemoveResource", mock.Anything, "totpAttempts:"+userId).Return(nil)
+	var storedHashes []string
+	e.userRepository.On("UpdateTOTPSecret", mock.Anything, userId, sealed, mock.MatchedBy(func(codeHashes []string) bool {
+		storedHashes = codeHashes
+		return len(codeHashes) == 10
+	}), mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return len(outbox) == 1 && mk.DecodeUserEvent(outbox[0]).GetUserUpdated().GetTwoFactorEnabled()
+	})).Return(nil)
+	e.profileCache.On("Invalidate", mock.Anything, userId).Return(nil)
+	e.redisRepository.On("RemoveResource", mock.Anything, "totpEnrollment:"+userId).Return(nil)
+
+	res, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: code}, userId)
+
+	e.NoError(err)
+	e.Len(res.RecoveryCodes, 10)
+	// only the hashes are stored, each shown code must match its own hash
+	for i, code := range res.RecoveryCodes {
+		e.Regexp(`^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+		e.True(utils.HashPasswordCompare(strings.ReplaceAll(code, "-", ""), storedHashes[i]))
+	}
+	e.userRepository.AssertExpectations(e.T())
+	e.redisRepository.AssertExpectations(e.T())
+	e.profileCache.AssertExpectations(e.T())
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_NoPendingEnrollment() {
+	userId := "user-123"
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpEnrollment:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND)
+
+	_, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: "123456"}, userId)
+
+	e.Equal(dto.Err_NOTFOUND_TOTP_ENROLLMENT, err)
+	e.userRepository.AssertNotCalled(e.T(), "UpdateTOTPSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_AlreadyEnabled() {
+	userId := "user-123"
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, TwoFactorEnabled: true},
+	}, nil)
+	e.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(sealTestTOTPSecret(userId), nil)
+
+	_, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: "123456"}, userId)
+
+	e.Equal(dto.Err_CONFLICT_TWO_FACTOR_ENABLED, err)
+	e.redisRepository.AssertNotCalled(e.T(), "GetResource", mock.Anything, mock.Anything)
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_WrongCode() {
+	userId := "user-123"
+	sealed := sealTestTOTPSecret(userId)
+	// a code from an hour ago is outside the skew window
+	code, _ := totp.GenerateCode(testTOTPSecret, time.Now().UTC().Add(-time.Hour))
+
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpEnrollment:"+userId).Return(base64.StdEncoding.EncodeToString(sealed), nil)
+	e.redisRepository.On("IncrementResource", mock.Anything, "totpAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpUsedCode:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND)
+	e.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: code}, userId)
+
+	e.Equal(dto.Err_UNAUTHORIZED_OTP_INVALID, err)
+	e.userRepository.AssertNotCalled(e.T(), "UpdateTOTPSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	e.logEmitter.AssertExpectations(e.T())
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_ReplayedCode() {
+	userId := "user-123"
+	sealed := sealTestTOTPSecret(userId)
+	code := currentTestTOTPCode()
+
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpEnrollment:"+userId).Return(base64.StdEncoding.EncodeToString(sealed), nil)
+	e.redisRepository.On("IncrementResource", mock.Anything, "totpAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpUsedCode:"+userId).Return(code, nil)
+	e.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: code}, userId)
+
+	e.Equal(dto.Err_UNAUTHORIZED_OTP_INVALID, err)
+
+	time.Sleep(time.Second)
+	e.logEmitter.AssertExpectations(e.T())
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_TooManyAttempts() {
+	userId := "user-123"
+	sealed := sealTestTOTPSecret(userId)
+
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpEnrollment:"+userId).Return(base64.StdEncoding.EncodeToString(sealed), nil)
+	e.redisRepository.On("IncrementResource", mock.Anything, "totpAttempts:"+userId, 15*time.Minute).Return(int64(6), nil)
+	e.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	// even the right code is refused once the attempts are used up
+	_, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: currentTestTOTPCode()}, userId)
+
+	e.Equal(dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS, err)
+	e.userRepository.AssertNotCalled(e.T(), "UpdateTOTPSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	e.logEmitter.AssertExpectations(e.T())
+}
+
+func (e *EnableTOTPServiceSuite) TestUserService_EnableTOTP_SecretOfAnotherUser() {
+	userId := "user-123"
+	// sealed for another user, the additional data does not match
+	sealed := sealTestTOTPSecret("user-456")
+
+	e.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+	e.redisRepository.On("GetResource", mock.Anything, "totpEnrollment:"+userId).Return(base64.StdEncoding.EncodeToString(sealed), nil)
+	e.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := e.userService.EnableTOTP(context.Background(), &dto.EnableTOTPRequest{Code: currentTestTOTPCode()}, userId)
+
+	e.Equal(dto.Err_INTERNAL_TOTP_SECRET, err)
+
+	time.Sleep(time.Second)
+	e.logEmitter.AssertExpectations(e.T())
+}
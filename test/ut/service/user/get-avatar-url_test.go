@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/pkg/avatar"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetAvatarURLServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (u *GetAvatarURLServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	u.userRepository = mockUserRepo
+	u.profileCache = mockProfileCache
+	u.fileService = mockFileService
+	u.outboxRepository = mockOutboxRepository
+	u.redisRepository = mockRedisRepository
+	u.logEmitter = mockLogEmitter
+	viper.Set("app.avatar_base_url", "https://files.example.com/image/")
+	viper.Set("app.default_avatar_base_url", "https://api.example.com/user/avatar/default/")
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (u *GetAvatarURLServiceSuite) SetupTest() {
+	u.userRepository.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
+	u.fileService.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
+	u.redisRepository.ExpectedCalls = nil
+	u.logEmitter.ExpectedCalls = nil
+
+	u.userRepository.Calls = nil
+	u.profileCache.Calls = nil
+	u.fileService.Calls = nil
+	u.outboxRepository.Calls = nil
+	u.redisRepository.Calls = nil
+	u.logEmitter.Calls = nil
+}
+
+func (u *GetAvatarURLServiceSuite) TearDownSuite() {
+	viper.Reset()
+}
+
+func TestGetAvatarURLServiceSuite(t *testing.T) {
+	suite.Run(t, &GetAvatarURLServiceSuite{})
+}
+
+func (u *GetAvatarURLServiceSuite) TestUserService_GetAvatarURL_Variant() {
+	image := "img_512.jpg"
+	u.profileCache.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{
+		User:          model.User{ID: "user-123", Image: &image},
+		ImageVariants: dto.ImageVariants{"64": "img_64.jpg", "256": "img_256.jpg", "512": "img_512.jpg"},
+	}, nil)
+
+	location, err := u.userService.GetAvatarURL(context.Background(), "user-123", "64")
+	u.NoError(err)
+	u.Equal("https://files.example.com/image/img_64.jpg", location)
+
+	location, err = u.userService.GetAvatarURL(context.Background(), "user-123", "")
+	u.NoError(err)
+	u.Equal("https://files.example.com/image/img_512.jpg", location)
+}
+
+func (u *GetAvatarURLServiceSuite) TestUserService_GetAvatarURL_LegacyImage() {
+	image := "legacy.png"
+	u.profileCache.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{
+		User: model.User{ID: "user-123", Image: &image},
+	}, nil)
+
+	location, err := u.userService.GetAvatarURL(context.Background(), "user-123", "256")
+	u.NoError(err)
+	u.Equal("https://files.example.com/image/legacy.png", location)
+}
+
+func (u *GetAvatarURLServiceSuite) TestUserService_GetAvatarURL_NoAvatar() {
+	u.profileCache.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{
+		User: model.User{ID: "user-123"},
+	}, nil)
+
+	location, err := u.userService.GetAvatarURL(context.Background(), "user-123", "")
+	u.NoError(err)
+	u.Equal("https://api.example.com/user/avatar/default/user-123", location)
+}
+
+func (u *GetAvatarURLServiceSuite) TestUserService_GetAvatarURL_InvalidSize() {
+	_, err := u.userService.GetAvatarURL(context.Background(), "user-123", "128")
+	u.Equal(dto.Err_BAD_REQUEST_INVALID_AVATAR_SIZE, err)
+	u.profileCache.AssertNotCalled(u.T(), "QueryProfileByUserId", mock.Anything, mock.Anything)
+}
+
+func (u *GetAvatarURLServiceSuite) TestUserService_GetDefaultAvatar_OnlyFromId() {
+	u.profileCache.On("QueryProfileByUserId", mock.Anything, "user-123").Return(&dto.UserProfile{
+		User: model.User{ID: "user-123", FullName: "John Doe"},
+	}, nil)
+
+	svg, err := u.userService.GetDefaultAvatar(context.Background(), "user-123")
+	u.NoError(err)
+	u.Equal(avatar.Default("user-123"), svg)
+	u.NotContains(string(svg), "JD")
+	u.NotContains(string(svg), "<text")
+}
+
+func (u *GetAvatarURLServiceSuite) TestUserService_GetDefaultAvatar_NotFound() {
+	u.profileCache.On("QueryProfileByUserId", mock.Anything, "user-123").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	svg, err := u.userService.GetDefaultAvatar(context.Background(), "user-123")
+	u.Nil(svg)
+	u.ErrorIs(err, dto.Err_NOTFOUND_USER_NOT_FOUND)
+}
@@ -1,56 +1,62 @@
 package service_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/service"
 	mk "github.com/micros-template/user-service/test/mocks"
 
 	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type GetProfileServiceSuite struct {
 	suite.Suite
-	userService        service.UserService
-	userRepository     *mk.UserRepositoryMock
-	eventEmitter       *mk.EmitterMock
-	fileService        *mk.MockFileServiceClient
-	notificationStream *mk.MockNatsInfra
-	redisRepository    *mk.MockRedisRepository
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
 }
 
 func (g *GetProfileServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mk.UserRepositoryMock)
-	mockEventEmitter := new(mk.EmitterMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
 	mockFileService := new(mk.MockFileServiceClient)
-	mockNotificationStream := new(mk.MockNatsInfra)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
 	mockRedisRepository := new(mk.MockRedisRepository)
 	mockLogEmitter := new(mk.LoggerInfraMock)
 
 	logger := zerolog.Nop()
 	g.userRepository = mockUserRepo
-	g.eventEmitter = mockEventEmitter
+	g.profileCache = mockProfileCache
 	g.fileService = mockFileService
-	g.notificationStream = mockNotificationStream
+	g.outboxRepository = mockOutboxRepository
 	g.redisRepository = mockRedisRepository
-	g.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockNotificationStream, mockEventEmitter, mockLogEmitter)
+	viper.Set("app.default_avatar_base_url", "https://api.example.com/user/avatar/default/")
+	g.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
 }
 
 func (g *GetProfileServiceSuite) SetupTest() {
 	g.userRepository.ExpectedCalls = nil
-	g.eventEmitter.ExpectedCalls = nil
+	g.profileCache.ExpectedCalls = nil
 	g.fileService.ExpectedCalls = nil
-	g.notificationStream.ExpectedCalls = nil
+	g.outboxRepository.ExpectedCalls = nil
 	g.redisRepository.ExpectedCalls = nil
 
 	g.userRepository.Calls = nil
-	g.eventEmitter.Calls = nil
+	g.profileCache.Calls = nil
 	g.fileService.Calls = nil
-	g.notificationStream.Calls = nil
+	g.outboxRepository.Calls = nil
 	g.redisRepository.Calls = nil
 }
 
@@ -60,16 +66,19 @@ func TestGetProfileServiceSuite(t *testing.T) {
 func (g *GetProfileServiceSuite) TestUserService_GetProfile_Success() {
 	userId := "user-123"
 	image := "image.png"
-	expectedUser := &model.User{
-		FullName:         "John Doe",
-		Image:            &image,
-		Email:            "john@example.com",
-		Verified:         true,
-		TwoFactorEnabled: true,
+	expectedUser := &dto.UserProfile{
+		User: model.User{
+			FullName:         "John Doe",
+			Image:            &image,
+			Email:            "john@example.com",
+			Verified:         true,
+			TwoFactorEnabled: true,
+		},
+		ImageVariants: dto.ImageVariants{"64": "image_64.png", "256": "image_256.png", "512": "image.png"},
 	}
-	g.userRepository.On("QueryUserByUserId", userId).Return(expectedUser, nil)
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, userId).Return(expectedUser, nil)
 
-	profile, err := g.userService.GetProfile(userId)
+	profile, err := g.userService.GetProfile(context.Background(), userId)
 
 	g.NoError(err)
 	g.Equal(expectedUser.FullName, profile.FullName)
@@ -77,16 +86,66 @@ func (g *GetProfileServiceSuite) TestUserService_GetProfile_Success() {
 	g.Equal(expectedUser.Email, profile.Email)
 	g.Equal(expectedUser.Verified, profile.Verified)
 	g.Equal(expectedUser.TwoFactorEnabled, profile.TwoFactorEnabled)
-	g.userRepository.AssertExpectations(g.T())
+	g.Equal(expectedUser.ImageVariants, profile.ImageVariants)
+	g.False(profile.DefaultImage)
+	g.profileCache.AssertExpectations(g.T())
+}
+
+func (g *GetProfileServiceSuite) TestUserService_GetProfile_DefaultImage() {
+	userId := "user-123"
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, FullName: "John Doe", Email: "john@example.com"},
+	}, nil)
+
+	profile, err := g.userService.GetProfile(context.Background(), userId)
+
+	g.NoError(err)
+	g.Require().NotNil(profile.Image)
+	g.Equal("https://api.example.com/user/avatar/default/user-123", *profile.Image)
+	g.True(profile.DefaultImage)
+	g.profileCache.AssertExpectations(g.T())
+}
+
+func (g *GetProfileServiceSuite) TestUserService_GetProfile_EmptyImageDefaultImage() {
+	userId := "user-123"
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, FullName: "John Doe", Email: "john@example.com", Image: utils.StringPtr("")},
+	}, nil)
+
+	profile, err := g.userService.GetProfile(context.Background(), userId)
+
+	g.NoError(err)
+	g.Require().NotNil(profile.Image)
+	g.Equal("https://api.example.com/user/avatar/default/user-123", *profile.Image)
+	g.True(profile.DefaultImage)
+	g.profileCache.AssertExpectations(g.T())
+}
+
+func (g *GetProfileServiceSuite) TestUserService_GetProfile_ProfileFields() {
+	userId := "user-123"
+	username := "john.doe"
+	dateOfBirth := time.Date(1990, time.January, 31, 0, 0, 0, 0, time.UTC)
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe"},
+		ProfileFields: dto.ProfileFields{Username: &username, DateOfBirth: &dateOfBirth},
+	}, nil)
+
+	profile, err := g.userService.GetProfile(context.Background(), userId)
+
+	g.NoError(err)
+	g.Equal(&username, profile.Username)
+	g.Nil(profile.Bio)
+	g.Require().NotNil(profile.DateOfBirth)
+	g.Equal("1990-01-31", *profile.DateOfBirth)
 }
 
 func (g *GetProfileServiceSuite) TestUserService_GetProfile_UserNotFound() {
 	userId := "user-404"
-	g.userRepository.On("QueryUserByUserId", userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+	g.profileCache.On("QueryProfileByUserId", mock.Anything, userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
 
-	profile, err := g.userService.GetProfile(userId)
+	profile, err := g.userService.GetProfile(context.Background(), userId)
 
 	g.Error(err)
 	g.Empty(profile)
-	g.userRepository.AssertExpectations(g.T())
+	g.profileCache.AssertExpectations(g.T())
 }
@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListUsersServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (l *ListUsersServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	l.userRepository = mockUserRepo
+	l.profileCache = mockProfileCache
+	l.fileService = mockFileService
+	l.outboxRepository = mockOutboxRepository
+	l.redisRepository = mockRedisRepository
+	l.logEmitter = mockLogEmitter
+	l.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (l *ListUsersServiceSuite) SetupTest() {
+	l.userRepository.ExpectedCalls = nil
+	l.profileCache.ExpectedCalls = nil
+	l.fileService.ExpectedCalls = nil
+	l.outboxRepository.ExpectedCalls = nil
+	l.redisRepository.ExpectedCalls = nil
+	l.logEmitter.ExpectedCalls = nil
+
+	l.userRepository.Calls = nil
+	l.profileCache.Calls = nil
+	l.fileService.Calls = nil
+	l.outboxRepository.Calls = nil
+	l.redisRepository.Calls = nil
+	l.logEmitter.Calls = nil
+}
+
+func TestListUsersServiceSuite(t *testing.T) {
+	suite.Run(t, &ListUsersServiceSuite{})
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_Defaults() {
+	l.userRepository.On("ListUsers", mock.Anything, mock.MatchedBy(func(q *dto.ListUsersQuery) bool {
+		return q.Limit == 21 && q.SortBy == "created_at" && q.SortDesc && q.After == nil
+	})).Return([]dto.UserListItem{{ID: "user-1"}}, nil)
+
+	res, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{})
+
+	l.NoError(err)
+	l.Len(res.Users, 1)
+	l.Nil(res.NextCursor)
+	l.userRepository.AssertExpectations(l.T())
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_NextCursor() {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []dto.UserListItem{
+		{ID: "user-1", CreatedAt: createdAt.Add(time.Hour)},
+		{ID: "user-2", CreatedAt: createdAt},
+		{ID: "user-3", CreatedAt: createdAt.Add(-time.Hour)},
+	}
+	l.userRepository.On("ListUsers", mock.Anything, mock.MatchedBy(func(q *dto.ListUsersQuery) bool {
+		return q.Limit == 3
+	})).Return(users, nil)
+
+	res, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{Limit: 2})
+
+	l.NoError(err)
+	l.Len(res.Users, 2)
+	l.NotNil(res.NextCursor)
+
+	raw, err := base64.RawURLEncoding.DecodeString(*res.NextCursor)
+	l.NoError(err)
+	var cursor dto.ListUsersCursor
+	l.NoError(json.Unmarshal(raw, &cursor))
+	l.Equal(dto.ListUsersCursor{SortBy: "created_at", Desc: true, Value: createdAt.Format(time.RFC3339Nano), ID: "user-2"}, cursor)
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_WithCursor() {
+	raw, _ := json.Marshal(dto.ListUsersCursor{SortBy: "email", Value: "john@example.com", ID: "user-1"})
+	cursor := base64.RawURLEncoding.EncodeToString(raw)
+	l.userRepository.On("ListUsers", mock.Anything, mock.MatchedBy(func(q *dto.ListUsersQuery) bool {
+		return q.SortBy == "email" && !q.SortDesc && q.After != nil && q.After.ID == "user-1" && q.After.Value == "john@example.com"
+	})).Return([]dto.UserListItem{}, nil)
+
+	res, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{Cursor: cursor, SortBy: "email", SortOrder: "asc"})
+
+	l.NoError(err)
+	l.Empty(res.Users)
+	l.Nil(res.NextCursor)
+	l.userRepository.AssertExpectations(l.T())
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_InvalidCursor() {
+	l.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{Cursor: "%%%"})
+
+	l.Equal(dto.Err_BAD_REQUEST_INVALID_CURSOR, err)
+	l.userRepository.AssertNotCalled(l.T(), "ListUsers", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	l.logEmitter.AssertExpectations(l.T())
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_CursorSortMismatch() {
+	raw, _ := json.Marshal(dto.ListUsersCursor{SortBy: "email", Value: "john@example.com", ID: "user-1"})
+	l.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{Cursor: base64.RawURLEncoding.EncodeToString(raw), SortBy: "full_name"})
+
+	l.Equal(dto.Err_BAD_REQUEST_INVALID_CURSOR, err)
+	l.userRepository.AssertNotCalled(l.T(), "ListUsers", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	l.logEmitter.AssertExpectations(l.T())
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_CursorDirectionMismatch() {
+	raw, _ := json.Marshal(dto.ListUsersCursor{SortBy: "email", Value: "john@example.com", ID: "user-1"})
+	l.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	// issued for an ascending page, replaying it descending would skip or repeat rows
+	_, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{Cursor: base64.RawURLEncoding.EncodeToString(raw), SortBy: "email", SortOrder: "desc"})
+
+	l.Equal(dto.Err_BAD_REQUEST_INVALID_CURSOR, err)
+	l.userRepository.AssertNotCalled(l.T(), "ListUsers", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	l.logEmitter.AssertExpectations(l.T())
+}
+
+func (l *ListUsersServiceSuite) TestUserService_ListUsers_RepositoryError() {
+	l.userRepository.On("ListUsers", mock.Anything, mock.Anything).Return(nil, dto.Err_INTERNAL_FAILED_LIST_USERS)
+
+	_, err := l.userService.ListUsers(context.Background(), &dto.ListUsersRequest{})
+
+	l.Equal(dto.Err_INTERNAL_FAILED_LIST_USERS, err)
+}
@@ -0,0 +1,216 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasskeyRegistrationServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (p *PasskeyRegistrationServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	p.userRepository = mockUserRepo
+	p.profileCache = mockProfileCache
+	p.fileService = mockFileService
+	p.outboxRepository = mockOutboxRepository
+	p.redisRepository = mockRedisRepository
+	p.logEmitter = mockLogEmitter
+	p.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.webauthn.rp_id", "localhost")
+	viper.Set("app.webauthn.rp_display_name", "dropboks")
+	viper.Set("app.webauthn.rp_origins", []string{"http://localhost:9090"})
+	viper.Set("app.webauthn.session_ttl", "5m")
+}
+
+func (p *PasskeyRegistrationServiceSuite) SetupTest() {
+	p.userRepository.ExpectedCalls = nil
+	p.profileCache.ExpectedCalls = nil
+	p.fileService.ExpectedCalls = nil
+	p.outboxRepository.ExpectedCalls = nil
+	p.redisRepository.ExpectedCalls = nil
+	p.logEmitter.ExpectedCalls = nil
+
+	p.userRepository.Calls = nil
+	p.profileCache.Calls = nil
+	p.fileService.Calls = nil
+	p.outboxRepository.Calls = nil
+	p.redisRepository.Calls = nil
+	p.logEmitter.Calls = nil
+}
+
+func TestPasskeyRegistrationServiceSuite(t *testing.T) {
+	suite.Run(t, &PasskeyRegistrationServiceSuite{})
+}
+
+// runs the begin step and returns the options with the session it stored
+func (p *PasskeyRegistrationServiceSuite) beginRegistration(userId string, existing []dto.WebAuthnCredential) (*protocol.CredentialCreation, string) {
+	var session string
+	p.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, FullName: "John Doe", Email: "john@example.com"},
+	}, nil).Once()
+	p.userRepository.On("QueryWebAuthnCredentials", mock.Anything, userId).Return(existing, nil).Once()
+	p.redisRepository.On("SetResource", mock.Anything, "passkeyRegistration:"+userId, mock.AnythingOfType("string"), 5*time.Minute).
+		Run(func(args mock.Arguments) { session = args.String(2) }).
+		Return(nil).Once()
+
+	creation, err := p.userService.BeginPasskeyRegistration(context.Background(), userId)
+	p.Require().NoError(err)
+	return creation, session
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_BeginPasskeyRegistration_Success() {
+	userId := "user-123"
+	existing := []dto.WebAuthnCredential{{ID: 1, CredentialID: []byte("existing-credential"), Transports: []string{"usb"}}}
+
+	creation, session := p.beginRegistration(userId, existing)
+
+	p.Equal("localhost", creation.Response.RelyingParty.ID)
+	p.Equal([]byte(userId), []byte(creation.Response.User.ID.(protocol.URLEncodedBase64)))
+	p.Equal("john@example.com", creation.Response.User.Name)
+	p.Len(creation.Response.CredentialExcludeList, 1)
+	p.Equal([]byte("existing-credential"), []byte(creation.Response.CredentialExcludeList[0].CredentialID))
+	p.Contains(session, creation.Response.Challenge.String())
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_FinishPasskeyRegistration_Success() {
+	userId := "user-123"
+	authenticator := mk.NewSoftwareAuthenticator("http://localhost:9090")
+	creation, session := p.beginRegistration(userId, nil)
+
+	p.redisRepository.On("GetResource", mock.Anything, "passkeyRegistration:"+userId).Return(session, nil)
+	p.redisRepository.On("RemoveResource", mock.Anything, "passkeyRegistration:"+userId).Return(nil)
+	p.userRepository.On("CreateWebAuthnCredential", mock.Anything, mock.MatchedBy(func(credential *dto.WebAuthnCredential) bool {
+		return credential.UserID == userId &&
+			string(credential.CredentialID) == string(authenticator.CredentialID) &&
+			credential.Name == "Laptop" &&
+			credential.AttestationType == "none" &&
+			len(credential.PublicKey) > 0
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*dto.WebAuthnCredential).ID = 7
+	}).Return(nil)
+
+	res, err := p.userService.FinishPasskeyRegistration(context.Background(), &dto.FinishPasskeyRegistrationRequest{
+		Name:       "Laptop",
+		Credential: authenticator.Register(creation),
+	}, userId)
+
+	p.NoError(err)
+	p.Equal(int64(7), res.ID)
+	p.Equal("Laptop", res.Name)
+	p.Equal([]string{"internal"}, res.Transports)
+	p.userRepository.AssertExpectations(p.T())
+	p.redisRepository.AssertExpectations(p.T())
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_FinishPasskeyRegistration_WrongChallenge() {
+	userId := "user-123"
+	authenticator := mk.NewSoftwareAuthenticator("http://localhost:9090")
+	creation, session := p.beginRegistration(userId, nil)
+
+	p.redisRepository.On("GetResource", mock.Anything, "passkeyRegistration:"+userId).Return(session, nil)
+	p.redisRepository.On("RemoveResource", mock.Anything, "passkeyRegistration:"+userId).Return(nil)
+	p.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := p.userService.FinishPasskeyRegistration(context.Background(), &dto.FinishPasskeyRegistrationRequest{
+		Name:       "Laptop",
+		Credential: authenticator.RegisterWithChallenge(creation, []byte("another-challenge-that-was-never-issued")),
+	}, userId)
+
+	p.Equal(dto.Err_BAD_REQUEST_INVALID_PASSKEY, err)
+	p.userRepository.AssertNotCalled(p.T(), "CreateWebAuthnCredential", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_FinishPasskeyRegistration_WrongOrigin() {
+	userId := "user-123"
+	authenticator := mk.NewSoftwareAuthenticator("https://phishing.example")
+	creation, session := p.beginRegistration(userId, nil)
+
+	p.redisRepository.On("GetResource", mock.Anything, "passkeyRegistration:"+userId).Return(session, nil)
+	p.redisRepository.On("RemoveResource", mock.Anything, "passkeyRegistration:"+userId).Return(nil)
+	p.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := p.userService.FinishPasskeyRegistration(context.Background(), &dto.FinishPasskeyRegistrationRequest{
+		Name:       "Laptop",
+		Credential: authenticator.Register(creation),
+	}, userId)
+
+	p.Equal(dto.Err_BAD_REQUEST_INVALID_PASSKEY, err)
+	p.userRepository.AssertNotCalled(p.T(), "CreateWebAuthnCredential", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	p.logEmitter.AssertExpectations(p.T())
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_FinishPasskeyRegistration_NoSession() {
+	userId := "user-123"
+	p.redisRepository.On("GetResource", mock.Anything, "passkeyRegistration:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND)
+
+	_, err := p.userService.FinishPasskeyRegistration(context.Background(), &dto.FinishPasskeyRegistrationRequest{
+		Name:       "Laptop",
+		Credential: []byte(`{}`),
+	}, userId)
+
+	p.Equal(dto.Err_NOTFOUND_PASSKEY_SESSION, err)
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_FinishPasskeyRegistration_AlreadyRegistered() {
+	userId := "user-123"
+	authenticator := mk.NewSoftwareAuthenticator("http://localhost:9090")
+	creation, session := p.beginRegistration(userId, nil)
+
+	p.redisRepository.On("GetResource", mock.Anything, "passkeyRegistration:"+userId).Return(session, nil)
+	p.redisRepository.On("RemoveResource", mock.Anything, "passkeyRegistration:"+userId).Return(nil)
+	p.userRepository.On("CreateWebAuthnCredential", mock.Anything, mock.Anything).Return(dto.Err_CONFLICT_PASSKEY_EXIST)
+
+	_, err := p.userService.FinishPasskeyRegistration(context.Background(), &dto.FinishPasskeyRegistrationRequest{
+		Name:       "Laptop",
+		Credential: authenticator.Register(creation),
+	}, userId)
+
+	p.Equal(dto.Err_CONFLICT_PASSKEY_EXIST, err)
+}
+
+func (p *PasskeyRegistrationServiceSuite) TestUserService_ListPasskeys_Success() {
+	userId := "user-123"
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	p.userRepository.On("QueryWebAuthnCredentials", mock.Anything, userId).Return([]dto.WebAuthnCredential{
+		{ID: 1, Name: "Laptop", Transports: []string{"internal"}, BackupState: true, CreatedAt: createdAt},
+	}, nil)
+
+	res, err := p.userService.ListPasskeys(context.Background(), userId)
+
+	p.NoError(err)
+	p.Equal([]dto.PasskeyResponse{{ID: 1, Name: "Laptop", Transports: []string{"internal"}, Synced: true, CreatedAt: createdAt}}, res)
+}
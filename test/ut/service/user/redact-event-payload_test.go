@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RedactEventPayloadUserServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (r *RedactEventPayloadUserServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	r.userRepository = mockUserRepo
+	r.profileCache = mockProfileCache
+	r.fileService = mockFileService
+	r.outboxRepository = mockOutboxRepository
+	r.redisRepository = mockRedisRepository
+	r.logEmitter = mockLogEmitter
+	r.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (r *RedactEventPayloadUserServiceSuite) SetupTest() {
+	r.userRepository.ExpectedCalls = nil
+	r.profileCache.ExpectedCalls = nil
+	r.fileService.ExpectedCalls = nil
+	r.outboxRepository.ExpectedCalls = nil
+	r.redisRepository.ExpectedCalls = nil
+	r.logEmitter.ExpectedCalls = nil
+
+	r.userRepository.Calls = nil
+	r.profileCache.Calls = nil
+	r.fileService.Calls = nil
+	r.outboxRepository.Calls = nil
+	r.redisRepository.Calls = nil
+	r.logEmitter.Calls = nil
+}
+
+func (r *RedactEventPayloadUserServiceSuite) TearDownTest() {
+	viper.Reset()
+}
+
+func TestRedactEventPayloadUserServiceSuite(t *testing.T) {
+	suite.Run(t, &RedactEventPayloadUserServiceSuite{})
+}
+
+func (r *RedactEventPayloadUserServiceSuite) TestUserService_UpdatePassword_PayloadWithoutPassword() {
+	userId := "user-123"
+	oldPassword := "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6"
+	newPassword := "Tr1cky-Horse"
+	req := &dto.UpdatePasswordRequest{
+		Password:           "password123",
+		NewPassword:        newPassword,
+		ConfirmNewPassword: newPassword,
+	}
+	user := &model.User{
+		ID:       userId,
+		FullName: "John Doe",
+		Email:    "john@example.com",
+		Password: oldPassword,
+		Verified: true,
+	}
+	r.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(user, nil).Once()
+	r.userRepository.On("UpdateUserPassword", mock.Anything, userId, mock.MatchedBy(func(hash string) bool {
+		return utils.HashPasswordCompare(newPassword, hash)
+	}), oldPassword, 0, mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
+	r.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+
+	err := r.userService.UpdatePassword(context.Background(), req, userId)
+	r.NoError(err)
+
+	r.userRepository.AssertExpectations(r.T())
+	r.profileCache.AssertExpectations(r.T())
+	outbox := r.userRepository.Calls[1].Arguments.Get(5).([]*dto.OutboxMessage)
+	r.Len(outbox, 1)
+	payload := mk.DecodeUserEvent(outbox[0]).GetUserUpdated()
+	r.Empty(payload.GetPassword())
+	r.Equal(userId, payload.GetId())
+	r.Equal("John Doe", payload.GetFullName())
+	r.Equal("john@example.com", payload.GetEmail())
+	r.True(payload.GetVerified())
+}
+
+func (r *RedactEventPayloadUserServiceSuite) TestUserService_VerifyEmailChange_ConfiguredFieldsOnly() {
+	viper.Set("jetstream.event.payload_fields.update_user", []string{"email", "password"})
+	userId := "user-123"
+	user := &model.User{
+		ID:       userId,
+		FullName: "John Doe",
+		Email:    "old@example.com",
+		Password: "$2a$10$hashedpassword",
+		Verified: true,
+	}
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+
+	r.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	r.redisRepository.On("TakeResource", mock.Anything, "newEmail:"+userId).Return("new@example.com", nil).Once()
+	r.userRepository.On("QueryUserByEmail", mock.Anything, "new@example.com").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+	r.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: *user, Version: 7}, nil).Once()
+	r.userRepository.On("UpdateUser", mock.Anything, mock.AnythingOfType("*model.User"), int64(7), mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
+	r.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+
+	err := r.userService.VerifyEmailChange(context.Background(), req, userId)
+	r.NoError(err)
+
+	r.userRepository.AssertExpectations(r.T())
+	r.redisRepository.AssertExpectations(r.T())
+	outbox := r.userRepository.Calls[2].Arguments.Get(3).([]*dto.OutboxMessage)
+	r.Len(outbox, 1)
+	payload := mk.DecodeUserEvent(outbox[0]).GetUserUpdated()
+	r.Equal(userId, payload.GetId())
+	r.Equal("new@example.com", payload.GetEmail())
+	r.Empty(payload.GetFullName())
+	r.False(payload.GetVerified())
+	r.Empty(payload.GetPassword())
+}
+
+func (r *RedactEventPayloadUserServiceSuite) TestUserService_UpdateUser_PayloadWithoutPassword() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{FullName: ptr("Updated Name")}
+	user := &dto.UserProfile{User: model.User{
+		ID:       userId,
+		FullName: "Original Name",
+		Email:    "john@example.com",
+		Password: "$2a$10$hashedpassword",
+	}, Version: 4}
+	r.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil).Once()
+	r.userRepository.On("UpdateProfile", mock.Anything, mock.Anything, int64(4), mock.AnythingOfType("[]*dto.OutboxMessage")).Return(nil).Once()
+	r.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+
+	_, err := r.userService.UpdateUser(context.Background(), req, userId)
+	r.NoError(err)
+
+	r.userRepository.AssertExpectations(r.T())
+	outbox := r.userRepository.Calls[1].Arguments.Get(3).([]*dto.OutboxMessage)
+	r.Len(outbox, 1)
+	payload := mk.DecodeUserEvent(outbox[0]).GetUserUpdated()
+	r.Empty(payload.GetPassword())
+	r.Equal(userId, payload.GetId())
+	r.Equal("Updated Name", payload.GetFullName())
+	r.Equal("john@example.com", payload.GetEmail())
+}
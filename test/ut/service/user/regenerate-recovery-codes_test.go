@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RegenerateRecoveryCodesServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (r *RegenerateRecoveryCodesServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	r.userRepository = mockUserRepo
+	r.profileCache = mockProfileCache
+	r.fileService = mockFileService
+	r.outboxRepository = mockOutboxRepository
+	r.redisRepository = mockRedisRepository
+	r.logEmitter = mockLogEmitter
+	r.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.two_factor.encryption_key", testTOTPEncryptionKey)
+	viper.Set("app.two_factor.max_attempts", 5)
+	viper.Set("app.two_factor.attempt_window", "15m")
+}
+
+func (r *RegenerateRecoveryCodesServiceSuite) SetupTest() {
+	r.userRepository.ExpectedCalls = nil
+	r.profileCache.ExpectedCalls = nil
+	r.fileService.ExpectedCalls = nil
+	r.outboxRepository.ExpectedCalls = nil
+	r.redisRepository.ExpectedCalls = nil
+	r.logEmitter.ExpectedCalls = nil
+
+	r.userRepository.Calls = nil
+	r.profileCache.Calls = nil
+	r.fileService.Calls = nil
+	r.outboxRepository.Calls = nil
+	r.redisRepository.Calls = nil
+	r.logEmitter.Calls = nil
+}
+
+func TestRegenerateRecoveryCodesServiceSuite(t *testing.T) {
+	suite.Run(t, &RegenerateRecoveryCodesServiceSuite{})
+}
+
+func (r *RegenerateRecoveryCodesServiceSuite) TestUserService_RegenerateRecoveryCodes_WithPassword() {
+	userId := "user-123"
+	hashedPassword, _ := utils.HashPassword("password123")
+	r.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Password: hashedPassword, TwoFactorEnabled: true},
+	}, nil)
+	r.userRepository.On("ReplaceRecoveryCodes", mock.Anything, userId, mock.MatchedBy(func(codeHashes []string) bool {
+		return len(codeHashes) == 10
+	})).Return(nil)
+
+	res, err := r.userService.RegenerateRecoveryCodes(context.Background(), &dto.RegenerateRecoveryCodesRequest{Password: "password123"}, userId)
+
+	r.NoError(err)
+	r.Len(res.RecoveryCodes, 10)
+	r.userRepository.AssertExpectations(r.T())
+}
+
+func (r *RegenerateRecoveryCodesServiceSuite) TestUserService_RegenerateRecoveryCodes_WrongPassword() {
+	userId := "user-123"
+	hashedPassword, _ := utils.HashPassword("password123")
+	r.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Password: hashedPassword, TwoFactorEnabled: true},
+	}, nil)
+	r.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := r.userService.RegenerateRecoveryCodes(context.Background(), &dto.RegenerateRecoveryCodesRequest{Password: "wrong"}, userId)
+
+	r.Equal(dto.Err_UNAUTHORIZED_PASSWORD_WRONG, err)
+	r.userRepository.AssertNotCalled(r.T(), "ReplaceRecoveryCodes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (r *RegenerateRecoveryCodesServiceSuite) TestUserService_RegenerateRecoveryCodes_TwoFactorDisabled() {
+	userId := "user-123"
+	r.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId},
+	}, nil)
+
+	_, err := r.userService.RegenerateRecoveryCodes(context.Background(), &dto.RegenerateRecoveryCodesRequest{Password: "password123"}, userId)
+
+	r.Equal(dto.Err_CONFLICT_TWO_FACTOR_DISABLED, err)
+	r.userRepository.AssertNotCalled(r.T(), "ReplaceRecoveryCodes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (r *RegenerateRecoveryCodesServiceSuite) TestUserService_CountRecoveryCodes_Success() {
+	userId := "user-123"
+	r.userRepository.On("CountRecoveryCodes", mock.Anything, userId).Return(7, nil)
+
+	res, err := r.userService.CountRecoveryCodes(context.Background(), userId)
+
+	r.NoError(err)
+	r.Equal(7, res.Remaining)
+}
@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RestoreUserServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (r *RestoreUserServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	r.userRepository = mockUserRepo
+	r.profileCache = mockProfileCache
+	r.fileService = mockFileService
+	r.outboxRepository = mockOutboxRepository
+	r.redisRepository = mockRedisRepository
+	r.logEmitter = mockLogEmitter
+	r.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (r *RestoreUserServiceSuite) SetupTest() {
+	r.userRepository.ExpectedCalls = nil
+	r.profileCache.ExpectedCalls = nil
+	r.fileService.ExpectedCalls = nil
+	r.outboxRepository.ExpectedCalls = nil
+	r.redisRepository.ExpectedCalls = nil
+	r.logEmitter.ExpectedCalls = nil
+
+	r.userRepository.Calls = nil
+	r.profileCache.Calls = nil
+	r.fileService.Calls = nil
+	r.outboxRepository.Calls = nil
+	r.redisRepository.Calls = nil
+	r.logEmitter.Calls = nil
+}
+
+func TestRestoreUserServiceSuite(t *testing.T) {
+	suite.Run(t, &RestoreUserServiceSuite{})
+}
+
+func (r *RestoreUserServiceSuite) TestUserService_RestoreUser_Success() {
+	r.userRepository.On("RestoreUser", mock.Anything, "userid-123", mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := r.userService.RestoreUser(context.Background(), "userid-123")
+
+	r.NoError(err)
+	r.userRepository.AssertExpectations(r.T())
+}
+
+func (r *RestoreUserServiceSuite) TestUserService_RestoreUser_WindowExpired() {
+	r.userRepository.On("RestoreUser", mock.Anything, "userid-123", mock.AnythingOfType("time.Time")).Return(dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	err := r.userService.RestoreUser(context.Background(), "userid-123")
+
+	r.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	r.userRepository.AssertExpectations(r.T())
+}
+
+func (r *RestoreUserServiceSuite) TestUserService_PurgeDeletedUsers_StoreDeleteEvent() {
+	r.userRepository.On("PurgeDeletedUsers", mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything).Return([]string{"user-1", "user-2"}, nil)
+
+	purged, err := r.userService.PurgeDeletedUsers(context.Background())
+
+	r.NoError(err)
+	r.Equal(2, purged)
+	r.userRepository.AssertExpectations(r.T())
+
+	newEvent := r.userRepository.Calls[0].Arguments.Get(2).(func(string) (*dto.OutboxMessage, error))
+	msg, err := newEvent("user-1")
+	r.NoError(err)
+	r.Equal("user-1", mk.DecodeUserEvent(msg).GetUserDeleted().GetId())
+}
+
+func (r *RestoreUserServiceSuite) TestUserService_PurgeDeletedUsers_RepositoryError() {
+	r.userRepository.On("PurgeDeletedUsers", mock.Anything, mock.AnythingOfType("time.Time"), mock.Anything).Return(nil, dto.Err_INTERNAL_FAILED_PURGE_USER)
+
+	purged, err := r.userService.PurgeDeletedUsers(context.Background())
+
+	r.Equal(dto.Err_INTERNAL_FAILED_PURGE_USER, err)
+	r.Zero(purged)
+}
@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/png"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/pkg/secret"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// 32 zero bytes, only used by the unit tests
+const testTOTPEncryptionKey = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+
+type SetupTOTPServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (s *SetupTOTPServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	s.userRepository = mockUserRepo
+	s.profileCache = mockProfileCache
+	s.fileService = mockFileService
+	s.outboxRepository = mockOutboxRepository
+	s.redisRepository = mockRedisRepository
+	s.logEmitter = mockLogEmitter
+	s.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.two_factor.issuer", "dropboks")
+	viper.Set("app.two_factor.encryption_key", testTOTPEncryptionKey)
+	viper.Set("app.two_factor.enrollment_ttl", "10m")
+}
+
+func (s *SetupTOTPServiceSuite) SetupTest() {
+	s.userRepository.ExpectedCalls = nil
+	s.profileCache.ExpectedCalls = nil
+	s.fileService.ExpectedCalls = nil
+	s.outboxRepository.ExpectedCalls = nil
+	s.redisRepository.ExpectedCalls = nil
+	s.logEmitter.ExpectedCalls = nil
+
+	s.userRepository.Calls = nil
+	s.profileCache.Calls = nil
+	s.fileService.Calls = nil
+	s.outboxRepository.Calls = nil
+	s.redisRepository.Calls = nil
+	s.logEmitter.Calls = nil
+}
+
+func TestSetupTOTPServiceSuite(t *testing.T) {
+	suite.Run(t, &SetupTOTPServiceSuite{})
+}
+
+func (s *SetupTOTPServiceSuite) TestUserService_SetupTOTP_Success() {
+	userId := "user-123"
+	s.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Email: "john@example.com"},
+	}, nil)
+	s.redisRepository.On("SetResource", mock.Anything, "totpEnrollment:"+userId, mock.Anything, 10*time.Minute).Return(nil)
+
+	setup, err := s.userService.SetupTOTP(context.Background(), userId)
+
+	s.NoError(err)
+	uri, err := url.Parse(setup.OtpauthURI)
+	s.NoError(err)
+	s.Equal("otpauth", uri.Scheme)
+	s.Equal("totp", uri.Host)
+	s.Equal("dropboks", uri.Query().Get("issuer"))
+	s.Equal(setup.Secret, uri.Query().Get("secret"))
+	s.Contains(uri.Path, "john@example.com")
+
+	qrCode, err := base64.StdEncoding.DecodeString(setup.QRCode)
+	s.NoError(err)
+	_, err = png.Decode(bytes.NewReader(qrCode))
+	s.NoError(err)
+
+	// the pending secret is only kept sealed and bound to the user
+	stored := s.redisRepository.Calls[0].Arguments.String(2)
+	sealed, err := base64.StdEncoding.DecodeString(stored)
+	s.NoError(err)
+	s.NotContains(string(sealed), setup.Secret)
+	key, _ := secret.ParseKey(testTOTPEncryptionKey)
+	plaintext, err := secret.Open(key, sealed, []byte(userId))
+	s.NoError(err)
+	s.Equal(setup.Secret, string(plaintext))
+	s.userRepository.AssertExpectations(s.T())
+	s.redisRepository.AssertExpectations(s.T())
+}
+
+func (s *SetupTOTPServiceSuite) TestUserService_SetupTOTP_AlreadyEnabled() {
+	userId := "user-123"
+	s.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Email: "john@example.com", TwoFactorEnabled: true},
+	}, nil)
+	s.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(sealTestTOTPSecret(userId), nil)
+
+	_, err := s.userService.SetupTOTP(context.Background(), userId)
+
+	s.Equal(dto.Err_CONFLICT_TWO_FACTOR_ENABLED, err)
+	s.redisRepository.AssertNotCalled(s.T(), "SetResource", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// the old toggle left users enabled without a secret, they can enroll again
+func (s *SetupTOTPServiceSuite) TestUserService_SetupTOTP_EnabledWithoutSecret() {
+	userId := "user-123"
+	s.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Email: "john@example.com", TwoFactorEnabled: true},
+	}, nil)
+	s.userRepository.On("QueryTOTPSecret", mock.Anything, userId).Return(nil, nil)
+	s.redisRepository.On("SetResource", mock.Anything, "totpEnrollment:"+userId, mock.Anything, 10*time.Minute).Return(nil)
+
+	setup, err := s.userService.SetupTOTP(context.Background(), userId)
+
+	s.NoError(err)
+	s.NotEmpty(setup.Secret)
+	s.userRepository.AssertExpectations(s.T())
+	s.redisRepository.AssertExpectations(s.T())
+}
+
+func (s *SetupTOTPServiceSuite) TestUserService_SetupTOTP_InvalidEncryptionKey() {
+	userId := "user-123"
+	viper.Set("app.two_factor.encryption_key", "too-short")
+	defer viper.Set("app.two_factor.encryption_key", testTOTPEncryptionKey)
+	s.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, Email: "john@example.com"},
+	}, nil)
+	s.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := s.userService.SetupTOTP(context.Background(), userId)
+
+	s.Equal(dto.Err_INTERNAL_TOTP_SECRET, err)
+	s.redisRepository.AssertNotCalled(s.T(), "SetResource", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	s.logEmitter.AssertExpectations(s.T())
+}
+
+func (s *SetupTOTPServiceSuite) TestUserService_SetupTOTP_UserNotFound() {
+	userId := "user-404"
+	s.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	_, err := s.userService.SetupTOTP(context.Background(), userId)
+
+	s.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+}
@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/proto-file/pkg/fpb"
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateAvatarServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (u *UpdateAvatarServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	u.userRepository = mockUserRepo
+	u.profileCache = mockProfileCache
+	u.fileService = mockFileService
+	u.outboxRepository = mockOutboxRepository
+	u.redisRepository = mockRedisRepository
+	u.logEmitter = mockLogEmitter
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (u *UpdateAvatarServiceSuite) SetupTest() {
+	u.userRepository.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
+	u.fileService.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
+	u.redisRepository.ExpectedCalls = nil
+	u.logEmitter.ExpectedCalls = nil
+
+	u.userRepository.Calls = nil
+	u.profileCache.Calls = nil
+	u.fileService.Calls = nil
+	u.outboxRepository.Calls = nil
+	u.redisRepository.Calls = nil
+	u.logEmitter.Calls = nil
+}
+
+func TestUpdateAvatarServiceSuite(t *testing.T) {
+	suite.Run(t, &UpdateAvatarServiceSuite{})
+}
+
+func (u *UpdateAvatarServiceSuite) TestUserService_UpdateAvatar_Success() {
+	userId := "user-123"
+	oldImage := "old_512.png"
+	user := &dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe", Image: &oldImage},
+		ImageVariants: dto.ImageVariants{"64": "old_64.png", "256": "old_256.png", "512": "old_512.png"},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	for _, name := range []string{"new_64.jpg", "new_256.jpg", "new_512.jpg"} {
+		u.fileService.On("SaveProfileImage", mock.Anything, mock.Anything).Return(&fpb.ImageName{Name: name}, nil).Once()
+	}
+	u.userRepository.On("UpdateUserImage", mock.Anything, mock.MatchedBy(func(us *model.User) bool {
+		return us.FullName == "John Doe" && us.Image != nil && *us.Image == "new_512.jpg"
+	}), dto.ImageVariants{"64": "new_64.jpg", "256": "new_256.jpg", "512": "new_512.jpg"}, mock.Anything, mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return len(outbox) == 1 && mk.DecodeUserEvent(outbox[0]).GetUserUpdated().GetImage() == "new_512.jpg"
+	})).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	for _, name := range []string{"old_64.png", "old_256.png", "old_512.png"} {
+		u.fileService.On("RemoveProfileImage", mock.Anything, &fpb.ImageName{Name: name}).Return(nil, nil).Once()
+	}
+
+	err := u.userService.UpdateAvatar(context.Background(), imageFileHeader(u.T(), "avatar.jpg", encodeJPEG(u.T(), 300, 300)), userId)
+
+	u.NoError(err)
+	u.userRepository.AssertExpectations(u.T())
+	u.fileService.AssertExpectations(u.T())
+	u.profileCache.AssertExpectations(u.T())
+}
+
+func (u *UpdateAvatarServiceSuite) TestUserService_UpdateAvatar_UpdateFailsRemovesNewFiles() {
+	userId := "user-123"
+	user := &dto.UserProfile{User: model.User{ID: userId}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	for _, name := range []string{"new_64.jpg", "new_256.jpg", "new_512.jpg"} {
+		u.fileService.On("SaveProfileImage", mock.Anything, mock.Anything).Return(&fpb.ImageName{Name: name}, nil).Once()
+		u.fileService.On("RemoveProfileImage", mock.Anything, &fpb.ImageName{Name: name}).Return(nil, nil).Once()
+	}
+	u.userRepository.On("UpdateUserImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dto.Err_INTERNAL_FAILED_UPDATE_USER).Once()
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := u.userService.UpdateAvatar(context.Background(), imageFileHeader(u.T(), "avatar.jpg", encodeJPEG(u.T(), 300, 300)), userId)
+
+	u.Equal(dto.Err_INTERNAL_FAILED_UPDATE_USER, err)
+	u.fileService.AssertExpectations(u.T())
+	u.profileCache.AssertNotCalled(u.T(), "Invalidate", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdateAvatarServiceSuite) TestUserService_UpdateAvatar_UserNotFound() {
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, "user-404").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+
+	err := u.userService.UpdateAvatar(context.Background(), imageFileHeader(u.T(), "avatar.jpg", encodeJPEG(u.T(), 300, 300)), "user-404")
+
+	u.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
+	u.fileService.AssertNotCalled(u.T(), "SaveProfileImage", mock.Anything, mock.Anything)
+}
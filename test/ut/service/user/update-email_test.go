@@ -1,8 +1,9 @@
 package service_test
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,7 +11,6 @@ import (
 	"github.com/micros-template/user-service/internal/domain/service"
 	mk "github.com/micros-template/user-service/test/mocks"
 
-	"github.com/nats-io/nats.go/jetstream"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -18,46 +18,46 @@ import (
 
 type UpdateEmailServiceSuite struct {
 	suite.Suite
-	userService        service.UserService
-	userRepository     *mk.UserRepositoryMock
-	eventEmitter       *mk.EmitterMock
-	fileService        *mk.MockFileServiceClient
-	notificationStream *mk.MockNatsInfra
-	redisRepository    *mk.MockRedisRepository
-	logEmitter         *mk.LoggerInfraMock
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
 }
 
 func (u *UpdateEmailServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mk.UserRepositoryMock)
-	mockEventEmitter := new(mk.EmitterMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
 	mockFileService := new(mk.MockFileServiceClient)
-	mockNotificationStream := new(mk.MockNatsInfra)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
 	mockRedisRepository := new(mk.MockRedisRepository)
 	mockLogEmitter := new(mk.LoggerInfraMock)
 
 	logger := zerolog.Nop()
 	u.userRepository = mockUserRepo
-	u.eventEmitter = mockEventEmitter
+	u.profileCache = mockProfileCache
 	u.fileService = mockFileService
-	u.notificationStream = mockNotificationStream
+	u.outboxRepository = mockOutboxRepository
 	u.redisRepository = mockRedisRepository
 	u.logEmitter = mockLogEmitter
-	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockNotificationStream, mockEventEmitter, mockLogEmitter)
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
 }
 
 func (u *UpdateEmailServiceSuite) SetupTest() {
 	u.userRepository.ExpectedCalls = nil
-	u.eventEmitter.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
 	u.fileService.ExpectedCalls = nil
-	u.notificationStream.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
 	u.redisRepository.ExpectedCalls = nil
 	u.logEmitter.ExpectedCalls = nil
 
 	u.userRepository.Calls = nil
-	u.eventEmitter.Calls = nil
+	u.profileCache.Calls = nil
 	u.fileService.Calls = nil
-	u.notificationStream.Calls = nil
+	u.outboxRepository.Calls = nil
 	u.redisRepository.Calls = nil
 	u.logEmitter.Calls = nil
 }
@@ -76,13 +76,15 @@ func (u *UpdateEmailServiceSuite) TestUserService_UpdateEmail_Success() {
 
 	u.redisRepository.On("SetResource", mock.Anything, "newEmail:"+userId, req.Email, mock.Anything).Return(nil).Once()
 	u.redisRepository.On("SetResource", mock.Anything, "changeEmailToken:"+userId, mock.Anything, mock.Anything).Return(nil).Once()
-	u.notificationStream.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(&jetstream.PubAck{}, nil).Once()
+	u.outboxRepository.On("Enqueue", mock.Anything, mock.MatchedBy(func(msgs []*dto.OutboxMessage) bool {
+		return len(msgs) == 1 && strings.HasSuffix(msgs[0].Subject, "."+userId) && strings.Contains(string(msgs[0].Payload), email)
+	})).Return(nil).Once()
 
-	err := u.userService.UpdateEmail(req, userId)
+	err := u.userService.UpdateEmail(context.Background(), req, userId)
 
 	u.NoError(err)
 	u.redisRepository.AssertExpectations(u.T())
-	u.notificationStream.AssertExpectations(u.T())
+	u.outboxRepository.AssertExpectations(u.T())
 }
 
 func (u *UpdateEmailServiceSuite) TestUserService_UpdateEmail_RedisError() {
@@ -95,13 +97,13 @@ func (u *UpdateEmailServiceSuite) TestUserService_UpdateEmail_RedisError() {
 
 	u.redisRepository.On("SetResource", mock.Anything, "newEmail:"+userId, req.Email, mock.Anything).Return(dto.Err_INTERNAL_SET_RESOURCE).Once()
 
-	err := u.userService.UpdateEmail(req, userId)
+	err := u.userService.UpdateEmail(context.Background(), req, userId)
 
 	u.Error(err)
 	u.redisRepository.AssertExpectations(u.T())
 }
 
-func (u *UpdateEmailServiceSuite) TestUserService_UpdateEmail_JetstreamError() {
+func (u *UpdateEmailServiceSuite) TestUserService_UpdateEmail_OutboxError() {
 	email := fmt.Sprintf("test+%d@example.com", time.Now().UnixNano())
 
 	req := &dto.UpdateEmailRequest{
@@ -111,15 +113,11 @@ func (u *UpdateEmailServiceSuite) TestUserService_UpdateEmail_JetstreamError() {
 
 	u.redisRepository.On("SetResource", mock.Anything, "newEmail:"+userId, req.Email, mock.Anything).Return(nil).Once()
 	u.redisRepository.On("SetResource", mock.Anything, "changeEmailToken:"+userId, mock.Anything, mock.Anything).Return(nil).Once()
-	u.notificationStream.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(&jetstream.PubAck{}, errors.New("failed to update email")).Once()
-	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+	u.outboxRepository.On("Enqueue", mock.Anything, mock.Anything).Return(dto.Err_INTERNAL_FAILED_INSERT_OUTBOX).Once()
 
-	err := u.userService.UpdateEmail(req, userId)
+	err := u.userService.UpdateEmail(context.Background(), req, userId)
 
-	u.Error(err)
+	u.Equal(dto.Err_INTERNAL_FAILED_INSERT_OUTBOX, err)
 	u.redisRepository.AssertExpectations(u.T())
-	u.notificationStream.AssertExpectations(u.T())
-
-	time.Sleep(time.Second)
-	u.logEmitter.AssertExpectations(u.T())
+	u.outboxRepository.AssertExpectations(u.T())
 }
@@ -1,61 +1,73 @@
 package service_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/micros-template/user-service/internal/domain/dto"
 	"github.com/micros-template/user-service/internal/domain/service"
+	"github.com/micros-template/user-service/pkg/password"
 	mk "github.com/micros-template/user-service/test/mocks"
 
 	"github.com/micros-template/sharedlib/model"
+	"github.com/micros-template/sharedlib/utils"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type UpdatePasswordServiceSuite struct {
 	suite.Suite
-	userService        service.UserService
-	userRepository     *mk.UserRepositoryMock
-	eventEmitter       *mk.EmitterMock
-	fileService        *mk.MockFileServiceClient
-	notificationStream *mk.MockNatsInfra
-	redisRepository    *mk.MockRedisRepository
-	logEmitter         *mk.LoggerInfraMock
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
 }
 
 func (u *UpdatePasswordServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mk.UserRepositoryMock)
-	mockEventEmitter := new(mk.EmitterMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
 	mockFileService := new(mk.MockFileServiceClient)
-	mockNotificationStream := new(mk.MockNatsInfra)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
 	mockRedisRepository := new(mk.MockRedisRepository)
 	mockLogEmitter := new(mk.LoggerInfraMock)
 
 	logger := zerolog.Nop()
 	u.userRepository = mockUserRepo
-	u.eventEmitter = mockEventEmitter
+	u.profileCache = mockProfileCache
 	u.fileService = mockFileService
-	u.notificationStream = mockNotificationStream
+	u.outboxRepository = mockOutboxRepository
 	u.redisRepository = mockRedisRepository
 	u.logEmitter = mockLogEmitter
-	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockNotificationStream, mockEventEmitter, mockLogEmitter)
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.password_policy.min_length", 8)
+	viper.Set("app.password_policy.require_upper", true)
+	viper.Set("app.password_policy.require_lower", true)
+	viper.Set("app.password_policy.require_digit", true)
+	viper.Set("app.password_policy.ban_common", true)
+	viper.Set("app.password_policy.ban_personal_info", true)
+	viper.Set("app.password_policy.history_size", 5)
 }
 
 func (u *UpdatePasswordServiceSuite) SetupTest() {
 	u.userRepository.ExpectedCalls = nil
-	u.eventEmitter.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
 	u.fileService.ExpectedCalls = nil
-	u.notificationStream.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
 	u.redisRepository.ExpectedCalls = nil
 	u.logEmitter.ExpectedCalls = nil
 
 	u.userRepository.Calls = nil
-	u.eventEmitter.Calls = nil
+	u.profileCache.Calls = nil
 	u.fileService.Calls = nil
-	u.notificationStream.Calls = nil
+	u.outboxRepository.Calls = nil
 	u.redisRepository.Calls = nil
 	u.logEmitter.Calls = nil
 }
@@ -67,7 +79,7 @@ func TestUpdatePasswordServiceSuite(t *testing.T) {
 func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_Success() {
 	userId := "user-123"
 	oldPassword := "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6"
-	newPassword := "new-password"
+	newPassword := "Tr1cky-Horse"
 
 	req := &dto.UpdatePasswordRequest{
 		Password:           "password123",
@@ -79,16 +91,20 @@ func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_Success() {
 		ID:       userId,
 		Password: oldPassword,
 	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(user, nil)
-	u.userRepository.On("UpdateUser", mock.Anything).Return(nil)
-	u.eventEmitter.On("UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User")).Return(nil).Maybe()
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("QueryPasswordHistory", mock.Anything, userId, 4).Return([]string{}, nil)
+	u.userRepository.On("UpdateUserPassword", mock.Anything, userId, mock.MatchedBy(func(hash string) bool {
+		return utils.HashPasswordCompare(newPassword, hash)
+	}), oldPassword, 4, mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return len(outbox) == 1 && mk.DecodeUserEvent(outbox[0]).GetUserUpdated().GetId() == userId
+	})).Return(nil)
 
-	err := u.userService.UpdatePassword(req, userId)
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	err := u.userService.UpdatePassword(context.Background(), req, userId)
 
 	u.NoError(err)
 	u.userRepository.AssertExpectations(u.T())
-	time.Sleep(time.Second)
-	u.eventEmitter.AssertCalled(u.T(), "UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User"))
+	u.profileCache.AssertExpectations(u.T())
 }
 
 func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_UserNotFound() {
@@ -101,8 +117,8 @@ func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_UserNotFound
 		ConfirmNewPassword: newPassword,
 	}
 
-	u.userRepository.On("QueryUserByUserId", userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
-	err := u.userService.UpdatePassword(req, userId)
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+	err := u.userService.UpdatePassword(context.Background(), req, userId)
 
 	u.Error(err)
 	u.userRepository.AssertExpectations(u.T())
@@ -116,7 +132,7 @@ func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_PasswordDoes
 	}
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userService.UpdatePassword(req, "userid-123")
+	err := u.userService.UpdatePassword(context.Background(), req, "userid-123")
 
 	u.Error(err)
 	u.userRepository.AssertExpectations(u.T())
@@ -139,13 +155,115 @@ func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_WrongPasswor
 		ID:       userId,
 		Password: oldPassword,
 	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(user, nil)
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(user, nil)
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userService.UpdatePassword(req, userId)
+	err := u.userService.UpdatePassword(context.Background(), req, userId)
 
 	u.Error(err)
 	u.userRepository.AssertExpectations(u.T())
 	time.Sleep(time.Second)
 	u.logEmitter.AssertExpectations(u.T())
 }
+
+func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_PolicyViolation() {
+	userId := "user-123"
+	oldPassword := "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6"
+
+	req := &dto.UpdatePasswordRequest{
+		Password:           "password123",
+		NewPassword:        "johndoe1",
+		ConfirmNewPassword: "johndoe1",
+	}
+
+	user := &model.User{
+		ID:       userId,
+		FullName: "John Doe",
+		Email:    "john.doe@example.com",
+		Password: oldPassword,
+	}
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("QueryPasswordHistory", mock.Anything, userId, 4).Return([]string{}, nil)
+	u.logEmitter.On("EmitLog", "ERR", "password policy violated. rules: uppercase,personal_info, userId: user-123").Return(nil)
+
+	err := u.userService.UpdatePassword(context.Background(), req, userId)
+
+	var policyErr *dto.PasswordPolicyError
+	u.ErrorAs(err, &policyErr)
+	u.Equal([]password.Violation{
+		{Rule: password.RuleUppercase, Message: "must contain an uppercase letter"},
+		{Rule: password.RulePersonalInfo, Message: "must not contain your email or name"},
+	}, policyErr.Violations)
+	u.userRepository.AssertNotCalled(u.T(), "UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_SameAsCurrent() {
+	userId := "user-123"
+	current, err := utils.HashPassword("Tr1cky-Horse")
+	u.Require().NoError(err)
+
+	req := &dto.UpdatePasswordRequest{
+		Password:           "Tr1cky-Horse",
+		NewPassword:        "Tr1cky-Horse",
+		ConfirmNewPassword: "Tr1cky-Horse",
+	}
+
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(&model.User{ID: userId, Password: current}, nil)
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err = u.userService.UpdatePassword(context.Background(), req, userId)
+
+	var policyErr *dto.PasswordPolicyError
+	u.ErrorAs(err, &policyErr)
+	u.Equal([]password.Violation{password.ReuseViolation()}, policyErr.Violations)
+	u.userRepository.AssertNotCalled(u.T(), "QueryPasswordHistory", mock.Anything, mock.Anything, mock.Anything)
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_ReusedFromHistory() {
+	userId := "user-123"
+	oldPassword := "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6"
+	previous, err := utils.HashPassword("Tr1cky-Horse")
+	u.Require().NoError(err)
+
+	req := &dto.UpdatePasswordRequest{
+		Password:           "password123",
+		NewPassword:        "Tr1cky-Horse",
+		ConfirmNewPassword: "Tr1cky-Horse",
+	}
+
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(&model.User{ID: userId, Password: oldPassword}, nil)
+	u.userRepository.On("QueryPasswordHistory", mock.Anything, userId, 4).Return([]string{previous}, nil)
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err = u.userService.UpdatePassword(context.Background(), req, userId)
+
+	var policyErr *dto.PasswordPolicyError
+	u.ErrorAs(err, &policyErr)
+	u.Equal([]password.Violation{password.ReuseViolation()}, policyErr.Violations)
+	u.userRepository.AssertNotCalled(u.T(), "UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdatePasswordServiceSuite) TestUserService_UpdatePassword_HistoryQueryFailed() {
+	userId := "user-123"
+	oldPassword := "$2a$10$Nwjs8PdFOCnjbRM3x/2WAuEtqOSrm6wHByYaw0ZDp5mV7e560dIb6"
+
+	req := &dto.UpdatePasswordRequest{
+		Password:           "password123",
+		NewPassword:        "Tr1cky-Horse",
+		ConfirmNewPassword: "Tr1cky-Horse",
+	}
+
+	u.userRepository.On("QueryUserByUserId", mock.Anything, userId).Return(&model.User{ID: userId, Password: oldPassword}, nil)
+	u.userRepository.On("QueryPasswordHistory", mock.Anything, userId, 4).Return(nil, dto.Err_INTERNAL_FAILED_QUERY_HISTORY)
+
+	err := u.userService.UpdatePassword(context.Background(), req, userId)
+
+	u.Equal(dto.Err_INTERNAL_FAILED_QUERY_HISTORY, err)
+	u.userRepository.AssertExpectations(u.T())
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	_dto "github.com/micros-template/sharedlib/dto"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdatePhoneNumberServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (u *UpdatePhoneNumberServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	u.userRepository = mockUserRepo
+	u.profileCache = mockProfileCache
+	u.fileService = mockFileService
+	u.outboxRepository = mockOutboxRepository
+	u.redisRepository = mockRedisRepository
+	u.logEmitter = mockLogEmitter
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("jetstream.notification.subject.sms", "notification.sms")
+	viper.Set("app.phone_verification.otp_ttl", "10m")
+	viper.Set("app.phone_verification.max_sends", 5)
+	viper.Set("app.phone_verification.send_window", "1h")
+}
+
+func (u *UpdatePhoneNumberServiceSuite) SetupTest() {
+	u.userRepository.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
+	u.fileService.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
+	u.redisRepository.ExpectedCalls = nil
+	u.logEmitter.ExpectedCalls = nil
+
+	u.userRepository.Calls = nil
+	u.profileCache.Calls = nil
+	u.fileService.Calls = nil
+	u.outboxRepository.Calls = nil
+	u.redisRepository.Calls = nil
+	u.logEmitter.Calls = nil
+}
+
+func TestUpdatePhoneNumberServiceSuite(t *testing.T) {
+	suite.Run(t, &UpdatePhoneNumberServiceSuite{})
+}
+
+func (u *UpdatePhoneNumberServiceSuite) TestUserService_UpdatePhoneNumber_Success() {
+	userId := "user-123"
+	phoneNumber := "+6281234567890"
+	var code string
+
+	u.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpSends:"+userId, time.Hour).Return(int64(1), nil).Once()
+	u.redisRepository.On("SetResource", mock.Anything, "newPhone:"+userId, phoneNumber, 10*time.Minute).Return(nil).Once()
+	u.redisRepository.On("SetResource", mock.Anything, "phoneOtp:"+userId, mock.MatchedBy(func(c string) bool {
+		code = c
+		return regexp.MustCompile(`^[0-9]{6}$`).MatchString(c)
+	}), 10*time.Minute).Return(nil).Once()
+	u.redisRepository.On("RemoveResource", mock.Anything, "phoneOtpAttempts:"+userId).Return(nil).Once()
+	u.outboxRepository.On("Enqueue", mock.Anything, mock.MatchedBy(func(msgs []*dto.OutboxMessage) bool {
+		var msg _dto.MailNotificationMessage
+		if len(msgs) != 1 || msgs[0].Subject != "notification.sms."+userId || json.Unmarshal(msgs[0].Payload, &msg) != nil {
+			return false
+		}
+		return msg.MsgType == "verifyPhone" && msg.Message == code && len(msg.Receiver) == 1 && msg.Receiver[0] == phoneNumber
+	})).Return(nil).Once()
+
+	err := u.userService.UpdatePhoneNumber(context.Background(), phoneNumber, userId)
+
+	u.NoError(err)
+	u.redisRepository.AssertExpectations(u.T())
+	u.outboxRepository.AssertExpectations(u.T())
+}
+
+func (u *UpdatePhoneNumberServiceSuite) TestUserService_UpdatePhoneNumber_TooManySends() {
+	userId := "user-123"
+
+	u.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpSends:"+userId, time.Hour).Return(int64(6), nil).Once()
+	u.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := u.userService.UpdatePhoneNumber(context.Background(), "+6281234567890", userId)
+
+	u.Equal(dto.Err_TOO_MANY_REQUESTS_OTP_SENT, err)
+	u.redisRepository.AssertNotCalled(u.T(), "SetResource", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	u.outboxRepository.AssertNotCalled(u.T(), "Enqueue", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdatePhoneNumberServiceSuite) TestUserService_UpdatePhoneNumber_RedisError() {
+	userId := "user-123"
+
+	u.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpSends:"+userId, time.Hour).Return(int64(0), dto.Err_INTERNAL_SET_RESOURCE).Once()
+
+	err := u.userService.UpdatePhoneNumber(context.Background(), "+6281234567890", userId)
+
+	u.Equal(dto.Err_INTERNAL_SET_RESOURCE, err)
+	u.outboxRepository.AssertNotCalled(u.T(), "Enqueue", mock.Anything, mock.Anything)
+}
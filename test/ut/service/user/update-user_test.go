@@ -2,8 +2,14 @@ package service_test
 
 import (
 	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
 	"log"
+	"maps"
 	"mime/multipart"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +20,7 @@ import (
 	"github.com/micros-template/proto-file/pkg/fpb"
 	"github.com/micros-template/sharedlib/model"
 	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc/codes"
@@ -22,46 +29,46 @@ import (
 
 type UpdateUserUserServiceSuite struct {
 	suite.Suite
-	userService        service.UserService
-	userRepository     *mk.UserRepositoryMock
-	eventEmitter       *mk.EmitterMock
-	fileService        *mk.MockFileServiceClient
-	notificationStream *mk.MockNatsInfra
-	redisRepository    *mk.MockRedisRepository
-	logEmitter         *mk.LoggerInfraMock
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
 }
 
 func (u *UpdateUserUserServiceSuite) SetupSuite() {
 
 	mockUserRepo := new(mk.UserRepositoryMock)
-	mockEventEmitter := new(mk.EmitterMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
 	mockFileService := new(mk.MockFileServiceClient)
-	mockNotificationStream := new(mk.MockNatsInfra)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
 	mockRedisRepository := new(mk.MockRedisRepository)
 	mockLogEmitter := new(mk.LoggerInfraMock)
 
 	logger := zerolog.Nop()
 	u.userRepository = mockUserRepo
-	u.eventEmitter = mockEventEmitter
+	u.profileCache = mockProfileCache
 	u.fileService = mockFileService
-	u.notificationStream = mockNotificationStream
+	u.outboxRepository = mockOutboxRepository
 	u.redisRepository = mockRedisRepository
 	u.logEmitter = mockLogEmitter
-	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockNotificationStream, mockEventEmitter, mockLogEmitter)
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
 }
 
 func (u *UpdateUserUserServiceSuite) SetupTest() {
 	u.userRepository.ExpectedCalls = nil
-	u.eventEmitter.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
 	u.fileService.ExpectedCalls = nil
-	u.notificationStream.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
 	u.redisRepository.ExpectedCalls = nil
 	u.logEmitter.ExpectedCalls = nil
 
 	u.userRepository.Calls = nil
-	u.eventEmitter.Calls = nil
+	u.profileCache.Calls = nil
 	u.fileService.Calls = nil
-	u.notificationStream.Calls = nil
+	u.outboxRepository.Calls = nil
 	u.redisRepository.Calls = nil
 	u.logEmitter.Calls = nil
 }
@@ -73,36 +80,38 @@ func TestUpdateUserUserServiceSuite(t *testing.T) {
 func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_Success() {
 	userId := "user-123"
 	req := &dto.UpdateUserRequest{
-		FullName:         "Updated Name",
-		TwoFactorEnabled: true,
-		Image:            nil,
+		FullName: ptr("Updated Name"),
+		Image:    nil,
 	}
-	user := &model.User{
+	user := &dto.UserProfile{User: model.User{
 		ID:               userId,
 		FullName:         "Original Name",
 		TwoFactorEnabled: false,
 		Image:            nil,
-	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(user, nil)
-	u.userRepository.On("UpdateUser", mock.Anything).Return(nil)
-	u.eventEmitter.On("UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User")).Return(nil).Maybe()
-	err := u.userService.UpdateUser(req, userId)
+	}, Version: 4}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.Anything, int64(4), mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return len(outbox) == 1 && mk.DecodeUserEvent(outbox[0]).GetUserUpdated().GetFullName() == "Updated Name"
+	})).Return(nil)
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	profile, err := u.userService.UpdateUser(context.Background(), req, userId)
 
 	u.NoError(err)
+	u.Equal("Updated Name", profile.FullName)
+	u.False(profile.TwoFactorEnabled)
+	u.Equal(int64(5), profile.Version)
 	u.userRepository.AssertExpectations(u.T())
-
-	time.Sleep(time.Second)
-	u.eventEmitter.AssertCalled(u.T(), "UpdateUser", mock.Anything, mock.AnythingOfType("*upb.User"))
+	u.profileCache.AssertExpectations(u.T())
 }
 
 func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_UserNotFound() {
 	userId := "user-404"
 	req := &dto.UpdateUserRequest{
-		FullName: "Nonexistent User",
+		FullName: ptr("Nonexistent User"),
 	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND)
 
-	err := u.userService.UpdateUser(req, userId)
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
 
 	u.Error(err)
 	u.Equal(dto.Err_NOTFOUND_USER_NOT_FOUND, err)
@@ -116,13 +125,13 @@ func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_InvalidImageExte
 			Filename: "invalid.bmp",
 		},
 	}
-	user := &model.User{
+	user := &dto.UserProfile{User: model.User{
 		ID: userId,
-	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(user, nil)
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userService.UpdateUser(req, userId)
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
 
 	u.Error(err)
 	u.Equal(dto.Err_BAD_REQUEST_WRONG_EXTENSION, err)
@@ -151,13 +160,13 @@ func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_SizeLimitExceede
 	req := &dto.UpdateUserRequest{
 		Image: fileHeader,
 	}
-	user := &model.User{
+	user := &dto.UserProfile{User: model.User{
 		ID: userId,
-	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(user, nil)
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
 	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	err := u.userService.UpdateUser(req, userId)
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
 
 	u.Error(err)
 	u.Equal(dto.Err_BAD_REQUEST_LIMIT_SIZE_EXCEEDED, err)
@@ -169,43 +178,353 @@ func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_SizeLimitExceede
 
 func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_ImageUploadError() {
 	userId := "user-123"
-
-	imageData := bytes.Repeat([]byte("test"), 1024)
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-	part, _ := writer.CreateFormFile("image", "valid.jpg")
-	if _, err := part.Write(imageData); err != nil {
-		log.Fatal("failed to write image data:", err)
+	req := &dto.UpdateUserRequest{
+		Image: imageFileHeader(u.T(), "valid.jpg", encodeJPEG(u.T(), 600, 400)),
 	}
-	if err := writer.Close(); err != nil {
-		log.Fatal("failed to close form writer")
+	user := &dto.UserProfile{User: model.User{
+		ID: userId,
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.fileService.On("SaveProfileImage", mock.Anything, mock.MatchedBy(func(img *fpb.Image) bool {
+		return img.GetExt() == "jpg"
+	})).Return(&fpb.ImageName{Name: "variant_64.jpg"}, nil).Once()
+	u.fileService.On("SaveProfileImage", mock.Anything, mock.Anything).Return(nil, status.Errorf(codes.Internal, "upload error")).Once()
+	// the variant stored before the failure is removed again
+	u.fileService.On("RemoveProfileImage", mock.Anything, &fpb.ImageName{Name: "variant_64.jpg"}).Return(nil, nil).Once()
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.Error(err)
+	u.userRepository.AssertExpectations(u.T())
+	u.userRepository.AssertNotCalled(u.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	u.fileService.AssertExpectations(u.T())
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_SpoofedExtension() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{
+		Image: imageFileHeader(u.T(), "script.jpg", []byte("#!/bin/sh\necho not an image\n")),
 	}
+	user := &dto.UserProfile{User: model.User{
+		ID: userId,
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
 
-	reader := multipart.NewReader(&buf, writer.Boundary())
-	form, _ := reader.ReadForm(32 << 20)
-	fileHeader := form.File["image"][0]
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.Equal(dto.Err_BAD_REQUEST_UNSUPPORTED_IMAGE, err)
+	u.fileService.AssertNotCalled(u.T(), "SaveProfileImage", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
 
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_CorruptImage() {
+	userId := "user-123"
+	// a real jpeg header followed by garbage
+	data := append(encodeJPEG(u.T(), 32, 32)[:200], bytes.Repeat([]byte{0x00}, 64)...)
 	req := &dto.UpdateUserRequest{
-		Image: fileHeader,
+		Image: imageFileHeader(u.T(), "broken.jpg", data),
 	}
-	user := &model.User{
+	user := &dto.UserProfile{User: model.User{
 		ID: userId,
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.Equal(dto.Err_BAD_REQUEST_CORRUPT_IMAGE, err)
+	u.fileService.AssertNotCalled(u.T(), "SaveProfileImage", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_ImageVariants() {
+	userId := "user-123"
+	oldImage := "old_512.jpg"
+	req := &dto.UpdateUserRequest{
+		FullName: ptr("John Doe"),
+		Image:    imageFileHeader(u.T(), "avatar.jpg", encodeJPEG(u.T(), 800, 600)),
 	}
-	u.userRepository.On("QueryUserByUserId", userId).Return(user, nil)
+	user := &dto.UserProfile{
+		User: model.User{
+			ID:       userId,
+			FullName: "John Doe",
+			Image:    &oldImage,
+		},
+		ImageVariants: dto.ImageVariants{"64": "old_64.jpg", "256": "old_256.jpg", "512": "old_512.jpg"},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	for _, name := range []string{"new_64.jpg", "new_256.jpg", "new_512.jpg"} {
+		u.fileService.On("SaveProfileImage", mock.Anything, mock.Anything).Return(&fpb.ImageName{Name: name}, nil).Once()
+	}
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *dto.UserProfile) bool {
+		return p.Image != nil && *p.Image == "new_512.jpg" &&
+			maps.Equal(p.ImageVariants, dto.ImageVariants{"64": "new_64.jpg", "256": "new_256.jpg", "512": "new_512.jpg"})
+	}), mock.Anything, mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	for _, name := range []string{"old_64.jpg", "old_256.jpg", "old_512.jpg"} {
+		u.fileService.On("RemoveProfileImage", mock.Anything, &fpb.ImageName{Name: name}).Return(nil, nil).Once()
+	}
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
 
-	imageReq := &fpb.Image{
-		Image: imageData,
-		Ext:   "jpg",
+	u.NoError(err)
+	u.userRepository.AssertExpectations(u.T())
+	u.fileService.AssertExpectations(u.T())
+	u.fileService.AssertNumberOfCalls(u.T(), "RemoveProfileImage", 3)
+	for _, call := range u.fileService.Calls {
+		if call.Method != "SaveProfileImage" {
+			continue
+		}
+		// every uploaded variant is a freshly encoded square without the original metadata
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(call.Arguments.Get(1).(*fpb.Image).GetImage()))
+		u.NoError(err)
+		u.Equal(cfg.Width, cfg.Height)
 	}
-	u.fileService.On("SaveProfileImage", mock.Anything, imageReq).Return(nil, status.Errorf(codes.Internal, "upload error"))
-	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_AbsentFieldsUnchanged() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{
+		FullName: ptr("  New Name "),
+	}
+	user := &dto.UserProfile{User: model.User{
+		ID:               userId,
+		FullName:         "Original Name",
+		TwoFactorEnabled: true,
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *dto.UserProfile) bool {
+		// nil variants leave the stored ones alone
+		return p.FullName == "New Name" && p.TwoFactorEnabled && p.ImageVariants == nil
+	}), mock.Anything, mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
 
-	err := u.userService.UpdateUser(req, userId)
+	profile, err := u.userService.UpdateUser(context.Background(), req, userId)
 
-	u.Error(err)
+	u.NoError(err)
+	u.Equal("New Name", profile.FullName)
+	u.True(profile.TwoFactorEnabled)
+	u.userRepository.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_NoChanges() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{
+		FullName: ptr("John Doe"),
+	}
+	user := &dto.UserProfile{User: model.User{
+		ID:               userId,
+		FullName:         "John Doe",
+		TwoFactorEnabled: true,
+	}}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+
+	profile, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.NoError(err)
+	u.Equal("John Doe", profile.FullName)
+	u.userRepository.AssertNotCalled(u.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	u.profileCache.AssertNotCalled(u.T(), "Invalidate", mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_RemoveImage() {
+	userId := "user-123"
+	oldImage := "old_512.jpg"
+	req := &dto.UpdateUserRequest{RemoveImage: true}
+	user := &dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe", Image: &oldImage},
+		ImageVariants: dto.ImageVariants{"64": "old_64.jpg", "512": "old_512.jpg"},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *dto.UserProfile) bool {
+		return p.Image == nil && p.ImageVariants != nil && len(p.ImageVariants) == 0
+	}), mock.Anything, mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	for _, name := range []string{"old_64.jpg", "old_512.jpg"} {
+		u.fileService.On("RemoveProfileImage", mock.Anything, &fpb.ImageName{Name: name}).Return(nil, nil).Once()
+	}
+
+	profile, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.NoError(err)
+	u.True(profile.DefaultImage)
 	u.userRepository.AssertExpectations(u.T())
 	u.fileService.AssertExpectations(u.T())
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_VersionMismatch() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{
+		FullName:         ptr("New Name"),
+		Image:            imageFileHeader(u.T(), "avatar.jpg", encodeJPEG(u.T(), 64, 64)),
+		ExpectedVersions: []int64{2},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User:    model.User{ID: userId, FullName: "Original Name"},
+		Version: 3,
+	}, nil)
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.ErrorIs(err, dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+	// nothing is uploaded for a request that cannot be applied
+	u.fileService.AssertNotCalled(u.T(), "SaveProfileImage", mock.Anything, mock.Anything)
+	u.userRepository.AssertNotCalled(u.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_ProfileFields() {
+	userId := "user-123"
+	bio := "old bio"
+	locale := "en-US"
+	req := &dto.UpdateUserRequest{
+		Username:    dto.PatchString{Set: true, Value: ptr("john.doe")},
+		Bio:         dto.PatchString{Set: true},
+		DateOfBirth: dto.PatchString{Set: true, Value: ptr("1990-01-31")},
+	}
+	user := &dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe"},
+		ProfileFields: dto.ProfileFields{Bio: &bio, Locale: &locale},
+		Version:       2,
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *dto.UserProfile) bool {
+		// present members are replaced or cleared, absent ones keep the stored value
+		return *p.Username == "john.doe" && p.Bio == nil && *p.Locale == "en-US" &&
+			p.DateOfBirth.Equal(time.Date(1990, time.January, 31, 0, 0, 0, 0, time.UTC))
+	}), int64(2), mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+
+	profile, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.NoError(err)
+	u.Equal(ptr("john.doe"), profile.Username)
+	u.Nil(profile.Bio)
+	u.Equal(ptr("1990-01-31"), profile.DateOfBirth)
+	u.userRepository.AssertExpectations(u.T())
+	// the shared user event has no room for the profile fields, they go out on their own subject
+	outbox := u.userRepository.Calls[1].Arguments.Get(3).([]*dto.OutboxMessage)
+	u.Len(outbox, 2)
+	u.True(strings.HasSuffix(outbox[1].Subject, ".user_profile.user-123"))
+	event := mk.DecodeUserProfile(outbox[1])
+	u.Equal(userId, event.GetId())
+	u.Equal("john.doe", event.GetUsername())
+	u.Nil(event.Bio)
+	u.Equal("en-US", event.GetLocale())
+	u.Equal("1990-01-31", event.GetDateOfBirth())
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_ProfileEventConfiguredFields() {
+	viper.Set("jetstream.event.payload_fields.update_user_profile", []string{"username"})
+	defer viper.Reset()
+	userId := "user-123"
+	phone := "+6281234567890"
+	req := &dto.UpdateUserRequest{
+		Username:    dto.PatchString{Set: true, Value: ptr("john.doe")},
+		DateOfBirth: dto.PatchString{Set: true, Value: ptr("1990-01-31")},
+	}
+	user := &dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe"},
+		ProfileFields: dto.ProfileFields{PhoneNumber: &phone, PhoneVerified: true},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.Anything, int64(0), mock.Anything).Return(nil).Once()
+	u.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.NoError(err)
+	outbox := u.userRepository.Calls[1].Arguments.Get(3).([]*dto.OutboxMessage)
+	u.Len(outbox, 2)
+	event := mk.DecodeUserProfile(outbox[1])
+	u.Equal("john.doe", event.GetUsername())
+	u.Nil(event.PhoneNumber)
+	u.False(event.GetPhoneVerified())
+	u.Nil(event.DateOfBirth)
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_ProfileFieldsUnchanged() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{
+		Timezone:    dto.PatchString{Set: true, Value: ptr("Asia/Jakarta")},
+		DateOfBirth: dto.PatchString{Set: true, Value: ptr("1990-01-31")},
+	}
+	timezone := "Asia/Jakarta"
+	dateOfBirth := time.Date(1990, time.January, 31, 0, 0, 0, 0, time.UTC)
+	user := &dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe"},
+		ProfileFields: dto.ProfileFields{Timezone: &timezone, DateOfBirth: &dateOfBirth},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(user, nil)
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.NoError(err)
+	u.userRepository.AssertNotCalled(u.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *UpdateUserUserServiceSuite) TestUserService_UpdateUser_UsernameTaken() {
+	userId := "user-123"
+	req := &dto.UpdateUserRequest{
+		Username: dto.PatchString{Set: true, Value: ptr("taken")},
+	}
+	u.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User: model.User{ID: userId, FullName: "John Doe"},
+	}, nil)
+	u.userRepository.On("UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dto.Err_CONFLICT_USERNAME_EXIST).Once()
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	_, err := u.userService.UpdateUser(context.Background(), req, userId)
+
+	u.Equal(dto.Err_CONFLICT_USERNAME_EXIST, err)
+	u.profileCache.AssertNotCalled(u.T(), "Invalidate", mock.Anything, mock.Anything)
 
 	time.Sleep(time.Second)
 	u.logEmitter.AssertExpectations(u.T())
 }
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func imageFileHeader(t *testing.T, name string, data []byte) *multipart.FileHeader {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("image", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	form, err := multipart.NewReader(&buf, writer.Boundary()).ReadForm(32 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return form.File["image"][0]
+}
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/utils"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UseRecoveryCodeServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+	codes            []dto.RecoveryCode
+}
+
+func (u *UseRecoveryCodeServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	u.userRepository = mockUserRepo
+	u.profileCache = mockProfileCache
+	u.fileService = mockFileService
+	u.outboxRepository = mockOutboxRepository
+	u.redisRepository = mockRedisRepository
+	u.logEmitter = mockLogEmitter
+	u.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.two_factor.max_attempts", 5)
+	viper.Set("app.two_factor.attempt_window", "15m")
+
+	firstHash, _ := utils.HashPassword("k7m2px9q4r")
+	secondHash, _ := utils.HashPassword("b3n8tw6z2h")
+	u.codes = []dto.RecoveryCode{{ID: 1, CodeHash: firstHash}, {ID: 2, CodeHash: secondHash}}
+}
+
+func (u *UseRecoveryCodeServiceSuite) SetupTest() {
+	u.userRepository.ExpectedCalls = nil
+	u.profileCache.ExpectedCalls = nil
+	u.fileService.ExpectedCalls = nil
+	u.outboxRepository.ExpectedCalls = nil
+	u.redisRepository.ExpectedCalls = nil
+	u.logEmitter.ExpectedCalls = nil
+
+	u.userRepository.Calls = nil
+	u.profileCache.Calls = nil
+	u.fileService.Calls = nil
+	u.outboxRepository.Calls = nil
+	u.redisRepository.Calls = nil
+	u.logEmitter.Calls = nil
+}
+
+func TestUseRecoveryCodeServiceSuite(t *testing.T) {
+	suite.Run(t, &UseRecoveryCodeServiceSuite{})
+}
+
+func (u *UseRecoveryCodeServiceSuite) TestUserService_UseRecoveryCode_Success() {
+	userId := "user-123"
+	u.redisRepository.On("IncrementResource", mock.Anything, "recoveryCodeAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	u.userRepository.On("QueryRecoveryCodes", mock.Anything, userId).Return(u.codes, nil)
+	u.userRepository.On("UseRecoveryCode", mock.Anything, int64(2)).Return(nil)
+	u.redisRepository.On("RemoveResource", mock.Anything, "recoveryCodeAttempts:"+userId).Return(nil)
+
+	// the separator and case are not significant
+	err := u.userService.UseRecoveryCode(context.Background(), "B3N8T-W6Z2H", userId)
+
+	u.NoError(err)
+	u.userRepository.AssertExpectations(u.T())
+	u.redisRepository.AssertExpectations(u.T())
+}
+
+func (u *UseRecoveryCodeServiceSuite) TestUserService_UseRecoveryCode_Invalid() {
+	userId := "user-123"
+	u.redisRepository.On("IncrementResource", mock.Anything, "recoveryCodeAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	u.userRepository.On("QueryRecoveryCodes", mock.Anything, userId).Return(u.codes, nil)
+	u.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := u.userService.UseRecoveryCode(context.Background(), "aaaaa-aaaaa", userId)
+
+	u.Equal(dto.Err_UNAUTHORIZED_RECOVERY_INVALID, err)
+	u.userRepository.AssertNotCalled(u.T(), "UseRecoveryCode", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
+
+func (u *UseRecoveryCodeServiceSuite) TestUserService_UseRecoveryCode_AlreadyUsed() {
+	userId := "user-123"
+	u.redisRepository.On("IncrementResource", mock.Anything, "recoveryCodeAttempts:"+userId, 15*time.Minute).Return(int64(1), nil)
+	u.userRepository.On("QueryRecoveryCodes", mock.Anything, userId).Return(u.codes, nil)
+	u.userRepository.On("UseRecoveryCode", mock.Anything, int64(1)).Return(dto.Err_UNAUTHORIZED_RECOVERY_INVALID)
+
+	err := u.userService.UseRecoveryCode(context.Background(), "k7m2p-x9q4r", userId)
+
+	u.Equal(dto.Err_UNAUTHORIZED_RECOVERY_INVALID, err)
+}
+
+func (u *UseRecoveryCodeServiceSuite) TestUserService_UseRecoveryCode_TooManyAttempts() {
+	userId := "user-123"
+	u.redisRepository.On("IncrementResource", mock.Anything, "recoveryCodeAttempts:"+userId, 15*time.Minute).Return(int64(6), nil)
+	u.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := u.userService.UseRecoveryCode(context.Background(), "k7m2p-x9q4r", userId)
+
+	u.Equal(dto.Err_TOO_MANY_REQUESTS_TOTP_ATTEMPTS, err)
+	u.userRepository.AssertNotCalled(u.T(), "QueryRecoveryCodes", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	u.logEmitter.AssertExpectations(u.T())
+}
@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifyEmailChangeServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (v *VerifyEmailChangeServiceSuite) SetupSuite() {
+
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	v.userRepository = mockUserRepo
+	v.profileCache = mockProfileCache
+	v.fileService = mockFileService
+	v.outboxRepository = mockOutboxRepository
+	v.redisRepository = mockRedisRepository
+	v.logEmitter = mockLogEmitter
+	v.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+}
+
+func (v *VerifyEmailChangeServiceSuite) SetupTest() {
+	v.userRepository.ExpectedCalls = nil
+	v.profileCache.ExpectedCalls = nil
+	v.fileService.ExpectedCalls = nil
+	v.outboxRepository.ExpectedCalls = nil
+	v.redisRepository.ExpectedCalls = nil
+	v.logEmitter.ExpectedCalls = nil
+
+	v.userRepository.Calls = nil
+	v.profileCache.Calls = nil
+	v.fileService.Calls = nil
+	v.outboxRepository.Calls = nil
+	v.redisRepository.Calls = nil
+	v.logEmitter.Calls = nil
+}
+
+func TestVerifyEmailChangeServiceSuite(t *testing.T) {
+	suite.Run(t, &VerifyEmailChangeServiceSuite{})
+}
+
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_Success() {
+	userId := "user-123"
+	user := &model.User{
+		ID:       userId,
+		FullName: "John Doe",
+		Email:    "old@example.com",
+		Password: "hashedpassword",
+		Verified: true,
+	}
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	v.redisRepository.On("TakeResource", mock.Anything, "newEmail:"+userId).Return("new@example.com", nil).Once()
+	v.userRepository.On("QueryUserByEmail", mock.Anything, "new@example.com").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: *user, Version: 7}, nil).Once()
+	v.userRepository.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+		return u.ID == userId && u.Email == "new@example.com"
+	}), int64(7), mock.MatchedBy(func(outbox []*dto.OutboxMessage) bool {
+		return len(outbox) == 1 && mk.DecodeUserEvent(outbox[0]).GetUserUpdated().GetEmail() == "new@example.com"
+	})).Return(nil).Once()
+
+	v.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+
+	v.NoError(err)
+	v.redisRepository.AssertExpectations(v.T())
+	v.userRepository.AssertExpectations(v.T())
+	v.profileCache.AssertExpectations(v.T())
+}
+
+// a profile edit that lands between the read and the write is kept, the email is applied on top of it
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_ConcurrentUpdateRetried() {
+	userId := "user-123"
+	before := model.User{ID: userId, FullName: "John Doe", Email: "old@example.com"}
+	after := model.User{ID: userId, FullName: "Johnny Doe", Email: "old@example.com"}
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	v.redisRepository.On("TakeResource", mock.Anything, "newEmail:"+userId).Return("new@example.com", nil).Once()
+	v.userRepository.On("QueryUserByEmail", mock.Anything, "new@example.com").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: before, Version: 7}, nil).Once()
+	v.userRepository.On("UpdateUser", mock.Anything, mock.Anything, int64(7), mock.Anything).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: after, Version: 8}, nil).Once()
+	v.userRepository.On("UpdateUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+		return u.FullName == "Johnny Doe" && u.Email == "new@example.com"
+	}), int64(8), mock.Anything).Return(nil).Once()
+	v.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+
+	v.NoError(err)
+	v.userRepository.AssertExpectations(v.T())
+}
+
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_ConflictPersists() {
+	userId := "user-123"
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	v.redisRepository.On("TakeResource", mock.Anything, "newEmail:"+userId).Return("new@example.com", nil).Once()
+	v.userRepository.On("QueryUserByEmail", mock.Anything, "new@example.com").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: model.User{ID: userId}, Version: 7}, nil)
+	v.userRepository.On("UpdateUser", mock.Anything, mock.Anything, int64(7), mock.Anything).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+
+	v.Equal(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH, err)
+	v.userRepository.AssertNumberOfCalls(v.T(), "UpdateUser", 3)
+	// the token survives so the link can be used again
+	v.redisRepository.AssertNotCalled(v.T(), "RemoveResource", mock.Anything, mock.Anything)
+}
+
+// the token is consumed before the write, a failed write does not leave it usable for the rest of its ttl
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_TokenNotReplayable() {
+	userId := "user-123"
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	v.redisRepository.On("TakeResource", mock.Anything, "newEmail:"+userId).Return("new@example.com", nil).Once()
+	v.userRepository.On("QueryUserByEmail", mock.Anything, "new@example.com").Return(nil, dto.Err_NOTFOUND_USER_NOT_FOUND).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(nil, dto.Err_INTERNAL_FAILED_SCAN_USER).Once()
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND).Once()
+
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+	v.Equal(dto.Err_INTERNAL_FAILED_SCAN_USER, err)
+
+	err = v.userService.VerifyEmailChange(context.Background(), req, userId)
+	v.Equal(dto.Err_UNAUTHORIZED_TOKEN_INVALID, err)
+	v.redisRepository.AssertExpectations(v.T())
+	v.userRepository.AssertNumberOfCalls(v.T(), "QueryProfileByUserId", 1)
+}
+
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_TokenExpired() {
+	userId := "user-123"
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND).Once()
+
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+
+	v.Equal(dto.Err_UNAUTHORIZED_TOKEN_INVALID, err)
+	v.userRepository.AssertNotCalled(v.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_TokenMismatch() {
+	userId := "user-123"
+	req := &dto.VerifyEmailChangeRequest{Token: "wrong-token"}
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	v.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+
+	v.Equal(dto.Err_UNAUTHORIZED_TOKEN_INVALID, err)
+	v.userRepository.AssertNotCalled(v.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	v.logEmitter.AssertExpectations(v.T())
+}
+
+func (v *VerifyEmailChangeServiceSuite) TestUserService_VerifyEmailChange_EmailTaken() {
+	userId := "user-123"
+	req := &dto.VerifyEmailChangeRequest{Token: "valid-token"}
+	v.redisRepository.On("TakeResource", mock.Anything, "changeEmailToken:"+userId).Return("valid-token", nil).Once()
+	v.redisRepository.On("TakeResource", mock.Anything, "newEmail:"+userId).Return("new@example.com", nil).Once()
+	v.userRepository.On("QueryUserByEmail", mock.Anything, "new@example.com").Return(&model.User{ID: "other-user"}, nil).Once()
+	v.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := v.userService.VerifyEmailChange(context.Background(), req, userId)
+
+	v.Equal(dto.Err_CONFLICT_EMAIL_EXIST, err)
+	v.userRepository.AssertNotCalled(v.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	v.redisRepository.AssertNotCalled(v.T(), "RemoveResource", mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	v.logEmitter.AssertExpectations(v.T())
+}
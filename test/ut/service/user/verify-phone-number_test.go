@@ -0,0 +1,200 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micros-template/user-service/internal/domain/dto"
+	"github.com/micros-template/user-service/internal/domain/service"
+	mk "github.com/micros-template/user-service/test/mocks"
+
+	"github.com/micros-template/sharedlib/model"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type VerifyPhoneNumberServiceSuite struct {
+	suite.Suite
+	userService      service.UserService
+	userRepository   *mk.UserRepositoryMock
+	profileCache     *mk.ProfileCacheRepositoryMock
+	fileService      *mk.MockFileServiceClient
+	outboxRepository *mk.OutboxRepositoryMock
+	redisRepository  *mk.MockRedisRepository
+	logEmitter       *mk.LoggerInfraMock
+}
+
+func (v *VerifyPhoneNumberServiceSuite) SetupSuite() {
+	mockUserRepo := new(mk.UserRepositoryMock)
+	mockProfileCache := new(mk.ProfileCacheRepositoryMock)
+	mockFileService := new(mk.MockFileServiceClient)
+	mockOutboxRepository := new(mk.OutboxRepositoryMock)
+	mockRedisRepository := new(mk.MockRedisRepository)
+	mockLogEmitter := new(mk.LoggerInfraMock)
+
+	logger := zerolog.Nop()
+	v.userRepository = mockUserRepo
+	v.profileCache = mockProfileCache
+	v.fileService = mockFileService
+	v.outboxRepository = mockOutboxRepository
+	v.redisRepository = mockRedisRepository
+	v.logEmitter = mockLogEmitter
+	v.userService = service.NewUserService(mockUserRepo, logger, mockFileService, mockRedisRepository, mockOutboxRepository, mockProfileCache, mockLogEmitter)
+
+	viper.Set("app.phone_verification.otp_ttl", "10m")
+	viper.Set("app.phone_verification.max_attempts", 5)
+}
+
+func (v *VerifyPhoneNumberServiceSuite) SetupTest() {
+	v.userRepository.ExpectedCalls = nil
+	v.profileCache.ExpectedCalls = nil
+	v.fileService.ExpectedCalls = nil
+	v.outboxRepository.ExpectedCalls = nil
+	v.redisRepository.ExpectedCalls = nil
+	v.logEmitter.ExpectedCalls = nil
+
+	v.userRepository.Calls = nil
+	v.profileCache.Calls = nil
+	v.fileService.Calls = nil
+	v.outboxRepository.Calls = nil
+	v.redisRepository.Calls = nil
+	v.logEmitter.Calls = nil
+}
+
+func TestVerifyPhoneNumberServiceSuite(t *testing.T) {
+	suite.Run(t, &VerifyPhoneNumberServiceSuite{})
+}
+
+func (v *VerifyPhoneNumberServiceSuite) TestUserService_VerifyPhoneNumber_Success() {
+	userId := "user-123"
+	oldPhone := "+6280000000000"
+	req := &dto.VerifyPhoneNumberRequest{Code: "123456"}
+
+	v.redisRepository.On("GetResource", mock.Anything, "phoneOtp:"+userId).Return("123456", nil).Once()
+	v.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpAttempts:"+userId, 10*time.Minute).Return(int64(1), nil).Once()
+	v.redisRepository.On("GetResource", mock.Anything, "newPhone:"+userId).Return("+6281234567890", nil).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User:          model.User{ID: userId, FullName: "John Doe"},
+		ImageVariants: dto.ImageVariants{"512": "image_512.jpg"},
+		ProfileFields: dto.ProfileFields{PhoneNumber: &oldPhone},
+		Version:       3,
+	}, nil).Once()
+	v.userRepository.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *dto.UserProfile) bool {
+		return *p.PhoneNumber == "+6281234567890" && p.PhoneVerified && p.ImageVariants == nil
+	}), int64(3), mock.Anything).Return(nil).Once()
+	v.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	for _, key := range []string{"phoneOtp:", "newPhone:", "phoneOtpAttempts:"} {
+		v.redisRepository.On("RemoveResource", mock.Anything, key+userId).Return(nil).Once()
+	}
+
+	err := v.userService.VerifyPhoneNumber(context.Background(), req, userId)
+
+	v.NoError(err)
+	v.userRepository.AssertExpectations(v.T())
+	v.redisRepository.AssertExpectations(v.T())
+	v.profileCache.AssertExpectations(v.T())
+	outbox := v.userRepository.Calls[1].Arguments.Get(3).([]*dto.OutboxMessage)
+	v.Len(outbox, 2)
+	event := mk.DecodeUserProfile(outbox[1])
+	v.Equal("+6281234567890", event.GetPhoneNumber())
+	v.True(event.GetPhoneVerified())
+}
+
+func (v *VerifyPhoneNumberServiceSuite) TestUserService_VerifyPhoneNumber_ConcurrentUpdateRetried() {
+	userId := "user-123"
+	req := &dto.VerifyPhoneNumberRequest{Code: "123456"}
+	bio := "written in between"
+
+	v.redisRepository.On("GetResource", mock.Anything, "phoneOtp:"+userId).Return("123456", nil).Once()
+	v.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpAttempts:"+userId, 10*time.Minute).Return(int64(1), nil).Once()
+	v.redisRepository.On("GetResource", mock.Anything, "newPhone:"+userId).Return("+6281234567890", nil).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: model.User{ID: userId}, Version: 3}, nil).Once()
+	v.userRepository.On("UpdateProfile", mock.Anything, mock.Anything, int64(3), mock.Anything).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{
+		User:          model.User{ID: userId},
+		ProfileFields: dto.ProfileFields{Bio: &bio},
+		Version:       4,
+	}, nil).Once()
+	v.userRepository.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *dto.UserProfile) bool {
+		// the concurrent write is kept
+		return *p.PhoneNumber == "+6281234567890" && *p.Bio == bio
+	}), int64(4), mock.Anything).Return(nil).Once()
+	v.profileCache.On("Invalidate", mock.Anything, userId).Return(nil).Once()
+	v.redisRepository.On("RemoveResource", mock.Anything, mock.Anything).Return(nil)
+
+	err := v.userService.VerifyPhoneNumber(context.Background(), req, userId)
+
+	v.NoError(err)
+	v.userRepository.AssertExpectations(v.T())
+}
+
+func (v *VerifyPhoneNumberServiceSuite) TestUserService_VerifyPhoneNumber_ConflictPersists() {
+	userId := "user-123"
+	req := &dto.VerifyPhoneNumberRequest{Code: "123456"}
+
+	v.redisRepository.On("GetResource", mock.Anything, "phoneOtp:"+userId).Return("123456", nil).Once()
+	v.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpAttempts:"+userId, 10*time.Minute).Return(int64(1), nil).Once()
+	v.redisRepository.On("GetResource", mock.Anything, "newPhone:"+userId).Return("+6281234567890", nil).Once()
+	v.userRepository.On("QueryProfileByUserId", mock.Anything, userId).Return(&dto.UserProfile{User: model.User{ID: userId}, Version: 3}, nil)
+	v.userRepository.On("UpdateProfile", mock.Anything, mock.Anything, int64(3), mock.Anything).Return(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH)
+
+	err := v.userService.VerifyPhoneNumber(context.Background(), req, userId)
+
+	v.Equal(dto.Err_PRECONDITION_FAILED_VERSION_MISMATCH, err)
+	v.userRepository.AssertNumberOfCalls(v.T(), "UpdateProfile", 3)
+	// the code survives so it can be submitted again
+	v.redisRepository.AssertNotCalled(v.T(), "RemoveResource", mock.Anything, mock.Anything)
+}
+
+func (v *VerifyPhoneNumberServiceSuite) TestUserService_VerifyPhoneNumber_WrongCode() {
+	userId := "user-123"
+	req := &dto.VerifyPhoneNumberRequest{Code: "654321"}
+
+	v.redisRepository.On("GetResource", mock.Anything, "phoneOtp:"+userId).Return("123456", nil).Once()
+	v.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpAttempts:"+userId, 10*time.Minute).Return(int64(2), nil).Once()
+	v.logEmitter.On("EmitLog", "ERR", mock.Anything).Return(nil)
+
+	err := v.userService.VerifyPhoneNumber(context.Background(), req, userId)
+
+	v.Equal(dto.Err_UNAUTHORIZED_OTP_INVALID, err)
+	v.userRepository.AssertNotCalled(v.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	v.logEmitter.AssertExpectations(v.T())
+}
+
+func (v *VerifyPhoneNumberServiceSuite) TestUserService_VerifyPhoneNumber_TooManyAttempts() {
+	userId := "user-123"
+	// even the right code is refused once the attempts are used up
+	req := &dto.VerifyPhoneNumberRequest{Code: "123456"}
+
+	v.redisRepository.On("GetResource", mock.Anything, "phoneOtp:"+userId).Return("123456", nil).Once()
+	v.redisRepository.On("IncrementResource", mock.Anything, "phoneOtpAttempts:"+userId, 10*time.Minute).Return(int64(6), nil).Once()
+	v.redisRepository.On("RemoveResource", mock.Anything, "phoneOtp:"+userId).Return(nil).Once()
+	v.redisRepository.On("RemoveResource", mock.Anything, "newPhone:"+userId).Return(nil).Once()
+	v.logEmitter.On("EmitLog", "WARN", mock.Anything).Return(nil)
+
+	err := v.userService.VerifyPhoneNumber(context.Background(), req, userId)
+
+	v.Equal(dto.Err_TOO_MANY_REQUESTS_OTP_ATTEMPTS, err)
+	v.redisRepository.AssertExpectations(v.T())
+	v.userRepository.AssertNotCalled(v.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	time.Sleep(time.Second)
+	v.logEmitter.AssertExpectations(v.T())
+}
+
+func (v *VerifyPhoneNumberServiceSuite) TestUserService_VerifyPhoneNumber_Expired() {
+	userId := "user-123"
+	req := &dto.VerifyPhoneNumberRequest{Code: "123456"}
+
+	v.redisRepository.On("GetResource", mock.Anything, "phoneOtp:"+userId).Return("", dto.Err_NOTFOUND_KEY_NOTFOUND).Once()
+
+	err := v.userService.VerifyPhoneNumber(context.Background(), req, userId)
+
+	v.Equal(dto.Err_UNAUTHORIZED_OTP_INVALID, err)
+	v.redisRepository.AssertNotCalled(v.T(), "IncrementResource", mock.Anything, mock.Anything, mock.Anything)
+}